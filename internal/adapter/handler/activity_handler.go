@@ -20,7 +20,7 @@ func NewActivityHandler(svc port.ActivityService) *ActivityHandler {
 	return &ActivityHandler{svc: svc}
 }
 
-// List returns activities for the authenticated user, optionally filtered by date and grouped by day, month, or year.
+// List returns activities for the authenticated user, optionally filtered by date and tag and grouped by day, month, or year.
 func (h *ActivityHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
 	if !ok {
@@ -38,8 +38,12 @@ func (h *ActivityHandler) List(w http.ResponseWriter, r *http.Request) {
 
 	dateFilter, timezone := resolveDateFilter(dateParam, tzParam)
 
-	resp, err := h.svc.ListActivities(r.Context(), userID, groupBy, dateFilter, timezone)
+	resp, err := h.svc.ListActivities(r.Context(), userID, groupBy, dateFilter, timezone, parseTagFilter(r))
 	if err != nil {
+		if strings.Contains(err.Error(), "must") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list activities", err.Error())
 		return
 	}
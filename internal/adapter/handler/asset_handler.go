@@ -115,7 +115,7 @@ func (h *AssetHandler) Update(w http.ResponseWriter, r *http.Request) {
 			response.ErrorWithLog(w, r, http.StatusNotFound, "asset not found", nil)
 			return
 		}
-		if strings.Contains(err.Error(), "must be") {
+		if strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "cannot be edited") || strings.Contains(err.Error(), "exceeds") {
 			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
@@ -144,6 +144,10 @@ func (h *AssetHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			response.ErrorWithLog(w, r, http.StatusNotFound, "asset not found", nil)
 			return
 		}
+		if strings.Contains(err.Error(), "cannot be deleted") {
+			response.ErrorWithLog(w, r, http.StatusConflict, err.Error(), nil)
+			return
+		}
 		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to delete asset", err.Error())
 		return
 	}
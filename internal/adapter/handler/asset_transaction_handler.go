@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"monity/internal/adapter/middleware"
+	"monity/internal/core/port"
+	"monity/internal/pkg/response"
+)
+
+type AssetTransactionHandler struct {
+	svc port.AssetTransactionService
+}
+
+func NewAssetTransactionHandler(svc port.AssetTransactionService) *AssetTransactionHandler {
+	return &AssetTransactionHandler{svc: svc}
+}
+
+// assetTransactionError writes the response for a service error; validation failures, disposals of more
+// than is held and unknown CASH assets are 400s.
+func assetTransactionError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	if err.Error() == "asset not found" || err.Error() == "transaction not found" {
+		response.ErrorWithLog(w, r, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	if strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "exceeds") || strings.Contains(err.Error(), "not found") {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	response.ErrorWithLog(w, r, http.StatusInternalServerError, fallback, err.Error())
+}
+
+func (h *AssetTransactionHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	assetUUID := r.PathValue("uuid")
+	if strings.TrimSpace(assetUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid asset uuid", nil)
+		return
+	}
+
+	txs, err := h.svc.ListTransactions(r.Context(), userID, assetUUID)
+	if err != nil {
+		assetTransactionError(w, r, err, "failed to list transactions")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "transactions retrieved", txs)
+}
+
+func (h *AssetTransactionHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	assetUUID, txUUID := r.PathValue("uuid"), r.PathValue("txUuid")
+	if strings.TrimSpace(assetUUID) == "" || strings.TrimSpace(txUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid asset or transaction uuid", nil)
+		return
+	}
+
+	tx, err := h.svc.GetTransaction(r.Context(), userID, assetUUID, txUUID)
+	if err != nil {
+		assetTransactionError(w, r, err, "failed to get transaction")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "transaction retrieved", tx)
+}
+
+func (h *AssetTransactionHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	assetUUID := r.PathValue("uuid")
+	if strings.TrimSpace(assetUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid asset uuid", nil)
+		return
+	}
+
+	var req port.CreateAssetTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	tx, err := h.svc.CreateTransaction(r.Context(), userID, assetUUID, req)
+	if err != nil {
+		assetTransactionError(w, r, err, "failed to record transaction")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "transaction recorded", tx)
+}
+
+func (h *AssetTransactionHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	assetUUID, txUUID := r.PathValue("uuid"), r.PathValue("txUuid")
+	if strings.TrimSpace(assetUUID) == "" || strings.TrimSpace(txUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid asset or transaction uuid", nil)
+		return
+	}
+
+	var req port.UpdateAssetTransactionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	tx, err := h.svc.UpdateTransaction(r.Context(), userID, assetUUID, txUUID, req)
+	if err != nil {
+		assetTransactionError(w, r, err, "failed to update transaction")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "transaction updated", tx)
+}
+
+func (h *AssetTransactionHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	assetUUID, txUUID := r.PathValue("uuid"), r.PathValue("txUuid")
+	if strings.TrimSpace(assetUUID) == "" || strings.TrimSpace(txUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid asset or transaction uuid", nil)
+		return
+	}
+
+	if err := h.svc.DeleteTransaction(r.Context(), userID, assetUUID, txUUID); err != nil {
+		assetTransactionError(w, r, err, "failed to delete transaction")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "transaction deleted", nil)
+}
+
+func (h *AssetTransactionHandler) Sell(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	assetUUID := r.PathValue("uuid")
+	if strings.TrimSpace(assetUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid asset uuid", nil)
+		return
+	}
+
+	var req port.SellAssetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	sale, err := h.svc.SellAsset(r.Context(), userID, assetUUID, req)
+	if err != nil {
+		assetTransactionError(w, r, err, "failed to sell asset")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "asset sold", sale)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"monity/internal/adapter/middleware"
+	"monity/internal/core/port"
+	"monity/internal/pkg/response"
+)
+
+type BudgetHandler struct {
+	svc port.BudgetService
+}
+
+func NewBudgetHandler(svc port.BudgetService) *BudgetHandler {
+	return &BudgetHandler{svc: svc}
+}
+
+// budgetError writes the response for a service error; a second budget for the same category and month is
+// a 409.
+func budgetError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	msg := err.Error()
+	if msg == "budget not found" {
+		response.ErrorWithLog(w, r, http.StatusNotFound, msg, nil)
+		return
+	}
+	if strings.Contains(msg, "already exists") {
+		response.ErrorWithLog(w, r, http.StatusConflict, msg, nil)
+		return
+	}
+	if strings.Contains(msg, "must") || strings.Contains(msg, "positive") || strings.Contains(msg, "invalid") {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, msg, nil)
+		return
+	}
+	response.ErrorWithLog(w, r, http.StatusInternalServerError, fallback, msg)
+}
+
+func (h *BudgetHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req port.CreateBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	budget, err := h.svc.CreateBudget(r.Context(), userID, req)
+	if err != nil {
+		budgetError(w, r, err, "failed to create budget")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "budget created", budget)
+}
+
+func (h *BudgetHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	page, limit := parsePageLimit(r, 1, 20, 100)
+	budgets, meta, err := h.svc.ListBudgets(r.Context(), userID, page, limit)
+	if err != nil {
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list budgets", err.Error())
+		return
+	}
+	response.Success(w, http.StatusOK, "budgets retrieved", port.ListResponse{Items: budgets, Meta: meta})
+}
+
+func (h *BudgetHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid budget uuid", nil)
+		return
+	}
+
+	budget, err := h.svc.GetBudget(r.Context(), userID, uuid)
+	if err != nil {
+		budgetError(w, r, err, "failed to get budget")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "budget retrieved", budget)
+}
+
+func (h *BudgetHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid budget uuid", nil)
+		return
+	}
+
+	var req port.UpdateBudgetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	budget, err := h.svc.UpdateBudget(r.Context(), userID, uuid, req)
+	if err != nil {
+		budgetError(w, r, err, "failed to update budget")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "budget updated", budget)
+}
+
+func (h *BudgetHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid budget uuid", nil)
+		return
+	}
+
+	if err := h.svc.DeleteBudget(r.Context(), userID, uuid); err != nil {
+		budgetError(w, r, err, "failed to delete budget")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "budget deleted", nil)
+}
+
+// Status reports spending against the budgets of ?month=YYYY-MM (default current month).
+func (h *BudgetHandler) Status(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	status, err := h.svc.GetStatus(r.Context(), userID, r.URL.Query().Get("month"))
+	if err != nil {
+		budgetError(w, r, err, "failed to get budget status")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "budget status retrieved", status)
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"monity/internal/adapter/middleware"
+	"monity/internal/core/port"
+	"monity/internal/pkg/response"
+)
+
+type CategoryHandler struct {
+	svc port.CategoryService
+}
+
+func NewCategoryHandler(svc port.CategoryService) *CategoryHandler {
+	return &CategoryHandler{svc: svc}
+}
+
+// categoryError writes the response for a service error; a duplicate name, or deleting a category that is
+// still used, is a 409.
+func categoryError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	msg := err.Error()
+	if msg == "category not found" {
+		response.ErrorWithLog(w, r, http.StatusNotFound, msg, nil)
+		return
+	}
+	if strings.Contains(msg, "already exists") || strings.Contains(msg, "archive it instead") || strings.Contains(msg, "archive them instead") {
+		response.ErrorWithLog(w, r, http.StatusConflict, msg, nil)
+		return
+	}
+	if strings.Contains(msg, "must") || strings.Contains(msg, "required") || strings.Contains(msg, "cannot") {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, msg, nil)
+		return
+	}
+	response.ErrorWithLog(w, r, http.StatusInternalServerError, fallback, msg)
+}
+
+func (h *CategoryHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req port.CreateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	category, err := h.svc.CreateCategory(r.Context(), userID, req)
+	if err != nil {
+		categoryError(w, r, err, "failed to create category")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "category created", category)
+}
+
+// List returns the user's categories, parents before their subcategories; ?include_archived=true adds the
+// archived ones.
+func (h *CategoryHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	includeArchived := r.URL.Query().Get("include_archived") == "true"
+	categories, err := h.svc.ListCategories(r.Context(), userID, includeArchived)
+	if err != nil {
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list categories", err.Error())
+		return
+	}
+	response.Success(w, http.StatusOK, "categories retrieved", categories)
+}
+
+func (h *CategoryHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid category uuid", nil)
+		return
+	}
+
+	category, err := h.svc.GetCategory(r.Context(), userID, uuid)
+	if err != nil {
+		categoryError(w, r, err, "failed to get category")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "category retrieved", category)
+}
+
+func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid category uuid", nil)
+		return
+	}
+
+	var req port.UpdateCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	category, err := h.svc.UpdateCategory(r.Context(), userID, uuid, req)
+	if err != nil {
+		categoryError(w, r, err, "failed to update category")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "category updated", category)
+}
+
+func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid category uuid", nil)
+		return
+	}
+
+	if err := h.svc.DeleteCategory(r.Context(), userID, uuid); err != nil {
+		categoryError(w, r, err, "failed to delete category")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "category deleted", nil)
+}
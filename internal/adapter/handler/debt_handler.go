@@ -34,7 +34,7 @@ func (h *DebtHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	debt, err := h.svc.CreateDebt(r.Context(), userID, req)
 	if err != nil {
-		if strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "not found") {
+		if strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "cannot exceed") || strings.Contains(err.Error(), "not found") {
 			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
@@ -57,8 +57,12 @@ func (h *DebtHandler) List(w http.ResponseWriter, r *http.Request) {
 	if s := strings.TrimSpace(r.URL.Query().Get("status")); s != "" && isValidObligationStatus(s) {
 		status = &s
 	}
-	debts, meta, err := h.svc.ListDebts(r.Context(), userID, status, dueFrom, dueTo, page, limit)
+	debts, meta, err := h.svc.ListDebts(r.Context(), userID, status, dueFrom, dueTo, parseTagFilter(r), page, limit)
 	if err != nil {
+		if strings.Contains(err.Error(), "must") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list debts", err.Error())
 		return
 	}
@@ -116,7 +120,7 @@ func (h *DebtHandler) Update(w http.ResponseWriter, r *http.Request) {
 			response.ErrorWithLog(w, r, http.StatusNotFound, "debt not found", nil)
 			return
 		}
-		if strings.Contains(err.Error(), "empty") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") {
+		if strings.Contains(err.Error(), "empty") || strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "cannot exceed") {
 			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
@@ -145,6 +149,10 @@ func (h *DebtHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			response.ErrorWithLog(w, r, http.StatusNotFound, "debt not found", nil)
 			return
 		}
+		if strings.Contains(err.Error(), "cannot exceed") {
+			response.ErrorWithLog(w, r, http.StatusConflict, err.Error(), nil)
+			return
+		}
 		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to delete debt", err.Error())
 		return
 	}
@@ -214,6 +222,99 @@ func (h *DebtHandler) ListPayments(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, http.StatusOK, "payments retrieved", payments)
 }
 
+func (h *DebtHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	debtUUID, paymentUUID := r.PathValue("uuid"), r.PathValue("paymentUuid")
+	if strings.TrimSpace(debtUUID) == "" || strings.TrimSpace(paymentUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid debt or payment uuid", nil)
+		return
+	}
+
+	payment, err := h.svc.GetDebtPayment(r.Context(), userID, debtUUID, paymentUUID)
+	if err != nil {
+		if err.Error() == "debt not found" || err.Error() == "payment not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to get payment", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "payment retrieved", payment)
+}
+
+func (h *DebtHandler) UpdatePayment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	debtUUID, paymentUUID := r.PathValue("uuid"), r.PathValue("paymentUuid")
+	if strings.TrimSpace(debtUUID) == "" || strings.TrimSpace(paymentUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid debt or payment uuid", nil)
+		return
+	}
+
+	var req port.UpdateDebtPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	payment, err := h.svc.UpdateDebtPayment(r.Context(), userID, debtUUID, paymentUUID, req)
+	if err != nil {
+		if err.Error() == "debt not found" || err.Error() == "payment not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		if strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "voided") || strings.Contains(err.Error(), "cannot exceed") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "not found") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to update payment", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "payment updated", payment)
+}
+
+// VoidPayment handles DELETE on a payment: the payment is kept (with voidedAt set) but no longer counts.
+func (h *DebtHandler) VoidPayment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	debtUUID, paymentUUID := r.PathValue("uuid"), r.PathValue("paymentUuid")
+	if strings.TrimSpace(debtUUID) == "" || strings.TrimSpace(paymentUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid debt or payment uuid", nil)
+		return
+	}
+
+	payment, err := h.svc.VoidDebtPayment(r.Context(), userID, debtUUID, paymentUUID)
+	if err != nil {
+		if err.Error() == "debt not found" || err.Error() == "payment not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		if strings.Contains(err.Error(), "already voided") {
+			response.ErrorWithLog(w, r, http.StatusConflict, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to void payment", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "payment voided", payment)
+}
+
 func isValidObligationStatus(s string) bool {
 	switch s {
 	case string(models.ObligationStatusPending), string(models.ObligationStatusPartial),
@@ -31,14 +31,14 @@ func (h *ExpenseHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.Amount <= 0 || req.Category == "" {
+	if req.Amount <= 0 || (req.Category == "" && (req.CategoryUUID == nil || *req.CategoryUUID == "") && len(req.Splits) == 0) {
 		response.ErrorWithLog(w, r, http.StatusBadRequest, "missing required fields", nil)
 		return
 	}
 
 	expense, err := h.svc.CreateExpense(r.Context(), userID, req)
 	if err != nil {
-		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "exceed") {
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must") || strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "exceed") {
 			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
@@ -57,8 +57,12 @@ func (h *ExpenseHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 	page, limit := parsePageLimit(r, 1, 20, 100)
 	dateFrom, dateTo := parseDateFilter(r)
-	expenses, meta, err := h.svc.ListExpenses(r.Context(), userID, dateFrom, dateTo, page, limit)
+	expenses, meta, err := h.svc.ListExpenses(r.Context(), userID, dateFrom, dateTo, parseTagFilter(r), page, limit)
 	if err != nil {
+		if strings.Contains(err.Error(), "must") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list expenses", err.Error())
 		return
 	}
@@ -116,7 +120,7 @@ func (h *ExpenseHandler) Update(w http.ResponseWriter, r *http.Request) {
 			response.ErrorWithLog(w, r, http.StatusNotFound, "expense not found", nil)
 			return
 		}
-		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "exceed") {
+		if strings.Contains(err.Error(), "invalid") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must") || strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "exceed") {
 			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
@@ -57,8 +57,12 @@ func (h *IncomeHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 	page, limit := parsePageLimit(r, 1, 20, 100)
 	dateFrom, dateTo := parseDateFilter(r)
-	incomes, meta, err := h.svc.ListIncomes(r.Context(), userID, dateFrom, dateTo, page, limit)
+	incomes, meta, err := h.svc.ListIncomes(r.Context(), userID, dateFrom, dateTo, parseTagFilter(r), page, limit)
 	if err != nil {
+		if strings.Contains(err.Error(), "must") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list incomes", err.Error())
 		return
 	}
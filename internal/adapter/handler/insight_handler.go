@@ -26,8 +26,9 @@ func (h *InsightHandler) GetCashflow(w http.ResponseWriter, r *http.Request) {
 
 	// Get month from query params (optional, defaults to current month)
 	month := r.URL.Query().Get("month")
+	rollup := r.URL.Query().Get("rollup") == "true"
 
-	summary, err := h.svc.GetCashflowSummary(r.Context(), userID, month)
+	summary, err := h.svc.GetCashflowSummary(r.Context(), userID, month, rollup)
 	if err != nil {
 		if strings.Contains(err.Error(), "invalid month format") {
 			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
@@ -55,3 +56,26 @@ func (h *InsightHandler) GetOverview(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, http.StatusOK, "financial overview retrieved", overview)
 }
+
+// GetTags returns income and spending per tag over date_from/date_to, month or year (all time by default);
+// tag keeps only the named tags.
+func (h *InsightHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	dateFrom, dateTo := parseDateFilter(r)
+	summary, err := h.svc.GetTagSummary(r.Context(), userID, dateFrom, dateTo, parseTagFilter(r))
+	if err != nil {
+		if strings.Contains(err.Error(), "must") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to get tag summary", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "tag summary retrieved", summary)
+}
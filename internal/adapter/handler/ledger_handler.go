@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"monity/internal/adapter/middleware"
+	"monity/internal/core/port"
+	"monity/internal/pkg/response"
+)
+
+type LedgerHandler struct {
+	svc port.LedgerService
+}
+
+func NewLedgerHandler(svc port.LedgerService) *LedgerHandler {
+	return &LedgerHandler{svc: svc}
+}
+
+func (h *LedgerHandler) GetAssetLedger(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid asset uuid", nil)
+		return
+	}
+
+	page, limit := parsePageLimit(r, 1, 20, 100)
+	ledger, err := h.svc.GetAssetLedger(r.Context(), userID, uuid, page, limit)
+	if err != nil {
+		if err.Error() == "asset not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "asset not found", nil)
+			return
+		}
+		if strings.Contains(err.Error(), "must be") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to get asset ledger", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "asset ledger retrieved", ledger)
+}
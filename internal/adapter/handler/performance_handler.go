@@ -64,3 +64,29 @@ func (h *PerformanceHandler) GetPortfolioPerformance(w http.ResponseWriter, r *h
 
 	response.Success(w, http.StatusOK, "portfolio performance retrieved", performance)
 }
+
+func (h *PerformanceHandler) GetAssetIncome(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid asset uuid", nil)
+		return
+	}
+
+	income, err := h.svc.GetAssetIncome(r.Context(), userID, uuid, r.URL.Query().Get("currency"))
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "asset not found", nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to get asset income", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "asset income retrieved", income)
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"monity/internal/core/port"
 	"monity/internal/pkg/response"
@@ -18,12 +19,16 @@ var (
 	stockChartIntervalAllowed = map[string]bool{"1d": true, "1wk": true, "1mo": true}
 )
 
+// maxOHLCVDays caps the date range of one OHLCV request.
+const maxOHLCVDays = 365
+
 type PriceHandler struct {
 	svc port.PriceService
+	fx  port.FXService
 }
 
-func NewPriceHandler(svc port.PriceService) *PriceHandler {
-	return &PriceHandler{svc: svc}
+func NewPriceHandler(svc port.PriceService, fx port.FXService) *PriceHandler {
+	return &PriceHandler{svc: svc, fx: fx}
 }
 
 func (h *PriceHandler) GetCryptoPrice(w http.ResponseWriter, r *http.Request) {
@@ -139,7 +144,10 @@ func (h *PriceHandler) GetStockChart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chart, err := h.svc.GetStockChart(r.Context(), symbol, rangeParam, interval)
+	// Optional: without it the chart is in the listing currency.
+	currency := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("currency")))
+
+	chart, err := h.svc.GetStockChart(r.Context(), symbol, rangeParam, interval, currency)
 	if err != nil {
 		if strings.Contains(err.Error(), "no chart data") {
 			response.ErrorWithLog(w, r, http.StatusNotFound, "chart not found", err.Error())
@@ -151,3 +159,74 @@ func (h *PriceHandler) GetStockChart(w http.ResponseWriter, r *http.Request) {
 
 	response.Success(w, http.StatusOK, "stock chart retrieved", chart)
 }
+
+func (h *PriceHandler) GetCryptoOHLCV(w http.ResponseWriter, r *http.Request) {
+	symbol := strings.TrimSpace(r.PathValue("symbol"))
+	if symbol == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "symbol is required", nil)
+		return
+	}
+
+	currency := r.URL.Query().Get("currency")
+	if currency == "" {
+		currency = port.DefaultCurrency
+	}
+	currency = strings.ToUpper(currency)
+
+	// Default: the last 30 days
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -30)
+	if dateFrom, dateTo := parseDateFilter(r); dateFrom != nil && dateTo != nil {
+		from, to = *dateFrom, *dateTo
+	}
+	if to.Before(from) || to.Sub(from) > maxOHLCVDays*24*time.Hour {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "date range must be between 0 and 365 days", nil)
+		return
+	}
+
+	candles, err := h.svc.GetHistoricalCryptoOHLCV(r.Context(), symbol, currency, from, to, "daily")
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "price history not found", err.Error())
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusBadGateway, "failed to fetch OHLCV", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "crypto OHLCV retrieved", candles)
+}
+
+// GetExchangeRate answers the rate between two currencies, now or as of date (YYYY-MM-DD).
+func (h *PriceHandler) GetExchangeRate(w http.ResponseWriter, r *http.Request) {
+	from := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("from")))
+	to := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("to")))
+	if len(from) != 3 || len(to) != 3 {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "from and to must be ISO 4217 currency codes", nil)
+		return
+	}
+
+	date := time.Now().UTC()
+	var rate float64
+	var err error
+	if d := r.URL.Query().Get("date"); d != "" {
+		date, err = time.Parse("2006-01-02", d)
+		if err != nil {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, "date must be YYYY-MM-DD", nil)
+			return
+		}
+		rate, err = h.fx.RateAt(r.Context(), from, to, date)
+	} else {
+		rate, err = h.fx.Rate(r.Context(), from, to)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "not found") {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "exchange rate not found", err.Error())
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusBadGateway, "failed to fetch exchange rate", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "exchange rate retrieved", port.FXRateResponse{From: from, To: to, Rate: rate, Date: date})
+}
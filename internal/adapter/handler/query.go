@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -78,3 +79,17 @@ func parseDueFilter(r *http.Request) (dueFrom, dueTo *time.Time) {
 	}
 	return dueFrom, dueTo
 }
+
+// parseTagFilter returns the tag names from query: repeated tag params and/or comma-separated, e.g.
+// tag=trip-bali&tag=food or tag=trip-bali,food.
+func parseTagFilter(r *http.Request) []string {
+	var tags []string
+	for _, v := range r.URL.Query()["tag"] {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				tags = append(tags, name)
+			}
+		}
+	}
+	return tags
+}
@@ -33,7 +33,7 @@ func (h *ReceivableHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	rec, err := h.svc.CreateReceivable(r.Context(), userID, req)
 	if err != nil {
-		if strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "not found") {
+		if strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "cannot exceed") || strings.Contains(err.Error(), "not found") {
 			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
@@ -56,8 +56,12 @@ func (h *ReceivableHandler) List(w http.ResponseWriter, r *http.Request) {
 	if s := strings.TrimSpace(r.URL.Query().Get("status")); s != "" && isValidObligationStatus(s) {
 		status = &s
 	}
-	recs, meta, err := h.svc.ListReceivables(r.Context(), userID, status, dueFrom, dueTo, page, limit)
+	recs, meta, err := h.svc.ListReceivables(r.Context(), userID, status, dueFrom, dueTo, parseTagFilter(r), page, limit)
 	if err != nil {
+		if strings.Contains(err.Error(), "must") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
 		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list receivables", err.Error())
 		return
 	}
@@ -115,7 +119,7 @@ func (h *ReceivableHandler) Update(w http.ResponseWriter, r *http.Request) {
 			response.ErrorWithLog(w, r, http.StatusNotFound, "receivable not found", nil)
 			return
 		}
-		if strings.Contains(err.Error(), "empty") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") {
+		if strings.Contains(err.Error(), "empty") || strings.Contains(err.Error(), "required") || strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "cannot exceed") {
 			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
 			return
 		}
@@ -144,6 +148,10 @@ func (h *ReceivableHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			response.ErrorWithLog(w, r, http.StatusNotFound, "receivable not found", nil)
 			return
 		}
+		if strings.Contains(err.Error(), "cannot exceed") {
+			response.ErrorWithLog(w, r, http.StatusConflict, err.Error(), nil)
+			return
+		}
 		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to delete receivable", err.Error())
 		return
 	}
@@ -212,3 +220,96 @@ func (h *ReceivableHandler) ListPayments(w http.ResponseWriter, r *http.Request)
 
 	response.Success(w, http.StatusOK, "payments retrieved", payments)
 }
+
+func (h *ReceivableHandler) GetPayment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	receivableUUID, paymentUUID := r.PathValue("uuid"), r.PathValue("paymentUuid")
+	if strings.TrimSpace(receivableUUID) == "" || strings.TrimSpace(paymentUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid receivable or payment uuid", nil)
+		return
+	}
+
+	payment, err := h.svc.GetReceivablePayment(r.Context(), userID, receivableUUID, paymentUUID)
+	if err != nil {
+		if err.Error() == "receivable not found" || err.Error() == "payment not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to get payment", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "payment retrieved", payment)
+}
+
+func (h *ReceivableHandler) UpdatePayment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	receivableUUID, paymentUUID := r.PathValue("uuid"), r.PathValue("paymentUuid")
+	if strings.TrimSpace(receivableUUID) == "" || strings.TrimSpace(paymentUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid receivable or payment uuid", nil)
+		return
+	}
+
+	var req port.UpdateReceivablePaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	payment, err := h.svc.UpdateReceivablePayment(r.Context(), userID, receivableUUID, paymentUUID, req)
+	if err != nil {
+		if err.Error() == "receivable not found" || err.Error() == "payment not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		if strings.Contains(err.Error(), "positive") || strings.Contains(err.Error(), "voided") || strings.Contains(err.Error(), "cannot exceed") || strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "not found") {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to update payment", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "payment updated", payment)
+}
+
+// VoidPayment handles DELETE on a payment: the payment is kept (with voidedAt set) but no longer counts.
+func (h *ReceivableHandler) VoidPayment(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	receivableUUID, paymentUUID := r.PathValue("uuid"), r.PathValue("paymentUuid")
+	if strings.TrimSpace(receivableUUID) == "" || strings.TrimSpace(paymentUUID) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid receivable or payment uuid", nil)
+		return
+	}
+
+	payment, err := h.svc.VoidReceivablePayment(r.Context(), userID, receivableUUID, paymentUUID)
+	if err != nil {
+		if err.Error() == "receivable not found" || err.Error() == "payment not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, err.Error(), nil)
+			return
+		}
+		if strings.Contains(err.Error(), "already voided") || strings.Contains(err.Error(), "cannot exceed") {
+			response.ErrorWithLog(w, r, http.StatusConflict, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to void payment", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "payment voided", payment)
+}
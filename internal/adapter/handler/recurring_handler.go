@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"monity/internal/adapter/middleware"
+	"monity/internal/core/port"
+	"monity/internal/pkg/response"
+)
+
+type RecurringHandler struct {
+	svc port.RecurringService
+}
+
+func NewRecurringHandler(svc port.RecurringService) *RecurringHandler {
+	return &RecurringHandler{svc: svc}
+}
+
+// recurringError writes the response for a service error; validation failures and postings the CASH asset
+// cannot cover are 400s.
+func recurringError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	if err.Error() == "recurring rule not found" || err.Error() == "occurrence not found" {
+		response.ErrorWithLog(w, r, http.StatusNotFound, err.Error(), nil)
+		return
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "must") || strings.Contains(msg, "required") || strings.Contains(msg, "positive") ||
+		strings.Contains(msg, "invalid") || strings.Contains(msg, "cannot exceed") || msg == "asset not found" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, msg, nil)
+		return
+	}
+	response.ErrorWithLog(w, r, http.StatusInternalServerError, fallback, msg)
+}
+
+func (h *RecurringHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req port.CreateRecurringRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	rule, err := h.svc.CreateRule(r.Context(), userID, req)
+	if err != nil {
+		recurringError(w, r, err, "failed to create recurring rule")
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "recurring rule created", rule)
+}
+
+func (h *RecurringHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	page, limit := parsePageLimit(r, 1, 20, 100)
+	rules, meta, err := h.svc.ListRules(r.Context(), userID, page, limit)
+	if err != nil {
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list recurring rules", err.Error())
+		return
+	}
+	response.Success(w, http.StatusOK, "recurring rules retrieved", port.ListResponse{Items: rules, Meta: meta})
+}
+
+func (h *RecurringHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid recurring rule uuid", nil)
+		return
+	}
+
+	rule, err := h.svc.GetRule(r.Context(), userID, uuid)
+	if err != nil {
+		recurringError(w, r, err, "failed to get recurring rule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "recurring rule retrieved", rule)
+}
+
+func (h *RecurringHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid recurring rule uuid", nil)
+		return
+	}
+
+	var req port.UpdateRecurringRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	rule, err := h.svc.UpdateRule(r.Context(), userID, uuid, req)
+	if err != nil {
+		recurringError(w, r, err, "failed to update recurring rule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "recurring rule updated", rule)
+}
+
+func (h *RecurringHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid recurring rule uuid", nil)
+		return
+	}
+
+	if err := h.svc.DeleteRule(r.Context(), userID, uuid); err != nil {
+		recurringError(w, r, err, "failed to delete recurring rule")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "recurring rule deleted", nil)
+}
+
+func (h *RecurringHandler) ListOccurrences(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid recurring rule uuid", nil)
+		return
+	}
+
+	occs, err := h.svc.ListOccurrences(r.Context(), userID, uuid)
+	if err != nil {
+		recurringError(w, r, err, "failed to list occurrences")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "occurrences retrieved", occs)
+}
+
+// UpdateOccurrence skips or edits one occurrence, addressed by its due date (YYYY-MM-DD).
+func (h *RecurringHandler) UpdateOccurrence(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	date, err := time.Parse("2006-01-02", r.PathValue("date"))
+	if strings.TrimSpace(uuid) == "" || err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid recurring rule uuid or date (YYYY-MM-DD)", nil)
+		return
+	}
+
+	var req port.UpdateOccurrenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	occ, err := h.svc.UpdateOccurrence(r.Context(), userID, uuid, date, req)
+	if err != nil {
+		recurringError(w, r, err, "failed to update occurrence")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "occurrence updated", occ)
+}
+
+func (h *RecurringHandler) ConfirmOccurrence(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	date, err := time.Parse("2006-01-02", r.PathValue("date"))
+	if strings.TrimSpace(uuid) == "" || err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid recurring rule uuid or date (YYYY-MM-DD)", nil)
+		return
+	}
+
+	occ, err := h.svc.ConfirmOccurrence(r.Context(), userID, uuid, date)
+	if err != nil {
+		recurringError(w, r, err, "failed to confirm occurrence")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "occurrence posted", occ)
+}
+
+// Upcoming lists occurrences awaiting confirmation and those due within ?days= (default 30).
+func (h *RecurringHandler) Upcoming(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	days := 0
+	if d := r.URL.Query().Get("days"); d != "" {
+		v, err := strconv.Atoi(d)
+		if err != nil {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, "days must be a number", nil)
+			return
+		}
+		days = v
+	}
+
+	upcoming, err := h.svc.Upcoming(r.Context(), userID, days)
+	if err != nil {
+		recurringError(w, r, err, "failed to list upcoming occurrences")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "upcoming occurrences retrieved", upcoming)
+}
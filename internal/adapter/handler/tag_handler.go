@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"monity/internal/adapter/middleware"
+	"monity/internal/core/port"
+	"monity/internal/pkg/response"
+)
+
+type TagHandler struct {
+	svc port.TagService
+}
+
+func NewTagHandler(svc port.TagService) *TagHandler {
+	return &TagHandler{svc: svc}
+}
+
+// tagError writes the response for a service error; renaming onto a name already in use is a 409.
+func tagError(w http.ResponseWriter, r *http.Request, err error, fallback string) {
+	msg := err.Error()
+	if msg == "tag not found" {
+		response.ErrorWithLog(w, r, http.StatusNotFound, msg, nil)
+		return
+	}
+	if strings.Contains(msg, "already exists") {
+		response.ErrorWithLog(w, r, http.StatusConflict, msg, nil)
+		return
+	}
+	if strings.Contains(msg, "must") || strings.Contains(msg, "required") {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, msg, nil)
+		return
+	}
+	response.ErrorWithLog(w, r, http.StatusInternalServerError, fallback, msg)
+}
+
+func (h *TagHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	tags, err := h.svc.ListTags(r.Context(), userID)
+	if err != nil {
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list tags", err.Error())
+		return
+	}
+	response.Success(w, http.StatusOK, "tags retrieved", tags)
+}
+
+// Update renames a tag on everything it labels.
+func (h *TagHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid tag uuid", nil)
+		return
+	}
+
+	var req port.UpdateTagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	tag, err := h.svc.RenameTag(r.Context(), userID, uuid, req)
+	if err != nil {
+		tagError(w, r, err, "failed to update tag")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "tag updated", tag)
+}
+
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid tag uuid", nil)
+		return
+	}
+
+	if err := h.svc.DeleteTag(r.Context(), userID, uuid); err != nil {
+		tagError(w, r, err, "failed to delete tag")
+		return
+	}
+
+	response.Success(w, http.StatusOK, "tag deleted", nil)
+}
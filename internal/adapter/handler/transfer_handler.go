@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"monity/internal/adapter/middleware"
+	"monity/internal/core/port"
+	"monity/internal/pkg/response"
+)
+
+type TransferHandler struct {
+	svc port.TransferService
+}
+
+func NewTransferHandler(svc port.TransferService) *TransferHandler {
+	return &TransferHandler{svc: svc}
+}
+
+func (h *TransferHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	var req port.CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	if req.Amount <= 0 || req.FromAssetUUID == "" || req.ToAssetUUID == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "missing required fields", nil)
+		return
+	}
+
+	transfer, err := h.svc.CreateTransfer(r.Context(), userID, req)
+	if err != nil {
+		if err.Error() == "asset not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "asset not found", nil)
+			return
+		}
+		if isTransferValidationError(err) {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to create transfer", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusCreated, "transfer created", transfer)
+}
+
+func (h *TransferHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+	page, limit := parsePageLimit(r, 1, 20, 100)
+	dateFrom, dateTo := parseDateFilter(r)
+	transfers, meta, err := h.svc.ListTransfers(r.Context(), userID, dateFrom, dateTo, page, limit)
+	if err != nil {
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to list transfers", err.Error())
+		return
+	}
+	response.Success(w, http.StatusOK, "transfers retrieved", port.ListResponse{Items: transfers, Meta: meta})
+}
+
+func (h *TransferHandler) Get(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid transfer uuid", nil)
+		return
+	}
+
+	transfer, err := h.svc.GetTransfer(r.Context(), userID, uuid)
+	if err != nil {
+		if err.Error() == "transfer not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "transfer not found", nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to get transfer", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "transfer retrieved", transfer)
+}
+
+func (h *TransferHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid transfer uuid", nil)
+		return
+	}
+
+	var req port.UpdateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid request body", err.Error())
+		return
+	}
+
+	transfer, err := h.svc.UpdateTransfer(r.Context(), userID, uuid, req)
+	if err != nil {
+		if err.Error() == "transfer not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "transfer not found", nil)
+			return
+		}
+		if err.Error() == "asset not found" {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "asset not found", nil)
+			return
+		}
+		if isTransferValidationError(err) {
+			response.ErrorWithLog(w, r, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to update transfer", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "transfer updated", transfer)
+}
+
+func (h *TransferHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := r.Context().Value(middleware.CtxKeyUserID).(int64)
+	if !ok {
+		response.ErrorWithLog(w, r, http.StatusUnauthorized, "unauthorized", nil)
+		return
+	}
+
+	uuid := r.PathValue("uuid")
+	if strings.TrimSpace(uuid) == "" {
+		response.ErrorWithLog(w, r, http.StatusBadRequest, "invalid transfer uuid", nil)
+		return
+	}
+
+	if err := h.svc.DeleteTransfer(r.Context(), userID, uuid); err != nil {
+		if strings.Contains(err.Error(), "not found") || strings.Contains(err.Error(), "not owned") {
+			response.ErrorWithLog(w, r, http.StatusNotFound, "transfer not found", nil)
+			return
+		}
+		if strings.Contains(err.Error(), "cannot exceed") {
+			response.ErrorWithLog(w, r, http.StatusConflict, err.Error(), nil)
+			return
+		}
+		response.ErrorWithLog(w, r, http.StatusInternalServerError, "failed to delete transfer", err.Error())
+		return
+	}
+
+	response.Success(w, http.StatusOK, "transfer deleted", nil)
+}
+
+func isTransferValidationError(err error) bool {
+	msg := err.Error()
+	for _, s := range []string{"invalid", "positive", "must be", "must not", "required", "exceed"} {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,222 @@
+package priceprovider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+)
+
+// CoinGecko serves crypto quotes, history and charts from the free CoinGecko API (no key needed).
+// https://api.coingecko.com/api/v3/simple/price?ids=solana&vs_currencies=usd
+type CoinGecko struct {
+	baseURL  string
+	upstream *Upstream
+	coins    port.CoinIDResolver
+}
+
+const coinGeckoName = "coingecko"
+
+func NewCoinGecko(baseURL string, upstream *Upstream, coins port.CoinIDResolver) port.PriceProvider {
+	return &CoinGecko{baseURL: strings.TrimRight(baseURL, "/"), upstream: upstream, coins: coins}
+}
+
+func (p *CoinGecko) Name() string { return coinGeckoName }
+
+func (p *CoinGecko) Supports(kind string) bool { return kind == port.PriceKindCrypto }
+
+// coinID returns the pinned coin ID when there is one, otherwise resolves the ticker from the coin list.
+func (p *CoinGecko) coinID(ctx context.Context, sym port.PriceSymbol) (string, error) {
+	if sym.ProviderID != "" {
+		return sym.ProviderID, nil
+	}
+	return p.coins.ResolveCoinID(ctx, sym.Ticker)
+}
+
+func (p *CoinGecko) Quote(ctx context.Context, kind string, sym port.PriceSymbol, currency string) (*port.PriceData, error) {
+	symbol := sym.Ticker
+	coinID, err := p.coinID(ctx, sym)
+	if err != nil {
+		return nil, err
+	}
+	vsCurrency := strings.ToLower(currency)
+	url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s", p.baseURL, coinID, vsCurrency)
+
+	// Response: {"solana":{"usd":86.59}}
+	var result map[string]map[string]float64
+	if err := p.upstream.GetJSON(ctx, coinGeckoName, "price", url, nil, &result); err != nil {
+		return nil, err
+	}
+	coinData, ok := result[coinID]
+	if !ok {
+		return nil, fmt.Errorf("price not found for %s", symbol)
+	}
+	price, ok := coinData[vsCurrency]
+	if !ok {
+		return nil, fmt.Errorf("price in %s not found for %s", currency, symbol)
+	}
+	return &port.PriceData{
+		Symbol:    symbol,
+		Price:     price,
+		Currency:  currency,
+		Source:    coinGeckoName,
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+// coinGeckoBatch caps the ids sent in one /simple/price call to keep the URL short.
+const coinGeckoBatch = 100
+
+// QuoteMany prices several coins with /simple/price?ids=a,b,c. Tickers that cannot be resolved to a coin
+// ID are left out of the result.
+func (p *CoinGecko) QuoteMany(ctx context.Context, kind string, syms []port.PriceSymbol, currency string) (map[port.PriceSymbol]*port.PriceData, error) {
+	byID := make(map[string][]port.PriceSymbol)
+	var ids []string
+	for _, sym := range syms {
+		coinID, err := p.coinID(ctx, sym)
+		if err != nil {
+			slog.Debug("coingecko_unresolved", "symbol", sym.Ticker, "error", err)
+			continue
+		}
+		if _, ok := byID[coinID]; !ok {
+			ids = append(ids, coinID)
+		}
+		byID[coinID] = append(byID[coinID], sym)
+	}
+
+	vsCurrency := strings.ToLower(currency)
+	out := make(map[port.PriceSymbol]*port.PriceData, len(syms))
+	now := time.Now()
+	for start := 0; start < len(ids); start += coinGeckoBatch {
+		chunk := ids[start:min(start+coinGeckoBatch, len(ids))]
+		url := fmt.Sprintf("%s/simple/price?ids=%s&vs_currencies=%s", p.baseURL, strings.Join(chunk, ","), vsCurrency)
+		var result map[string]map[string]float64
+		if err := p.upstream.GetJSON(ctx, coinGeckoName, "price", url, nil, &result); err != nil {
+			if len(out) > 0 {
+				// Keep what earlier chunks found; the rest fall through to the next provider.
+				slog.Warn("price_api_error", "kind", kind, "source", coinGeckoName, "error", err)
+				return out, nil
+			}
+			return nil, err
+		}
+		for _, coinID := range chunk {
+			price, ok := result[coinID][vsCurrency]
+			if !ok {
+				continue
+			}
+			for _, sym := range byID[coinID] {
+				out[sym] = &port.PriceData{
+					Symbol:    sym.Ticker,
+					Price:     price,
+					Currency:  currency,
+					Source:    coinGeckoName,
+					FetchedAt: now,
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+func (p *CoinGecko) HistoricalPrice(ctx context.Context, kind, symbol, currency string, at time.Time) (*port.PriceData, error) {
+	coinID, err := p.coinID(ctx, port.PriceSymbol{Ticker: symbol})
+	if err != nil {
+		return nil, err
+	}
+	// CoinGecko history endpoint: /coins/{id}/history?date=dd-mm-yyyy
+	dateStr := at.UTC().Format("02-01-2006")
+	url := fmt.Sprintf("%s/coins/%s/history?date=%s&localization=false", p.baseURL, coinID, dateStr)
+
+	var result struct {
+		MarketData struct {
+			CurrentPrice map[string]float64 `json:"current_price"`
+		} `json:"market_data"`
+	}
+	if err := p.upstream.GetJSON(ctx, coinGeckoName, "history", url, nil, &result); err != nil {
+		return nil, err
+	}
+	price, ok := result.MarketData.CurrentPrice[strings.ToLower(currency)]
+	if !ok {
+		return nil, fmt.Errorf("historical price not found for %s at %s", symbol, dateStr)
+	}
+	return &port.PriceData{
+		Symbol:    symbol,
+		Price:     price,
+		Currency:  currency,
+		Source:    coinGeckoName,
+		FetchedAt: at,
+	}, nil
+}
+
+func (p *CoinGecko) OHLCV(ctx context.Context, kind, symbol, currency string, from, to time.Time) ([]port.OHLCVData, error) {
+	coinID, err := p.coinID(ctx, port.PriceSymbol{Ticker: symbol})
+	if err != nil {
+		return nil, err
+	}
+	// CoinGecko OHLC endpoint: /coins/{id}/ohlc?vs_currency=usd&days=30
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days < 1 {
+		days = 1
+	}
+	if days > 365 {
+		days = 365
+	}
+	url := fmt.Sprintf("%s/coins/%s/ohlc?vs_currency=%s&days=%d", p.baseURL, coinID, strings.ToLower(currency), days)
+
+	// Response: [[timestamp, open, high, low, close], ...]
+	var rawData [][]float64
+	if err := p.upstream.GetJSON(ctx, coinGeckoName, "OHLC", url, nil, &rawData); err != nil {
+		return nil, err
+	}
+	out := make([]port.OHLCVData, 0, len(rawData))
+	for _, candle := range rawData {
+		if len(candle) < 5 {
+			continue
+		}
+		ts := time.UnixMilli(int64(candle[0]))
+		out = append(out, port.OHLCVData{
+			Symbol:    symbol,
+			TimeOpen:  ts,
+			TimeClose: ts,
+			Open:      candle[1],
+			High:      candle[2],
+			Low:       candle[3],
+			Close:     candle[4],
+			Volume:    0, // CoinGecko OHLC doesn't include volume
+			Currency:  currency,
+			Source:    coinGeckoName,
+		})
+	}
+	return out, nil
+}
+
+func (p *CoinGecko) Chart(ctx context.Context, kind, symbol string, req port.ChartRequest) (*port.ChartResponse, error) {
+	coinID, err := p.coinID(ctx, port.PriceSymbol{Ticker: symbol})
+	if err != nil {
+		return nil, err
+	}
+	url := fmt.Sprintf("%s/coins/%s/market_chart?vs_currency=%s&days=%d", p.baseURL, coinID, strings.ToLower(req.Currency), req.Days)
+
+	var result struct {
+		Prices [][]float64 `json:"prices"`
+	}
+	if err := p.upstream.GetJSON(ctx, coinGeckoName, "market_chart", url, nil, &result); err != nil {
+		return nil, err
+	}
+	data := make([]port.ChartDataPoint, 0, len(result.Prices))
+	for _, pair := range result.Prices {
+		if len(pair) < 2 {
+			continue
+		}
+		ms := int64(pair[0])
+		data = append(data, port.ChartDataPoint{T: ms / 1000, P: pair[1]})
+	}
+	return &port.ChartResponse{Symbol: symbol, Currency: req.Currency, Source: coinGeckoName, Data: data}, nil
+}
+
+func (p *CoinGecko) ExchangeRate(ctx context.Context, from, to string) (float64, error) {
+	return 0, port.ErrProviderUnsupported
+}
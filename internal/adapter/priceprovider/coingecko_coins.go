@@ -0,0 +1,203 @@
+package priceprovider
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+)
+
+// CoinList resolves crypto tickers to CoinGecko coin IDs. The list is kept in the crypto_coins table,
+// refreshed from CoinGecko by the coin-list-refresh job, and read into memory on first use and again every
+// coinListReload, so replicas that did not run the job pick up its coins.
+// https://api.coingecko.com/api/v3/coins/list
+type CoinList struct {
+	baseURL  string
+	upstream *Upstream
+	repo     port.CryptoCoinRepository
+
+	mu          sync.RWMutex
+	bySymbol    map[string]string // upper-case ticker -> coin ID
+	loadedAt    time.Time
+	lastAttempt time.Time
+}
+
+const (
+	// coinMarketPages is how many /coins/markets pages (250 coins each) are read for market-cap ranks.
+	coinMarketPages = 4
+	// coinListRetry throttles loading the list when the table is empty and CoinGecko is failing.
+	coinListRetry = 5 * time.Minute
+	// coinListReload is how long the in-memory index is used before crypto_coins is read again.
+	coinListReload = 30 * time.Minute
+)
+
+func NewCoinList(baseURL string, upstream *Upstream, repo port.CryptoCoinRepository) port.CoinIDResolver {
+	return &CoinList{baseURL: strings.TrimRight(baseURL, "/"), upstream: upstream, repo: repo}
+}
+
+func (l *CoinList) ResolveCoinID(ctx context.Context, ticker string) (string, error) {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if err := l.ensureLoaded(ctx); err != nil {
+		return "", err
+	}
+	l.mu.RLock()
+	id, ok := l.bySymbol[ticker]
+	l.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("unsupported crypto symbol: %s (pin priceProviderId on the asset)", ticker)
+	}
+	return id, nil
+}
+
+// ensureLoaded reads the stored list on first use and once the index is coinListReload old, fetching it
+// from CoinGecko when the table is empty. When a reload fails the previous index keeps being used.
+func (l *CoinList) ensureLoaded(ctx context.Context) error {
+	l.mu.RLock()
+	fresh := l.bySymbol != nil && time.Since(l.loadedAt) < coinListReload
+	l.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	l.mu.Lock()
+	loaded := l.bySymbol != nil
+	if loaded && time.Since(l.loadedAt) < coinListReload {
+		l.mu.Unlock()
+		return nil
+	}
+	if loaded {
+		// Set first so other lookups keep using the current index while this one reloads it.
+		l.loadedAt = time.Now()
+	}
+	l.mu.Unlock()
+
+	coins, err := l.repo.ListAll(ctx)
+	if err != nil {
+		if loaded {
+			slog.Warn("coin_list_reload_failed", "error", err)
+			return nil
+		}
+		return err
+	}
+	if len(coins) > 0 {
+		l.setCoins(coins)
+		return nil
+	}
+	if loaded {
+		return nil
+	}
+
+	l.mu.Lock()
+	if time.Since(l.lastAttempt) < coinListRetry {
+		l.mu.Unlock()
+		return fmt.Errorf("crypto coin list not loaded yet")
+	}
+	l.lastAttempt = time.Now()
+	l.mu.Unlock()
+	_, err = l.RefreshCoinList(ctx)
+	return err
+}
+
+func (l *CoinList) setCoins(coins []models.CryptoCoin) {
+	bySymbol := pickBySymbol(coins)
+	l.mu.Lock()
+	l.bySymbol = bySymbol
+	l.loadedAt = time.Now()
+	l.mu.Unlock()
+}
+
+// RefreshCoinList fetches the full coin list plus the market-cap ranks of the largest coins, stores them
+// and swaps the in-memory index.
+func (l *CoinList) RefreshCoinList(ctx context.Context) (int, error) {
+	// Response: [{"id":"bitcoin","symbol":"btc","name":"Bitcoin"}, ...]
+	var list []struct {
+		ID     string `json:"id"`
+		Symbol string `json:"symbol"`
+		Name   string `json:"name"`
+	}
+	if err := l.upstream.GetJSON(ctx, coinGeckoName, "coin list", l.baseURL+"/coins/list", nil, &list); err != nil {
+		return 0, err
+	}
+
+	ranks := make(map[string]int)
+	for page := 1; page <= coinMarketPages; page++ {
+		// Response: [{"id":"bitcoin","market_cap_rank":1}, ...]
+		var markets []struct {
+			ID            string `json:"id"`
+			MarketCapRank *int   `json:"market_cap_rank"`
+		}
+		url := fmt.Sprintf("%s/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=250&page=%d", l.baseURL, page)
+		if err := l.upstream.GetJSON(ctx, coinGeckoName, "markets", url, nil, &markets); err != nil {
+			return 0, err
+		}
+		for _, m := range markets {
+			if m.MarketCapRank != nil {
+				ranks[m.ID] = *m.MarketCapRank
+			}
+		}
+		if len(markets) < 250 {
+			break
+		}
+	}
+
+	now := time.Now()
+	coins := make([]models.CryptoCoin, 0, len(list))
+	for _, c := range list {
+		if c.ID == "" || c.Symbol == "" {
+			continue
+		}
+		coin := models.CryptoCoin{ID: c.ID, Symbol: strings.ToUpper(c.Symbol), Name: c.Name, UpdatedAt: now}
+		if rank, ok := ranks[c.ID]; ok {
+			coin.MarketCapRank = &rank
+		}
+		coins = append(coins, coin)
+	}
+	if len(coins) == 0 {
+		return 0, fmt.Errorf("coingecko coin list is empty")
+	}
+	if err := l.repo.Upsert(ctx, coins); err != nil {
+		return 0, err
+	}
+	l.setCoins(coins)
+	slog.Info("coin_list_refreshed", "coins", len(coins), "ranked", len(ranks))
+	return len(coins), nil
+}
+
+// pickBySymbol indexes coins by upper-case ticker. When several coins share a ticker the one with the best
+// market-cap rank wins; ranked coins beat unranked ones, and remaining ties go to the shortest, then
+// alphabetically first, ID so the choice is stable.
+func pickBySymbol(coins []models.CryptoCoin) map[string]string {
+	best := make(map[string]models.CryptoCoin, len(coins))
+	for _, c := range coins {
+		sym := strings.ToUpper(c.Symbol)
+		cur, ok := best[sym]
+		if !ok || betterCoin(c, cur) {
+			best[sym] = c
+		}
+	}
+	out := make(map[string]string, len(best))
+	for sym, c := range best {
+		out[sym] = c.ID
+	}
+	return out
+}
+
+func betterCoin(a, b models.CryptoCoin) bool {
+	switch {
+	case a.MarketCapRank != nil && b.MarketCapRank == nil:
+		return true
+	case a.MarketCapRank == nil && b.MarketCapRank != nil:
+		return false
+	case a.MarketCapRank != nil && *a.MarketCapRank != *b.MarketCapRank:
+		return *a.MarketCapRank < *b.MarketCapRank
+	case len(a.ID) != len(b.ID):
+		return len(a.ID) < len(b.ID)
+	default:
+		return a.ID < b.ID
+	}
+}
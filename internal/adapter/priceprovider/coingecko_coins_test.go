@@ -0,0 +1,80 @@
+package priceprovider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"monity/internal/models"
+)
+
+func Test_pickBySymbol(t *testing.T) {
+	rank := func(n int) *int { return &n }
+	coins := []models.CryptoCoin{
+		{ID: "uniswap-wormhole", Symbol: "uni"},
+		{ID: "uniswap", Symbol: "uni", MarketCapRank: rank(25)},
+		{ID: "universe-token", Symbol: "UNI", MarketCapRank: rank(3000)},
+		{ID: "bitcoin", Symbol: "btc", MarketCapRank: rank(1)},
+		{ID: "batcat", Symbol: "btc"},
+		{ID: "foo-b", Symbol: "foo"},
+		{ID: "foo-a", Symbol: "foo"},
+		{ID: "foo-long", Symbol: "foo"},
+	}
+	want := map[string]string{
+		"UNI": "uniswap",
+		"BTC": "bitcoin",
+		"FOO": "foo-a",
+	}
+
+	got := pickBySymbol(coins)
+	if len(got) != len(want) {
+		t.Fatalf("pickBySymbol() = %v, want %v", got, want)
+	}
+	for sym, id := range want {
+		if got[sym] != id {
+			t.Errorf("pickBySymbol()[%s] = %q, want %q", sym, got[sym], id)
+		}
+	}
+}
+
+// memCoinRepo is an in-memory port.CryptoCoinRepository.
+type memCoinRepo struct {
+	coins []models.CryptoCoin
+	err   error
+}
+
+func (r *memCoinRepo) ListAll(ctx context.Context) ([]models.CryptoCoin, error) {
+	return r.coins, r.err
+}
+
+func (r *memCoinRepo) Upsert(ctx context.Context, coins []models.CryptoCoin) error {
+	r.coins = coins
+	return nil
+}
+
+func TestCoinList_reload(t *testing.T) {
+	ctx := context.Background()
+	repo := &memCoinRepo{coins: []models.CryptoCoin{{ID: "bitcoin", Symbol: "BTC"}}}
+	l := NewCoinList("", nil, repo).(*CoinList)
+	if id, err := l.ResolveCoinID(ctx, "btc"); err != nil || id != "bitcoin" {
+		t.Fatalf("ResolveCoinID(btc) = %q, %v", id, err)
+	}
+
+	// Coins stored by another replica are picked up only once the index is stale
+	repo.coins = append(repo.coins, models.CryptoCoin{ID: "solana", Symbol: "SOL"})
+	if _, err := l.ResolveCoinID(ctx, "sol"); err == nil {
+		t.Errorf("ResolveCoinID(sol) before reload: want unsupported error")
+	}
+	l.loadedAt = time.Now().Add(-coinListReload)
+	if id, err := l.ResolveCoinID(ctx, "sol"); err != nil || id != "solana" {
+		t.Errorf("ResolveCoinID(sol) after reload = %q, %v", id, err)
+	}
+
+	// A failed reload keeps the previous index
+	repo.err = errors.New("db down")
+	l.loadedAt = time.Now().Add(-coinListReload)
+	if id, err := l.ResolveCoinID(ctx, "btc"); err != nil || id != "bitcoin" {
+		t.Errorf("ResolveCoinID(btc) after failed reload = %q, %v", id, err)
+	}
+}
@@ -0,0 +1,153 @@
+package priceprovider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+)
+
+// CoinMarketCap serves crypto quotes and history from the CoinMarketCap Pro API.
+// It needs an API key; without one it reports no support and is skipped.
+// https://pro-api.coinmarketcap.com/v2/cryptocurrency/quotes/latest?symbol=BTC&convert=IDR
+type CoinMarketCap struct {
+	baseURL  string
+	apiKey   string
+	upstream *Upstream
+}
+
+const coinMarketCapName = "coinmarketcap"
+
+func NewCoinMarketCap(baseURL, apiKey string, upstream *Upstream) port.PriceProvider {
+	return &CoinMarketCap{baseURL: strings.TrimRight(baseURL, "/"), apiKey: apiKey, upstream: upstream}
+}
+
+func (p *CoinMarketCap) Name() string { return coinMarketCapName }
+
+func (p *CoinMarketCap) Supports(kind string) bool {
+	return kind == port.PriceKindCrypto && p.apiKey != ""
+}
+
+func (p *CoinMarketCap) get(ctx context.Context, api, path string, query url.Values, out any) error {
+	if p.apiKey == "" {
+		return port.ErrProviderUnsupported
+	}
+	u := fmt.Sprintf("%s%s?%s", p.baseURL, path, query.Encode())
+	return p.upstream.GetJSON(ctx, coinMarketCapName, api, u, map[string]string{"X-CMC_PRO_API_KEY": p.apiKey}, out)
+}
+
+type cmcQuote struct {
+	Price     float64 `json:"price"`
+	Open      float64 `json:"open"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Close     float64 `json:"close"`
+	Volume    float64 `json:"volume"`
+	MarketCap float64 `json:"market_cap"`
+}
+
+func (p *CoinMarketCap) Quote(ctx context.Context, kind string, sym port.PriceSymbol, currency string) (*port.PriceData, error) {
+	symbol := strings.ToUpper(sym.Ticker)
+	// Response: {"data":{"BTC":[{"quote":{"IDR":{"price":...}}}]}}
+	var result struct {
+		Data map[string][]struct {
+			Quote map[string]cmcQuote `json:"quote"`
+		} `json:"data"`
+	}
+	query := url.Values{"symbol": {symbol}, "convert": {currency}}
+	if err := p.get(ctx, "quotes", "/v2/cryptocurrency/quotes/latest", query, &result); err != nil {
+		return nil, err
+	}
+	coins := result.Data[symbol]
+	if len(coins) == 0 {
+		return nil, fmt.Errorf("price not found for %s", symbol)
+	}
+	q, ok := coins[0].Quote[currency]
+	if !ok {
+		return nil, fmt.Errorf("price in %s not found for %s", currency, symbol)
+	}
+	return &port.PriceData{
+		Symbol:    symbol,
+		Price:     q.Price,
+		Currency:  currency,
+		Source:    coinMarketCapName,
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+func (p *CoinMarketCap) HistoricalPrice(ctx context.Context, kind, symbol, currency string, at time.Time) (*port.PriceData, error) {
+	candles, err := p.OHLCV(ctx, kind, symbol, currency, at.AddDate(0, 0, -1), at)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("historical price not found for %s at %s", symbol, at.UTC().Format("2006-01-02"))
+	}
+	last := candles[len(candles)-1]
+	return &port.PriceData{
+		Symbol:    last.Symbol,
+		Price:     last.Close,
+		Currency:  currency,
+		Source:    coinMarketCapName,
+		FetchedAt: at,
+	}, nil
+}
+
+func (p *CoinMarketCap) OHLCV(ctx context.Context, kind, symbol, currency string, from, to time.Time) ([]port.OHLCVData, error) {
+	symbol = strings.ToUpper(symbol)
+	var result struct {
+		Data map[string][]struct {
+			Quotes []struct {
+				TimeOpen  time.Time           `json:"time_open"`
+				TimeClose time.Time           `json:"time_close"`
+				Quote     map[string]cmcQuote `json:"quote"`
+			} `json:"quotes"`
+		} `json:"data"`
+	}
+	query := url.Values{
+		"symbol":     {symbol},
+		"convert":    {currency},
+		"interval":   {"daily"},
+		"time_start": {from.UTC().Format(time.RFC3339)},
+		"time_end":   {to.UTC().Format(time.RFC3339)},
+	}
+	if err := p.get(ctx, "OHLCV", "/v2/cryptocurrency/ohlcv/historical", query, &result); err != nil {
+		return nil, err
+	}
+	coins := result.Data[symbol]
+	if len(coins) == 0 {
+		return nil, fmt.Errorf("historical price not found for %s", symbol)
+	}
+	out := make([]port.OHLCVData, 0, len(coins[0].Quotes))
+	for _, c := range coins[0].Quotes {
+		q, ok := c.Quote[currency]
+		if !ok {
+			continue
+		}
+		out = append(out, port.OHLCVData{
+			Symbol:    symbol,
+			TimeOpen:  c.TimeOpen,
+			TimeClose: c.TimeClose,
+			Open:      q.Open,
+			High:      q.High,
+			Low:       q.Low,
+			Close:     q.Close,
+			Volume:    q.Volume,
+			MarketCap: q.MarketCap,
+			Currency:  currency,
+			Source:    coinMarketCapName,
+		})
+	}
+	return out, nil
+}
+
+func (p *CoinMarketCap) Chart(ctx context.Context, kind, symbol string, req port.ChartRequest) (*port.ChartResponse, error) {
+	return nil, port.ErrProviderUnsupported
+}
+
+func (p *CoinMarketCap) ExchangeRate(ctx context.Context, from, to string) (float64, error) {
+	return 0, port.ErrProviderUnsupported
+}
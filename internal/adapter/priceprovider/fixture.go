@@ -0,0 +1,306 @@
+package priceprovider
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+)
+
+// Fixture serves quotes, history, charts and exchange rates from Fixtures instead of an upstream API,
+// so development and tests run offline and deterministically. Select it by listing "fixture" in
+// PRICE_PROVIDERS_CRYPTO/STOCK/FX.
+//
+// With RandomWalk set, symbols without a daily series get one generated from a seeded walk: the same
+// seed, symbol and date always give the same price, so charts and snapshots are stable between runs.
+type Fixture struct {
+	quotes map[string]FixtureQuote     // kind|SYMBOL
+	series map[string][]port.OHLCVData // kind|SYMBOL, oldest first
+	rates  map[string]float64          // FROM|TO
+
+	walk bool
+	seed int64
+	now  func() time.Time
+}
+
+const fixtureName = "fixture"
+
+// walkEpoch anchors generated series: each day's price is the previous day's moved by a seeded step.
+var walkEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func NewFixture(data *Fixtures, randomWalk bool, seed int64) port.PriceProvider {
+	return newFixture(data, randomWalk, seed, time.Now)
+}
+
+func newFixture(data *Fixtures, randomWalk bool, seed int64, now func() time.Time) *Fixture {
+	p := &Fixture{
+		quotes: make(map[string]FixtureQuote),
+		series: make(map[string][]port.OHLCVData),
+		rates:  make(map[string]float64),
+		walk:   randomWalk,
+		seed:   seed,
+		now:    now,
+	}
+	if data == nil {
+		return p
+	}
+	for _, q := range data.Quotes {
+		q.Currency = strings.ToUpper(q.Currency)
+		p.quotes[fixtureKey(q.Kind, q.Symbol)] = q
+	}
+	for _, s := range data.Series {
+		key := fixtureKey(s.Kind, s.Symbol)
+		for _, c := range s.Candles {
+			day, err := time.Parse("2006-01-02", c.Date)
+			if err != nil {
+				continue
+			}
+			p.series[key] = append(p.series[key], port.OHLCVData{
+				Symbol:    strings.ToUpper(s.Symbol),
+				TimeOpen:  day,
+				TimeClose: day.Add(24*time.Hour - time.Second),
+				Open:      c.Open,
+				High:      c.High,
+				Low:       c.Low,
+				Close:     c.Close,
+				Volume:    c.Volume,
+				Currency:  strings.ToUpper(s.Currency),
+				Source:    fixtureName,
+			})
+		}
+		sort.Slice(p.series[key], func(i, j int) bool { return p.series[key][i].TimeOpen.Before(p.series[key][j].TimeOpen) })
+	}
+	for _, r := range data.FX {
+		if r.Rate > 0 {
+			p.rates[strings.ToUpper(r.From)+"|"+strings.ToUpper(r.To)] = r.Rate
+		}
+	}
+	return p
+}
+
+func fixtureKey(kind, symbol string) string {
+	return strings.ToUpper(kind) + "|" + strings.ToUpper(symbol)
+}
+
+func (p *Fixture) Name() string { return fixtureName }
+
+func (p *Fixture) Supports(kind string) bool {
+	switch kind {
+	case port.PriceKindCrypto, port.PriceKindStock, port.PriceKindFX:
+		return true
+	}
+	return false
+}
+
+func (p *Fixture) Quote(ctx context.Context, kind string, sym port.PriceSymbol, currency string) (*port.PriceData, error) {
+	symbol := strings.ToUpper(sym.Ticker)
+	key := fixtureKey(kind, symbol)
+	if q, ok := p.quotes[key]; ok {
+		return &port.PriceData{Symbol: symbol, Price: q.Price, Currency: q.Currency, Source: fixtureName, FetchedAt: p.now()}, nil
+	}
+	if candles := p.series[key]; len(candles) > 0 {
+		last := candles[len(candles)-1]
+		return &port.PriceData{Symbol: symbol, Price: last.Close, Currency: last.Currency, Source: fixtureName, FetchedAt: p.now()}, nil
+	}
+	if p.walk {
+		today := truncateDay(p.now())
+		candle := p.walkCandles(kind, symbol, currency, today, today)[0]
+		return &port.PriceData{Symbol: symbol, Price: candle.Close, Currency: candle.Currency, Source: fixtureName, FetchedAt: p.now()}, nil
+	}
+	return nil, fmt.Errorf("price not found for %s", symbol)
+}
+
+func (p *Fixture) HistoricalPrice(ctx context.Context, kind, symbol, currency string, at time.Time) (*port.PriceData, error) {
+	day := truncateDay(at)
+	// Look back a few days so weekends and gaps in the fixture still find the last close.
+	candles, err := p.OHLCV(ctx, kind, symbol, currency, day.AddDate(0, 0, -5), day)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("historical price not found for %s at %s", symbol, day.Format("2006-01-02"))
+	}
+	last := candles[len(candles)-1]
+	return &port.PriceData{Symbol: last.Symbol, Price: last.Close, Currency: last.Currency, Source: fixtureName, FetchedAt: last.TimeOpen}, nil
+}
+
+func (p *Fixture) OHLCV(ctx context.Context, kind, symbol, currency string, from, to time.Time) ([]port.OHLCVData, error) {
+	symbol = strings.ToUpper(symbol)
+	from, to = truncateDay(from), truncateDay(to)
+	if candles, ok := p.series[fixtureKey(kind, symbol)]; ok {
+		var out []port.OHLCVData
+		for _, c := range candles {
+			if !c.TimeOpen.Before(from) && !c.TimeOpen.After(to) {
+				out = append(out, c)
+			}
+		}
+		return out, nil
+	}
+	if kind == port.PriceKindFX && len(symbol) == 6 {
+		// FX pairs without a series hold the fixture rate every day.
+		if rate, err := p.ExchangeRate(ctx, symbol[:3], symbol[3:]); err == nil {
+			var out []port.OHLCVData
+			for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+				out = append(out, port.OHLCVData{
+					Symbol: symbol, TimeOpen: day, TimeClose: day.Add(24*time.Hour - time.Second),
+					Open: rate, High: rate, Low: rate, Close: rate, Currency: symbol[3:], Source: fixtureName,
+				})
+			}
+			return out, nil
+		}
+	}
+	if p.walk && kind != port.PriceKindFX {
+		return p.walkCandles(kind, symbol, currency, from, to), nil
+	}
+	return nil, fmt.Errorf("historical price not found for %s", symbol)
+}
+
+func (p *Fixture) Chart(ctx context.Context, kind, symbol string, req port.ChartRequest) (*port.ChartResponse, error) {
+	days := req.Days
+	if kind == port.PriceKindStock {
+		days = rangeDays(req.Range)
+	}
+	if days < 1 {
+		days = 1
+	}
+	to := truncateDay(p.now())
+	candles, err := p.OHLCV(ctx, kind, symbol, req.Currency, to.AddDate(0, 0, -days), to)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("no chart data found for %s", symbol)
+	}
+	data := make([]port.ChartDataPoint, 0, len(candles))
+	for _, c := range candles {
+		data = append(data, port.ChartDataPoint{T: c.TimeOpen.Unix(), P: c.Close})
+	}
+	return &port.ChartResponse{Symbol: strings.ToUpper(symbol), Currency: candles[0].Currency, Source: fixtureName, Data: data}, nil
+}
+
+// ExchangeRate uses the listed pair, its inverse, or a cross through USD.
+func (p *Fixture) ExchangeRate(ctx context.Context, from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return 1, nil
+	}
+	if rate, ok := p.rate(from, to); ok {
+		return rate, nil
+	}
+	fromUSD, ok1 := p.rate(from, "USD")
+	usdTo, ok2 := p.rate("USD", to)
+	if ok1 && ok2 {
+		return fromUSD * usdTo, nil
+	}
+	return 0, fmt.Errorf("exchange rate not found for %s to %s", from, to)
+}
+
+func (p *Fixture) rate(from, to string) (float64, bool) {
+	if from == to {
+		return 1, true
+	}
+	if r, ok := p.rates[from+"|"+to]; ok {
+		return r, true
+	}
+	if r, ok := p.rates[to+"|"+from]; ok {
+		return 1 / r, true
+	}
+	return 0, false
+}
+
+// walkCandles generates daily candles for [from, to]. The walk starts at walkEpoch from the fixture quote
+// (or a price derived from the symbol) and moves by a step drawn from hash(seed, symbol, day).
+func (p *Fixture) walkCandles(kind, symbol, currency string, from, to time.Time) []port.OHLCVData {
+	key := fixtureKey(kind, symbol)
+	base := 0.0
+	if q, ok := p.quotes[key]; ok {
+		base, currency = q.Price, q.Currency
+	} else {
+		base = 1 + float64(p.hash(key, "base")%100000)/100
+	}
+	currency = strings.ToUpper(currency)
+	vol := 0.015
+	if kind == port.PriceKindCrypto {
+		vol = 0.04
+	}
+	if from.Before(walkEpoch) {
+		from = walkEpoch
+	}
+
+	var out []port.OHLCVData
+	price := base
+	for day := walkEpoch; !day.After(to); day = day.AddDate(0, 0, 1) {
+		open := price
+		price = open * (1 + vol*p.unit(key, day, "close"))
+		if day.Before(from) {
+			continue
+		}
+		spread := math.Abs(p.unit(key, day, "range")) * vol * open
+		out = append(out, port.OHLCVData{
+			Symbol:    symbol,
+			TimeOpen:  day,
+			TimeClose: day.Add(24*time.Hour - time.Second),
+			Open:      open,
+			High:      math.Max(open, price) + spread,
+			Low:       math.Max(math.Min(open, price)-spread, 0),
+			Close:     price,
+			Volume:    float64(p.hash(key, day.Format("2006-01-02"), "volume") % 1000000),
+			Currency:  currency,
+			Source:    fixtureName,
+		})
+	}
+	return out
+}
+
+// unit maps the seeded hash of the inputs onto [-1, 1].
+func (p *Fixture) unit(key string, day time.Time, salt string) float64 {
+	return float64(p.hash(key, day.Format("2006-01-02"), salt)%2001)/1000 - 1
+}
+
+func (p *Fixture) hash(parts ...string) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d", p.seed)
+	for _, s := range parts {
+		h.Write([]byte{0})
+		h.Write([]byte(s))
+	}
+	return h.Sum64()
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// rangeDays converts a Yahoo chart range (1d, 5d, 1mo, 1y, ...) to days.
+func rangeDays(r string) int {
+	switch r {
+	case "1d":
+		return 1
+	case "5d":
+		return 5
+	case "1mo":
+		return 30
+	case "3mo":
+		return 90
+	case "6mo":
+		return 180
+	case "ytd":
+		now := time.Now().UTC()
+		return now.YearDay()
+	case "1y":
+		return 365
+	case "2y":
+		return 730
+	case "5y":
+		return 1825
+	case "10y", "max":
+		return 3650
+	}
+	return 30
+}
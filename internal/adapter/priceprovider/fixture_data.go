@@ -0,0 +1,209 @@
+package priceprovider
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fixtures is market data loaded from files, served by the fixture provider and by the pricetest server.
+//
+// A fixture directory holds any number of *.json files shaped like Fixtures, and optionally:
+//
+//	quotes.csv  kind,symbol,currency,price[,id]
+//	ohlc.csv    kind,symbol,currency,date,open,high,low,close[,volume]
+//	fx.csv      from,to,rate
+type Fixtures struct {
+	Quotes []FixtureQuote  `json:"quotes"`
+	Series []FixtureSeries `json:"ohlc"`
+	FX     []FixtureRate   `json:"fx"`
+}
+
+type FixtureQuote struct {
+	Kind     string  `json:"kind"`
+	Symbol   string  `json:"symbol"`
+	ID       string  `json:"id,omitempty"` // CoinGecko coin ID for crypto; defaults to the lower-case symbol
+	Currency string  `json:"currency"`
+	Price    float64 `json:"price"`
+}
+
+type FixtureSeries struct {
+	Kind     string          `json:"kind"`
+	Symbol   string          `json:"symbol"`
+	Currency string          `json:"currency"`
+	Candles  []FixtureCandle `json:"candles"`
+}
+
+type FixtureCandle struct {
+	Date   string  `json:"date"` // YYYY-MM-DD
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume float64 `json:"volume,omitempty"`
+}
+
+type FixtureRate struct {
+	From string  `json:"from"`
+	To   string  `json:"to"`
+	Rate float64 `json:"rate"`
+}
+
+// CoinID returns the CoinGecko coin ID of a crypto quote.
+func (q FixtureQuote) CoinID() string {
+	if q.ID != "" {
+		return q.ID
+	}
+	return strings.ToLower(q.Symbol)
+}
+
+// LoadFixtures reads every fixture file in dir.
+func LoadFixtures(dir string) (*Fixtures, error) {
+	out := &Fixtures{}
+	jsonFiles, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(jsonFiles)
+	for _, path := range jsonFiles {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read fixture %s: %w", path, err)
+		}
+		var f Fixtures
+		if err := json.Unmarshal(raw, &f); err != nil {
+			return nil, fmt.Errorf("parse fixture %s: %w", path, err)
+		}
+		out.Quotes = append(out.Quotes, f.Quotes...)
+		out.Series = append(out.Series, f.Series...)
+		out.FX = append(out.FX, f.FX...)
+	}
+
+	if err := readCSV(filepath.Join(dir, "quotes.csv"), []string{"kind", "symbol", "currency", "price"}, func(row csvRow) error {
+		price, err := row.float("price")
+		if err != nil {
+			return err
+		}
+		out.Quotes = append(out.Quotes, FixtureQuote{Kind: row.get("kind"), Symbol: row.get("symbol"), ID: row.get("id"), Currency: row.get("currency"), Price: price})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	series := make(map[string]int) // kind|symbol|currency -> index in out.Series
+	if err := readCSV(filepath.Join(dir, "ohlc.csv"), []string{"kind", "symbol", "currency", "date", "open", "high", "low", "close"}, func(row csvRow) error {
+		var c FixtureCandle
+		var err error
+		c.Date = row.get("date")
+		if c.Open, err = row.float("open"); err != nil {
+			return err
+		}
+		if c.High, err = row.float("high"); err != nil {
+			return err
+		}
+		if c.Low, err = row.float("low"); err != nil {
+			return err
+		}
+		if c.Close, err = row.float("close"); err != nil {
+			return err
+		}
+		if row.get("volume") != "" {
+			if c.Volume, err = row.float("volume"); err != nil {
+				return err
+			}
+		}
+		key := row.get("kind") + "|" + row.get("symbol") + "|" + row.get("currency")
+		i, ok := series[key]
+		if !ok {
+			i = len(out.Series)
+			series[key] = i
+			out.Series = append(out.Series, FixtureSeries{Kind: row.get("kind"), Symbol: row.get("symbol"), Currency: row.get("currency")})
+		}
+		out.Series[i].Candles = append(out.Series[i].Candles, c)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := readCSV(filepath.Join(dir, "fx.csv"), []string{"from", "to", "rate"}, func(row csvRow) error {
+		rate, err := row.float("rate")
+		if err != nil {
+			return err
+		}
+		out.FX = append(out.FX, FixtureRate{From: row.get("from"), To: row.get("to"), Rate: rate})
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+type csvRow struct {
+	cols   map[string]int
+	record []string
+}
+
+func (r csvRow) get(name string) string {
+	i, ok := r.cols[name]
+	if !ok || i >= len(r.record) {
+		return ""
+	}
+	return strings.TrimSpace(r.record[i])
+}
+
+func (r csvRow) float(name string) (float64, error) {
+	v, err := strconv.ParseFloat(r.get(name), 64)
+	if err != nil {
+		return 0, fmt.Errorf("column %s: %w", name, err)
+	}
+	return v, nil
+}
+
+// readCSV calls fn for every row of the CSV file at path, whose header must name the required columns.
+// A missing file is not an error.
+func readCSV(path string, required []string, fn func(csvRow) error) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open fixture %s: %w", path, err)
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.Comment = '#'
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("read fixture %s header: %w", path, err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range required {
+		if _, ok := cols[name]; !ok {
+			return fmt.Errorf("fixture %s: missing column %s", path, name)
+		}
+	}
+	for line := 2; ; line++ {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read fixture %s: %w", path, err)
+		}
+		if err := fn(csvRow{cols: cols, record: record}); err != nil {
+			return fmt.Errorf("fixture %s line %d: %w", path, line, err)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package priceprovider
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"monity/internal/core/port"
+)
+
+func TestFixture(t *testing.T) {
+	data := &Fixtures{
+		Quotes: []FixtureQuote{{Kind: port.PriceKindCrypto, Symbol: "BTC", Currency: "USD", Price: 60000}},
+		FX:     []FixtureRate{{From: "USD", To: "IDR", Rate: 16000}, {From: "EUR", To: "USD", Rate: 1.1}},
+	}
+	now := func() time.Time { return time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+
+	rates := []struct {
+		from, to string
+		want     float64
+	}{
+		{"USD", "IDR", 16000},
+		{"IDR", "USD", 1.0 / 16000},
+		{"EUR", "IDR", 1.1 * 16000},
+		{"IDR", "IDR", 1},
+	}
+	p := newFixture(data, false, 0, now)
+	for _, tt := range rates {
+		if got, err := p.ExchangeRate(ctx, tt.from, tt.to); err != nil || got != tt.want {
+			t.Errorf("ExchangeRate(%s, %s) = %v, %v; want %v", tt.from, tt.to, got, err, tt.want)
+		}
+	}
+	if _, err := p.ExchangeRate(ctx, "JPY", "IDR"); err == nil {
+		t.Error("ExchangeRate(JPY, IDR): expected an error")
+	}
+	if _, err := p.Quote(ctx, port.PriceKindStock, port.PriceSymbol{Ticker: "BBCA"}, "IDR"); err == nil {
+		t.Error("Quote(BBCA) without random walk: expected an error")
+	}
+
+	// The same seed always walks the same way; another seed does not.
+	from, to := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	a, _ := newFixture(data, true, 7, now).OHLCV(ctx, port.PriceKindStock, "BBCA", "IDR", from, to)
+	b, _ := newFixture(data, true, 7, now).OHLCV(ctx, port.PriceKindStock, "BBCA", "IDR", from.AddDate(0, 0, 10), to)
+	c, _ := newFixture(data, true, 8, now).OHLCV(ctx, port.PriceKindStock, "BBCA", "IDR", from, to)
+	if len(a) != 31 || len(b) != 21 {
+		t.Fatalf("walk candles: got %d and %d, want 31 and 21", len(a), len(b))
+	}
+	if a[10] != b[0] || a[30] != b[20] {
+		t.Errorf("walk depends on the requested range: %+v vs %+v", a[10], b[0])
+	}
+	if a[30].Close == c[30].Close {
+		t.Error("walk ignores the seed")
+	}
+	for _, k := range a {
+		if k.Low > k.Open || k.Low > k.Close || k.High < k.Open || k.High < k.Close {
+			t.Errorf("inconsistent candle %+v", k)
+		}
+	}
+}
@@ -0,0 +1,319 @@
+// Package pricetest runs an HTTP stand-in for the CoinGecko and Yahoo Finance APIs backed by fixtures,
+// so the real providers can be exercised end to end without network access.
+package pricetest
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"time"
+
+	"monity/internal/adapter/priceprovider"
+	"monity/internal/core/port"
+)
+
+// Server answers the CoinGecko endpoints under /coingecko and Yahoo's chart endpoint under /yahoo.
+type Server struct {
+	*httptest.Server
+	prices port.PriceProvider
+	coins  []coin // crypto fixtures in market-cap order
+}
+
+type coin struct {
+	ID     string `json:"id"`
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// NewServer starts a server serving data. Crypto coins are listed in fixture order, which is also their
+// market-cap rank. Close it when done.
+func NewServer(data *priceprovider.Fixtures) *Server {
+	s := &Server{prices: priceprovider.NewFixture(data, false, 0)}
+	seen := make(map[string]bool)
+	addCoin := func(id, symbol string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		s.coins = append(s.coins, coin{ID: id, Symbol: strings.ToLower(symbol), Name: strings.ToUpper(symbol)})
+	}
+	if data != nil {
+		for _, q := range data.Quotes {
+			if q.Kind == port.PriceKindCrypto {
+				addCoin(q.CoinID(), q.Symbol)
+			}
+		}
+		for _, series := range data.Series {
+			if series.Kind == port.PriceKindCrypto {
+				addCoin(strings.ToLower(series.Symbol), series.Symbol)
+			}
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /coingecko/simple/price", s.simplePrice)
+	mux.HandleFunc("GET /coingecko/coins/list", s.coinList)
+	mux.HandleFunc("GET /coingecko/coins/markets", s.coinMarkets)
+	mux.HandleFunc("GET /coingecko/coins/{id}/history", s.coinHistory)
+	mux.HandleFunc("GET /coingecko/coins/{id}/ohlc", s.coinOHLC)
+	mux.HandleFunc("GET /coingecko/coins/{id}/market_chart", s.coinMarketChart)
+	mux.HandleFunc("GET /yahoo/v8/finance/chart/{symbol}", s.yahooChart)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// CoinGeckoURL is the base URL to pass to priceprovider.NewCoinGecko and NewCoinList.
+func (s *Server) CoinGeckoURL() string { return s.URL + "/coingecko" }
+
+// YahooURL is the base URL to pass to priceprovider.NewYahoo.
+func (s *Server) YahooURL() string { return s.URL + "/yahoo" }
+
+func (s *Server) symbolFor(id string) (string, bool) {
+	for _, c := range s.coins {
+		if c.ID == id {
+			return c.Symbol, true
+		}
+	}
+	return "", false
+}
+
+// quoteIn prices a fixture in currency, converting with the fixture exchange rates when needed.
+func (s *Server) quoteIn(ctx context.Context, kind, symbol, currency string) (float64, bool) {
+	q, err := s.prices.Quote(ctx, kind, port.PriceSymbol{Ticker: symbol}, currency)
+	if err != nil {
+		return 0, false
+	}
+	return s.convert(ctx, q.Price, q.Currency, currency)
+}
+
+func (s *Server) convert(ctx context.Context, amount float64, from, to string) (float64, bool) {
+	if strings.EqualFold(from, to) {
+		return amount, true
+	}
+	rate, err := s.prices.ExchangeRate(ctx, from, to)
+	if err != nil {
+		return 0, false
+	}
+	return amount * rate, true
+}
+
+// candlesIn returns the daily candles between from and to, converted to currency.
+func (s *Server) candlesIn(ctx context.Context, kind, symbol, currency string, from, to time.Time) ([]port.OHLCVData, bool) {
+	candles, err := s.prices.OHLCV(ctx, kind, symbol, currency, from, to)
+	if err != nil {
+		return nil, false
+	}
+	for i := range candles {
+		c := &candles[i]
+		rate, ok := s.convert(ctx, 1, c.Currency, currency)
+		if !ok {
+			return nil, false
+		}
+		c.Open, c.High, c.Low, c.Close = c.Open*rate, c.High*rate, c.Low*rate, c.Close*rate
+		c.Currency = strings.ToUpper(currency)
+	}
+	return candles, true
+}
+
+func (s *Server) simplePrice(w http.ResponseWriter, r *http.Request) {
+	vs := strings.ToLower(r.URL.Query().Get("vs_currencies"))
+	out := make(map[string]map[string]float64)
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		symbol, ok := s.symbolFor(id)
+		if !ok {
+			continue
+		}
+		if price, ok := s.quoteIn(r.Context(), port.PriceKindCrypto, symbol, vs); ok {
+			out[id] = map[string]float64{vs: price}
+		}
+	}
+	writeJSON(w, out)
+}
+
+func (s *Server) coinList(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.coins)
+}
+
+func (s *Server) coinMarkets(w http.ResponseWriter, r *http.Request) {
+	type market struct {
+		ID            string `json:"id"`
+		MarketCapRank int    `json:"market_cap_rank"`
+	}
+	out := []market{}
+	if page, _ := strconv.Atoi(r.URL.Query().Get("page")); page <= 1 {
+		for i, c := range s.coins {
+			out = append(out, market{ID: c.ID, MarketCapRank: i + 1})
+		}
+	}
+	writeJSON(w, out)
+}
+
+func (s *Server) coinHistory(w http.ResponseWriter, r *http.Request) {
+	symbol, ok := s.symbolFor(r.PathValue("id"))
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	at, err := time.Parse("02-01-2006", r.URL.Query().Get("date"))
+	if err != nil {
+		http.Error(w, "invalid date", http.StatusBadRequest)
+		return
+	}
+	prices := make(map[string]float64)
+	if candles, err := s.prices.OHLCV(r.Context(), port.PriceKindCrypto, symbol, "", at.AddDate(0, 0, -5), at); err == nil && len(candles) > 0 {
+		c := candles[len(candles)-1]
+		// CoinGecko prices history in every currency; offer the fixture currency and the ones it converts to.
+		for _, cur := range []string{c.Currency, "USD", "IDR", "EUR"} {
+			if price, ok := s.convert(r.Context(), c.Close, c.Currency, cur); ok {
+				prices[strings.ToLower(cur)] = price
+			}
+		}
+	}
+	var out struct {
+		MarketData struct {
+			CurrentPrice map[string]float64 `json:"current_price"`
+		} `json:"market_data"`
+	}
+	out.MarketData.CurrentPrice = prices
+	writeJSON(w, out)
+}
+
+func (s *Server) coinOHLC(w http.ResponseWriter, r *http.Request) {
+	candles, ok := s.coinCandles(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	out := make([][]float64, 0, len(candles))
+	for _, c := range candles {
+		out = append(out, []float64{float64(c.TimeOpen.UnixMilli()), c.Open, c.High, c.Low, c.Close})
+	}
+	writeJSON(w, out)
+}
+
+func (s *Server) coinMarketChart(w http.ResponseWriter, r *http.Request) {
+	candles, ok := s.coinCandles(r)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	var out struct {
+		Prices [][]float64 `json:"prices"`
+	}
+	for _, c := range candles {
+		out.Prices = append(out.Prices, []float64{float64(c.TimeOpen.UnixMilli()), c.Close})
+	}
+	writeJSON(w, out)
+}
+
+// coinCandles reads the id path value and the vs_currency and days query parameters.
+func (s *Server) coinCandles(r *http.Request) ([]port.OHLCVData, bool) {
+	symbol, ok := s.symbolFor(r.PathValue("id"))
+	if !ok {
+		return nil, false
+	}
+	days, _ := strconv.Atoi(r.URL.Query().Get("days"))
+	if days < 1 {
+		days = 1
+	}
+	to := time.Now().UTC()
+	return s.candlesIn(r.Context(), port.PriceKindCrypto, symbol, r.URL.Query().Get("vs_currency"), to.AddDate(0, 0, -days), to)
+}
+
+// yahooResult mirrors one entry of the /v8/finance/chart response.
+type yahooResult struct {
+	Meta struct {
+		Symbol             string  `json:"symbol"`
+		RegularMarketPrice float64 `json:"regularMarketPrice"`
+		Currency           string  `json:"currency"`
+	} `json:"meta"`
+	Timestamp  []int64 `json:"timestamp"`
+	Indicators struct {
+		Quote []yahooQuote `json:"quote"`
+	} `json:"indicators"`
+}
+
+type yahooQuote struct {
+	Open   []float64 `json:"open"`
+	High   []float64 `json:"high"`
+	Low    []float64 `json:"low"`
+	Close  []float64 `json:"close"`
+	Volume []float64 `json:"volume"`
+}
+
+func (s *Server) yahooChart(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	yahooSymbol := r.PathValue("symbol")
+	var res yahooResult
+	res.Meta.Symbol = yahooSymbol
+
+	if pair, ok := strings.CutSuffix(yahooSymbol, "=X"); ok && len(pair) == 6 {
+		rate, err := s.prices.ExchangeRate(ctx, pair[:3], pair[3:])
+		if err != nil {
+			yahooError(w, "No data found, symbol may be delisted")
+			return
+		}
+		res.Meta.RegularMarketPrice = rate
+		res.Meta.Currency = pair[3:]
+		writeJSON(w, map[string]any{"chart": map[string]any{"result": []yahooResult{res}, "error": nil}})
+		return
+	}
+
+	// Fixtures are keyed by ticker; Yahoo symbols carry an exchange suffix (BBRI.JK).
+	symbol, _, _ := strings.Cut(yahooSymbol, ".")
+	q, err := s.prices.Quote(ctx, port.PriceKindStock, port.PriceSymbol{Ticker: symbol}, "")
+	if err != nil {
+		yahooError(w, "No data found, symbol may be delisted")
+		return
+	}
+	res.Meta.RegularMarketPrice = q.Price
+	res.Meta.Currency = q.Currency
+
+	query := r.URL.Query()
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -1)
+	if p1, err := strconv.ParseInt(query.Get("period1"), 10, 64); err == nil {
+		from = time.Unix(p1, 0).UTC()
+		if p2, err := strconv.ParseInt(query.Get("period2"), 10, 64); err == nil {
+			// period2 is exclusive.
+			to = time.Unix(p2, 0).UTC().Add(-time.Second)
+		}
+	} else if rg := query.Get("range"); rg != "" {
+		chart, err := s.prices.Chart(ctx, port.PriceKindStock, symbol, port.ChartRequest{Range: rg})
+		if err == nil && len(chart.Data) > 0 {
+			from = time.Unix(chart.Data[0].T, 0).UTC()
+		}
+	}
+	candles, err := s.prices.OHLCV(ctx, port.PriceKindStock, symbol, q.Currency, from, to)
+	if err != nil {
+		candles = nil
+	}
+	var quote yahooQuote
+	for _, c := range candles {
+		res.Timestamp = append(res.Timestamp, c.TimeOpen.Unix())
+		quote.Open = append(quote.Open, c.Open)
+		quote.High = append(quote.High, c.High)
+		quote.Low = append(quote.Low, c.Low)
+		quote.Close = append(quote.Close, c.Close)
+		quote.Volume = append(quote.Volume, c.Volume)
+	}
+	res.Indicators.Quote = []yahooQuote{quote}
+	writeJSON(w, map[string]any{"chart": map[string]any{"result": []yahooResult{res}, "error": nil}})
+}
+
+// yahooError answers like Yahoo does for an unknown symbol.
+func yahooError(w http.ResponseWriter, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusNotFound)
+	json.NewEncoder(w).Encode(map[string]any{
+		"chart": map[string]any{"result": nil, "error": map[string]string{"code": "Not Found", "description": description}},
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,66 @@
+package pricetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"monity/internal/adapter/priceprovider"
+	"monity/internal/core/port"
+	"monity/internal/models"
+)
+
+type memCoins struct{ coins []models.CryptoCoin }
+
+func (m *memCoins) ListAll(ctx context.Context) ([]models.CryptoCoin, error) { return m.coins, nil }
+
+func (m *memCoins) Upsert(ctx context.Context, coins []models.CryptoCoin) error {
+	m.coins = coins
+	return nil
+}
+
+func TestServer_realProviders(t *testing.T) {
+	data, err := priceprovider.LoadFixtures("../../../../fixtures/prices")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(data)
+	defer srv.Close()
+
+	ctx := context.Background()
+	upstream := priceprovider.NewUpstream(srv.Client(), priceprovider.UpstreamConfig{})
+
+	coins := priceprovider.NewCoinList(srv.CoinGeckoURL(), upstream, &memCoins{})
+	if _, err := coins.(*priceprovider.CoinList).RefreshCoinList(ctx); err != nil {
+		t.Fatalf("refresh coin list: %v", err)
+	}
+	gecko := priceprovider.NewCoinGecko(srv.CoinGeckoURL(), upstream, coins)
+	q, err := gecko.Quote(ctx, port.PriceKindCrypto, port.PriceSymbol{Ticker: "BTC"}, "IDR")
+	if err != nil || q.Price != 64250*16250 {
+		t.Errorf("coingecko BTC in IDR: %+v, %v", q, err)
+	}
+	many, err := gecko.(port.BatchQuoter).QuoteMany(ctx, port.PriceKindCrypto, []port.PriceSymbol{{Ticker: "ETH"}, {Ticker: "SOL"}, {Ticker: "NOPE"}}, "USD")
+	if err != nil || len(many) != 2 || many[port.PriceSymbol{Ticker: "SOL"}].Price != 148.20 {
+		t.Errorf("coingecko QuoteMany: %v, %v", many, err)
+	}
+	h, err := gecko.HistoricalPrice(ctx, port.PriceKindCrypto, "BTC", "USD", time.Date(2026, 1, 5, 12, 0, 0, 0, time.UTC))
+	if err != nil || h.Price != 64010 {
+		t.Errorf("coingecko BTC history: %+v, %v", h, err)
+	}
+
+	yahoo := priceprovider.NewYahoo(srv.YahooURL(), upstream, func(ctx context.Context, symbol, currency string) string { return symbol + ".JK" })
+	q, err = yahoo.Quote(ctx, port.PriceKindStock, port.PriceSymbol{Ticker: "BBRI"}, "IDR")
+	if err != nil || q.Price != 4720 || q.Currency != "IDR" {
+		t.Errorf("yahoo BBRI: %+v, %v", q, err)
+	}
+	h, err = yahoo.HistoricalPrice(ctx, port.PriceKindStock, "BBCA", "IDR", time.Date(2026, 1, 6, 0, 0, 0, 0, time.UTC))
+	if err != nil || h.Price != 9850 {
+		t.Errorf("yahoo BBCA history: %+v, %v", h, err)
+	}
+	if rate, err := yahoo.ExchangeRate(ctx, "EUR", "IDR"); err != nil || rate != 1.085*16250 {
+		t.Errorf("yahoo EUR/IDR: %v, %v", rate, err)
+	}
+	if _, err := yahoo.Quote(ctx, port.PriceKindStock, port.PriceSymbol{Ticker: "NOPE"}, "IDR"); err == nil {
+		t.Error("yahoo unknown symbol: expected an error")
+	}
+}
@@ -0,0 +1,40 @@
+// Package priceprovider implements port.PriceProvider for the upstream market data APIs.
+package priceprovider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient returns the transport-level client wrapped by Upstream; its timeout applies per attempt.
+func NewHTTPClient() *http.Client {
+	// Force IPv4 to avoid IPv6 connection issues with some API providers (e.g. CoinGecko/Cloudflare)
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp4", addr)
+		},
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+		DisableCompression:  false,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+	return &http.Client{
+		Timeout:   15 * time.Second,
+		Transport: transport,
+	}
+}
+
+// StatusError is returned when an upstream API answers with a non-200 status.
+type StatusError struct {
+	Provider string
+	API      string
+	Code     int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s %s API returned status %d", e.Provider, e.API, e.Code)
+}
@@ -0,0 +1,329 @@
+package priceprovider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// UpstreamConfig tunes Upstream. Zero values use the defaults below.
+type UpstreamConfig struct {
+	// RatePerMinute is the request budget per provider name; providers not listed are not throttled.
+	RatePerMinute    map[string]int
+	MaxRetries       int           // retries after the first attempt (default 2; negative disables retries)
+	BaseBackoff      time.Duration // first retry delay, doubled per retry, with full jitter (default 500ms)
+	MaxBackoff       time.Duration // longest delay waited, including Retry-After (default 10s)
+	BreakerThreshold int           // consecutive failures that open a provider's circuit (default 5)
+	BreakerCooldown  time.Duration // how long an open circuit rejects calls before one trial (default 60s)
+}
+
+// ErrCircuitOpen is returned without calling the provider while its circuit is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// upstreamMetrics is published at /debug/vars as "upstream": per provider request, error, retry, status and
+// latency counters (e.g. "coingecko.requests", "coingecko.status_429", "coingecko.latency_ms").
+var upstreamMetrics = expvar.NewMap("upstream")
+
+// Upstream is the HTTP client shared by the providers. Each provider gets a token bucket, so bursts wait
+// instead of drawing 429s; transient failures (network errors, 429, 5xx) are retried with jittered backoff,
+// honouring Retry-After; and a circuit breaker stops calling a provider that keeps failing, so PriceService
+// moves straight on to the next provider or to cached quotes.
+type Upstream struct {
+	client *http.Client
+	cfg    UpstreamConfig
+
+	mu        sync.Mutex
+	providers map[string]*providerState
+}
+
+func NewUpstream(client *http.Client, cfg UpstreamConfig) *Upstream {
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 2
+	}
+	if cfg.BaseBackoff <= 0 {
+		cfg.BaseBackoff = 500 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 10 * time.Second
+	}
+	if cfg.BreakerThreshold <= 0 {
+		cfg.BreakerThreshold = 5
+	}
+	if cfg.BreakerCooldown <= 0 {
+		cfg.BreakerCooldown = time.Minute
+	}
+	return &Upstream{client: client, cfg: cfg, providers: make(map[string]*providerState)}
+}
+
+type providerState struct {
+	bucket  *tokenBucket
+	breaker *breaker
+}
+
+func (u *Upstream) state(provider string) *providerState {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	st, ok := u.providers[provider]
+	if !ok {
+		st = &providerState{breaker: &breaker{threshold: u.cfg.BreakerThreshold, cooldown: u.cfg.BreakerCooldown}}
+		if rpm := u.cfg.RatePerMinute[provider]; rpm > 0 {
+			st.bucket = newTokenBucket(float64(rpm)/60, max(1, rpm/10))
+		}
+		u.providers[provider] = st
+	}
+	return st
+}
+
+// GetJSON GETs url and decodes the JSON body into out.
+func (u *Upstream) GetJSON(ctx context.Context, provider, api, url string, headers map[string]string, out any) error {
+	st := u.state(provider)
+	if !st.breaker.allow(time.Now()) {
+		upstreamMetrics.Add(provider+".short_circuited", 1)
+		return fmt.Errorf("%s %s API: %w", provider, api, ErrCircuitOpen)
+	}
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		if st.bucket != nil {
+			if werr := st.bucket.wait(ctx); werr != nil {
+				st.breaker.release()
+				return fmt.Errorf("fetch %s %s: %w", provider, api, werr)
+			}
+		}
+		var retryAfter time.Duration
+		retryAfter, err = u.do(ctx, provider, api, url, headers, out)
+		if err == nil {
+			st.breaker.success()
+			return nil
+		}
+		if !retryable(err) || ctx.Err() != nil {
+			break
+		}
+		if retryAfter > 0 && st.bucket != nil {
+			st.bucket.pause(retryAfter)
+		}
+		if attempt >= u.cfg.MaxRetries {
+			break
+		}
+		delay := retryAfter
+		if delay <= 0 {
+			delay = backoff(u.cfg.BaseBackoff, attempt)
+		}
+		if delay > u.cfg.MaxBackoff {
+			break
+		}
+		upstreamMetrics.Add(provider+".retries", 1)
+		slog.Debug("upstream_retry", "provider", provider, "api", api, "attempt", attempt+1, "delay", delay, "error", err)
+		if !sleep(ctx, delay) {
+			break
+		}
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		// The caller gave up; that says nothing about the provider.
+		st.breaker.release()
+	case retryable(err):
+		if st.breaker.failure(time.Now()) {
+			upstreamMetrics.Add(provider+".circuit_opened", 1)
+			slog.Warn("upstream_circuit_open", "provider", provider, "cooldown", u.cfg.BreakerCooldown, "error", err)
+		}
+	default:
+		// The provider answered; a 404 for an unknown symbol says nothing about its health.
+		st.breaker.success()
+	}
+	return err
+}
+
+// do makes one attempt and returns the Retry-After delay the provider asked for, if any.
+func (u *Upstream) do(ctx context.Context, provider, api, url string, headers map[string]string, out any) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	started := time.Now()
+	upstreamMetrics.Add(provider+".requests", 1)
+	resp, err := u.client.Do(req)
+	upstreamMetrics.Add(provider+".latency_ms", time.Since(started).Milliseconds())
+	if err != nil {
+		upstreamMetrics.Add(provider+".errors", 1)
+		return 0, fmt.Errorf("fetch %s %s: %w", provider, api, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		upstreamMetrics.Add(provider+".errors", 1)
+		upstreamMetrics.Add(fmt.Sprintf("%s.status_%d", provider, resp.StatusCode), 1)
+		return parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()), &StatusError{Provider: provider, API: api, Code: resp.StatusCode}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		upstreamMetrics.Add(provider+".errors", 1)
+		return 0, fmt.Errorf("decode %s response: %w", provider, err)
+	}
+	return 0, nil
+}
+
+// retryable reports whether err is worth retrying and counts against the provider's health:
+// transport errors, 429 and 5xx. Other statuses and decode errors are answers, not outages.
+func retryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Code == http.StatusTooManyRequests || se.Code >= 500
+	}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	return !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(v); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// backoff is base doubled per attempt with full jitter.
+func backoff(base time.Duration, attempt int) time.Duration {
+	d := base << attempt
+	return d/2 + rand.N(d/2+1)
+}
+
+func sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// tokenBucket allows rate requests per second with bursts of up to burst.
+type tokenBucket struct {
+	mu       sync.Mutex
+	rate     float64
+	burst    float64
+	tokens   float64
+	last     time.Time
+	notUntil time.Time // set from Retry-After; no tokens are handed out before it
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve takes a token, returning how long the caller must wait before using it.
+func (b *tokenBucket) reserve(now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens--
+	var wait time.Duration
+	if b.tokens < 0 {
+		wait = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	if pause := b.notUntil.Sub(now); pause > wait {
+		wait = pause
+	}
+	return wait
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	d := b.reserve(time.Now())
+	if d <= 0 {
+		return nil
+	}
+	if !sleep(ctx, d) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (b *tokenBucket) pause(d time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if until := time.Now().Add(d); until.After(b.notUntil) {
+		b.notUntil = until
+	}
+}
+
+// breaker is a per-provider circuit breaker: threshold consecutive failures open it for cooldown, after
+// which one trial call is let through; its success closes the circuit, its failure opens it again.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	trial     bool // a half-open trial call is in flight
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.trial {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.trial = false
+}
+
+// release ends a trial call that never reached the provider.
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// failure records a failed call and reports whether it opened the circuit.
+func (b *breaker) failure(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.trial = false
+	if b.failures < b.threshold {
+		return false
+	}
+	b.openUntil = now.Add(b.cooldown)
+	return true
+}
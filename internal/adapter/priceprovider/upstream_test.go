@@ -0,0 +1,78 @@
+package priceprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_parseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"3", 3 * time.Second},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.in, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestUpstream_GetJSON(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/flaky":
+			// Rate limited on the first call, fine afterwards.
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Write([]byte(`{"ok":true}`))
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	u := NewUpstream(srv.Client(), UpstreamConfig{BaseBackoff: time.Millisecond, BreakerThreshold: 2, BreakerCooldown: time.Hour})
+	ctx := context.Background()
+
+	var out struct{ OK bool }
+	if err := u.GetJSON(ctx, "flaky", "test", srv.URL+"/flaky", nil, &out); err != nil || !out.OK {
+		t.Fatalf("retry after 429: err %v, out %+v", err, out)
+	}
+
+	// A 404 is an answer: it is not retried and does not count against the provider.
+	for i := 0; i < 3; i++ {
+		var se *StatusError
+		if err := u.GetJSON(ctx, "missing", "test", srv.URL+"/missing", nil, &out); !errors.As(err, &se) || se.Code != http.StatusNotFound {
+			t.Fatalf("404: got %v", err)
+		}
+	}
+
+	// Two failed calls open the circuit; the next one is rejected without reaching the server.
+	for i := 0; i < 2; i++ {
+		if err := u.GetJSON(ctx, "down", "test", srv.URL+"/down", nil, &out); err == nil {
+			t.Fatal("503: expected an error")
+		}
+	}
+	if err := u.GetJSON(ctx, "down", "test", srv.URL+"/down", nil, &out); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("after %d failures: got %v, want ErrCircuitOpen", 2, err)
+	}
+}
@@ -0,0 +1,218 @@
+package priceprovider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+)
+
+// Yahoo serves stock quotes, history and charts, and exchange rates, from Yahoo Finance (no key needed).
+// https://query1.finance.yahoo.com/v8/finance/chart/BBRI.JK?interval=1d&range=1d
+// Prices come back in the listing currency (IDR for IDX stocks).
+type Yahoo struct {
+	baseURL  string
+	upstream *Upstream
+	// symbolFor maps a ticker to Yahoo's symbol (e.g. BBRI -> BBRI.JK for IDX stocks), preferring the listing
+	// traded in currency.
+	symbolFor func(ctx context.Context, symbol, currency string) string
+}
+
+const yahooName = "yahoo"
+
+var yahooHeaders = map[string]string{"User-Agent": "Mozilla/5.0"}
+
+func NewYahoo(baseURL string, upstream *Upstream, symbolFor func(ctx context.Context, symbol, currency string) string) port.PriceProvider {
+	if symbolFor == nil {
+		symbolFor = func(ctx context.Context, symbol, currency string) string { return symbol }
+	}
+	return &Yahoo{baseURL: strings.TrimRight(baseURL, "/"), upstream: upstream, symbolFor: symbolFor}
+}
+
+func (p *Yahoo) Name() string { return yahooName }
+
+func (p *Yahoo) Supports(kind string) bool {
+	return kind == port.PriceKindStock || kind == port.PriceKindFX
+}
+
+// yahooChart is the part of the /v8/finance/chart response the provider reads.
+type yahooChart struct {
+	Chart struct {
+		Result []struct {
+			Meta struct {
+				RegularMarketPrice float64 `json:"regularMarketPrice"`
+				Currency           string  `json:"currency"`
+			} `json:"meta"`
+			Timestamp  []int64 `json:"timestamp"`
+			Indicators struct {
+				Quote []struct {
+					Open   []float64 `json:"open"`
+					High   []float64 `json:"high"`
+					Low    []float64 `json:"low"`
+					Close  []float64 `json:"close"`
+					Volume []float64 `json:"volume"`
+				} `json:"quote"`
+			} `json:"indicators"`
+		} `json:"result"`
+		Error *struct {
+			Code        string `json:"code"`
+			Description string `json:"description"`
+		} `json:"error"`
+	} `json:"chart"`
+}
+
+func (p *Yahoo) chart(ctx context.Context, api, yahooSymbol, query string) (*yahooChart, error) {
+	url := fmt.Sprintf("%s/v8/finance/chart/%s?%s", p.baseURL, yahooSymbol, query)
+	var result yahooChart
+	if err := p.upstream.GetJSON(ctx, yahooName, api, url, yahooHeaders, &result); err != nil {
+		return nil, err
+	}
+	if result.Chart.Error != nil {
+		return nil, fmt.Errorf("yahoo finance error: %s", result.Chart.Error.Description)
+	}
+	return &result, nil
+}
+
+func (p *Yahoo) Quote(ctx context.Context, kind string, sym port.PriceSymbol, currency string) (*port.PriceData, error) {
+	if kind != port.PriceKindStock {
+		return nil, port.ErrProviderUnsupported
+	}
+	symbol := sym.Ticker
+	yahooSymbol := sym.ProviderID
+	if yahooSymbol == "" {
+		yahooSymbol = p.symbolFor(ctx, symbol, currency)
+	}
+	result, err := p.chart(ctx, "quote", yahooSymbol, "interval=1d&range=1d")
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Chart.Result) == 0 {
+		return nil, fmt.Errorf("no price data found for %s", yahooSymbol)
+	}
+	meta := result.Chart.Result[0].Meta
+	return &port.PriceData{
+		Symbol:    symbol,
+		Price:     meta.RegularMarketPrice,
+		Currency:  strings.ToUpper(meta.Currency),
+		Source:    yahooName,
+		FetchedAt: time.Now(),
+	}, nil
+}
+
+func (p *Yahoo) HistoricalPrice(ctx context.Context, kind, symbol, currency string, at time.Time) (*port.PriceData, error) {
+	day := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	// Look back a few days so weekends and holidays still find the last close.
+	candles, err := p.OHLCV(ctx, kind, symbol, currency, day.AddDate(0, 0, -5), day)
+	if err != nil {
+		return nil, err
+	}
+	if len(candles) == 0 {
+		return nil, fmt.Errorf("historical price not found for %s at %s", symbol, day.Format("2006-01-02"))
+	}
+	last := candles[len(candles)-1]
+	return &port.PriceData{
+		Symbol:    symbol,
+		Price:     last.Close,
+		Currency:  last.Currency,
+		Source:    yahooName,
+		FetchedAt: last.TimeOpen,
+	}, nil
+}
+
+// OHLCV reads daily candles of a stock, or of an FX pair given as FROMTO (e.g. USDIDR).
+func (p *Yahoo) OHLCV(ctx context.Context, kind, symbol, currency string, from, to time.Time) ([]port.OHLCVData, error) {
+	var yahooSymbol string
+	switch kind {
+	case port.PriceKindStock:
+		yahooSymbol = p.symbolFor(ctx, symbol, currency)
+	case port.PriceKindFX:
+		yahooSymbol = symbol + "=X"
+	default:
+		return nil, port.ErrProviderUnsupported
+	}
+	query := fmt.Sprintf("interval=1d&period1=%d&period2=%d", from.Unix(), to.AddDate(0, 0, 1).Unix())
+	result, err := p.chart(ctx, "history", yahooSymbol, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Chart.Result) == 0 || len(result.Chart.Result[0].Indicators.Quote) == 0 {
+		return nil, fmt.Errorf("no price data found for %s", yahooSymbol)
+	}
+	r := result.Chart.Result[0]
+	q := r.Indicators.Quote[0]
+	listed := strings.ToUpper(r.Meta.Currency)
+	out := make([]port.OHLCVData, 0, len(r.Timestamp))
+	for i, ts := range r.Timestamp {
+		if i >= len(q.Close) || i >= len(q.Open) || i >= len(q.High) || i >= len(q.Low) {
+			break
+		}
+		t := time.Unix(ts, 0).UTC()
+		c := port.OHLCVData{
+			Symbol:    symbol,
+			TimeOpen:  t,
+			TimeClose: t,
+			Open:      q.Open[i],
+			High:      q.High[i],
+			Low:       q.Low[i],
+			Close:     q.Close[i],
+			Currency:  listed,
+			Source:    yahooName,
+		}
+		if i < len(q.Volume) {
+			c.Volume = q.Volume[i]
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+func (p *Yahoo) Chart(ctx context.Context, kind, symbol string, req port.ChartRequest) (*port.ChartResponse, error) {
+	if kind != port.PriceKindStock {
+		return nil, port.ErrProviderUnsupported
+	}
+	yahooSymbol := p.symbolFor(ctx, symbol, req.Currency)
+	result, err := p.chart(ctx, "chart", yahooSymbol, fmt.Sprintf("range=%s&interval=%s", req.Range, req.Interval))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Chart.Result) == 0 {
+		return nil, fmt.Errorf("no chart data found for %s", yahooSymbol)
+	}
+
+	r := result.Chart.Result[0]
+	currency := strings.ToUpper(r.Meta.Currency)
+	if currency == "" {
+		currency = port.DefaultCurrency
+	}
+	times := r.Timestamp
+	quotes := r.Indicators.Quote
+	if len(quotes) == 0 || len(quotes[0].Close) == 0 {
+		return nil, fmt.Errorf("no quote data for %s", yahooSymbol)
+	}
+	closes := quotes[0].Close
+
+	n := len(times)
+	if len(closes) < n {
+		n = len(closes)
+	}
+	data := make([]port.ChartDataPoint, 0, n)
+	for i := 0; i < n; i++ {
+		data = append(data, port.ChartDataPoint{T: times[i], P: closes[i]})
+	}
+	return &port.ChartResponse{Symbol: symbol, Currency: currency, Source: yahooName, Data: data}, nil
+}
+
+// ExchangeRate reads the Yahoo FX pair (e.g. USDIDR=X).
+func (p *Yahoo) ExchangeRate(ctx context.Context, from, to string) (float64, error) {
+	fxSymbol := fmt.Sprintf("%s%s=X", from, to)
+	result, err := p.chart(ctx, "exchange rate", fxSymbol, "interval=1d&range=1d")
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Chart.Result) == 0 {
+		return 0, fmt.Errorf("exchange rate not found for %s to %s", from, to)
+	}
+	return result.Chart.Result[0].Meta.RegularMarketPrice, nil
+}
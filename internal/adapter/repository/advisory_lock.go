@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"monity/internal/core/port"
+)
+
+// AdvisoryLockElector holds a Postgres session-level advisory lock on a dedicated connection.
+// Whichever replica holds the lock is the leader; if the connection drops, Postgres releases the lock
+// and another replica takes over on its next TryAcquire.
+type AdvisoryLockElector struct {
+	db  *sql.DB
+	key int64
+
+	mu   sync.Mutex
+	conn *sql.Conn
+}
+
+func NewAdvisoryLockElector(db *sql.DB, key int64) port.LeaderElector {
+	return &AdvisoryLockElector{db: db, key: key}
+}
+
+func (e *AdvisoryLockElector) TryAcquire(ctx context.Context) (bool, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn != nil {
+		// Still leader as long as the session holding the lock is alive.
+		if err := e.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		_ = e.conn.Close()
+		e.conn = nil
+	}
+
+	conn, err := e.db.Conn(ctx)
+	if err != nil {
+		return false, fmt.Errorf("get connection for advisory lock: %w", err)
+	}
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", e.key).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, fmt.Errorf("try advisory lock: %w", err)
+	}
+	if !acquired {
+		_ = conn.Close()
+		return false, nil
+	}
+	e.conn = conn
+	return true, nil
+}
+
+func (e *AdvisoryLockElector) Release(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.conn == nil {
+		return nil
+	}
+	_, err := e.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", e.key)
+	closeErr := e.conn.Close()
+	e.conn = nil
+	if err != nil {
+		return fmt.Errorf("release advisory lock: %w", err)
+	}
+	return closeErr
+}
@@ -9,6 +9,7 @@ import (
 	"monity/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AssetPriceHistoryRepo struct {
@@ -53,3 +54,39 @@ func (r *AssetPriceHistoryRepo) GetLatestByAssetID(ctx context.Context, assetID
 	}
 	return &history, nil
 }
+
+func (r *AssetPriceHistoryRepo) GetLatestByAssetIDs(ctx context.Context, assetIDs []int64) (map[int64]*models.AssetPriceHistory, error) {
+	out := make(map[int64]*models.AssetPriceHistory, len(assetIDs))
+	if len(assetIDs) == 0 {
+		return out, nil
+	}
+	var histories []models.AssetPriceHistory
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (asset_id) *
+		FROM asset_price_histories
+		WHERE asset_id IN ?
+		ORDER BY asset_id, recorded_at DESC`, assetIDs).Scan(&histories).Error
+	if err != nil {
+		return nil, fmt.Errorf("get latest prices: %w", err)
+	}
+	for i := range histories {
+		out[histories[i].AssetID] = &histories[i]
+	}
+	return out, nil
+}
+
+func (r *AssetPriceHistoryRepo) UpsertSnapshots(ctx context.Context, histories []models.AssetPriceHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "asset_id"}, {Name: "snapshot_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"price", "currency", "source", "recorded_at"}),
+		}).
+		CreateInBatches(histories, 200)
+	if result.Error != nil {
+		return fmt.Errorf("upsert price snapshots: %w", result.Error)
+	}
+	return nil
+}
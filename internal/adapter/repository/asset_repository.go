@@ -9,6 +9,7 @@ import (
 	"monity/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AssetRepo struct {
@@ -51,6 +52,32 @@ func (r *AssetRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*
 	return &asset, nil
 }
 
+// GetByIDForUpdate locks the asset row until the surrounding transaction ends.
+func (r *AssetRepo) GetByIDForUpdate(ctx context.Context, id int64) (*models.Asset, error) {
+	var asset models.Asset
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).First(&asset, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get asset by id for update: %w", result.Error)
+	}
+	return &asset, nil
+}
+
+// GetByUUIDForUpdate locks the user's asset row until the surrounding transaction ends.
+func (r *AssetRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Asset, error) {
+	var asset models.Asset
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND user_id = ?", uuid, userID).First(&asset)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get asset for update: %w", result.Error)
+	}
+	return &asset, nil
+}
+
 func (r *AssetRepo) ListByUserID(ctx context.Context, userID int64, page, limit int) ([]models.Asset, int64, error) {
 	var total int64
 	if err := r.db.WithContext(ctx).Model(&models.Asset{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
@@ -68,6 +95,18 @@ func (r *AssetRepo) ListByUserID(ctx context.Context, userID int64, page, limit
 	return assets, total, nil
 }
 
+func (r *AssetRepo) ListActiveWithSymbol(ctx context.Context, types []models.AssetType) ([]models.Asset, error) {
+	var assets []models.Asset
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND type IN ? AND symbol IS NOT NULL AND symbol <> ''", models.AssetStatusActive, types).
+		Order("id").
+		Find(&assets)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list active assets with symbol: %w", result.Error)
+	}
+	return assets, nil
+}
+
 func (r *AssetRepo) Update(ctx context.Context, asset *models.Asset) error {
 	// Use Save to update all fields including zero values if they were scanned into the struct
 	// But since we want to be careful about what we update, and asset comes from service with values set
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type AssetTransactionRepo struct {
+	db *gorm.DB
+}
+
+func NewAssetTransactionRepository(db *gorm.DB) port.AssetTransactionRepository {
+	return &AssetTransactionRepo{db: db}
+}
+
+func (r *AssetTransactionRepo) Create(ctx context.Context, tx *models.AssetTransaction) error {
+	result := r.db.WithContext(ctx).Create(tx)
+	if result.Error != nil {
+		return fmt.Errorf("create asset transaction: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *AssetTransactionRepo) GetByUUID(ctx context.Context, assetID int64, uuid string) (*models.AssetTransaction, error) {
+	var tx models.AssetTransaction
+	result := r.db.WithContext(ctx).Where("uuid = ? AND asset_id = ?", uuid, assetID).First(&tx)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get asset transaction: %w", result.Error)
+	}
+	return &tx, nil
+}
+
+func (r *AssetTransactionRepo) ListByAssetID(ctx context.Context, assetID int64) ([]models.AssetTransaction, error) {
+	var txs []models.AssetTransaction
+	result := r.db.WithContext(ctx).Where("asset_id = ?", assetID).Order("transaction_date ASC, id ASC").Find(&txs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list asset transactions: %w", result.Error)
+	}
+	return txs, nil
+}
+
+func (r *AssetTransactionRepo) ListByUserID(ctx context.Context, userID int64) ([]models.AssetTransaction, error) {
+	var txs []models.AssetTransaction
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("asset_id ASC, transaction_date ASC, id ASC").Find(&txs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list user asset transactions: %w", result.Error)
+	}
+	return txs, nil
+}
+
+func (r *AssetTransactionRepo) Update(ctx context.Context, tx *models.AssetTransaction) error {
+	result := r.db.WithContext(ctx).Save(tx)
+	if result.Error != nil {
+		return fmt.Errorf("update asset transaction: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *AssetTransactionRepo) Delete(ctx context.Context, assetID int64, uuid string) error {
+	result := r.db.WithContext(ctx).Where("uuid = ? AND asset_id = ?", uuid, assetID).Delete(&models.AssetTransaction{})
+	if result.Error != nil {
+		return fmt.Errorf("delete asset transaction: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("transaction not found")
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BudgetRepo struct {
+	db *gorm.DB
+}
+
+func NewBudgetRepository(db *gorm.DB) port.BudgetRepository {
+	return &BudgetRepo{db: db}
+}
+
+func (r *BudgetRepo) Create(ctx context.Context, budget *models.Budget) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(budget)
+	if result.Error != nil {
+		return fmt.Errorf("create budget: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *BudgetRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Budget, error) {
+	var budget models.Budget
+	result := r.db.WithContext(ctx).Preload("Category").Where("uuid = ? AND user_id = ?", uuid, userID).First(&budget)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get budget: %w", result.Error)
+	}
+	return &budget, nil
+}
+
+func (r *BudgetRepo) FindByCategory(ctx context.Context, userID int64, categoryID int64, period models.BudgetPeriod, month time.Time) (*models.Budget, error) {
+	q := r.db.WithContext(ctx).Where("user_id = ? AND category_id = ? AND period = ?", userID, categoryID, period)
+	if period == models.BudgetPeriodMonthly {
+		q = q.Where("month = ?", month)
+	}
+	var budget models.Budget
+	result := q.First(&budget)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find budget: %w", result.Error)
+	}
+	return &budget, nil
+}
+
+func (r *BudgetRepo) ListByUserID(ctx context.Context, userID int64, page, limit int) ([]models.Budget, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.Budget{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count budgets: %w", err)
+	}
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	var budgets []models.Budget
+	result := r.db.WithContext(ctx).Preload("Category").Where("user_id = ?", userID).
+		Order("month desc, category_id asc").Offset(offset).Limit(limit).Find(&budgets)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("list budgets: %w", result.Error)
+	}
+	return budgets, total, nil
+}
+
+func (r *BudgetRepo) ListForMonth(ctx context.Context, userID int64, month time.Time) ([]models.Budget, error) {
+	var budgets []models.Budget
+	result := r.db.WithContext(ctx).Preload("Category").
+		Where("user_id = ? AND ((period = ? AND month = ?) OR (period = ? AND month <= ?))",
+			userID, models.BudgetPeriodMonthly, month, models.BudgetPeriodRolling, month).
+		Order("category_id asc").Find(&budgets)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list budgets for month: %w", result.Error)
+	}
+	return budgets, nil
+}
+
+func (r *BudgetRepo) Update(ctx context.Context, budget *models.Budget) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(budget)
+	if result.Error != nil {
+		return fmt.Errorf("update budget: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *BudgetRepo) Delete(ctx context.Context, uuid string, userID int64) error {
+	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).Delete(&models.Budget{})
+	if result.Error != nil {
+		return fmt.Errorf("delete budget: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("budget not found")
+	}
+	return nil
+}
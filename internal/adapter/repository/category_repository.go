@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CategoryRepo struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) port.CategoryRepository {
+	return &CategoryRepo{db: db}
+}
+
+func (r *CategoryRepo) Create(ctx context.Context, category *models.Category) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(category)
+	if result.Error != nil {
+		return fmt.Errorf("create category: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *CategoryRepo) CreateDefaults(ctx context.Context, userID int64, defaults []models.Category) error {
+	rows := make([]models.Category, len(defaults))
+	for i, d := range defaults {
+		rows[i] = models.Category{UserID: userID, Name: d.Name, Code: d.Code, Icon: d.Icon, Color: d.Color}
+	}
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows)
+	if result.Error != nil {
+		return fmt.Errorf("create default categories: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *CategoryRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Category, error) {
+	var category models.Category
+	result := r.db.WithContext(ctx).Preload("Parent").Where("uuid = ? AND user_id = ?", uuid, userID).First(&category)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get category: %w", result.Error)
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepo) GetByID(ctx context.Context, id int64) (*models.Category, error) {
+	var category models.Category
+	result := r.db.WithContext(ctx).Preload("Parent").First(&category, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get category: %w", result.Error)
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepo) GetByCode(ctx context.Context, userID int64, code string) (*models.Category, error) {
+	var category models.Category
+	result := r.db.WithContext(ctx).Where("user_id = ? AND code = ?", userID, code).First(&category)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get category by code: %w", result.Error)
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepo) GetByName(ctx context.Context, userID int64, parentID *int64, name string) (*models.Category, error) {
+	q := r.db.WithContext(ctx).Where("user_id = ? AND lower(name) = lower(?)", userID, name)
+	if parentID != nil {
+		q = q.Where("parent_id = ?", *parentID)
+	} else {
+		q = q.Where("parent_id IS NULL")
+	}
+	var category models.Category
+	if err := q.First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get category by name: %w", err)
+	}
+	return &category, nil
+}
+
+func (r *CategoryRepo) ListByUserID(ctx context.Context, userID int64, includeArchived bool) ([]models.Category, error) {
+	q := r.db.WithContext(ctx).Preload("Parent").Where("user_id = ?", userID)
+	if !includeArchived {
+		q = q.Where("archived_at IS NULL")
+	}
+	var categories []models.Category
+	result := q.Order("COALESCE(parent_id, id), parent_id NULLS FIRST, lower(name)").Find(&categories)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list categories: %w", result.Error)
+	}
+	return categories, nil
+}
+
+func (r *CategoryRepo) CountChildren(ctx context.Context, id int64) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&models.Category{}).Where("parent_id = ?", id).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("count subcategories: %w", err)
+	}
+	return count, nil
+}
+
+func (r *CategoryRepo) InUse(ctx context.Context, id int64) (bool, error) {
+	var used bool
+	err := r.db.WithContext(ctx).Raw(`SELECT EXISTS (SELECT 1 FROM expenses WHERE category_id = ?)
+		OR EXISTS (SELECT 1 FROM expense_splits WHERE category_id = ?)
+		OR EXISTS (SELECT 1 FROM recurring_rules WHERE category_id = ?)
+		OR EXISTS (SELECT 1 FROM budgets WHERE category_id = ?)`, id, id, id, id).Scan(&used).Error
+	if err != nil {
+		return false, fmt.Errorf("check category use: %w", err)
+	}
+	return used, nil
+}
+
+func (r *CategoryRepo) Update(ctx context.Context, category *models.Category) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(category)
+	if result.Error != nil {
+		return fmt.Errorf("update category: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *CategoryRepo) Delete(ctx context.Context, id int64) error {
+	result := r.db.WithContext(ctx).Delete(&models.Category{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("delete category: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CryptoCoinRepo struct {
+	db *gorm.DB
+}
+
+func NewCryptoCoinRepository(db *gorm.DB) port.CryptoCoinRepository {
+	return &CryptoCoinRepo{db: db}
+}
+
+func (r *CryptoCoinRepo) ListAll(ctx context.Context) ([]models.CryptoCoin, error) {
+	var coins []models.CryptoCoin
+	if err := r.db.WithContext(ctx).Find(&coins).Error; err != nil {
+		return nil, fmt.Errorf("list crypto coins: %w", err)
+	}
+	return coins, nil
+}
+
+func (r *CryptoCoinRepo) Upsert(ctx context.Context, coins []models.CryptoCoin) error {
+	if len(coins) == 0 {
+		return nil
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"symbol", "name", "market_cap_rank", "updated_at"}),
+		}).
+		CreateInBatches(coins, 500)
+	if result.Error != nil {
+		return fmt.Errorf("upsert crypto coins: %w", result.Error)
+	}
+	return nil
+}
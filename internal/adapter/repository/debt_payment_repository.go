@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"monity/internal/core/port"
 	"monity/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DebtPaymentRepo struct {
@@ -34,3 +36,36 @@ func (r *DebtPaymentRepo) ListByDebtID(ctx context.Context, debtID int64) ([]mod
 	}
 	return payments, nil
 }
+
+func (r *DebtPaymentRepo) GetByUUID(ctx context.Context, debtID int64, uuid string) (*models.DebtPayment, error) {
+	var payment models.DebtPayment
+	result := r.db.WithContext(ctx).Where("uuid = ? AND debt_id = ?", uuid, debtID).First(&payment)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get debt payment: %w", result.Error)
+	}
+	return &payment, nil
+}
+
+// GetByUUIDForUpdate locks the payment row until the surrounding transaction ends.
+func (r *DebtPaymentRepo) GetByUUIDForUpdate(ctx context.Context, debtID int64, uuid string) (*models.DebtPayment, error) {
+	var payment models.DebtPayment
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND debt_id = ?", uuid, debtID).First(&payment)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get debt payment for update: %w", result.Error)
+	}
+	return &payment, nil
+}
+
+func (r *DebtPaymentRepo) Update(ctx context.Context, payment *models.DebtPayment) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(payment)
+	if result.Error != nil {
+		return fmt.Errorf("update debt payment: %w", result.Error)
+	}
+	return nil
+}
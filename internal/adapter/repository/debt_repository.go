@@ -10,6 +10,7 @@ import (
 	"monity/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DebtRepo struct {
@@ -21,7 +22,7 @@ func NewDebtRepository(db *gorm.DB) port.DebtRepository {
 }
 
 func (r *DebtRepo) Create(ctx context.Context, debt *models.Debt) error {
-	result := r.db.WithContext(ctx).Create(debt)
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(debt)
 	if result.Error != nil {
 		return fmt.Errorf("create debt: %w", result.Error)
 	}
@@ -30,7 +31,7 @@ func (r *DebtRepo) Create(ctx context.Context, debt *models.Debt) error {
 
 func (r *DebtRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Debt, error) {
 	var debt models.Debt
-	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).First(&debt)
+	result := r.db.WithContext(ctx).Preload("Tags").Where("uuid = ? AND user_id = ?", uuid, userID).First(&debt)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -40,8 +41,24 @@ func (r *DebtRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*m
 	return &debt, nil
 }
 
-func (r *DebtRepo) ListByUserID(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, page, limit int) ([]models.Debt, int64, error) {
+// GetByUUIDForUpdate locks the debt row until the surrounding transaction ends.
+func (r *DebtRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Debt, error) {
+	var debt models.Debt
+	result := r.db.WithContext(ctx).Preload("Tags").Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND user_id = ?", uuid, userID).First(&debt)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get debt for update: %w", result.Error)
+	}
+	return &debt, nil
+}
+
+func (r *DebtRepo) ListByUserID(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, tags []string, page, limit int) ([]models.Debt, int64, error) {
 	q := r.db.WithContext(ctx).Model(&models.Debt{}).Where("user_id = ?", userID)
+	if len(tags) > 0 {
+		q = q.Where("id IN (?)", taggedIDs(r.db, "debt_tags", "debt_id", userID, tags))
+	}
 	if status != nil && *status != "" {
 		q = q.Where("status = ?", *status)
 	}
@@ -61,7 +78,10 @@ func (r *DebtRepo) ListByUserID(ctx context.Context, userID int64, status *strin
 	if offset < 0 {
 		offset = 0
 	}
-	listQ := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	listQ := r.db.WithContext(ctx).Preload("Tags").Where("user_id = ?", userID)
+	if len(tags) > 0 {
+		listQ = listQ.Where("id IN (?)", taggedIDs(r.db, "debt_tags", "debt_id", userID, tags))
+	}
 	if status != nil && *status != "" {
 		listQ = listQ.Where("status = ?", *status)
 	}
@@ -80,13 +100,20 @@ func (r *DebtRepo) ListByUserID(ctx context.Context, userID int64, status *strin
 }
 
 func (r *DebtRepo) Update(ctx context.Context, debt *models.Debt) error {
-	result := r.db.WithContext(ctx).Save(debt)
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(debt)
 	if result.Error != nil {
 		return fmt.Errorf("update debt: %w", result.Error)
 	}
 	return nil
 }
 
+func (r *DebtRepo) ReplaceTags(ctx context.Context, debt *models.Debt, tags []models.Tag) error {
+	if err := replaceTags(ctx, r.db, debt, tags); err != nil {
+		return fmt.Errorf("replace debt tags: %w", err)
+	}
+	return nil
+}
+
 func (r *DebtRepo) Delete(ctx context.Context, uuid string, userID int64) error {
 	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).Delete(&models.Debt{})
 	if result.Error != nil {
@@ -97,3 +124,25 @@ func (r *DebtRepo) Delete(ctx context.Context, uuid string, userID int64) error
 	}
 	return nil
 }
+
+func (r *DebtRepo) MarkOverdue(ctx context.Context, userID int64, now time.Time) (int64, error) {
+	return r.markOverdue(r.db.WithContext(ctx).Where("user_id = ?", userID), now)
+}
+
+func (r *DebtRepo) MarkAllOverdue(ctx context.Context, now time.Time) (int64, error) {
+	return r.markOverdue(r.db.WithContext(ctx), now)
+}
+
+func (r *DebtRepo) markOverdue(q *gorm.DB, now time.Time) (int64, error) {
+	result := q.Model(&models.Debt{}).
+		Where("due_date < ? AND status IN ?", now, []models.ObligationStatus{models.ObligationStatusPending, models.ObligationStatusPartial}).
+		Updates(map[string]interface{}{
+			"status":            models.ObligationStatusOverdue,
+			"status_changed_at": now,
+			"updated_at":        now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("mark overdue debts: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -10,6 +10,7 @@ import (
 	"monity/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ExpenseRepo struct {
@@ -21,7 +22,7 @@ func NewExpenseRepository(db *gorm.DB) port.ExpenseRepository {
 }
 
 func (r *ExpenseRepo) Create(ctx context.Context, expense *models.Expense) error {
-	result := r.db.WithContext(ctx).Create(expense)
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(expense)
 	if result.Error != nil {
 		return fmt.Errorf("create expense: %w", result.Error)
 	}
@@ -30,7 +31,7 @@ func (r *ExpenseRepo) Create(ctx context.Context, expense *models.Expense) error
 
 func (r *ExpenseRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Expense, error) {
 	var expense models.Expense
-	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).First(&expense)
+	result := r.db.WithContext(ctx).Preload("Category").Preload("Tags").Preload("Splits", orderSplits).Preload("Splits.Category").Where("uuid = ? AND user_id = ?", uuid, userID).First(&expense)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -40,8 +41,37 @@ func (r *ExpenseRepo) GetByUUID(ctx context.Context, uuid string, userID int64)
 	return &expense, nil
 }
 
-func (r *ExpenseRepo) ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Expense, int64, error) {
+// GetByUUIDForUpdate locks the expense row until the surrounding transaction ends.
+func (r *ExpenseRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Expense, error) {
+	var expense models.Expense
+	result := r.db.WithContext(ctx).Preload("Category").Preload("Tags").Preload("Splits", orderSplits).Preload("Splits.Category").Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND user_id = ?", uuid, userID).First(&expense)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get expense for update: %w", result.Error)
+	}
+	return &expense, nil
+}
+
+// GetByIDForUpdate locks the expense row with the given internal ID until the surrounding transaction ends.
+func (r *ExpenseRepo) GetByIDForUpdate(ctx context.Context, id int64) (*models.Expense, error) {
+	var expense models.Expense
+	result := r.db.WithContext(ctx).Preload("Category").Preload("Tags").Preload("Splits", orderSplits).Preload("Splits.Category").Clauses(clause.Locking{Strength: "UPDATE"}).First(&expense, id)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get expense by id for update: %w", result.Error)
+	}
+	return &expense, nil
+}
+
+func (r *ExpenseRepo) ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string, page, limit int) ([]models.Expense, int64, error) {
 	q := r.db.WithContext(ctx).Model(&models.Expense{}).Where("user_id = ?", userID)
+	if len(tags) > 0 {
+		q = q.Where("id IN (?)", taggedIDs(r.db, "expense_tags", "expense_id", userID, tags))
+	}
 	if dateFrom != nil {
 		q = q.Where("date >= ?", dateFrom)
 	}
@@ -58,7 +88,10 @@ func (r *ExpenseRepo) ListByUserID(ctx context.Context, userID int64, dateFrom,
 	if offset < 0 {
 		offset = 0
 	}
-	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("date desc, created_at desc")
+	result := r.db.WithContext(ctx).Preload("Category").Preload("Tags").Preload("Splits", orderSplits).Preload("Splits.Category").Where("user_id = ?", userID).Order("date desc, created_at desc")
+	if len(tags) > 0 {
+		result = result.Where("id IN (?)", taggedIDs(r.db, "expense_tags", "expense_id", userID, tags))
+	}
 	if dateFrom != nil {
 		result = result.Where("date >= ?", dateFrom)
 	}
@@ -74,13 +107,41 @@ func (r *ExpenseRepo) ListByUserID(ctx context.Context, userID int64, dateFrom,
 }
 
 func (r *ExpenseRepo) Update(ctx context.Context, expense *models.Expense) error {
-	result := r.db.WithContext(ctx).Save(expense)
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(expense)
 	if result.Error != nil {
 		return fmt.Errorf("update expense: %w", result.Error)
 	}
 	return nil
 }
 
+func (r *ExpenseRepo) ReplaceTags(ctx context.Context, expense *models.Expense, tags []models.Tag) error {
+	if err := replaceTags(ctx, r.db, expense, tags); err != nil {
+		return fmt.Errorf("replace expense tags: %w", err)
+	}
+	return nil
+}
+
+func (r *ExpenseRepo) ReplaceSplits(ctx context.Context, expense *models.Expense, splits []models.ExpenseSplit) error {
+	if err := r.db.WithContext(ctx).Where("expense_id = ?", expense.ID).Delete(&models.ExpenseSplit{}).Error; err != nil {
+		return fmt.Errorf("delete expense splits: %w", err)
+	}
+	if len(splits) == 0 {
+		return nil
+	}
+	for i := range splits {
+		splits[i].ID, splits[i].ExpenseID = 0, expense.ID
+	}
+	if err := r.db.WithContext(ctx).Omit(clause.Associations).Create(&splits).Error; err != nil {
+		return fmt.Errorf("create expense splits: %w", err)
+	}
+	return nil
+}
+
+// orderSplits keeps split lines in the order they were given.
+func orderSplits(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
 func (r *ExpenseRepo) Delete(ctx context.Context, uuid string, userID int64) error {
 	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).Delete(&models.Expense{})
 	if result.Error != nil {
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type FXRateRepo struct {
+	db *gorm.DB
+}
+
+func NewFXRateRepository(db *gorm.DB) port.FXRateRepository {
+	return &FXRateRepo{db: db}
+}
+
+func (r *FXRateRepo) Upsert(ctx context.Context, rates []models.FXRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}, {Name: "rate_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+		}).
+		CreateInBatches(rates, 200)
+	if result.Error != nil {
+		return fmt.Errorf("upsert fx rates: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *FXRateRepo) GetOnOrBefore(ctx context.Context, base, quote string, date time.Time) (*models.FXRate, error) {
+	var rate models.FXRate
+	result := r.db.WithContext(ctx).
+		Where("base = ? AND quote = ? AND rate_date <= ?", base, quote, date.Format("2006-01-02")).
+		Order("rate_date desc").
+		First(&rate)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get fx rate: %w", result.Error)
+	}
+	return &rate, nil
+}
+
+func (r *FXRateRepo) ListQuotes(ctx context.Context, base string) ([]string, error) {
+	var quotes []string
+	if err := r.db.WithContext(ctx).Model(&models.FXRate{}).Where("base = ?", base).Distinct().Pluck("quote", &quotes).Error; err != nil {
+		return nil, fmt.Errorf("list fx quotes: %w", err)
+	}
+	return quotes, nil
+}
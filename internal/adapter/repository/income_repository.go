@@ -10,6 +10,7 @@ import (
 	"monity/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type IncomeRepo struct {
@@ -21,7 +22,7 @@ func NewIncomeRepository(db *gorm.DB) port.IncomeRepository {
 }
 
 func (r *IncomeRepo) Create(ctx context.Context, income *models.Income) error {
-	result := r.db.WithContext(ctx).Create(income)
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(income)
 	if result.Error != nil {
 		return fmt.Errorf("create income: %w", result.Error)
 	}
@@ -30,7 +31,7 @@ func (r *IncomeRepo) Create(ctx context.Context, income *models.Income) error {
 
 func (r *IncomeRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Income, error) {
 	var income models.Income
-	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).First(&income)
+	result := r.db.WithContext(ctx).Preload("SourceAsset").Preload("Tags").Where("uuid = ? AND user_id = ?", uuid, userID).First(&income)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -40,8 +41,24 @@ func (r *IncomeRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (
 	return &income, nil
 }
 
-func (r *IncomeRepo) ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Income, int64, error) {
+// GetByUUIDForUpdate locks the income row until the surrounding transaction ends.
+func (r *IncomeRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Income, error) {
+	var income models.Income
+	result := r.db.WithContext(ctx).Preload("Tags").Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND user_id = ?", uuid, userID).First(&income)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get income for update: %w", result.Error)
+	}
+	return &income, nil
+}
+
+func (r *IncomeRepo) ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string, page, limit int) ([]models.Income, int64, error) {
 	q := r.db.WithContext(ctx).Model(&models.Income{}).Where("user_id = ?", userID)
+	if len(tags) > 0 {
+		q = q.Where("id IN (?)", taggedIDs(r.db, "income_tags", "income_id", userID, tags))
+	}
 	if dateFrom != nil {
 		q = q.Where("date >= ?", dateFrom)
 	}
@@ -58,7 +75,10 @@ func (r *IncomeRepo) ListByUserID(ctx context.Context, userID int64, dateFrom, d
 	if offset < 0 {
 		offset = 0
 	}
-	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("date desc, created_at desc")
+	result := r.db.WithContext(ctx).Preload("SourceAsset").Preload("Tags").Where("user_id = ?", userID).Order("date desc, created_at desc")
+	if len(tags) > 0 {
+		result = result.Where("id IN (?)", taggedIDs(r.db, "income_tags", "income_id", userID, tags))
+	}
 	if dateFrom != nil {
 		result = result.Where("date >= ?", dateFrom)
 	}
@@ -73,14 +93,32 @@ func (r *IncomeRepo) ListByUserID(ctx context.Context, userID int64, dateFrom, d
 	return incomes, total, nil
 }
 
+func (r *IncomeRepo) ListBySourceAssetID(ctx context.Context, sourceAssetID, userID int64) ([]models.Income, error) {
+	var incomes []models.Income
+	result := r.db.WithContext(ctx).Preload("Asset").
+		Where("source_asset_id = ? AND user_id = ?", sourceAssetID, userID).
+		Order("date asc, id asc").Find(&incomes)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list incomes by source asset: %w", result.Error)
+	}
+	return incomes, nil
+}
+
 func (r *IncomeRepo) Update(ctx context.Context, income *models.Income) error {
-	result := r.db.WithContext(ctx).Save(income)
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(income)
 	if result.Error != nil {
 		return fmt.Errorf("update income: %w", result.Error)
 	}
 	return nil
 }
 
+func (r *IncomeRepo) ReplaceTags(ctx context.Context, income *models.Income, tags []models.Tag) error {
+	if err := replaceTags(ctx, r.db, income, tags); err != nil {
+		return fmt.Errorf("replace income tags: %w", err)
+	}
+	return nil
+}
+
 func (r *IncomeRepo) Delete(ctx context.Context, uuid string, userID int64) error {
 	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).Delete(&models.Income{})
 	if result.Error != nil {
@@ -58,17 +58,30 @@ func (r *InsightRepo) GetTotalExpenseByDateRange(ctx context.Context, userID int
 	return decimal.Zero, nil
 }
 
+// expenseLines is a subquery of the user's spending per category: one row per split line of a split expense,
+// and the expense itself otherwise. It keeps the expense's id, user_id and date.
+func expenseLines(db *gorm.DB) *gorm.DB {
+	return db.Table("expenses x").
+		Select("x.id, x.user_id, x.date, COALESCE(s.category_id, x.category_id) as category_id, COALESCE(s.amount, x.amount) as amount").
+		Joins("LEFT JOIN expense_splits s ON s.expense_id = x.id")
+}
+
 func (r *InsightRepo) GetExpensesByCategory(ctx context.Context, userID int64, startDate, endDate time.Time) ([]port.CategoryTotal, error) {
 	var results []struct {
-		Category string          `gorm:"column:category"`
-		Total    decimal.Decimal `gorm:"column:total"`
+		CategoryUUID string          `gorm:"column:category_uuid"`
+		Category     string          `gorm:"column:category"`
+		ParentUUID   *string         `gorm:"column:parent_uuid"`
+		Parent       *string         `gorm:"column:parent"`
+		Total        decimal.Decimal `gorm:"column:total"`
 	}
 
 	err := r.db.WithContext(ctx).
-		Model(&models.Expense{}).
-		Select("category, SUM(amount) as total").
-		Where("user_id = ? AND date >= ? AND date < ?", userID, startDate, endDate).
-		Group("category").
+		Table("(?) as e", expenseLines(r.db)).
+		Select("c.uuid as category_uuid, c.name as category, p.uuid as parent_uuid, p.name as parent, SUM(e.amount) as total").
+		Joins("JOIN categories c ON c.id = e.category_id").
+		Joins("LEFT JOIN categories p ON p.id = c.parent_id").
+		Where("e.user_id = ? AND e.date >= ? AND e.date < ?", userID, startDate, endDate).
+		Group("c.uuid, c.name, p.uuid, p.name").
 		Order("total desc").
 		Scan(&results).Error
 
@@ -90,15 +103,34 @@ func (r *InsightRepo) GetExpensesByCategory(ctx context.Context, userID int64, s
 			percentage, _ = pct.Float64()
 		}
 		categories[i] = port.CategoryTotal{
-			Category:   r.Category,
-			Total:      r.Total,
-			Percentage: percentage,
+			CategoryUUID: r.CategoryUUID,
+			Category:     r.Category,
+			ParentUUID:   r.ParentUUID,
+			Parent:       r.Parent,
+			Total:        r.Total,
+			Percentage:   percentage,
 		}
 	}
 
 	return categories, nil
 }
 
+func (r *InsightRepo) GetMonthlyExpensesByCategory(ctx context.Context, userID int64, startDate, endDate time.Time) ([]port.MonthlyCategoryTotal, error) {
+	var totals []port.MonthlyCategoryTotal
+	err := r.db.WithContext(ctx).
+		Table("(?) as e", expenseLines(r.db)).
+		Select("to_char(e.date AT TIME ZONE 'UTC', 'YYYY-MM') as month, e.category_id, c.parent_id, SUM(e.amount) as total").
+		Joins("JOIN categories c ON c.id = e.category_id").
+		Where("e.user_id = ? AND e.date >= ? AND e.date < ?", userID, startDate, endDate).
+		Group("month, e.category_id, c.parent_id").
+		Order("month, e.category_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("get monthly expenses by category: %w", err)
+	}
+	return totals, nil
+}
+
 func (r *InsightRepo) GetTotalAssetValue(ctx context.Context, userID int64) (decimal.Decimal, error) {
 	// For now, just count assets. Later this can be enhanced to calculate
 	// actual value based on quantity * latest price
@@ -178,12 +210,14 @@ func (r *InsightRepo) GetTotalReceivable(ctx context.Context, userID int64) (dec
 	return decimal.Zero, nil
 }
 
+// GetDebtOverdueCount counts debts stored as OVERDUE plus any the sweep has not reached yet,
+// matching what ListDebts returns for status=OVERDUE.
 func (r *InsightRepo) GetDebtOverdueCount(ctx context.Context, userID int64) (int, error) {
 	var count int64
 	now := time.Now()
 	err := r.db.WithContext(ctx).
 		Model(&models.Debt{}).
-		Where("user_id = ? AND due_date < ? AND status != ?", userID, now, "PAID").
+		Where("user_id = ? AND (status = ? OR (due_date < ? AND status IN ?))", userID, models.ObligationStatusOverdue, now, []models.ObligationStatus{models.ObligationStatusPending, models.ObligationStatusPartial}).
 		Count(&count).Error
 	if err != nil {
 		return 0, fmt.Errorf("get debt overdue count: %w", err)
@@ -191,15 +225,92 @@ func (r *InsightRepo) GetDebtOverdueCount(ctx context.Context, userID int64) (in
 	return int(count), nil
 }
 
+// GetReceivableOverdueCount is GetDebtOverdueCount for receivables.
 func (r *InsightRepo) GetReceivableOverdueCount(ctx context.Context, userID int64) (int, error) {
 	var count int64
 	now := time.Now()
 	err := r.db.WithContext(ctx).
 		Model(&models.Receivable{}).
-		Where("user_id = ? AND due_date < ? AND status != ?", userID, now, "PAID").
+		Where("user_id = ? AND (status = ? OR (due_date < ? AND status IN ?))", userID, models.ObligationStatusOverdue, now, []models.ObligationStatus{models.ObligationStatusPending, models.ObligationStatusPartial}).
 		Count(&count).Error
 	if err != nil {
 		return 0, fmt.Errorf("get receivable overdue count: %w", err)
 	}
 	return int(count), nil
 }
+
+func (r *InsightRepo) GetExpensesByTag(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string) ([]port.TagCategoryTotal, error) {
+	var results []struct {
+		TagUUID      string          `gorm:"column:tag_uuid"`
+		Tag          string          `gorm:"column:tag"`
+		CategoryUUID string          `gorm:"column:category_uuid"`
+		Category     string          `gorm:"column:category"`
+		ParentUUID   *string         `gorm:"column:parent_uuid"`
+		Parent       *string         `gorm:"column:parent"`
+		Total        decimal.Decimal `gorm:"column:total"`
+	}
+
+	q := r.db.WithContext(ctx).
+		Table("(?) as e", expenseLines(r.db)).
+		Select("t.uuid as tag_uuid, t.name as tag, c.uuid as category_uuid, c.name as category, p.uuid as parent_uuid, p.name as parent, SUM(e.amount) as total").
+		Joins("JOIN expense_tags et ON et.expense_id = e.id").
+		Joins("JOIN tags t ON t.id = et.tag_id").
+		Joins("JOIN categories c ON c.id = e.category_id").
+		Joins("LEFT JOIN categories p ON p.id = c.parent_id")
+	q = whereTagged(q, "e", userID, dateFrom, dateTo, tags)
+	err := q.Group("t.uuid, t.name, c.uuid, c.name, p.uuid, p.name").
+		Order("t.name, total desc").
+		Scan(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("get expenses by tag: %w", err)
+	}
+
+	totals := make([]port.TagCategoryTotal, len(results))
+	for i, r := range results {
+		totals[i] = port.TagCategoryTotal{
+			TagUUID: r.TagUUID,
+			Tag:     r.Tag,
+			CategoryTotal: port.CategoryTotal{
+				CategoryUUID: r.CategoryUUID,
+				Category:     r.Category,
+				ParentUUID:   r.ParentUUID,
+				Parent:       r.Parent,
+				Total:        r.Total,
+			},
+		}
+	}
+	return totals, nil
+}
+
+func (r *InsightRepo) GetIncomeByTag(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string) ([]port.TagIncomeTotal, error) {
+	var totals []port.TagIncomeTotal
+	q := r.db.WithContext(ctx).
+		Table("incomes i").
+		Select("t.uuid as tag_uuid, t.name as tag, SUM(i.amount) as total").
+		Joins("JOIN income_tags it ON it.income_id = i.id").
+		Joins("JOIN tags t ON t.id = it.tag_id")
+	q = whereTagged(q, "i", userID, dateFrom, dateTo, tags)
+	err := q.Group("t.uuid, t.name").
+		Order("t.name").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, fmt.Errorf("get income by tag: %w", err)
+	}
+	return totals, nil
+}
+
+// whereTagged applies the user, date and tag-name filters of the per-tag totals; alias names the expenses or
+// incomes table and t the tags table.
+func whereTagged(q *gorm.DB, alias string, userID int64, dateFrom, dateTo *time.Time, tags []string) *gorm.DB {
+	q = q.Where(alias+".user_id = ?", userID)
+	if dateFrom != nil {
+		q = q.Where(alias+".date >= ?", dateFrom)
+	}
+	if dateTo != nil {
+		q = q.Where(alias+".date < ?", dateTo.AddDate(0, 0, 1))
+	}
+	if len(tags) > 0 {
+		q = q.Where("t.name IN ?", tags)
+	}
+	return q
+}
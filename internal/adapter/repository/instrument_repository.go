@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type InstrumentRepo struct {
+	db *gorm.DB
+}
+
+func NewInstrumentRepository(db *gorm.DB) port.InstrumentRepository {
+	return &InstrumentRepo{db: db}
+}
+
+func (r *InstrumentRepo) ListAll(ctx context.Context) ([]models.Instrument, error) {
+	var instruments []models.Instrument
+	if err := r.db.WithContext(ctx).Order("symbol, exchange").Find(&instruments).Error; err != nil {
+		return nil, fmt.Errorf("list instruments: %w", err)
+	}
+	return instruments, nil
+}
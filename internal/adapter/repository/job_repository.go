@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobRepo struct {
+	db *gorm.DB
+}
+
+func NewJobRepository(db *gorm.DB) port.JobRepository {
+	return &JobRepo{db: db}
+}
+
+func (r *JobRepo) Enqueue(ctx context.Context, job *models.Job) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}, {Name: "scheduled_for"}}, DoNothing: true}).
+		Create(job)
+	if result.Error != nil {
+		return false, fmt.Errorf("enqueue job: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *JobRepo) LastScheduledFor(ctx context.Context, name string) (*time.Time, error) {
+	var last *time.Time
+	err := r.db.WithContext(ctx).Model(&models.Job{}).
+		Select("MAX(scheduled_for)").
+		Where("name = ?", name).
+		Scan(&last).Error
+	if err != nil {
+		return nil, fmt.Errorf("get last scheduled job: %w", err)
+	}
+	return last, nil
+}
+
+func (r *JobRepo) ClaimDue(ctx context.Context, names []string, workerID string, now time.Time, limit int) ([]models.Job, error) {
+	if len(names) == 0 || limit < 1 {
+		return nil, nil
+	}
+	var jobs []models.Job
+	err := r.db.WithContext(ctx).Raw(`
+		UPDATE scheduler_jobs
+		SET status = 'RUNNING', attempts = attempts + 1, locked_by = ?, locked_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM scheduler_jobs
+			WHERE status = 'PENDING' AND run_at <= ? AND name IN ?
+			ORDER BY run_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, workerID, now, now, now, names, limit).
+		Scan(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("claim due jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+func (r *JobRepo) Update(ctx context.Context, job *models.Job) error {
+	result := r.db.WithContext(ctx).Save(job)
+	if result.Error != nil {
+		return fmt.Errorf("update job: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *JobRepo) RequeueStale(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE scheduler_jobs
+		SET status = CASE WHEN attempts >= max_attempts THEN 'DEAD'::job_status ELSE 'PENDING'::job_status END,
+			run_at = ?, locked_by = NULL, locked_at = NULL, updated_at = ?,
+			last_error = COALESCE(last_error, 'worker stopped before finishing')
+		WHERE status = 'RUNNING' AND locked_at < ?`, now, now, cutoff)
+	if result.Error != nil {
+		return 0, fmt.Errorf("requeue stale jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+func (r *JobRepo) PurgeSucceeded(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("status = ? AND finished_at < ?", models.JobStatusSucceeded, cutoff).
+		Delete(&models.Job{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("purge succeeded jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type LedgerRepo struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) port.LedgerRepository {
+	return &LedgerRepo{db: db}
+}
+
+func (r *LedgerRepo) CreateTransaction(ctx context.Context, txn *models.LedgerTransaction) error {
+	result := r.db.WithContext(ctx).Create(txn)
+	if result.Error != nil {
+		return fmt.Errorf("create ledger transaction: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *LedgerRepo) NetByReference(ctx context.Context, refType models.LedgerReferenceType, refUUID string) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	err := r.db.WithContext(ctx).
+		Table("ledger_entries e").
+		Select("e.account, e.asset_id, SUM(e.amount) AS amount").
+		Joins("JOIN ledger_transactions t ON t.id = e.transaction_id").
+		Where("t.reference_type = ? AND t.reference_uuid = ?", refType, refUUID).
+		Group("e.account, e.asset_id").
+		Having("SUM(e.amount) <> 0").
+		Scan(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf("net ledger by reference: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *LedgerRepo) DeleteByReference(ctx context.Context, refType models.LedgerReferenceType, refUUID string) error {
+	err := r.db.WithContext(ctx).
+		Where("reference_type = ? AND reference_uuid = ?", refType, refUUID).
+		Delete(&models.LedgerTransaction{}).Error
+	if err != nil {
+		return fmt.Errorf("delete ledger transactions: %w", err)
+	}
+	return nil
+}
+
+func (r *LedgerRepo) BalanceByAssetID(ctx context.Context, assetID int64) (decimal.Decimal, error) {
+	var total decimal.NullDecimal
+	err := r.db.WithContext(ctx).
+		Model(&models.LedgerEntry{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("asset_id = ?", assetID).
+		Scan(&total).Error
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("get ledger balance: %w", err)
+	}
+	if total.Valid {
+		return total.Decimal, nil
+	}
+	return decimal.Zero, nil
+}
+
+func (r *LedgerRepo) ListByAssetID(ctx context.Context, assetID int64, page, limit int) ([]port.AssetLedgerEntry, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.LedgerEntry{}).Where("asset_id = ?", assetID).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count ledger entries: %w", err)
+	}
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	// Running balance is computed oldest-first over the whole history, then paged newest-first.
+	var entries []port.AssetLedgerEntry
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT * FROM (
+			SELECT e.id AS entry_id, e.uuid, t.uuid AS transaction_uuid, t.reference_type, t.reference_uuid,
+			       t.description, e.amount, t.occurred_at, t.created_at,
+			       SUM(e.amount) OVER (ORDER BY t.occurred_at, e.id) AS balance
+			FROM ledger_entries e
+			JOIN ledger_transactions t ON t.id = e.transaction_id
+			WHERE e.asset_id = ?
+		) history
+		ORDER BY occurred_at DESC, entry_id DESC
+		OFFSET ? LIMIT ?`, assetID, offset, limit).
+		Scan(&entries).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("list ledger entries: %w", err)
+	}
+	return entries, total, nil
+}
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"monity/internal/core/port"
 	"monity/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReceivablePaymentRepo struct {
@@ -34,3 +36,36 @@ func (r *ReceivablePaymentRepo) ListByReceivableID(ctx context.Context, receivab
 	}
 	return payments, nil
 }
+
+func (r *ReceivablePaymentRepo) GetByUUID(ctx context.Context, receivableID int64, uuid string) (*models.ReceivablePayment, error) {
+	var payment models.ReceivablePayment
+	result := r.db.WithContext(ctx).Where("uuid = ? AND receivable_id = ?", uuid, receivableID).First(&payment)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get receivable payment: %w", result.Error)
+	}
+	return &payment, nil
+}
+
+// GetByUUIDForUpdate locks the payment row until the surrounding transaction ends.
+func (r *ReceivablePaymentRepo) GetByUUIDForUpdate(ctx context.Context, receivableID int64, uuid string) (*models.ReceivablePayment, error) {
+	var payment models.ReceivablePayment
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND receivable_id = ?", uuid, receivableID).First(&payment)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get receivable payment for update: %w", result.Error)
+	}
+	return &payment, nil
+}
+
+func (r *ReceivablePaymentRepo) Update(ctx context.Context, payment *models.ReceivablePayment) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(payment)
+	if result.Error != nil {
+		return fmt.Errorf("update receivable payment: %w", result.Error)
+	}
+	return nil
+}
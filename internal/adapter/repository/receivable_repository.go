@@ -10,6 +10,7 @@ import (
 	"monity/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ReceivableRepo struct {
@@ -21,7 +22,7 @@ func NewReceivableRepository(db *gorm.DB) port.ReceivableRepository {
 }
 
 func (r *ReceivableRepo) Create(ctx context.Context, rec *models.Receivable) error {
-	result := r.db.WithContext(ctx).Create(rec)
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(rec)
 	if result.Error != nil {
 		return fmt.Errorf("create receivable: %w", result.Error)
 	}
@@ -30,7 +31,7 @@ func (r *ReceivableRepo) Create(ctx context.Context, rec *models.Receivable) err
 
 func (r *ReceivableRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Receivable, error) {
 	var rec models.Receivable
-	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).First(&rec)
+	result := r.db.WithContext(ctx).Preload("Tags").Where("uuid = ? AND user_id = ?", uuid, userID).First(&rec)
 	if result.Error != nil {
 		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 			return nil, nil
@@ -40,8 +41,24 @@ func (r *ReceivableRepo) GetByUUID(ctx context.Context, uuid string, userID int6
 	return &rec, nil
 }
 
-func (r *ReceivableRepo) ListByUserID(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, page, limit int) ([]models.Receivable, int64, error) {
+// GetByUUIDForUpdate locks the receivable row until the surrounding transaction ends.
+func (r *ReceivableRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Receivable, error) {
+	var rec models.Receivable
+	result := r.db.WithContext(ctx).Preload("Tags").Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND user_id = ?", uuid, userID).First(&rec)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get receivable for update: %w", result.Error)
+	}
+	return &rec, nil
+}
+
+func (r *ReceivableRepo) ListByUserID(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, tags []string, page, limit int) ([]models.Receivable, int64, error) {
 	q := r.db.WithContext(ctx).Model(&models.Receivable{}).Where("user_id = ?", userID)
+	if len(tags) > 0 {
+		q = q.Where("id IN (?)", taggedIDs(r.db, "receivable_tags", "receivable_id", userID, tags))
+	}
 	if status != nil && *status != "" {
 		q = q.Where("status = ?", *status)
 	}
@@ -61,7 +78,10 @@ func (r *ReceivableRepo) ListByUserID(ctx context.Context, userID int64, status
 	if offset < 0 {
 		offset = 0
 	}
-	listQ := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	listQ := r.db.WithContext(ctx).Preload("Tags").Where("user_id = ?", userID)
+	if len(tags) > 0 {
+		listQ = listQ.Where("id IN (?)", taggedIDs(r.db, "receivable_tags", "receivable_id", userID, tags))
+	}
 	if status != nil && *status != "" {
 		listQ = listQ.Where("status = ?", *status)
 	}
@@ -80,13 +100,20 @@ func (r *ReceivableRepo) ListByUserID(ctx context.Context, userID int64, status
 }
 
 func (r *ReceivableRepo) Update(ctx context.Context, rec *models.Receivable) error {
-	result := r.db.WithContext(ctx).Save(rec)
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(rec)
 	if result.Error != nil {
 		return fmt.Errorf("update receivable: %w", result.Error)
 	}
 	return nil
 }
 
+func (r *ReceivableRepo) ReplaceTags(ctx context.Context, rec *models.Receivable, tags []models.Tag) error {
+	if err := replaceTags(ctx, r.db, rec, tags); err != nil {
+		return fmt.Errorf("replace receivable tags: %w", err)
+	}
+	return nil
+}
+
 func (r *ReceivableRepo) Delete(ctx context.Context, uuid string, userID int64) error {
 	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).Delete(&models.Receivable{})
 	if result.Error != nil {
@@ -97,3 +124,25 @@ func (r *ReceivableRepo) Delete(ctx context.Context, uuid string, userID int64)
 	}
 	return nil
 }
+
+func (r *ReceivableRepo) MarkOverdue(ctx context.Context, userID int64, now time.Time) (int64, error) {
+	return r.markOverdue(r.db.WithContext(ctx).Where("user_id = ?", userID), now)
+}
+
+func (r *ReceivableRepo) MarkAllOverdue(ctx context.Context, now time.Time) (int64, error) {
+	return r.markOverdue(r.db.WithContext(ctx), now)
+}
+
+func (r *ReceivableRepo) markOverdue(q *gorm.DB, now time.Time) (int64, error) {
+	result := q.Model(&models.Receivable{}).
+		Where("due_date < ? AND status IN ?", now, []models.ObligationStatus{models.ObligationStatusPending, models.ObligationStatusPartial}).
+		Updates(map[string]interface{}{
+			"status":            models.ObligationStatusOverdue,
+			"status_changed_at": now,
+			"updated_at":        now,
+		})
+	if result.Error != nil {
+		return 0, fmt.Errorf("mark overdue receivables: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
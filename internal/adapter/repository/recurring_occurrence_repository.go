@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringOccurrenceRepo struct {
+	db *gorm.DB
+}
+
+func NewRecurringOccurrenceRepository(db *gorm.DB) port.RecurringOccurrenceRepository {
+	return &RecurringOccurrenceRepo{db: db}
+}
+
+func (r *RecurringOccurrenceRepo) Get(ctx context.Context, ruleID int64, index int) (*models.RecurringOccurrence, error) {
+	var occ models.RecurringOccurrence
+	result := r.db.WithContext(ctx).Where("rule_id = ? AND occurrence_index = ?", ruleID, index).First(&occ)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get recurring occurrence: %w", result.Error)
+	}
+	return &occ, nil
+}
+
+func (r *RecurringOccurrenceRepo) ListByRuleID(ctx context.Context, ruleID int64) ([]models.RecurringOccurrence, error) {
+	var occs []models.RecurringOccurrence
+	result := r.db.WithContext(ctx).Preload("Expense").Preload("Income").
+		Where("rule_id = ?", ruleID).Order("occurrence_index asc").Find(&occs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list recurring occurrences: %w", result.Error)
+	}
+	return occs, nil
+}
+
+func (r *RecurringOccurrenceRepo) ListByUserID(ctx context.Context, userID int64, statuses []models.OccurrenceStatus) ([]models.RecurringOccurrence, error) {
+	var occs []models.RecurringOccurrence
+	result := r.db.WithContext(ctx).Where("user_id = ? AND status IN ?", userID, statuses).
+		Order("due_date asc, id asc").Find(&occs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list recurring occurrences: %w", result.Error)
+	}
+	return occs, nil
+}
+
+func (r *RecurringOccurrenceRepo) Save(ctx context.Context, occurrence *models.RecurringOccurrence) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(occurrence)
+	if result.Error != nil {
+		return fmt.Errorf("save recurring occurrence: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *RecurringOccurrenceRepo) DeleteUnposted(ctx context.Context, ruleID int64) error {
+	result := r.db.WithContext(ctx).Where("rule_id = ? AND status <> ?", ruleID, models.OccurrencePosted).
+		Delete(&models.RecurringOccurrence{})
+	if result.Error != nil {
+		return fmt.Errorf("delete recurring occurrences: %w", result.Error)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringRuleRepo struct {
+	db *gorm.DB
+}
+
+func NewRecurringRuleRepository(db *gorm.DB) port.RecurringRuleRepository {
+	return &RecurringRuleRepo{db: db}
+}
+
+func (r *RecurringRuleRepo) Create(ctx context.Context, rule *models.RecurringRule) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(rule)
+	if result.Error != nil {
+		return fmt.Errorf("create recurring rule: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *RecurringRuleRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.RecurringRule, error) {
+	var rule models.RecurringRule
+	result := r.db.WithContext(ctx).Preload("Asset").Preload("Category").Where("uuid = ? AND user_id = ?", uuid, userID).First(&rule)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get recurring rule: %w", result.Error)
+	}
+	return &rule, nil
+}
+
+// GetByUUIDForUpdate locks the rule row until the surrounding transaction ends.
+func (r *RecurringRuleRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.RecurringRule, error) {
+	var rule models.RecurringRule
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND user_id = ?", uuid, userID).First(&rule)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get recurring rule for update: %w", result.Error)
+	}
+	return &rule, nil
+}
+
+// GetByIDForUpdate locks the rule row until the surrounding transaction ends.
+func (r *RecurringRuleRepo) GetByIDForUpdate(ctx context.Context, id int64) (*models.RecurringRule, error) {
+	var rule models.RecurringRule
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&rule)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get recurring rule for update: %w", result.Error)
+	}
+	return &rule, nil
+}
+
+func (r *RecurringRuleRepo) ListByUserID(ctx context.Context, userID int64, page, limit int) ([]models.RecurringRule, int64, error) {
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&models.RecurringRule{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count recurring rules: %w", err)
+	}
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	var rules []models.RecurringRule
+	result := r.db.WithContext(ctx).Preload("Asset").Preload("Category").Where("user_id = ?", userID).
+		Order("next_due asc nulls last, created_at desc").Offset(offset).Limit(limit).Find(&rules)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("list recurring rules: %w", result.Error)
+	}
+	return rules, total, nil
+}
+
+func (r *RecurringRuleRepo) ListAllByUserID(ctx context.Context, userID int64) ([]models.RecurringRule, error) {
+	var rules []models.RecurringRule
+	result := r.db.WithContext(ctx).Preload("Asset").Preload("Category").Where("user_id = ?", userID).Order("id asc").Find(&rules)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list recurring rules: %w", result.Error)
+	}
+	return rules, nil
+}
+
+func (r *RecurringRuleRepo) ListDueIDs(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	var ids []int64
+	result := r.db.WithContext(ctx).Model(&models.RecurringRule{}).
+		Where("next_due IS NOT NULL AND next_due <= ?", now).
+		Order("next_due asc, id asc").Limit(limit).Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, fmt.Errorf("list due recurring rules: %w", result.Error)
+	}
+	return ids, nil
+}
+
+func (r *RecurringRuleRepo) Update(ctx context.Context, rule *models.RecurringRule) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(rule)
+	if result.Error != nil {
+		return fmt.Errorf("update recurring rule: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *RecurringRuleRepo) Delete(ctx context.Context, uuid string, userID int64) error {
+	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).Delete(&models.RecurringRule{})
+	if result.Error != nil {
+		return fmt.Errorf("delete recurring rule: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("recurring rule not found")
+	}
+	return nil
+}
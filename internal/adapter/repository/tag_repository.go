@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepo struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) port.TagRepository {
+	return &TagRepo{db: db}
+}
+
+func (r *TagRepo) FindOrCreate(ctx context.Context, userID int64, names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	tags := make([]models.Tag, len(names))
+	for i, name := range names {
+		tags[i] = models.Tag{UserID: userID, Name: name}
+	}
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return nil, fmt.Errorf("create tags: %w", err)
+	}
+	var found []models.Tag
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND name IN ?", userID, names).Order("name asc").Find(&found).Error; err != nil {
+		return nil, fmt.Errorf("find tags: %w", err)
+	}
+	return found, nil
+}
+
+func (r *TagRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Tag, error) {
+	var tag models.Tag
+	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).First(&tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get tag: %w", result.Error)
+	}
+	return &tag, nil
+}
+
+func (r *TagRepo) GetByName(ctx context.Context, userID int64, name string) (*models.Tag, error) {
+	var tag models.Tag
+	result := r.db.WithContext(ctx).Where("user_id = ? AND name = ?", userID, name).First(&tag)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get tag by name: %w", result.Error)
+	}
+	return &tag, nil
+}
+
+func (r *TagRepo) ListByUserID(ctx context.Context, userID int64) ([]models.Tag, error) {
+	var tags []models.Tag
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("name asc").Find(&tags).Error; err != nil {
+		return nil, fmt.Errorf("list tags: %w", err)
+	}
+	return tags, nil
+}
+
+func (r *TagRepo) Update(ctx context.Context, tag *models.Tag) error {
+	if err := r.db.WithContext(ctx).Save(tag).Error; err != nil {
+		return fmt.Errorf("update tag: %w", err)
+	}
+	return nil
+}
+
+func (r *TagRepo) Delete(ctx context.Context, uuid string, userID int64) error {
+	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).Delete(&models.Tag{})
+	if result.Error != nil {
+		return fmt.Errorf("delete tag: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("tag not found")
+	}
+	return nil
+}
+
+// taggedIDs is a subquery of the IDs, in ownerColumn of joinTable, of the user's rows carrying every one of
+// the tag names. names must be distinct.
+func taggedIDs(db *gorm.DB, joinTable, ownerColumn string, userID int64, names []string) *gorm.DB {
+	return db.Table(joinTable+" jt").Select("jt."+ownerColumn).
+		Joins("JOIN tags t ON t.id = jt.tag_id").
+		Where("t.user_id = ? AND t.name IN ?", userID, names).
+		Group("jt."+ownerColumn).
+		Having("COUNT(*) = ?", len(names))
+}
+
+// replaceTags makes tags the full set of tags on owner, through its Tags many-to-many association.
+func replaceTags(ctx context.Context, db *gorm.DB, owner any, tags []models.Tag) error {
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+	return db.WithContext(ctx).Model(owner).Omit("Tags.*").Association("Tags").Replace(tags)
+}
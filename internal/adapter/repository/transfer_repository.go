@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TransferRepo struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) port.TransferRepository {
+	return &TransferRepo{db: db}
+}
+
+func (r *TransferRepo) Create(ctx context.Context, transfer *models.Transfer) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Create(transfer)
+	if result.Error != nil {
+		return fmt.Errorf("create transfer: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *TransferRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Transfer, error) {
+	var transfer models.Transfer
+	result := r.db.WithContext(ctx).
+		Preload("FromAsset").Preload("ToAsset").Preload("FeeExpense").
+		Where("uuid = ? AND user_id = ?", uuid, userID).First(&transfer)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get transfer: %w", result.Error)
+	}
+	return &transfer, nil
+}
+
+// GetByUUIDForUpdate locks the transfer row until the surrounding transaction ends.
+func (r *TransferRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Transfer, error) {
+	var transfer models.Transfer
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ? AND user_id = ?", uuid, userID).First(&transfer)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get transfer for update: %w", result.Error)
+	}
+	return &transfer, nil
+}
+
+func (r *TransferRepo) ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Transfer, int64, error) {
+	q := r.db.WithContext(ctx).Model(&models.Transfer{}).Where("user_id = ?", userID)
+	if dateFrom != nil {
+		q = q.Where("date >= ?", dateFrom)
+	}
+	if dateTo != nil {
+		end := dateTo.AddDate(0, 0, 1)
+		q = q.Where("date < ?", end)
+	}
+	var total int64
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count transfers: %w", err)
+	}
+	var transfers []models.Transfer
+	offset := (page - 1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+	result := r.db.WithContext(ctx).Preload("FromAsset").Preload("ToAsset").Preload("FeeExpense").
+		Where("user_id = ?", userID).Order("date desc, created_at desc")
+	if dateFrom != nil {
+		result = result.Where("date >= ?", dateFrom)
+	}
+	if dateTo != nil {
+		end := dateTo.AddDate(0, 0, 1)
+		result = result.Where("date < ?", end)
+	}
+	result = result.Offset(offset).Limit(limit).Find(&transfers)
+	if result.Error != nil {
+		return nil, 0, fmt.Errorf("list transfers: %w", result.Error)
+	}
+	return transfers, total, nil
+}
+
+func (r *TransferRepo) Update(ctx context.Context, transfer *models.Transfer) error {
+	result := r.db.WithContext(ctx).Omit(clause.Associations).Save(transfer)
+	if result.Error != nil {
+		return fmt.Errorf("update transfer: %w", result.Error)
+	}
+	return nil
+}
+
+func (r *TransferRepo) Delete(ctx context.Context, uuid string, userID int64) error {
+	result := r.db.WithContext(ctx).Where("uuid = ? AND user_id = ?", uuid, userID).Delete(&models.Transfer{})
+	if result.Error != nil {
+		return fmt.Errorf("delete transfer: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("transfer not found or not owned by user")
+	}
+	return nil
+}
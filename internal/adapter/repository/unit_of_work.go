@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"monity/internal/core/port"
+
+	"gorm.io/gorm"
+)
+
+type UnitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) port.UnitOfWork {
+	return &UnitOfWork{db: db}
+}
+
+// txKey carries the transaction of the surrounding Do in its ctx.
+type txKey struct{}
+
+// Do called with the ctx of another Do joins that transaction through a savepoint: a failure rolls back
+// only the inner work, and the outer caller decides whether to go on.
+func (u *UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context, repos port.Repositories) error) error {
+	db := u.db.WithContext(ctx)
+	if outer, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		db = outer
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx), newRepositories(tx))
+	})
+}
+
+// newRepositories builds every repository on top of the same *gorm.DB (usually a transaction).
+func newRepositories(db *gorm.DB) port.Repositories {
+	return port.Repositories{
+		Assets:               NewAssetRepository(db),
+		AssetTransactions:    NewAssetTransactionRepository(db),
+		Expenses:             NewExpenseRepository(db),
+		Incomes:              NewIncomeRepository(db),
+		Debts:                NewDebtRepository(db),
+		DebtPayments:         NewDebtPaymentRepository(db),
+		Receivables:          NewReceivableRepository(db),
+		ReceivablePayments:   NewReceivablePaymentRepository(db),
+		Ledger:               NewLedgerRepository(db),
+		Transfers:            NewTransferRepository(db),
+		RecurringRules:       NewRecurringRuleRepository(db),
+		RecurringOccurrences: NewRecurringOccurrenceRepository(db),
+		Categories:           NewCategoryRepository(db),
+		Tags:                 NewTagRepository(db),
+	}
+}
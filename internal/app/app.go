@@ -2,24 +2,33 @@ package app
 
 import (
 	"context"
+	"expvar"
 	"log/slog"
 	"net/http"
+	"time"
 
 	"monity/internal/adapter/handler"
 	"monity/internal/adapter/middleware"
+	"monity/internal/adapter/priceprovider"
 	"monity/internal/adapter/repository"
 	"monity/internal/app/routes"
 	"monity/internal/config"
+	"monity/internal/core/port"
 	"monity/internal/core/service"
 	"monity/internal/pkg/cache"
+	"monity/internal/scheduler"
 
 	"gorm.io/gorm"
 )
 
+// schedulerLockKey is the Postgres advisory lock key held by the replica that schedules background jobs.
+const schedulerLockKey int64 = 0x6d6f6e697479 // "monity"
+
 type App struct {
-	cfg *config.Config
-	db  *gorm.DB
-	srv *http.Server
+	cfg       *config.Config
+	db        *gorm.DB
+	srv       *http.Server
+	scheduler *scheduler.Scheduler
 }
 
 func New(ctx context.Context, cfg *config.Config, db *gorm.DB, c cache.Cache) *App {
@@ -37,20 +46,58 @@ func New(ctx context.Context, cfg *config.Config, db *gorm.DB, c cache.Cache) *A
 	receivablePaymentRepo := repository.NewReceivablePaymentRepository(db)
 	assetPriceHistoryRepo := repository.NewAssetPriceHistoryRepository(db)
 	insightRepo := repository.NewInsightRepository(db)
+	ledgerRepo := repository.NewLedgerRepository(db)
+	transferRepo := repository.NewTransferRepository(db)
+	cryptoCoinRepo := repository.NewCryptoCoinRepository(db)
+	instrumentRepo := repository.NewInstrumentRepository(db)
+	fxRateRepo := repository.NewFXRateRepository(db)
+	assetTxRepo := repository.NewAssetTransactionRepository(db)
+	recurringRuleRepo := repository.NewRecurringRuleRepository(db)
+	recurringOccurrenceRepo := repository.NewRecurringOccurrenceRepository(db)
+	budgetRepo := repository.NewBudgetRepository(db)
+	categoryRepo := repository.NewCategoryRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	uow := repository.NewUnitOfWork(db)
 
 	authSvc := service.NewAuthService(userRepo, cfg, c)
-	assetSvc := service.NewAssetService(assetRepo)
-	activitySvc := service.NewActivityService(expenseRepo, incomeRepo, debtRepo, receivableRepo)
-	expenseSvc := service.NewExpenseService(expenseRepo, assetRepo)
-	incomeSvc := service.NewIncomeService(incomeRepo, assetRepo)
+	instruments := service.NewInstrumentRegistry(instrumentRepo)
+	assetSvc := service.NewAssetService(assetRepo, instruments, uow)
+	activitySvc := service.NewActivityService(expenseRepo, incomeRepo, debtRepo, receivableRepo, transferRepo)
+	categorySvc := service.NewCategoryService(categoryRepo)
+	tagSvc := service.NewTagService(tagRepo)
+	expenseSvc := service.NewExpenseService(expenseRepo, assetRepo, uow)
+	incomeSvc := service.NewIncomeService(incomeRepo, assetRepo, uow)
+	recurringSvc := service.NewRecurringService(recurringRuleRepo, recurringOccurrenceRepo, assetRepo, categoryRepo, expenseSvc, incomeSvc, uow)
 	savingGoalSvc := service.NewSavingGoalService(savingGoalRepo)
-	debtSvc := service.NewDebtService(debtRepo, debtPaymentRepo, assetRepo)
-	receivableSvc := service.NewReceivableService(receivableRepo, receivablePaymentRepo, assetRepo)
-	priceSvc := service.NewPriceService(&cfg.PriceAPI, c)
+	debtSvc := service.NewDebtService(debtRepo, debtPaymentRepo, assetRepo, uow)
+	receivableSvc := service.NewReceivableService(receivableRepo, receivablePaymentRepo, assetRepo, uow)
+	priceHTTP := priceprovider.NewUpstream(priceprovider.NewHTTPClient(), priceprovider.UpstreamConfig{
+		RatePerMinute:    cfg.PriceAPI.RateLimits,
+		MaxRetries:       cfg.PriceAPI.MaxRetries,
+		BreakerThreshold: cfg.PriceAPI.BreakerThreshold,
+		BreakerCooldown:  time.Duration(cfg.PriceAPI.BreakerCooldown) * time.Second,
+	})
+	coinList := priceprovider.NewCoinList(cfg.PriceAPI.CoinGeckoAPI, priceHTTP, cryptoCoinRepo)
+	providers := []port.PriceProvider{
+		priceprovider.NewCoinGecko(cfg.PriceAPI.CoinGeckoAPI, priceHTTP, coinList),
+		priceprovider.NewYahoo(cfg.PriceAPI.StockAPI, priceHTTP, instruments.ProviderSymbol),
+		priceprovider.NewCoinMarketCap(cfg.PriceAPI.CryptoAPI, cfg.PriceAPI.CryptoAPIKey, priceHTTP),
+	}
+	if fixture := newFixtureProvider(&cfg.PriceAPI); fixture != nil {
+		providers = append(providers, fixture)
+	}
+	fxSvc := service.NewFXService(&cfg.PriceAPI, c, fxRateRepo, providers...)
+	assetTxSvc := service.NewAssetTransactionService(assetTxRepo, assetRepo, instruments, fxSvc, uow)
+	priceSvc := service.NewPriceService(&cfg.PriceAPI, c, instruments, fxSvc, providers...)
 	assetPriceHistorySvc := service.NewAssetPriceHistoryService(assetPriceHistoryRepo, assetRepo, priceSvc)
 	insightSvc := service.NewInsightService(insightRepo)
-	portfolioSvc := service.NewPortfolioService(assetRepo, priceSvc, assetPriceHistoryRepo)
-	performanceSvc := service.NewPerformanceService(assetRepo, priceSvc)
+	budgetSvc := service.NewBudgetService(budgetRepo, categoryRepo, insightRepo)
+	portfolioSvc := service.NewPortfolioService(assetRepo, priceSvc, assetPriceHistoryRepo, instruments, fxSvc)
+	performanceSvc := service.NewPerformanceService(assetRepo, assetTxRepo, incomeRepo, priceSvc, instruments, fxSvc)
+	ledgerSvc := service.NewLedgerService(ledgerRepo, assetRepo)
+	transferSvc := service.NewTransferService(transferRepo, assetRepo, priceSvc, uow)
+	overdueSvc := service.NewOverdueService(debtRepo, receivableRepo)
+	priceSnapshotSvc := service.NewPriceSnapshotService(assetRepo, assetPriceHistoryRepo, priceSvc)
 
 	authMiddleware := middleware.NewAuthMiddleware(cfg, c)
 
@@ -60,14 +107,21 @@ func New(ctx context.Context, cfg *config.Config, db *gorm.DB, c cache.Cache) *A
 		Activity:          handler.NewActivityHandler(activitySvc),
 		Expense:           handler.NewExpenseHandler(expenseSvc),
 		Income:            handler.NewIncomeHandler(incomeSvc),
+		Transfer:          handler.NewTransferHandler(transferSvc),
 		SavingGoal:        handler.NewSavingGoalHandler(savingGoalSvc),
 		Debt:              handler.NewDebtHandler(debtSvc),
 		Receivable:        handler.NewReceivableHandler(receivableSvc),
-		Price:             handler.NewPriceHandler(priceSvc),
+		Price:             handler.NewPriceHandler(priceSvc, fxSvc),
 		AssetPriceHistory: handler.NewAssetPriceHistoryHandler(assetPriceHistorySvc),
+		AssetTransaction:  handler.NewAssetTransactionHandler(assetTxSvc),
 		Insight:           handler.NewInsightHandler(insightSvc),
 		Portfolio:         handler.NewPortfolioHandler(portfolioSvc),
 		Performance:       handler.NewPerformanceHandler(performanceSvc),
+		Ledger:            handler.NewLedgerHandler(ledgerSvc),
+		Recurring:         handler.NewRecurringHandler(recurringSvc),
+		Budget:            handler.NewBudgetHandler(budgetSvc),
+		Category:          handler.NewCategoryHandler(categorySvc),
+		Tag:               handler.NewTagHandler(tagSvc),
 	}
 
 	router := routes.New(authMiddleware, handlers)
@@ -79,6 +133,10 @@ func New(ctx context.Context, cfg *config.Config, db *gorm.DB, c cache.Cache) *A
 		w.Write([]byte(`{"status":"ok"}`))
 	})
 
+	if cfg.App.Metrics {
+		mux.Handle("GET /debug/vars", expvar.Handler())
+	}
+
 	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
@@ -117,18 +175,102 @@ func New(ctx context.Context, cfg *config.Config, db *gorm.DB, c cache.Cache) *A
 			Handler: chain,
 		},
 	}
+	if cfg.Scheduler.Enabled {
+		app.scheduler = newScheduler(cfg, db, []scheduledJob{
+			{"overdue-sweep", "@hourly", func(ctx context.Context) error {
+				_, err := overdueSvc.SweepOverdue(ctx)
+				return err
+			}, scheduler.JobOptions{Timeout: 5 * time.Minute, RunOnStart: true}},
+			{"recurring-generate", "5 * * * *", func(ctx context.Context) error {
+				_, err := recurringSvc.GenerateDue(ctx)
+				return err
+			}, scheduler.JobOptions{Timeout: 10 * time.Minute, MaxAttempts: 3, Backoff: 5 * time.Minute}},
+			{"price-snapshot", "15 */6 * * *", func(ctx context.Context) error {
+				_, err := priceSnapshotSvc.SnapshotPrices(ctx)
+				return err
+			}, scheduler.JobOptions{Timeout: 15 * time.Minute, MaxAttempts: 3, Backoff: 5 * time.Minute}},
+			{"coin-list-refresh", "30 3 * * *", func(ctx context.Context) error {
+				_, err := coinList.RefreshCoinList(ctx)
+				return err
+			}, scheduler.JobOptions{Timeout: 5 * time.Minute, MaxAttempts: 3, Backoff: 10 * time.Minute}},
+			{"fx-refresh", "0 */6 * * *", func(ctx context.Context) error {
+				_, err := fxSvc.RefreshRates(ctx)
+				return err
+			}, scheduler.JobOptions{Timeout: 5 * time.Minute, MaxAttempts: 3, Backoff: 5 * time.Minute}},
+		})
+	}
 
 	return app
 }
 
+type scheduledJob struct {
+	name string
+	spec string
+	fn   scheduler.JobFunc
+	opts scheduler.JobOptions
+}
+
+// newFixtureProvider returns the offline fixture provider when fixtures or the random walk are configured.
+// Like the other providers it answers a kind only when that kind's PRICE_PROVIDERS_* list names "fixture" (or is empty).
+func newFixtureProvider(cfg *config.PriceAPIConfig) port.PriceProvider {
+	if cfg.FixturesDir == "" && !cfg.FixturesRandomWalk {
+		return nil
+	}
+	data := &priceprovider.Fixtures{}
+	if cfg.FixturesDir != "" {
+		loaded, err := priceprovider.LoadFixtures(cfg.FixturesDir)
+		if err != nil {
+			slog.Error("price fixtures: load", "dir", cfg.FixturesDir, "error", err)
+		} else {
+			data = loaded
+			slog.Info("price fixtures loaded", "dir", cfg.FixturesDir, "quotes", len(data.Quotes), "series", len(data.Series), "fx", len(data.FX))
+		}
+	}
+	return priceprovider.NewFixture(data, cfg.FixturesRandomWalk, cfg.FixturesSeed)
+}
+
+// newScheduler registers the background jobs. It returns nil (jobs disabled) if the scheduler cannot be set up.
+func newScheduler(cfg *config.Config, db *gorm.DB, jobs []scheduledJob) *scheduler.Scheduler {
+	sqlDB, err := db.DB()
+	if err != nil {
+		slog.Error("scheduler disabled: get sql db", "error", err)
+		return nil
+	}
+	s := scheduler.New(
+		repository.NewJobRepository(db),
+		repository.NewAdvisoryLockElector(sqlDB, schedulerLockKey),
+		scheduler.Config{
+			PollInterval: time.Duration(cfg.Scheduler.PollInterval) * time.Second,
+			Concurrency:  cfg.Scheduler.Concurrency,
+		},
+	)
+	for _, j := range jobs {
+		if err := s.Register(j.name, j.spec, j.fn, j.opts); err != nil {
+			slog.Error("scheduler disabled: register job", "job", j.name, "error", err)
+			return nil
+		}
+	}
+	return s
+}
+
 func (a *App) Run() error {
+	if a.scheduler != nil {
+		a.scheduler.Start()
+	}
 	slog.Info("server listening", "port", a.cfg.App.Port, "env", a.cfg.App.Env)
 	return a.srv.ListenAndServe()
 }
 
+// Shutdown stops accepting requests, then lets in-flight background jobs drain until ctx expires.
 func (a *App) Shutdown(ctx context.Context) error {
+	var err error
 	if a.srv != nil {
-		return a.srv.Shutdown(ctx)
+		err = a.srv.Shutdown(ctx)
+	}
+	if a.scheduler != nil {
+		if jobErr := a.scheduler.Shutdown(ctx); jobErr != nil && err == nil {
+			err = jobErr
+		}
 	}
-	return nil
+	return err
 }
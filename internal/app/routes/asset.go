@@ -10,4 +10,13 @@ func (r *Router) registerAssetRoutes() {
 	r.mux.HandleFunc("GET "+APIPrefix+"/assets/{uuid}/prices", r.auth.RequireAuth(r.h.AssetPriceHistory.GetPriceHistory))
 	r.mux.HandleFunc("POST "+APIPrefix+"/assets/{uuid}/prices", r.auth.RequireAuth(r.h.AssetPriceHistory.RecordPrice))
 	r.mux.HandleFunc("POST "+APIPrefix+"/assets/{uuid}/prices/fetch", r.auth.RequireAuth(r.h.AssetPriceHistory.FetchAndRecordPrice))
+
+	r.mux.HandleFunc("POST "+APIPrefix+"/assets/{uuid}/sell", r.auth.RequireAuth(r.h.AssetTransaction.Sell))
+	r.mux.HandleFunc("GET "+APIPrefix+"/assets/{uuid}/transactions", r.auth.RequireAuth(r.h.AssetTransaction.List))
+	r.mux.HandleFunc("POST "+APIPrefix+"/assets/{uuid}/transactions", r.auth.RequireAuth(r.h.AssetTransaction.Create))
+	r.mux.HandleFunc("GET "+APIPrefix+"/assets/{uuid}/transactions/{txUuid}", r.auth.RequireAuth(r.h.AssetTransaction.Get))
+	r.mux.HandleFunc("PUT "+APIPrefix+"/assets/{uuid}/transactions/{txUuid}", r.auth.RequireAuth(r.h.AssetTransaction.Update))
+	r.mux.HandleFunc("DELETE "+APIPrefix+"/assets/{uuid}/transactions/{txUuid}", r.auth.RequireAuth(r.h.AssetTransaction.Delete))
+
+	r.mux.HandleFunc("GET "+APIPrefix+"/assets/{uuid}/ledger", r.auth.RequireAuth(r.h.Ledger.GetAssetLedger))
 }
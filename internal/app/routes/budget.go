@@ -0,0 +1,10 @@
+package routes
+
+func (r *Router) registerBudgetRoutes() {
+	r.mux.HandleFunc("POST "+APIPrefix+"/budgets", r.auth.RequireAuth(r.h.Budget.Create))
+	r.mux.HandleFunc("GET "+APIPrefix+"/budgets", r.auth.RequireAuth(r.h.Budget.List))
+	r.mux.HandleFunc("GET "+APIPrefix+"/budgets/status", r.auth.RequireAuth(r.h.Budget.Status))
+	r.mux.HandleFunc("GET "+APIPrefix+"/budgets/{uuid}", r.auth.RequireAuth(r.h.Budget.Get))
+	r.mux.HandleFunc("PUT "+APIPrefix+"/budgets/{uuid}", r.auth.RequireAuth(r.h.Budget.Update))
+	r.mux.HandleFunc("DELETE "+APIPrefix+"/budgets/{uuid}", r.auth.RequireAuth(r.h.Budget.Delete))
+}
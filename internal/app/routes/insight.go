@@ -3,4 +3,5 @@ package routes
 func (r *Router) registerInsightRoutes() {
 	r.mux.HandleFunc("GET "+APIPrefix+"/insights/cashflow", r.auth.RequireAuth(r.h.Insight.GetCashflow))
 	r.mux.HandleFunc("GET "+APIPrefix+"/insights/overview", r.auth.RequireAuth(r.h.Insight.GetOverview))
+	r.mux.HandleFunc("GET "+APIPrefix+"/insights/tags", r.auth.RequireAuth(r.h.Insight.GetTags))
 }
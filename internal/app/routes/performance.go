@@ -2,5 +2,6 @@ package routes
 
 func (r *Router) registerPerformanceRoutes() {
 	r.mux.HandleFunc("GET "+APIPrefix+"/assets/{uuid}/performance", r.auth.RequireAuth(r.h.Performance.GetAssetPerformance))
+	r.mux.HandleFunc("GET "+APIPrefix+"/assets/{uuid}/income", r.auth.RequireAuth(r.h.Performance.GetAssetIncome))
 	r.mux.HandleFunc("GET "+APIPrefix+"/portfolio/performance", r.auth.RequireAuth(r.h.Performance.GetPortfolioPerformance))
 }
@@ -0,0 +1,13 @@
+package routes
+
+func (r *Router) registerRecurringRoutes() {
+	r.mux.HandleFunc("POST "+APIPrefix+"/recurring", r.auth.RequireAuth(r.h.Recurring.Create))
+	r.mux.HandleFunc("GET "+APIPrefix+"/recurring", r.auth.RequireAuth(r.h.Recurring.List))
+	r.mux.HandleFunc("GET "+APIPrefix+"/recurring/upcoming", r.auth.RequireAuth(r.h.Recurring.Upcoming))
+	r.mux.HandleFunc("GET "+APIPrefix+"/recurring/{uuid}", r.auth.RequireAuth(r.h.Recurring.Get))
+	r.mux.HandleFunc("PUT "+APIPrefix+"/recurring/{uuid}", r.auth.RequireAuth(r.h.Recurring.Update))
+	r.mux.HandleFunc("DELETE "+APIPrefix+"/recurring/{uuid}", r.auth.RequireAuth(r.h.Recurring.Delete))
+	r.mux.HandleFunc("GET "+APIPrefix+"/recurring/{uuid}/occurrences", r.auth.RequireAuth(r.h.Recurring.ListOccurrences))
+	r.mux.HandleFunc("PUT "+APIPrefix+"/recurring/{uuid}/occurrences/{date}", r.auth.RequireAuth(r.h.Recurring.UpdateOccurrence))
+	r.mux.HandleFunc("POST "+APIPrefix+"/recurring/{uuid}/occurrences/{date}/confirm", r.auth.RequireAuth(r.h.Recurring.ConfirmOccurrence))
+}
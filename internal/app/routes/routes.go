@@ -15,14 +15,21 @@ type Handlers struct {
 	Activity          *handler.ActivityHandler
 	Expense           *handler.ExpenseHandler
 	Income            *handler.IncomeHandler
+	Transfer          *handler.TransferHandler
 	SavingGoal        *handler.SavingGoalHandler
 	Debt              *handler.DebtHandler
 	Receivable        *handler.ReceivableHandler
 	Price             *handler.PriceHandler
 	AssetPriceHistory *handler.AssetPriceHistoryHandler
+	AssetTransaction  *handler.AssetTransactionHandler
 	Insight           *handler.InsightHandler
 	Portfolio         *handler.PortfolioHandler
 	Performance       *handler.PerformanceHandler
+	Ledger            *handler.LedgerHandler
+	Recurring         *handler.RecurringHandler
+	Budget            *handler.BudgetHandler
+	Category          *handler.CategoryHandler
+	Tag               *handler.TagHandler
 }
 
 type Router struct {
@@ -43,8 +50,13 @@ func (r *Router) Setup() *http.ServeMux {
 	r.registerAuthRoutes()
 	r.registerAssetRoutes()
 	r.registerActivityRoutes()
+	r.registerCategoryRoutes()
+	r.registerTagRoutes()
 	r.registerExpenseRoutes()
 	r.registerIncomeRoutes()
+	r.registerRecurringRoutes()
+	r.registerBudgetRoutes()
+	r.registerTransferRoutes()
 	r.registerSavingGoalRoutes()
 	r.registerDebtRoutes()
 	r.registerReceivableRoutes()
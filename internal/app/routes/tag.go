@@ -0,0 +1,7 @@
+package routes
+
+func (r *Router) registerTagRoutes() {
+	r.mux.HandleFunc("GET "+APIPrefix+"/tags", r.auth.RequireAuth(r.h.Tag.List))
+	r.mux.HandleFunc("PUT "+APIPrefix+"/tags/{uuid}", r.auth.RequireAuth(r.h.Tag.Update))
+	r.mux.HandleFunc("DELETE "+APIPrefix+"/tags/{uuid}", r.auth.RequireAuth(r.h.Tag.Delete))
+}
@@ -0,0 +1,9 @@
+package routes
+
+func (r *Router) registerTransferRoutes() {
+	r.mux.HandleFunc("POST "+APIPrefix+"/transfers", r.auth.RequireAuth(r.h.Transfer.Create))
+	r.mux.HandleFunc("GET "+APIPrefix+"/transfers", r.auth.RequireAuth(r.h.Transfer.List))
+	r.mux.HandleFunc("GET "+APIPrefix+"/transfers/{uuid}", r.auth.RequireAuth(r.h.Transfer.Get))
+	r.mux.HandleFunc("PUT "+APIPrefix+"/transfers/{uuid}", r.auth.RequireAuth(r.h.Transfer.Update))
+	r.mux.HandleFunc("DELETE "+APIPrefix+"/transfers/{uuid}", r.auth.RequireAuth(r.h.Transfer.Delete))
+}
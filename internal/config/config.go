@@ -10,6 +10,7 @@ type Config struct {
 	PriceAPI  PriceAPIConfig
 	RateLimit RateLimitConfig
 	Security  SecurityConfig
+	Scheduler SchedulerConfig
 }
 
 type RedisConfig struct {
@@ -40,16 +41,49 @@ type SecurityConfig struct {
 	CORSAllowedOrigins string // comma-separated, e.g. "https://app.example.com,https://admin.example.com"
 }
 
+type SchedulerConfig struct {
+	Enabled      bool
+	PollInterval int // in seconds
+	Concurrency  int // jobs run at once per instance
+}
+
 type PriceAPIConfig struct {
-	CryptoAPI    string
-	CryptoAPIKey string
+	CryptoAPI    string // CoinMarketCap base URL
+	CryptoAPIKey string // CoinMarketCap API key; the provider is skipped without one
+	CoinGeckoAPI string
 	StockAPI     string
-	CacheTTL     int // in seconds
+	CacheTTL     int // in seconds; quotes younger than this are fresh
+	// CacheStaleTTL is how long (seconds) a quote past CacheTTL is still served, flagged stale, while it is
+	// refreshed in the background. LastGoodTTL is how long it is kept to answer when every provider fails.
+	CacheStaleTTL int
+	LastGoodTTL   int
+
+	// Upstream client: requests per minute by provider name, retries, and circuit breaker settings.
+	RateLimits       map[string]int
+	MaxRetries       int
+	BreakerThreshold int
+	BreakerCooldown  int // in seconds
+
+	// FXCurrencies are refreshed against USD by the fx-refresh job, besides those already in fx_rates.
+	FXCurrencies []string
+
+	// Offline fixture provider ("fixture"): quotes, candles and FX rates read from FixturesDir, and an
+	// optional seeded random walk for symbols the fixtures do not cover.
+	FixturesDir        string
+	FixturesRandomWalk bool
+	FixturesSeed       int64
+
+	// Provider fallback order per kind (provider names); empty uses every provider that supports the kind.
+	CryptoProviders []string
+	StockProviders  []string
+	FXProviders     []string
 }
 
 type AppConfig struct {
 	Env  string
 	Port string
+	// Metrics exposes expvar counters (upstream price API requests, errors, latency) at GET /debug/vars.
+	Metrics bool
 }
 
 type DatabaseConfig struct {
@@ -75,14 +109,27 @@ func Load() (*Config, error) {
 	maxOpen, _ := strconv.Atoi(getEnv("DATABASE_MAX_OPEN_CONNECTIONS", "10"))
 	maxIdle, _ := strconv.Atoi(getEnv("DATABASE_MAX_IDLE_CONNECTIONS", "10"))
 	cacheTTL, _ := strconv.Atoi(getEnv("REDIS_TTL_PRICE", "60"))
+	cacheStaleTTL, _ := strconv.Atoi(getEnv("PRICE_CACHE_STALE_TTL", "900"))
+	lastGoodTTL, _ := strconv.Atoi(getEnv("PRICE_LAST_GOOD_TTL", "604800"))
+	upstreamRetries, _ := strconv.Atoi(getEnv("PRICE_API_MAX_RETRIES", "2"))
+	breakerThreshold, _ := strconv.Atoi(getEnv("PRICE_API_BREAKER_THRESHOLD", "5"))
+	breakerCooldown, _ := strconv.Atoi(getEnv("PRICE_API_BREAKER_COOLDOWN", "60"))
+	fixturesWalk, _ := strconv.ParseBool(getEnv("PRICE_FIXTURES_RANDOM_WALK", "false"))
+	fixturesSeed, _ := strconv.ParseInt(getEnv("PRICE_FIXTURES_SEED", "1"), 10, 64)
+	metricsEnabled, _ := strconv.ParseBool(getEnv("METRICS_ENABLED", "false"))
 	rateLimitTTL, _ := strconv.Atoi(getEnv("RATE_LIMIT_TTL", "60"))
 	rateLimitLimit, _ := strconv.Atoi(getEnv("RATE_LIMIT_LIMIT", "100"))
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
+	schedulerEnabled, _ := strconv.ParseBool(getEnv("SCHEDULER_ENABLED", "true"))
+	schedulerPoll, _ := strconv.Atoi(getEnv("SCHEDULER_POLL_INTERVAL", "15"))
+	schedulerConcurrency, _ := strconv.Atoi(getEnv("SCHEDULER_CONCURRENCY", "4"))
 
 	return &Config{
 		App: AppConfig{
 			Env:  getEnv("APP_ENV", "development"),
 			Port: getEnv("APP_PORT", "8080"),
+
+			Metrics: metricsEnabled,
 		},
 		Database: DatabaseConfig{
 			Host:               getEnv("DATABASE_HOST", "localhost"),
@@ -107,10 +154,28 @@ func Load() (*Config, error) {
 			RefreshExpiration: getEnv("JWT_REFRESH_EXPIRATION_TIME", "168h"), // 7d default
 		},
 		PriceAPI: PriceAPIConfig{
-			CryptoAPI:    getEnv("CRYPTO_PRICE_API", "https://pro-api.coinmarketcap.com"),
-			CryptoAPIKey: getEnv("CRYPTO_PRICE_API_KEY", ""),
-			StockAPI:     getEnv("STOCK_PRICE_API", "https://query1.finance.yahoo.com"),
-			CacheTTL:     cacheTTL,
+			CryptoAPI:     getEnv("CRYPTO_PRICE_API", "https://pro-api.coinmarketcap.com"),
+			CryptoAPIKey:  getEnv("CRYPTO_PRICE_API_KEY", ""),
+			CoinGeckoAPI:  getEnv("COINGECKO_API", "https://api.coingecko.com/api/v3"),
+			StockAPI:      getEnv("STOCK_PRICE_API", "https://query1.finance.yahoo.com"),
+			CacheTTL:      cacheTTL,
+			CacheStaleTTL: cacheStaleTTL,
+			LastGoodTTL:   lastGoodTTL,
+
+			RateLimits:       getEnvRates("PRICE_API_RATE_LIMITS", "coingecko=25,coinmarketcap=30,yahoo=120"),
+			MaxRetries:       upstreamRetries,
+			BreakerThreshold: breakerThreshold,
+			BreakerCooldown:  breakerCooldown,
+
+			FXCurrencies: getEnvList("PRICE_FX_CURRENCIES", "IDR,EUR,GBP,JPY,SGD,AUD"),
+
+			FixturesDir:        getEnv("PRICE_FIXTURES_DIR", ""),
+			FixturesRandomWalk: fixturesWalk,
+			FixturesSeed:       fixturesSeed,
+
+			CryptoProviders: getEnvList("PRICE_PROVIDERS_CRYPTO", "coingecko,coinmarketcap"),
+			StockProviders:  getEnvList("PRICE_PROVIDERS_STOCK", "yahoo"),
+			FXProviders:     getEnvList("PRICE_PROVIDERS_FX", "yahoo"),
 		},
 		RateLimit: RateLimitConfig{
 			TTLSeconds: rateLimitTTL,
@@ -119,6 +184,11 @@ func Load() (*Config, error) {
 		Security: SecurityConfig{
 			CORSAllowedOrigins: getEnv("CORS_ALLOWED_ORIGINS", "*"),
 		},
+		Scheduler: SchedulerConfig{
+			Enabled:      schedulerEnabled,
+			PollInterval: schedulerPoll,
+			Concurrency:  schedulerConcurrency,
+		},
 	}, nil
 }
 
@@ -3,6 +3,8 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -22,3 +24,30 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+// getEnvList reads a comma-separated variable, trimming spaces and dropping empty items.
+func getEnvList(key, fallback string) []string {
+	var out []string
+	for _, item := range strings.Split(getEnv(key, fallback), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// getEnvRates reads a comma-separated list of name=number pairs (e.g. "coingecko=25,yahoo=120");
+// malformed items are skipped with a warning.
+func getEnvRates(key, fallback string) map[string]int {
+	out := make(map[string]int)
+	for _, item := range getEnvList(key, fallback) {
+		name, value, ok := strings.Cut(item, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || err != nil || n < 0 {
+			log.Printf("warning: %s: ignoring %q", key, item)
+			continue
+		}
+		out[strings.ToLower(strings.TrimSpace(name))] = n
+	}
+	return out
+}
@@ -8,20 +8,26 @@ import (
 )
 
 type ActivityService interface {
-	ListActivities(ctx context.Context, userID int64, groupBy string, dateFilter string, timezone string) (*ActivityResponse, error)
+	// ListActivities with tags keeps the items carrying every one of them; transfers carry no tags.
+	ListActivities(ctx context.Context, userID int64, groupBy string, dateFilter string, timezone string, tags []string) (*ActivityResponse, error)
 }
 
-// ActivityItem is one entry in a group: income, expense, debt, or receivable, in chronological order.
+// ActivityItem is one entry in a group: income, expense, debt, receivable, or transfer, in chronological order.
 type ActivityItem struct {
-	Type      string          `json:"type"` // "income", "expense", "debt", "receivable"
-	UUID      string          `json:"uuid"`
-	Amount    decimal.Decimal `json:"amount"`
-	Date      time.Time       `json:"date"`
-	CreatedAt time.Time       `json:"createdAt"`
-	Note      *string         `json:"note,omitempty"`
-	Source    string          `json:"source,omitempty"`    // income only
-	Category  string          `json:"category,omitempty"`  // expense only
-	PartyName string          `json:"partyName,omitempty"` // debt / receivable only
+	Type         string          `json:"type"` // "income", "expense", "debt", "receivable", "transfer"
+	UUID         string          `json:"uuid"`
+	Amount       decimal.Decimal `json:"amount"`
+	Date         time.Time       `json:"date"`
+	CreatedAt    time.Time       `json:"createdAt"`
+	Note         *string         `json:"note,omitempty"`
+	Source       string          `json:"source,omitempty"`       // income only
+	Category     string          `json:"category,omitempty"`     // expense only: the category name
+	CategoryUUID string          `json:"categoryUuid,omitempty"` // expense only
+	PartyName    string          `json:"partyName,omitempty"`    // debt / receivable only
+	Tags         []string        `json:"tags,omitempty"`         // tag names; not on transfers
+
+	FromAssetUUID string `json:"fromAssetUuid,omitempty"` // transfer only
+	ToAssetUUID   string `json:"toAssetUuid,omitempty"`   // transfer only
 }
 
 type ActivityGroup struct {
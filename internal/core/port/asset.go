@@ -9,7 +9,11 @@ type AssetRepository interface {
 	Create(ctx context.Context, asset *models.Asset) error
 	GetByID(ctx context.Context, id int64) (*models.Asset, error)
 	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Asset, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*models.Asset, error)
+	GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Asset, error)
 	ListByUserID(ctx context.Context, userID int64, page, limit int) ([]models.Asset, int64, error)
+	// ListActiveWithSymbol returns every user's ACTIVE assets of the given types that have a symbol.
+	ListActiveWithSymbol(ctx context.Context, types []models.AssetType) ([]models.Asset, error)
 	Update(ctx context.Context, asset *models.Asset) error
 	Delete(ctx context.Context, uuid string, userID int64) error
 }
@@ -27,12 +31,16 @@ type CreateAssetRequest struct {
 	Type     models.AssetType
 	Quantity float64 // Keeping as float64 for JSON request convenience, will convert to decimal
 	Symbol   *string
+	// PriceProviderID optionally pins the price provider's ID for Symbol (CoinGecko coin ID for CRYPTO).
+	PriceProviderID *string
 
 	// Purchase Information (required for new assets)
 	PurchasePrice    float64
 	PurchaseDate     string // ISO 8601 format
 	PurchaseCurrency string
 	TotalCost        float64
+	// CostMethod matches sales against purchase lots: FIFO (default) or AVERAGE.
+	CostMethod string
 
 	// Additional Costs (optional)
 	TransactionFee  *float64
@@ -59,12 +67,15 @@ type UpdateAssetRequest struct {
 	Type     *models.AssetType
 	Quantity *float64
 	Symbol   *string
+	// PriceProviderID pins the price provider's ID for Symbol (CoinGecko coin ID for CRYPTO); empty clears it.
+	PriceProviderID *string
 
 	// Purchase Information
 	PurchasePrice    *float64
 	PurchaseDate     *string
 	PurchaseCurrency *string
 	TotalCost        *float64
+	CostMethod       *string
 
 	// Additional Costs
 	TransactionFee  *float64
@@ -10,6 +10,10 @@ type AssetPriceHistoryRepository interface {
 	Create(ctx context.Context, history *models.AssetPriceHistory) error
 	ListByAssetID(ctx context.Context, assetID int64, limit int) ([]models.AssetPriceHistory, error)
 	GetLatestByAssetID(ctx context.Context, assetID int64) (*models.AssetPriceHistory, error)
+	// GetLatestByAssetIDs returns the latest price of each asset that has one, keyed by asset ID.
+	GetLatestByAssetIDs(ctx context.Context, assetIDs []int64) (map[int64]*models.AssetPriceHistory, error)
+	// UpsertSnapshots writes daily snapshot rows, replacing the row an asset already has for the same SnapshotDate.
+	UpsertSnapshots(ctx context.Context, histories []models.AssetPriceHistory) error
 }
 
 type AssetPriceHistoryService interface {
@@ -18,6 +22,19 @@ type AssetPriceHistoryService interface {
 	FetchAndRecordPrice(ctx context.Context, userID int64, assetUUID string) (*models.AssetPriceHistory, error)
 }
 
+// PriceSnapshotService records a daily price for every ACTIVE CRYPTO/STOCK asset with a symbol.
+type PriceSnapshotService interface {
+	SnapshotPrices(ctx context.Context) (*PriceSnapshotResult, error)
+}
+
+// PriceSnapshotResult summarises one snapshot run. Quotes counts the distinct symbol/currency lookups.
+type PriceSnapshotResult struct {
+	Assets   int `json:"assets"`
+	Quotes   int `json:"quotes"`
+	Recorded int `json:"recorded"`
+	Failed   int `json:"failed"`
+}
+
 type RecordPriceRequest struct {
 	Price      float64   `json:"price"`
 	Source     string    `json:"source"`
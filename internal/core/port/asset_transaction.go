@@ -0,0 +1,81 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+type AssetTransactionRepository interface {
+	Create(ctx context.Context, tx *models.AssetTransaction) error
+	GetByUUID(ctx context.Context, assetID int64, uuid string) (*models.AssetTransaction, error)
+	// ListByAssetID returns the asset's transactions in the order they are replayed: by date, then by ID.
+	ListByAssetID(ctx context.Context, assetID int64) ([]models.AssetTransaction, error)
+	// ListByUserID returns every transaction of the user's assets in replay order.
+	ListByUserID(ctx context.Context, userID int64) ([]models.AssetTransaction, error)
+	Update(ctx context.Context, tx *models.AssetTransaction) error
+	Delete(ctx context.Context, assetID int64, uuid string) error
+}
+
+// AssetTransactionService records the buys, sells, transfers and fees of non-cash assets. Every change
+// replays the asset's transactions and re-derives its quantity and cost basis.
+type AssetTransactionService interface {
+	ListTransactions(ctx context.Context, userID int64, assetUUID string) ([]models.AssetTransaction, error)
+	GetTransaction(ctx context.Context, userID int64, assetUUID, txUUID string) (*models.AssetTransaction, error)
+	CreateTransaction(ctx context.Context, userID int64, assetUUID string, req CreateAssetTransactionRequest) (*models.AssetTransaction, error)
+	UpdateTransaction(ctx context.Context, userID int64, assetUUID, txUUID string, req UpdateAssetTransactionRequest) (*models.AssetTransaction, error)
+	DeleteTransaction(ctx context.Context, userID int64, assetUUID, txUUID string) error
+	// SellAsset records a SELL of part or all of the position and credits the proceeds to a CASH asset.
+	SellAsset(ctx context.Context, userID int64, assetUUID string, req SellAssetRequest) (*AssetSaleResponse, error)
+}
+
+type SellAssetRequest struct {
+	Quantity *float64 `json:"quantity,omitempty"` // defaults to the whole position
+	// PricePerUnit or TotalAmount (gross proceeds in the asset's currency) is required.
+	PricePerUnit *float64   `json:"pricePerUnit,omitempty"`
+	TotalAmount  *float64   `json:"totalAmount,omitempty"`
+	Fee          float64    `json:"fee,omitempty"`
+	Date         *time.Time `json:"date,omitempty"` // defaults to now
+	// CashAssetUUID receives TotalAmount - Fee, converted at ExchangeRate (default: the rate on Date) when
+	// it is held in another currency. Without it the sale moves no cash.
+	CashAssetUUID *string  `json:"cashAssetUuid,omitempty"`
+	ExchangeRate  *float64 `json:"exchangeRate,omitempty"`
+	Notes         *string  `json:"notes,omitempty"`
+}
+
+type AssetSaleResponse struct {
+	Asset       *models.Asset            `json:"asset"`
+	Transaction *models.AssetTransaction `json:"transaction"`
+	// RealizedProfitLoss is this sale's proceeds less fee and the cost basis of the units sold, in the
+	// asset's currency.
+	RealizedProfitLoss decimal.Decimal `json:"realizedProfitLoss"`
+	CashAmount         decimal.Decimal `json:"cashAmount"` // credited to the CASH asset, in its currency
+	CashCurrency       string          `json:"cashCurrency,omitempty"`
+}
+
+type CreateAssetTransactionRequest struct {
+	Type         models.AssetTransactionType `json:"type"`
+	Quantity     float64                     `json:"quantity"`
+	PricePerUnit float64                     `json:"pricePerUnit"`
+	// PricePerUnit is per share; TotalAmount defaults to quantity × lot size × pricePerUnit (see
+	// InstrumentRegistry.LotSize).
+	TotalAmount *float64 `json:"totalAmount,omitempty"`
+	Fee         float64  `json:"fee,omitempty"`
+	// Currency must be the asset's purchase currency; it defaults to it.
+	Currency        string     `json:"currency,omitempty"`
+	TransactionDate *time.Time `json:"transactionDate,omitempty"` // defaults to now
+	Notes           *string    `json:"notes,omitempty"`
+}
+
+type UpdateAssetTransactionRequest struct {
+	Type            *models.AssetTransactionType `json:"type,omitempty"`
+	Quantity        *float64                     `json:"quantity,omitempty"`
+	PricePerUnit    *float64                     `json:"pricePerUnit,omitempty"`
+	TotalAmount     *float64                     `json:"totalAmount,omitempty"`
+	Fee             *float64                     `json:"fee,omitempty"`
+	TransactionDate *time.Time                   `json:"transactionDate,omitempty"`
+	Notes           *string                      `json:"notes,omitempty"`
+}
@@ -0,0 +1,77 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+type BudgetRepository interface {
+	Create(ctx context.Context, budget *models.Budget) error
+	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Budget, error)
+	// FindByCategory returns the user's ROLLING budget for the category, or its MONTHLY budget for month.
+	FindByCategory(ctx context.Context, userID int64, categoryID int64, period models.BudgetPeriod, month time.Time) (*models.Budget, error)
+	ListByUserID(ctx context.Context, userID int64, page, limit int) ([]models.Budget, int64, error)
+	// ListForMonth returns the budgets that apply to the month starting at month: MONTHLY ones for it and
+	// ROLLING ones started by then.
+	ListForMonth(ctx context.Context, userID int64, month time.Time) ([]models.Budget, error)
+	Update(ctx context.Context, budget *models.Budget) error
+	Delete(ctx context.Context, uuid string, userID int64) error
+}
+
+type BudgetService interface {
+	CreateBudget(ctx context.Context, userID int64, req CreateBudgetRequest) (*models.Budget, error)
+	GetBudget(ctx context.Context, userID int64, uuid string) (*models.Budget, error)
+	ListBudgets(ctx context.Context, userID int64, page, limit int) ([]models.Budget, ListMeta, error)
+	UpdateBudget(ctx context.Context, userID int64, uuid string, req UpdateBudgetRequest) (*models.Budget, error)
+	DeleteBudget(ctx context.Context, userID int64, uuid string) error
+	// GetStatus reports the spending against every budget of month (YYYY-MM, default current month).
+	GetStatus(ctx context.Context, userID int64, month string) (*BudgetStatusResponse, error)
+}
+
+// CreateBudgetRequest takes the category by CategoryUUID, or by Category, the code of a default category.
+type CreateBudgetRequest struct {
+	CategoryUUID *string                `json:"categoryUuid,omitempty"`
+	Category     models.ExpenseCategory `json:"category,omitempty"`
+	Period       models.BudgetPeriod    `json:"period"` // default MONTHLY
+	Month        string                 `json:"month"`  // YYYY-MM; default current month
+	Amount       float64                `json:"amount"`
+	Rollover     bool                   `json:"rollover,omitempty"` // ROLLING only
+}
+
+type UpdateBudgetRequest struct {
+	Amount   *float64 `json:"amount,omitempty"`
+	Rollover *bool    `json:"rollover,omitempty"`
+}
+
+type BudgetStatusResponse struct {
+	Month          string          `json:"month"` // YYYY-MM
+	DaysElapsed    int             `json:"daysElapsed"`
+	DaysInMonth    int             `json:"daysInMonth"`
+	TotalBudget    decimal.Decimal `json:"totalBudget"`
+	TotalSpent     decimal.Decimal `json:"totalSpent"`
+	TotalRemaining decimal.Decimal `json:"totalRemaining"`
+	Budgets        []BudgetStatus  `json:"budgets"`
+}
+
+// BudgetStatus is one budget in a month; a top-level category's spending includes its subcategories.
+// Available is Amount plus what earlier months rolled over;
+// Remaining goes negative once it is overspent. Projected extends the spending pace so far to the end of
+// the month, and ProjectedOverspend is how far that would go over Available.
+type BudgetStatus struct {
+	BudgetUUID         string              `json:"budgetUuid"`
+	CategoryUUID       string              `json:"categoryUuid"`
+	Category           string              `json:"category"` // the category name
+	Period             models.BudgetPeriod `json:"period"`
+	Amount             decimal.Decimal     `json:"amount"`
+	RolledOver         decimal.Decimal     `json:"rolledOver"`
+	Available          decimal.Decimal     `json:"available"`
+	Spent              decimal.Decimal     `json:"spent"`
+	Remaining          decimal.Decimal     `json:"remaining"`
+	Percent            float64             `json:"percent"`
+	Projected          decimal.Decimal     `json:"projected"`
+	ProjectedOverspend decimal.Decimal     `json:"projectedOverspend"`
+}
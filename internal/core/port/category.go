@@ -0,0 +1,49 @@
+package port
+
+import (
+	"context"
+
+	"monity/internal/models"
+)
+
+type CategoryRepository interface {
+	Create(ctx context.Context, category *models.Category) error
+	// CreateDefaults inserts the given default categories the user does not have yet (matched by Code).
+	CreateDefaults(ctx context.Context, userID int64, defaults []models.Category) error
+	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Category, error)
+	GetByID(ctx context.Context, id int64) (*models.Category, error)
+	GetByCode(ctx context.Context, userID int64, code string) (*models.Category, error)
+	// GetByName finds a category by name, case-insensitively, among the children of parentID (top level if nil).
+	GetByName(ctx context.Context, userID int64, parentID *int64, name string) (*models.Category, error)
+	// ListByUserID returns the user's categories, parents before their children, with archived ones if asked.
+	ListByUserID(ctx context.Context, userID int64, includeArchived bool) ([]models.Category, error)
+	CountChildren(ctx context.Context, id int64) (int64, error)
+	// InUse reports whether an expense, expense split, recurring rule or budget refers to the category.
+	InUse(ctx context.Context, id int64) (bool, error)
+	Update(ctx context.Context, category *models.Category) error
+	Delete(ctx context.Context, id int64) error
+}
+
+type CategoryService interface {
+	CreateCategory(ctx context.Context, userID int64, req CreateCategoryRequest) (*models.Category, error)
+	GetCategory(ctx context.Context, userID int64, uuid string) (*models.Category, error)
+	ListCategories(ctx context.Context, userID int64, includeArchived bool) ([]models.Category, error)
+	UpdateCategory(ctx context.Context, userID int64, uuid string, req UpdateCategoryRequest) (*models.Category, error)
+	// DeleteCategory removes an unused custom category; used and default ones can be archived instead.
+	DeleteCategory(ctx context.Context, userID int64, uuid string) error
+}
+
+type CreateCategoryRequest struct {
+	Name       string  `json:"name"`
+	ParentUUID *string `json:"parentUuid,omitempty"` // a top-level category
+	Icon       *string `json:"icon,omitempty"`
+	Color      *string `json:"color,omitempty"` // #RRGGBB
+}
+
+type UpdateCategoryRequest struct {
+	Name       *string `json:"name,omitempty"`
+	ParentUUID *string `json:"parentUuid,omitempty"` // empty string moves it to the top level
+	Icon       *string `json:"icon,omitempty"`
+	Color      *string `json:"color,omitempty"`
+	Archived   *bool   `json:"archived,omitempty"`
+}
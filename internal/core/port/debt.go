@@ -9,24 +9,39 @@ import (
 type DebtRepository interface {
 	Create(ctx context.Context, debt *models.Debt) error
 	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Debt, error)
-	ListByUserID(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, page, limit int) ([]models.Debt, int64, error)
+	GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Debt, error)
+	ListByUserID(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, tags []string, page, limit int) ([]models.Debt, int64, error)
 	Update(ctx context.Context, debt *models.Debt) error
+	// ReplaceTags makes tags the full set of tags on the debt.
+	ReplaceTags(ctx context.Context, debt *models.Debt, tags []models.Tag) error
 	Delete(ctx context.Context, uuid string, userID int64) error
+	// MarkOverdue moves the user's unpaid debts past their due date to OVERDUE and returns how many changed.
+	MarkOverdue(ctx context.Context, userID int64, now time.Time) (int64, error)
+	// MarkAllOverdue is MarkOverdue across every user, for the background sweep.
+	MarkAllOverdue(ctx context.Context, now time.Time) (int64, error)
 }
 
 type DebtPaymentRepository interface {
 	Create(ctx context.Context, payment *models.DebtPayment) error
 	ListByDebtID(ctx context.Context, debtID int64) ([]models.DebtPayment, error)
+	GetByUUID(ctx context.Context, debtID int64, uuid string) (*models.DebtPayment, error)
+	GetByUUIDForUpdate(ctx context.Context, debtID int64, uuid string) (*models.DebtPayment, error)
+	Update(ctx context.Context, payment *models.DebtPayment) error
 }
 
 type DebtService interface {
 	CreateDebt(ctx context.Context, userID int64, req CreateDebtRequest) (*models.Debt, error)
 	GetDebt(ctx context.Context, userID int64, uuid string) (*models.Debt, error)
-	ListDebts(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, page, limit int) ([]models.Debt, ListMeta, error)
+	// ListDebts with tags keeps the debts carrying every one of them.
+	ListDebts(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, tags []string, page, limit int) ([]models.Debt, ListMeta, error)
 	UpdateDebt(ctx context.Context, userID int64, uuid string, req UpdateDebtRequest) (*models.Debt, error)
 	DeleteDebt(ctx context.Context, userID int64, uuid string) error
 	RecordDebtPayment(ctx context.Context, userID int64, debtUUID string, req CreateDebtPaymentRequest) (*models.DebtPayment, error)
 	ListDebtPayments(ctx context.Context, userID int64, debtUUID string) ([]models.DebtPayment, error)
+	GetDebtPayment(ctx context.Context, userID int64, debtUUID, paymentUUID string) (*models.DebtPayment, error)
+	UpdateDebtPayment(ctx context.Context, userID int64, debtUUID, paymentUUID string, req UpdateDebtPaymentRequest) (*models.DebtPayment, error)
+	// VoidDebtPayment keeps the payment for audit but stops counting it and reverses its cash movement.
+	VoidDebtPayment(ctx context.Context, userID int64, debtUUID, paymentUUID string) (*models.DebtPayment, error)
 }
 
 type CreateDebtRequest struct {
@@ -35,14 +50,19 @@ type CreateDebtRequest struct {
 	DueDate   *time.Time `json:"dueDate,omitempty"`
 	Note      *string    `json:"note,omitempty"`
 	AssetUUID *string    `json:"assetUuid,omitempty"`
+	Tags      []string   `json:"tags,omitempty"` // tag names, created on first use
+	// RecordDisbursement adds the borrowed amount to the CASH asset (requires AssetUUID).
+	RecordDisbursement bool `json:"recordDisbursement,omitempty"`
 }
 
 type UpdateDebtRequest struct {
-	PartyName *string    `json:"partyName,omitempty"`
-	Amount    *float64   `json:"amount,omitempty"`
-	DueDate   *time.Time `json:"dueDate,omitempty"`
-	Note      *string    `json:"note,omitempty"`
-	AssetUUID *string    `json:"assetUuid,omitempty"`
+	PartyName          *string    `json:"partyName,omitempty"`
+	Amount             *float64   `json:"amount,omitempty"`
+	DueDate            *time.Time `json:"dueDate,omitempty"`
+	Note               *string    `json:"note,omitempty"`
+	AssetUUID          *string    `json:"assetUuid,omitempty"`
+	RecordDisbursement *bool      `json:"recordDisbursement,omitempty"`
+	Tags               *[]string  `json:"tags,omitempty"` // replaces every tag; [] removes them
 }
 
 type CreateDebtPaymentRequest struct {
@@ -51,3 +71,10 @@ type CreateDebtPaymentRequest struct {
 	Note      *string    `json:"note,omitempty"`
 	AssetUUID *string    `json:"assetUuid,omitempty"`
 }
+
+type UpdateDebtPaymentRequest struct {
+	Amount    *float64   `json:"amount,omitempty"`
+	Date      *time.Time `json:"date,omitempty"`
+	Note      *string    `json:"note,omitempty"`
+	AssetUUID *string    `json:"assetUuid,omitempty"`
+}
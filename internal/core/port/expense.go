@@ -9,31 +9,57 @@ import (
 type ExpenseRepository interface {
 	Create(ctx context.Context, expense *models.Expense) error
 	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Expense, error)
-	ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Expense, int64, error)
+	GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Expense, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*models.Expense, error)
+	ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string, page, limit int) ([]models.Expense, int64, error)
 	Update(ctx context.Context, expense *models.Expense) error
+	// ReplaceTags makes tags the full set of tags on the expense.
+	ReplaceTags(ctx context.Context, expense *models.Expense, tags []models.Tag) error
+	// ReplaceSplits makes splits the full set of split lines of the expense; none makes it a single-category
+	// expense again.
+	ReplaceSplits(ctx context.Context, expense *models.Expense, splits []models.ExpenseSplit) error
 	Delete(ctx context.Context, uuid string, userID int64) error
 }
 
 type ExpenseService interface {
 	CreateExpense(ctx context.Context, userID int64, req CreateExpenseRequest) (*models.Expense, error)
 	GetExpense(ctx context.Context, userID int64, uuid string) (*models.Expense, error)
-	ListExpenses(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Expense, ListMeta, error)
+	// ListExpenses with tags keeps the expenses carrying every one of them.
+	ListExpenses(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string, page, limit int) ([]models.Expense, ListMeta, error)
 	UpdateExpense(ctx context.Context, userID int64, uuid string, req UpdateExpenseRequest) (*models.Expense, error)
 	DeleteExpense(ctx context.Context, userID int64, uuid string) error
 }
 
+// CreateExpenseRequest takes the category by CategoryUUID, or by Category, the code of a default category.
+// A split expense leaves both out and gives Splits instead, whose amounts add up to Amount.
 type CreateExpenseRequest struct {
-	AssetUUID string                 `json:"assetUuid"`
-	Amount    float64                `json:"amount"`
-	Category  models.ExpenseCategory `json:"category"`
-	Note      *string                `json:"note,omitempty"`
-	Date      time.Time              `json:"date"`
+	AssetUUID    string                 `json:"assetUuid"`
+	Amount       float64                `json:"amount"`
+	CategoryUUID *string                `json:"categoryUuid,omitempty"`
+	Category     models.ExpenseCategory `json:"category,omitempty"`
+	Note         *string                `json:"note,omitempty"`
+	Date         time.Time              `json:"date"`
+	Tags         []string               `json:"tags,omitempty"` // tag names, created on first use
+	Splits       []ExpenseSplitRequest  `json:"splits,omitempty"`
 }
 
 type UpdateExpenseRequest struct {
-	AssetUUID *string                 `json:"assetUuid,omitempty"`
-	Amount    *float64                `json:"amount,omitempty"`
-	Category  *models.ExpenseCategory `json:"category,omitempty"`
-	Note      *string                 `json:"note,omitempty"`
-	Date      *time.Time              `json:"date,omitempty"`
+	AssetUUID    *string                 `json:"assetUuid,omitempty"`
+	Amount       *float64                `json:"amount,omitempty"`
+	CategoryUUID *string                 `json:"categoryUuid,omitempty"`
+	Category     *models.ExpenseCategory `json:"category,omitempty"`
+	Note         *string                 `json:"note,omitempty"`
+	Date         *time.Time              `json:"date,omitempty"`
+	Tags         *[]string               `json:"tags,omitempty"` // replaces every tag; [] removes them
+	// Splits replaces every split line; [] makes it a single-category expense again, in the first line's category
+	// unless one is given.
+	Splits *[]ExpenseSplitRequest `json:"splits,omitempty"`
+}
+
+// ExpenseSplitRequest is one line of a split expense, taking its category the same way as the expense.
+type ExpenseSplitRequest struct {
+	CategoryUUID *string                `json:"categoryUuid,omitempty"`
+	Category     models.ExpenseCategory `json:"category,omitempty"`
+	Amount       float64                `json:"amount"`
+	Note         *string                `json:"note,omitempty"`
 }
@@ -0,0 +1,43 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+type FXRateRepository interface {
+	// Upsert writes daily rates, replacing the row a pair already has for the same RateDate.
+	Upsert(ctx context.Context, rates []models.FXRate) error
+	// GetOnOrBefore returns the latest rate of the pair dated on or before date, or nil if there is none.
+	GetOnOrBefore(ctx context.Context, base, quote string, date time.Time) (*models.FXRate, error)
+	// ListQuotes returns the distinct quote currencies stored against base.
+	ListQuotes(ctx context.Context, base string) ([]string, error)
+}
+
+// FXService converts between any two ISO currencies. Rates are kept per day against USD and other pairs
+// are triangulated through it.
+type FXService interface {
+	// Rate returns how many units of to one unit of from buys now.
+	Rate(ctx context.Context, from, to string) (float64, error)
+	// RateAt returns the rate as of date: the latest daily rate on or before it. When no daily history of a
+	// currency can be found or fetched, today's rate stands in for it.
+	RateAt(ctx context.Context, from, to string, date time.Time) (float64, error)
+	// HistoricalRate is RateAt that also reports whether today's rate stood in for missing history.
+	HistoricalRate(ctx context.Context, from, to string, date time.Time) (rate float64, estimated bool, err error)
+	// Convert converts amount at the rate as of at; a zero at uses the current rate.
+	Convert(ctx context.Context, amount decimal.Decimal, from, to string, at time.Time) (decimal.Decimal, error)
+	// RefreshRates fetches today's rate of every tracked currency and stores it.
+	RefreshRates(ctx context.Context) (int, error)
+}
+
+// FXRateResponse answers GET /prices/fx.
+type FXRateResponse struct {
+	From string    `json:"from"`
+	To   string    `json:"to"`
+	Rate float64   `json:"rate"`
+	Date time.Time `json:"date"`
+}
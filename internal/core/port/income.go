@@ -9,15 +9,21 @@ import (
 type IncomeRepository interface {
 	Create(ctx context.Context, income *models.Income) error
 	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Income, error)
-	ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Income, int64, error)
+	GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Income, error)
+	ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string, page, limit int) ([]models.Income, int64, error)
+	// ListBySourceAssetID returns the incomes an asset earned, oldest first, with the CASH asset each landed in.
+	ListBySourceAssetID(ctx context.Context, sourceAssetID, userID int64) ([]models.Income, error)
 	Update(ctx context.Context, income *models.Income) error
+	// ReplaceTags makes tags the full set of tags on the income.
+	ReplaceTags(ctx context.Context, income *models.Income, tags []models.Tag) error
 	Delete(ctx context.Context, uuid string, userID int64) error
 }
 
 type IncomeService interface {
 	CreateIncome(ctx context.Context, userID int64, req CreateIncomeRequest) (*models.Income, error)
 	GetIncome(ctx context.Context, userID int64, uuid string) (*models.Income, error)
-	ListIncomes(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Income, ListMeta, error)
+	// ListIncomes with tags keeps the incomes carrying every one of them.
+	ListIncomes(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string, page, limit int) ([]models.Income, ListMeta, error)
 	UpdateIncome(ctx context.Context, userID int64, uuid string, req UpdateIncomeRequest) (*models.Income, error)
 	DeleteIncome(ctx context.Context, userID int64, uuid string) error
 }
@@ -28,6 +34,11 @@ type CreateIncomeRequest struct {
 	Source    string    `json:"source"`
 	Note      *string   `json:"note,omitempty"`
 	Date      time.Time `json:"date"`
+	Tags      []string  `json:"tags,omitempty"` // tag names, created on first use
+	// SourceAssetUUID names the asset that paid the income; Type (DIVIDEND, COUPON, INTEREST, RENT, OTHER)
+	// defaults to OTHER.
+	SourceAssetUUID *string `json:"sourceAssetUuid,omitempty"`
+	Type            string  `json:"type,omitempty"`
 }
 
 type UpdateIncomeRequest struct {
@@ -36,4 +47,8 @@ type UpdateIncomeRequest struct {
 	Source    *string    `json:"source,omitempty"`
 	Note      *string    `json:"note,omitempty"`
 	Date      *time.Time `json:"date,omitempty"`
+	Tags      *[]string  `json:"tags,omitempty"` // replaces every tag; [] removes them
+	// An empty SourceAssetUUID unlinks the income from its source asset.
+	SourceAssetUUID *string `json:"sourceAssetUuid,omitempty"`
+	Type            *string `json:"type,omitempty"`
 }
@@ -11,6 +11,13 @@ type InsightRepository interface {
 	GetTotalIncomeByDateRange(ctx context.Context, userID int64, startDate, endDate time.Time) (decimal.Decimal, error)
 	GetTotalExpenseByDateRange(ctx context.Context, userID int64, startDate, endDate time.Time) (decimal.Decimal, error)
 	GetExpensesByCategory(ctx context.Context, userID int64, startDate, endDate time.Time) ([]CategoryTotal, error)
+	// GetMonthlyExpensesByCategory totals expenses per category and UTC calendar month.
+	GetMonthlyExpensesByCategory(ctx context.Context, userID int64, startDate, endDate time.Time) ([]MonthlyCategoryTotal, error)
+	// GetExpensesByTag totals the expenses carrying each tag, per category. dateTo is inclusive; tags, when
+	// set, keeps only those tag names.
+	GetExpensesByTag(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string) ([]TagCategoryTotal, error)
+	// GetIncomeByTag totals the incomes carrying each tag, with the same filters as GetExpensesByTag.
+	GetIncomeByTag(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string) ([]TagIncomeTotal, error)
 	GetTotalAssetValue(ctx context.Context, userID int64) (decimal.Decimal, error)
 	GetTotalSavingGoalProgress(ctx context.Context, userID int64) (*SavingGoalSummary, error)
 	GetTotalDebt(ctx context.Context, userID int64) (decimal.Decimal, error)
@@ -20,8 +27,11 @@ type InsightRepository interface {
 }
 
 type InsightService interface {
-	GetCashflowSummary(ctx context.Context, userID int64, month string) (*CashflowSummary, error)
+	// GetCashflowSummary with rollup adds subcategory spending into the parent category's total.
+	GetCashflowSummary(ctx context.Context, userID int64, month string, rollup bool) (*CashflowSummary, error)
 	GetFinancialOverview(ctx context.Context, userID int64) (*FinancialOverview, error)
+	// GetTagSummary totals income and spending per tag, so e.g. a trip can be added up across categories.
+	GetTagSummary(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string) ([]TagSummary, error)
 }
 
 type CashflowSummary struct {
@@ -33,10 +43,44 @@ type CashflowSummary struct {
 	ExpenseByCategory []CategoryTotal `json:"expenseByCategory"`
 }
 
+// CategoryTotal is the spending of one category; Category is its name. A subcategory names its parent.
 type CategoryTotal struct {
-	Category   string          `json:"category"`
+	CategoryUUID string          `json:"categoryUuid"`
+	Category     string          `json:"category"`
+	ParentUUID   *string         `json:"parentUuid,omitempty"`
+	Parent       *string         `json:"parent,omitempty"`
+	Total        decimal.Decimal `json:"total"`
+	Percentage   float64         `json:"percentage"`
+}
+
+// TagSummary is everything carrying one tag. A record with several tags counts under each of them.
+type TagSummary struct {
+	TagUUID           string          `json:"tagUuid"`
+	Tag               string          `json:"tag"`
+	TotalIncome       decimal.Decimal `json:"totalIncome"`
+	TotalExpense      decimal.Decimal `json:"totalExpense"`
+	Net               decimal.Decimal `json:"net"`
+	ExpenseByCategory []CategoryTotal `json:"expenseByCategory"` // Percentage is of the tag's spending
+}
+
+// TagCategoryTotal is the spending of one category under one tag.
+type TagCategoryTotal struct {
+	TagUUID string
+	Tag     string
+	CategoryTotal
+}
+
+type TagIncomeTotal struct {
+	TagUUID string
+	Tag     string
+	Total   decimal.Decimal
+}
+
+type MonthlyCategoryTotal struct {
+	Month      string          `json:"month"` // YYYY-MM
+	CategoryID int64           `json:"-"`
+	ParentID   *int64          `json:"-"`
 	Total      decimal.Decimal `json:"total"`
-	Percentage float64         `json:"percentage"`
 }
 
 // MonthlyTrendPoint is one month in the overview trend (for line/area charts).
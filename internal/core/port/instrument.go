@@ -0,0 +1,23 @@
+package port
+
+import (
+	"context"
+
+	"monity/internal/models"
+)
+
+type InstrumentRepository interface {
+	ListAll(ctx context.Context) ([]models.Instrument, error)
+}
+
+// InstrumentRegistry answers where a stock ticker is listed and how it is quoted and counted.
+type InstrumentRegistry interface {
+	// Lookup returns the listing of ticker, preferring the one traded in currency when the ticker is listed on
+	// several exchanges. It returns nil for unknown tickers.
+	Lookup(ctx context.Context, ticker, currency string) *models.Instrument
+	// ProviderSymbol returns the symbol the price provider quotes ticker under, picking the listing as Lookup does;
+	// unknown tickers are returned as is.
+	ProviderSymbol(ctx context.Context, ticker, currency string) string
+	// LotSize returns the shares per unit of the asset's quantity: the instrument's lot size for listed stocks, else 1.
+	LotSize(ctx context.Context, asset *models.Asset) int64
+}
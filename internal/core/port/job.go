@@ -0,0 +1,32 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"monity/internal/models"
+)
+
+type JobRepository interface {
+	// Enqueue inserts the job unless a row for the same name and ScheduledFor already exists.
+	// It reports whether a new row was inserted.
+	Enqueue(ctx context.Context, job *models.Job) (bool, error)
+	// LastScheduledFor returns the newest schedule slot enqueued for name, or nil if none.
+	LastScheduledFor(ctx context.Context, name string) (*time.Time, error)
+	// ClaimDue marks up to limit PENDING jobs with the given names and run_at <= now as RUNNING for workerID
+	// (incrementing Attempts) and returns them. Rows claimed by another replica are skipped, never waited on.
+	ClaimDue(ctx context.Context, names []string, workerID string, now time.Time, limit int) ([]models.Job, error)
+	Update(ctx context.Context, job *models.Job) error
+	// RequeueStale returns RUNNING jobs locked before cutoff (their worker died) to PENDING,
+	// or to DEAD when they have no attempts left.
+	RequeueStale(ctx context.Context, cutoff, now time.Time) (int64, error)
+	// PurgeSucceeded deletes SUCCEEDED jobs finished before cutoff. DEAD jobs are kept.
+	PurgeSucceeded(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// LeaderElector decides which replica schedules jobs. TryAcquire is called on every scheduler tick
+// and keeps returning true for as long as this process holds leadership.
+type LeaderElector interface {
+	TryAcquire(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
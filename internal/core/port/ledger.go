@@ -0,0 +1,49 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+type LedgerRepository interface {
+	CreateTransaction(ctx context.Context, txn *models.LedgerTransaction) error
+	// NetByReference returns the net amount per (account, asset) posted so far for a reference,
+	// leaving out legs that already net to zero.
+	NetByReference(ctx context.Context, refType models.LedgerReferenceType, refUUID string) ([]models.LedgerEntry, error)
+	// DeleteByReference removes every transaction posted for a reference, entries included.
+	DeleteByReference(ctx context.Context, refType models.LedgerReferenceType, refUUID string) error
+	BalanceByAssetID(ctx context.Context, assetID int64) (decimal.Decimal, error)
+	ListByAssetID(ctx context.Context, assetID int64, page, limit int) ([]AssetLedgerEntry, int64, error)
+}
+
+type LedgerService interface {
+	GetAssetLedger(ctx context.Context, userID int64, assetUUID string, page, limit int) (*AssetLedgerResponse, error)
+}
+
+// AssetLedgerEntry is one ledger entry on a CASH asset with the asset balance right after it.
+type AssetLedgerEntry struct {
+	UUID            string                     `json:"uuid"`
+	TransactionUUID string                     `json:"transactionUuid"`
+	ReferenceType   models.LedgerReferenceType `json:"referenceType"`
+	ReferenceUUID   string                     `json:"referenceUuid"`
+	Description     string                     `json:"description"`
+	Amount          decimal.Decimal            `json:"amount"`
+	Balance         decimal.Decimal            `json:"balance"`
+	OccurredAt      time.Time                  `json:"occurredAt"`
+	CreatedAt       time.Time                  `json:"createdAt"`
+}
+
+// AssetLedgerResponse lists the running balance history of a CASH asset (newest first).
+// InSync is false when the stored asset quantity no longer matches the ledger balance.
+type AssetLedgerResponse struct {
+	AssetUUID     string             `json:"assetUuid"`
+	Quantity      decimal.Decimal    `json:"quantity"`
+	LedgerBalance decimal.Decimal    `json:"ledgerBalance"`
+	InSync        bool               `json:"inSync"`
+	Items         []AssetLedgerEntry `json:"items"`
+	Meta          ListMeta           `json:"meta"`
+}
@@ -0,0 +1,15 @@
+package port
+
+import "context"
+
+// OverdueService moves debts and receivables past their due date to OVERDUE in the background,
+// so obligations nobody has opened recently still show up in status filters and insights.
+type OverdueService interface {
+	SweepOverdue(ctx context.Context) (OverdueSweepResult, error)
+}
+
+// OverdueSweepResult counts how many obligations one sweep moved to OVERDUE.
+type OverdueSweepResult struct {
+	Debts       int64 `json:"debts"`
+	Receivables int64 `json:"receivables"`
+}
@@ -11,6 +11,7 @@ import (
 type AssetPerformanceService interface {
 	GetAssetPerformance(ctx context.Context, userID int64, assetUUID string, currency string) (*AssetPerformanceResponse, error)
 	GetPortfolioPerformance(ctx context.Context, userID int64, currency string) (*PortfolioPerformanceResponse, error)
+	GetAssetIncome(ctx context.Context, userID int64, assetUUID string, currency string) (*AssetIncomeResponse, error)
 }
 
 // AssetPerformanceResponse contains performance metrics for a single asset
@@ -23,21 +24,49 @@ type AssetPerformanceResponse struct {
 	CurrentData CurrentValueInfo    `json:"currentValue"`
 	Performance PerformanceMetrics  `json:"performance"`
 	Analysis    PerformanceAnalysis `json:"analysis"`
+	// Lots are the open purchase lots, oldest first, for assets held through transactions.
+	Lots []LotPerformance `json:"lots,omitempty"`
 }
 
 type InvestmentInfo struct {
-	Quantity         decimal.Decimal `json:"quantity"`
-	PurchasePrice    decimal.Decimal `json:"purchasePrice"`
-	PurchaseDate     time.Time       `json:"purchaseDate"`
-	TotalCost        decimal.Decimal `json:"totalCost"`
-	Currency         string          `json:"currency"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	PurchasePrice decimal.Decimal `json:"purchasePrice"`
+	PurchaseDate  time.Time       `json:"purchaseDate"`
+	TotalCost     decimal.Decimal `json:"totalCost"`
+	Currency      string          `json:"currency"`
+	// PurchaseCurrency is the currency the asset was bought in; PurchasePrice, TotalCost and TransactionFee
+	// are converted from it at PurchaseFXRate, the rate on PurchaseDate.
+	PurchaseCurrency string          `json:"purchaseCurrency"`
+	PurchaseFXRate   decimal.Decimal `json:"purchaseFxRate"`
+	// FXEstimated is set when no exchange-rate history was found for a purchase date and today's rate stood in
+	// for it: the cost basis is then approximate and its currency return is reported as zero.
+	FXEstimated      bool            `json:"fxEstimated,omitempty"`
 	TransactionFee   decimal.Decimal `json:"transactionFee,omitempty"`
 	DaysSinceHolding int             `json:"daysSinceHolding"`
+	CostMethod       string          `json:"costMethod,omitempty"` // FIFO or AVERAGE
+}
+
+// LotPerformance is one open purchase lot. Its cost basis is converted at the rate of the day it was
+// acquired, and its holding period and returns are its own.
+type LotPerformance struct {
+	TransactionUUID   string          `json:"transactionUuid"`
+	AcquiredAt        time.Time       `json:"acquiredAt"`
+	Quantity          decimal.Decimal `json:"quantity"`
+	UnitCost          decimal.Decimal `json:"unitCost"` // per share
+	CostBasis         decimal.Decimal `json:"costBasis"`
+	FXRate            decimal.Decimal `json:"fxRate"`                // rate on AcquiredAt from the purchase currency
+	FXEstimated       bool            `json:"fxEstimated,omitempty"` // today's rate stood in for FXRate
+	CurrentValue      decimal.Decimal `json:"currentValue"`
+	ProfitLoss        decimal.Decimal `json:"profitLoss"`
+	ProfitLossPercent decimal.Decimal `json:"profitLossPercent"`
+	HoldingDays       int             `json:"holdingDays"`
+	AnnualizedReturn  decimal.Decimal `json:"annualizedReturn"`
 }
 
 type CurrentValueInfo struct {
 	CurrentPrice   decimal.Decimal `json:"currentPrice"`
 	CurrentValue   decimal.Decimal `json:"currentValue"`
+	FXRate         decimal.Decimal `json:"fxRate"` // today's rate from the purchase currency
 	PriceChange24h float64         `json:"priceChange24h,omitempty"`
 	LastUpdated    time.Time       `json:"lastUpdated"`
 }
@@ -46,9 +75,67 @@ type PerformanceMetrics struct {
 	ProfitLoss        decimal.Decimal `json:"profitLoss"`
 	ProfitLossPercent decimal.Decimal `json:"profitLossPercent"`
 	ROI               decimal.Decimal `json:"roi"`
-	Status            string          `json:"status"`        // profit, loss, break-even
-	HoldingPeriod     int             `json:"holdingPeriod"` // days
-	AnnualizedReturn  decimal.Decimal `json:"annualizedReturn"`
+	// ProfitLoss = AssetReturn + CurrencyReturn: the price move valued at the purchase-date rate, and what the
+	// exchange rate added since. CurrencyReturn is zero when the asset is viewed in its purchase currency.
+	AssetReturn           decimal.Decimal `json:"assetReturn"`
+	AssetReturnPercent    decimal.Decimal `json:"assetReturnPercent"`
+	CurrencyReturn        decimal.Decimal `json:"currencyReturn"`
+	CurrencyReturnPercent decimal.Decimal `json:"currencyReturnPercent"`
+	Status                string          `json:"status"`        // profit, loss, break-even
+	HoldingPeriod         int             `json:"holdingPeriod"` // days
+	AnnualizedReturn      decimal.Decimal `json:"annualizedReturn"`
+	// RealizedProfitLoss is what sales locked in (less fees paid away), each converted at the rate of its own
+	// date; UnrealizedProfitLoss is the result of the units still held. A position sold down to zero reports
+	// its realized result as ProfitLoss, against the cost of the units sold.
+	RealizedProfitLoss   decimal.Decimal `json:"realizedProfitLoss"`
+	UnrealizedProfitLoss decimal.Decimal `json:"unrealizedProfitLoss"`
+	// IncomeReceived is what the asset paid out (dividends, coupons, rent), each income converted at the rate
+	// of its date. TotalReturn adds it to the realized and unrealized profit/loss; TotalReturnPercent is
+	// against everything invested, the cost of the units held plus that of the units sold. TTMYieldOnCost is
+	// the last twelve months' income over the cost basis held.
+	IncomeReceived     decimal.Decimal `json:"incomeReceived"`
+	TTMIncome          decimal.Decimal `json:"ttmIncome"`
+	TTMYieldOnCost     decimal.Decimal `json:"ttmYieldOnCost"`
+	TotalReturn        decimal.Decimal `json:"totalReturn"`
+	TotalReturnPercent decimal.Decimal `json:"totalReturnPercent"`
+}
+
+// AssetIncomeResponse is the income an asset paid, oldest first, in the requested currency.
+type AssetIncomeResponse struct {
+	AssetUUID string `json:"assetUuid"`
+	AssetName string `json:"assetName"`
+	Currency  string `json:"currency"`
+	// CostBasis is that of the units held now; TTMYieldOnCost is TTMIncome over it.
+	CostBasis      decimal.Decimal `json:"costBasis"`
+	TotalIncome    decimal.Decimal `json:"totalIncome"`
+	TTMIncome      decimal.Decimal `json:"ttmIncome"`
+	TTMYieldOnCost decimal.Decimal `json:"ttmYieldOnCost"`
+	// EstimatedYield and YieldPeriod are as entered on the asset, to compare with what it paid.
+	EstimatedYield *decimal.Decimal   `json:"estimatedYield,omitempty"`
+	YieldPeriod    *string            `json:"yieldPeriod,omitempty"`
+	Years          []IncomeYear       `json:"years"`
+	Entries        []AssetIncomeEntry `json:"entries"`
+}
+
+// AssetIncomeEntry is one income, converted from the currency of the CASH asset it landed in at the rate of
+// its date. YieldOnCost is Amount over the cost basis held on that date.
+type AssetIncomeEntry struct {
+	IncomeUUID   string          `json:"incomeUuid"`
+	Date         time.Time       `json:"date"`
+	Type         string          `json:"type"`
+	Amount       decimal.Decimal `json:"amount"`
+	CashAmount   decimal.Decimal `json:"cashAmount"`
+	CashCurrency string          `json:"cashCurrency"`
+	FXRate       decimal.Decimal `json:"fxRate"`
+	CostBasis    decimal.Decimal `json:"costBasis"`
+	YieldOnCost  decimal.Decimal `json:"yieldOnCost"` // %
+}
+
+// IncomeYear totals a calendar year's income; YieldOnCost adds up the yield of each of its entries.
+type IncomeYear struct {
+	Year        int             `json:"year"`
+	Income      decimal.Decimal `json:"income"`
+	YieldOnCost decimal.Decimal `json:"yieldOnCost"` // %
 }
 
 type PerformanceAnalysis struct {
@@ -72,7 +159,15 @@ type PortfolioOverview struct {
 	TotalProfitLoss        decimal.Decimal `json:"totalProfitLoss"`
 	TotalProfitLossPercent decimal.Decimal `json:"totalProfitLossPercent"`
 	TotalROI               decimal.Decimal `json:"totalROI"`
-	Currency               string          `json:"currency"`
+	TotalAssetReturn       decimal.Decimal `json:"totalAssetReturn"`
+	TotalCurrencyReturn    decimal.Decimal `json:"totalCurrencyReturn"`
+	// TotalProfitLoss is unrealized: the result of what is held. Positions sold down to zero only add to
+	// TotalRealizedProfitLoss; their proceeds are in the CASH assets they were paid to.
+	TotalRealizedProfitLoss   decimal.Decimal `json:"totalRealizedProfitLoss"`
+	TotalUnrealizedProfitLoss decimal.Decimal `json:"totalUnrealizedProfitLoss"`
+	Currency                  string          `json:"currency"`
+	// FXEstimated is set when the cost basis of any asset was converted at today's rate for lack of history.
+	FXEstimated bool `json:"fxEstimated,omitempty"`
 }
 
 type AssetTypeAllocation struct {
@@ -3,6 +3,8 @@ package port
 import (
 	"context"
 	"time"
+
+	"monity/internal/models"
 )
 
 const (
@@ -20,10 +22,35 @@ type PriceService interface {
 	GetCryptoPrice(ctx context.Context, symbol string) (*PriceData, error)
 	GetStockPrice(ctx context.Context, symbol string) (*PriceData, error)
 	GetPrice(ctx context.Context, assetType string, symbol string) (*PriceData, error)
-	GetHistoricalCryptoPrice(ctx context.Context, symbol string, timestamp time.Time) (*PriceData, error)
-	GetHistoricalCryptoOHLCV(ctx context.Context, symbol string, timeStart, timeEnd time.Time, interval string) ([]OHLCVData, error)
+	// GetAssetPrice quotes a CRYPTO/STOCK asset by its symbol, honouring the provider ID pinned on it.
+	GetAssetPrice(ctx context.Context, asset *models.Asset, currency string) (*PriceData, error)
+	// GetPrices quotes many symbols at once; results are in the order of reqs. Lookups are shared between
+	// equal requests, batched where the provider allows it and otherwise run concurrently.
+	GetPrices(ctx context.Context, reqs []SymbolRequest, currency string) ([]PriceResult, error)
+	// GetHistoricalCryptoPrice and GetHistoricalCryptoOHLCV answer in currency, converting at the rate of each day
+	// when the provider cannot.
+	GetHistoricalCryptoPrice(ctx context.Context, symbol, currency string, timestamp time.Time) (*PriceData, error)
+	GetHistoricalCryptoOHLCV(ctx context.Context, symbol, currency string, timeStart, timeEnd time.Time, interval string) ([]OHLCVData, error)
 	GetCryptoChart(ctx context.Context, symbol string, currency string, days int) (*ChartResponse, error)
-	GetStockChart(ctx context.Context, symbol string, rangeParam string, interval string) (*ChartResponse, error)
+	// GetStockChart charts in the listing currency, or converted to currency when it is not empty.
+	GetStockChart(ctx context.Context, symbol string, rangeParam string, interval string, currency string) (*ChartResponse, error)
+	// GetExchangeRate returns the current rate; see FXService for rates as of a date.
+	GetExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (float64, error)
+}
+
+// SymbolRequest is one entry of a GetPrices batch.
+type SymbolRequest struct {
+	Kind       string // PriceKindCrypto or PriceKindStock
+	Symbol     string
+	ProviderID string // provider ID pinned on the asset, optional
+	// Listing is the currency the asset is held in; it picks the listing of a stock traded on several exchanges.
+	Listing string
+}
+
+// PriceResult is the answer to one SymbolRequest: Data, or Err when no provider could price it.
+type PriceResult struct {
+	Data *PriceData
+	Err  error
 }
 
 // ChartDataPoint is one point for a line chart (t = Unix second, p = price).
@@ -36,6 +63,7 @@ type ChartDataPoint struct {
 type ChartResponse struct {
 	Symbol   string           `json:"symbol"`
 	Currency string           `json:"currency"`
+	Source   string           `json:"source,omitempty"`
 	Data     []ChartDataPoint `json:"data"`
 }
 
@@ -45,6 +73,8 @@ type PriceData struct {
 	Currency  string    `json:"currency"`
 	Source    string    `json:"source"`
 	FetchedAt time.Time `json:"fetchedAt"`
+	// Stale marks a cached quote past its TTL: served while a refresh runs, or because every provider failed.
+	Stale bool `json:"stale,omitempty"`
 }
 
 type OHLCVData struct {
@@ -0,0 +1,69 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"monity/internal/models"
+)
+
+// Price lookups are routed by these kinds; PriceKindFX selects providers for exchange rates.
+const (
+	PriceKindCrypto = "CRYPTO"
+	PriceKindStock  = "STOCK"
+	PriceKindFX     = "FX"
+)
+
+// ErrProviderUnsupported is returned by a PriceProvider for a request it cannot serve (asset kind, capability
+// or symbol). PriceService moves on to the next provider without counting it as an upstream failure.
+var ErrProviderUnsupported = errors.New("not supported by provider")
+
+// PriceProvider is one upstream market data source (CoinGecko, Yahoo Finance, CoinMarketCap, ...).
+// Prices may come back in a currency other than the one asked for; PriceData.Currency says which,
+// and PriceService converts. Source on every result is the provider's Name.
+type PriceProvider interface {
+	Name() string
+	// Supports reports whether the provider serves the kind (PriceKindCrypto, PriceKindStock or PriceKindFX).
+	Supports(kind string) bool
+	Quote(ctx context.Context, kind string, sym PriceSymbol, currency string) (*PriceData, error)
+	HistoricalPrice(ctx context.Context, kind, symbol, currency string, at time.Time) (*PriceData, error)
+	OHLCV(ctx context.Context, kind, symbol, currency string, from, to time.Time) ([]OHLCVData, error)
+	Chart(ctx context.Context, kind, symbol string, req ChartRequest) (*ChartResponse, error)
+	// ExchangeRate returns how many units of to one unit of from buys.
+	ExchangeRate(ctx context.Context, from, to string) (float64, error)
+}
+
+// BatchQuoter is implemented by providers that can quote several symbols in one upstream call. The result
+// holds the symbols the provider found; missing ones are left to the next provider.
+type BatchQuoter interface {
+	QuoteMany(ctx context.Context, kind string, syms []PriceSymbol, currency string) (map[PriceSymbol]*PriceData, error)
+}
+
+// PriceSymbol is what to quote: the ticker, plus the provider ID pinned on the asset (Asset.PriceProviderID),
+// which a provider that understands it uses instead of resolving the ticker.
+type PriceSymbol struct {
+	Ticker     string
+	ProviderID string
+}
+
+// ChartRequest carries the chart parameters; crypto charts use Currency and Days, stock charts Range and Interval.
+type ChartRequest struct {
+	Currency string
+	Days     int
+	Range    string
+	Interval string
+}
+
+// CoinIDResolver maps crypto tickers to CoinGecko coin IDs from the persisted coin list.
+type CoinIDResolver interface {
+	// ResolveCoinID returns the coin ID for ticker; when several coins share it the best market-cap rank wins.
+	ResolveCoinID(ctx context.Context, ticker string) (string, error)
+	// RefreshCoinList reloads the coin list from CoinGecko, stores it and returns how many coins it holds.
+	RefreshCoinList(ctx context.Context) (int, error)
+}
+
+type CryptoCoinRepository interface {
+	ListAll(ctx context.Context) ([]models.CryptoCoin, error)
+	Upsert(ctx context.Context, coins []models.CryptoCoin) error
+}
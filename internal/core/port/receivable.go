@@ -9,24 +9,39 @@ import (
 type ReceivableRepository interface {
 	Create(ctx context.Context, rec *models.Receivable) error
 	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Receivable, error)
-	ListByUserID(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, page, limit int) ([]models.Receivable, int64, error)
+	GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Receivable, error)
+	ListByUserID(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, tags []string, page, limit int) ([]models.Receivable, int64, error)
 	Update(ctx context.Context, rec *models.Receivable) error
+	// ReplaceTags makes tags the full set of tags on the receivable.
+	ReplaceTags(ctx context.Context, rec *models.Receivable, tags []models.Tag) error
 	Delete(ctx context.Context, uuid string, userID int64) error
+	// MarkOverdue moves the user's unpaid receivables past their due date to OVERDUE and returns how many changed.
+	MarkOverdue(ctx context.Context, userID int64, now time.Time) (int64, error)
+	// MarkAllOverdue is MarkOverdue across every user, for the background sweep.
+	MarkAllOverdue(ctx context.Context, now time.Time) (int64, error)
 }
 
 type ReceivablePaymentRepository interface {
 	Create(ctx context.Context, payment *models.ReceivablePayment) error
 	ListByReceivableID(ctx context.Context, receivableID int64) ([]models.ReceivablePayment, error)
+	GetByUUID(ctx context.Context, receivableID int64, uuid string) (*models.ReceivablePayment, error)
+	GetByUUIDForUpdate(ctx context.Context, receivableID int64, uuid string) (*models.ReceivablePayment, error)
+	Update(ctx context.Context, payment *models.ReceivablePayment) error
 }
 
 type ReceivableService interface {
 	CreateReceivable(ctx context.Context, userID int64, req CreateReceivableRequest) (*models.Receivable, error)
 	GetReceivable(ctx context.Context, userID int64, uuid string) (*models.Receivable, error)
-	ListReceivables(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, page, limit int) ([]models.Receivable, ListMeta, error)
+	// ListReceivables with tags keeps the receivables carrying every one of them.
+	ListReceivables(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, tags []string, page, limit int) ([]models.Receivable, ListMeta, error)
 	UpdateReceivable(ctx context.Context, userID int64, uuid string, req UpdateReceivableRequest) (*models.Receivable, error)
 	DeleteReceivable(ctx context.Context, userID int64, uuid string) error
 	RecordReceivablePayment(ctx context.Context, userID int64, receivableUUID string, req CreateReceivablePaymentRequest) (*models.ReceivablePayment, error)
 	ListReceivablePayments(ctx context.Context, userID int64, receivableUUID string) ([]models.ReceivablePayment, error)
+	GetReceivablePayment(ctx context.Context, userID int64, receivableUUID, paymentUUID string) (*models.ReceivablePayment, error)
+	UpdateReceivablePayment(ctx context.Context, userID int64, receivableUUID, paymentUUID string, req UpdateReceivablePaymentRequest) (*models.ReceivablePayment, error)
+	// VoidReceivablePayment keeps the payment for audit but stops counting it and reverses its cash movement.
+	VoidReceivablePayment(ctx context.Context, userID int64, receivableUUID, paymentUUID string) (*models.ReceivablePayment, error)
 }
 
 type CreateReceivableRequest struct {
@@ -35,14 +50,19 @@ type CreateReceivableRequest struct {
 	DueDate   *time.Time `json:"dueDate,omitempty"`
 	Note      *string    `json:"note,omitempty"`
 	AssetUUID *string    `json:"assetUuid,omitempty"`
+	Tags      []string   `json:"tags,omitempty"` // tag names, created on first use
+	// RecordDisbursement takes the lent amount from the CASH asset (requires AssetUUID).
+	RecordDisbursement bool `json:"recordDisbursement,omitempty"`
 }
 
 type UpdateReceivableRequest struct {
-	PartyName *string    `json:"partyName,omitempty"`
-	Amount    *float64   `json:"amount,omitempty"`
-	DueDate   *time.Time `json:"dueDate,omitempty"`
-	Note      *string    `json:"note,omitempty"`
-	AssetUUID *string    `json:"assetUuid,omitempty"`
+	PartyName          *string    `json:"partyName,omitempty"`
+	Amount             *float64   `json:"amount,omitempty"`
+	DueDate            *time.Time `json:"dueDate,omitempty"`
+	Note               *string    `json:"note,omitempty"`
+	AssetUUID          *string    `json:"assetUuid,omitempty"`
+	RecordDisbursement *bool      `json:"recordDisbursement,omitempty"`
+	Tags               *[]string  `json:"tags,omitempty"` // replaces every tag; [] removes them
 }
 
 type CreateReceivablePaymentRequest struct {
@@ -51,3 +71,10 @@ type CreateReceivablePaymentRequest struct {
 	Note      *string    `json:"note,omitempty"`
 	AssetUUID *string    `json:"assetUuid,omitempty"`
 }
+
+type UpdateReceivablePaymentRequest struct {
+	Amount    *float64   `json:"amount,omitempty"`
+	Date      *time.Time `json:"date,omitempty"`
+	Note      *string    `json:"note,omitempty"`
+	AssetUUID *string    `json:"assetUuid,omitempty"`
+}
@@ -0,0 +1,105 @@
+package port
+
+import (
+	"context"
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+type RecurringRuleRepository interface {
+	Create(ctx context.Context, rule *models.RecurringRule) error
+	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.RecurringRule, error)
+	GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.RecurringRule, error)
+	GetByIDForUpdate(ctx context.Context, id int64) (*models.RecurringRule, error)
+	ListByUserID(ctx context.Context, userID int64, page, limit int) ([]models.RecurringRule, int64, error)
+	ListAllByUserID(ctx context.Context, userID int64) ([]models.RecurringRule, error)
+	// ListDueIDs returns up to limit rules whose next occurrence is due at now, oldest due first.
+	ListDueIDs(ctx context.Context, now time.Time, limit int) ([]int64, error)
+	Update(ctx context.Context, rule *models.RecurringRule) error
+	Delete(ctx context.Context, uuid string, userID int64) error
+}
+
+type RecurringOccurrenceRepository interface {
+	// Get returns the recorded occurrence of a rule, or nil if there is none.
+	Get(ctx context.Context, ruleID int64, index int) (*models.RecurringOccurrence, error)
+	// ListByRuleID returns a rule's recorded occurrences with their expense or income, in index order.
+	ListByRuleID(ctx context.Context, ruleID int64) ([]models.RecurringOccurrence, error)
+	ListByUserID(ctx context.Context, userID int64, statuses []models.OccurrenceStatus) ([]models.RecurringOccurrence, error)
+	// Save inserts the occurrence or updates it when it has an ID.
+	Save(ctx context.Context, occurrence *models.RecurringOccurrence) error
+	// DeleteUnposted removes a rule's recorded occurrences that were not posted.
+	DeleteUnposted(ctx context.Context, ruleID int64) error
+}
+
+type RecurringService interface {
+	CreateRule(ctx context.Context, userID int64, req CreateRecurringRuleRequest) (*models.RecurringRule, error)
+	GetRule(ctx context.Context, userID int64, uuid string) (*models.RecurringRule, error)
+	ListRules(ctx context.Context, userID int64, page, limit int) ([]models.RecurringRule, ListMeta, error)
+	UpdateRule(ctx context.Context, userID int64, uuid string, req UpdateRecurringRuleRequest) (*models.RecurringRule, error)
+	DeleteRule(ctx context.Context, userID int64, uuid string) error
+	ListOccurrences(ctx context.Context, userID int64, uuid string) ([]models.RecurringOccurrence, error)
+	// UpdateOccurrence skips or edits the occurrence of a rule due on date, before it is posted.
+	UpdateOccurrence(ctx context.Context, userID int64, uuid string, date time.Time, req UpdateOccurrenceRequest) (*models.RecurringOccurrence, error)
+	// ConfirmOccurrence posts a PENDING occurrence.
+	ConfirmOccurrence(ctx context.Context, userID int64, uuid string, date time.Time) (*models.RecurringOccurrence, error)
+	// Upcoming lists the occurrences waiting for confirmation and those due in the next days.
+	Upcoming(ctx context.Context, userID int64, days int) ([]UpcomingOccurrence, error)
+	// GenerateDue materialises every due occurrence and returns how many were posted.
+	GenerateDue(ctx context.Context) (int, error)
+}
+
+type CreateRecurringRuleRequest struct {
+	AssetUUID    string                    `json:"assetUuid"`
+	Kind         models.RecurringKind      `json:"kind"`
+	Frequency    models.RecurringFrequency `json:"frequency"`
+	Interval     int                       `json:"interval,omitempty"` // default 1
+	Amount       float64                   `json:"amount"`
+	CategoryUUID *string                   `json:"categoryUuid,omitempty"` // EXPENSE needs this or category
+	Category     *models.ExpenseCategory   `json:"category,omitempty"`     // code of a default category
+	Source       *string                   `json:"source,omitempty"`       // required for INCOME
+	Note         *string                   `json:"note,omitempty"`
+	StartDate    time.Time                 `json:"startDate"`
+	EndDate      *time.Time                `json:"endDate,omitempty"`
+	AutoPost     *bool                     `json:"autoPost,omitempty"` // default true
+}
+
+// UpdateRecurringRuleRequest changes a rule from its next occurrence on. Frequency, Interval and StartDate
+// can only change before the first occurrence is generated.
+type UpdateRecurringRuleRequest struct {
+	AssetUUID    *string                    `json:"assetUuid,omitempty"`
+	Frequency    *models.RecurringFrequency `json:"frequency,omitempty"`
+	Interval     *int                       `json:"interval,omitempty"`
+	Amount       *float64                   `json:"amount,omitempty"`
+	CategoryUUID *string                    `json:"categoryUuid,omitempty"`
+	Category     *models.ExpenseCategory    `json:"category,omitempty"`
+	Source       *string                    `json:"source,omitempty"`
+	Note         *string                    `json:"note,omitempty"`
+	StartDate    *time.Time                 `json:"startDate,omitempty"`
+	EndDate      *string                    `json:"endDate,omitempty"` // RFC3339; empty string removes it
+	AutoPost     *bool                      `json:"autoPost,omitempty"`
+}
+
+type UpdateOccurrenceRequest struct {
+	Skip   *bool    `json:"skip,omitempty"`
+	Amount *float64 `json:"amount,omitempty"`
+	Note   *string  `json:"note,omitempty"`
+}
+
+// UpcomingOccurrence is one occurrence with the rule's values and its own edits applied. Status is PENDING
+// (due, waiting for confirmation or failed to post), SCHEDULED or SKIPPED.
+type UpcomingOccurrence struct {
+	RuleUUID  string                  `json:"ruleUuid"`
+	Kind      models.RecurringKind    `json:"kind"`
+	DueDate   time.Time               `json:"dueDate"`
+	Status    models.OccurrenceStatus `json:"status"`
+	Amount    decimal.Decimal         `json:"amount"`
+	Category  *models.Category        `json:"category,omitempty"`
+	Source    *string                 `json:"source,omitempty"`
+	Note      *string                 `json:"note,omitempty"`
+	AssetUUID string                  `json:"assetUuid"`
+	AutoPost  bool                    `json:"autoPost"`
+	LastError *string                 `json:"lastError,omitempty"`
+}
@@ -0,0 +1,28 @@
+package port
+
+import (
+	"context"
+
+	"monity/internal/models"
+)
+
+type TagRepository interface {
+	// FindOrCreate returns the user's tags with the given (normalized) names, creating the missing ones.
+	FindOrCreate(ctx context.Context, userID int64, names []string) ([]models.Tag, error)
+	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Tag, error)
+	GetByName(ctx context.Context, userID int64, name string) (*models.Tag, error)
+	ListByUserID(ctx context.Context, userID int64) ([]models.Tag, error)
+	Update(ctx context.Context, tag *models.Tag) error
+	// Delete removes the tag from everything it labels.
+	Delete(ctx context.Context, uuid string, userID int64) error
+}
+
+type TagService interface {
+	ListTags(ctx context.Context, userID int64) ([]models.Tag, error)
+	RenameTag(ctx context.Context, userID int64, uuid string, req UpdateTagRequest) (*models.Tag, error)
+	DeleteTag(ctx context.Context, userID int64, uuid string) error
+}
+
+type UpdateTagRequest struct {
+	Name string `json:"name"`
+}
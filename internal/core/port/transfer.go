@@ -0,0 +1,47 @@
+package port
+
+import (
+	"context"
+	"monity/internal/models"
+	"time"
+)
+
+type TransferRepository interface {
+	Create(ctx context.Context, transfer *models.Transfer) error
+	GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Transfer, error)
+	GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Transfer, error)
+	ListByUserID(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Transfer, int64, error)
+	Update(ctx context.Context, transfer *models.Transfer) error
+	Delete(ctx context.Context, uuid string, userID int64) error
+}
+
+type TransferService interface {
+	CreateTransfer(ctx context.Context, userID int64, req CreateTransferRequest) (*models.Transfer, error)
+	GetTransfer(ctx context.Context, userID int64, uuid string) (*models.Transfer, error)
+	ListTransfers(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Transfer, ListMeta, error)
+	UpdateTransfer(ctx context.Context, userID int64, uuid string, req UpdateTransferRequest) (*models.Transfer, error)
+	DeleteTransfer(ctx context.Context, userID int64, uuid string) error
+}
+
+// CreateTransferRequest moves Amount (in the source asset currency) from one CASH asset to another.
+// When the assets use different currencies the amount is converted at ExchangeRate, or at the current
+// market rate when ExchangeRate is omitted.
+type CreateTransferRequest struct {
+	FromAssetUUID string     `json:"fromAssetUuid"`
+	ToAssetUUID   string     `json:"toAssetUuid"`
+	Amount        float64    `json:"amount"`
+	Fee           *float64   `json:"fee,omitempty"`
+	ExchangeRate  *float64   `json:"exchangeRate,omitempty"`
+	Note          *string    `json:"note,omitempty"`
+	Date          *time.Time `json:"date,omitempty"`
+}
+
+type UpdateTransferRequest struct {
+	FromAssetUUID *string    `json:"fromAssetUuid,omitempty"`
+	ToAssetUUID   *string    `json:"toAssetUuid,omitempty"`
+	Amount        *float64   `json:"amount,omitempty"`
+	Fee           *float64   `json:"fee,omitempty"` // 0 removes the fee
+	ExchangeRate  *float64   `json:"exchangeRate,omitempty"`
+	Note          *string    `json:"note,omitempty"`
+	Date          *time.Time `json:"date,omitempty"`
+}
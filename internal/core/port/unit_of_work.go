@@ -0,0 +1,31 @@
+package port
+
+import "context"
+
+// UnitOfWork runs a function inside a single database transaction.
+// If fn returns an error (or panics) every write made through the given repositories is rolled back;
+// the error is returned unchanged so callers can keep matching on service error messages.
+// Do called with the ctx fn was given nests inside that transaction, so one service can run another's
+// writes atomically with its own.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context, repos Repositories) error) error
+}
+
+// Repositories are the transaction-bound repositories handed to a UnitOfWork function.
+// Use the ForUpdate lookups to lock rows (SELECT ... FOR UPDATE) before checking and changing balances.
+type Repositories struct {
+	Assets               AssetRepository
+	AssetTransactions    AssetTransactionRepository
+	Expenses             ExpenseRepository
+	Incomes              IncomeRepository
+	Debts                DebtRepository
+	DebtPayments         DebtPaymentRepository
+	Receivables          ReceivableRepository
+	ReceivablePayments   ReceivablePaymentRepository
+	Ledger               LedgerRepository
+	Transfers            TransferRepository
+	RecurringRules       RecurringRuleRepository
+	RecurringOccurrences RecurringOccurrenceRepository
+	Categories           CategoryRepository
+	Tags                 TagRepository
+}
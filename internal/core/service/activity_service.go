@@ -17,44 +17,57 @@ const (
 	groupByYear  = "year"
 )
 
-// ActivityService implements business logic for listing and grouping user activities (incomes, expenses, debts, receivables).
+// ActivityService implements business logic for listing and grouping user activities (incomes, expenses, debts, receivables, transfers).
 type ActivityService struct {
 	expenseRepo    port.ExpenseRepository
 	incomeRepo     port.IncomeRepository
 	debtRepo       port.DebtRepository
 	receivableRepo port.ReceivableRepository
+	transferRepo   port.TransferRepository
 }
 
 // NewActivityService returns a new ActivityService with the given repositories.
-func NewActivityService(expenseRepo port.ExpenseRepository, incomeRepo port.IncomeRepository, debtRepo port.DebtRepository, receivableRepo port.ReceivableRepository) port.ActivityService {
+func NewActivityService(expenseRepo port.ExpenseRepository, incomeRepo port.IncomeRepository, debtRepo port.DebtRepository, receivableRepo port.ReceivableRepository, transferRepo port.TransferRepository) port.ActivityService {
 	return &ActivityService{
 		expenseRepo:    expenseRepo,
 		incomeRepo:     incomeRepo,
 		debtRepo:       debtRepo,
 		receivableRepo: receivableRepo,
+		transferRepo:   transferRepo,
 	}
 }
 
-// ListActivities returns activities for the user, grouped by day, month, or year, and optionally filtered by date and timezone.
-func (s *ActivityService) ListActivities(ctx context.Context, userID int64, groupBy string, dateFilter string, timezone string) (*port.ActivityResponse, error) {
+// ListActivities returns activities for the user, grouped by day, month, or year, and optionally filtered by date, timezone and tags.
+func (s *ActivityService) ListActivities(ctx context.Context, userID int64, groupBy string, dateFilter string, timezone string, tags []string) (*port.ActivityResponse, error) {
 	groupBy = normalizeGroupBy(groupBy)
+	tags, err := normalizeTagNames(tags)
+	if err != nil {
+		return nil, err
+	}
 
-	expenses, _, err := s.expenseRepo.ListByUserID(ctx, userID, nil, nil, 1, 10000)
+	expenses, _, err := s.expenseRepo.ListByUserID(ctx, userID, nil, nil, tags, 1, 10000)
 	if err != nil {
 		return nil, fmt.Errorf("list expenses: %w", err)
 	}
-	incomes, _, err := s.incomeRepo.ListByUserID(ctx, userID, nil, nil, 1, 10000)
+	incomes, _, err := s.incomeRepo.ListByUserID(ctx, userID, nil, nil, tags, 1, 10000)
 	if err != nil {
 		return nil, fmt.Errorf("list incomes: %w", err)
 	}
-	debts, _, err := s.debtRepo.ListByUserID(ctx, userID, nil, nil, nil, 1, 1000)
+	debts, _, err := s.debtRepo.ListByUserID(ctx, userID, nil, nil, nil, tags, 1, 1000)
 	if err != nil {
 		return nil, fmt.Errorf("list debts: %w", err)
 	}
-	receivables, _, err := s.receivableRepo.ListByUserID(ctx, userID, nil, nil, nil, 1, 1000)
+	receivables, _, err := s.receivableRepo.ListByUserID(ctx, userID, nil, nil, nil, tags, 1, 1000)
 	if err != nil {
 		return nil, fmt.Errorf("list receivables: %w", err)
 	}
+	// Transfers carry no tags, so a tag filter leaves them all out.
+	var transfers []models.Transfer
+	if len(tags) == 0 {
+		if transfers, _, err = s.transferRepo.ListByUserID(ctx, userID, nil, nil, 1, 10000); err != nil {
+			return nil, fmt.Errorf("list transfers: %w", err)
+		}
+	}
 
 	var loc *time.Location
 	if timezone != "" {
@@ -68,6 +81,7 @@ func (s *ActivityService) ListActivities(ctx context.Context, userID int64, grou
 		expenses = filterExpensesByDate(expenses, dateFilter, loc)
 		debts = filterDebtsByDate(debts, dateFilter, loc)
 		receivables = filterReceivablesByDate(receivables, dateFilter, loc)
+		transfers = filterTransfersByDate(transfers, dateFilter, loc)
 	}
 
 	// group key -> slice of items (will merge and sort per group)
@@ -83,6 +97,7 @@ func (s *ActivityService) ListActivities(ctx context.Context, userID int64, grou
 			CreatedAt: incomes[i].CreatedAt,
 			Note:      incomes[i].Note,
 			Source:    incomes[i].Source,
+			Tags:      tagNames(incomes[i].Tags),
 		}
 		groupsMap[key] = append(groupsMap[key], item)
 	}
@@ -95,7 +110,10 @@ func (s *ActivityService) ListActivities(ctx context.Context, userID int64, grou
 			Date:      expenses[i].Date,
 			CreatedAt: expenses[i].CreatedAt,
 			Note:      expenses[i].Note,
-			Category:  string(expenses[i].Category),
+			Tags:      tagNames(expenses[i].Tags),
+		}
+		if c := expenses[i].Category; c != nil {
+			item.Category, item.CategoryUUID = c.Name, c.UUID
 		}
 		groupsMap[key] = append(groupsMap[key], item)
 	}
@@ -109,6 +127,7 @@ func (s *ActivityService) ListActivities(ctx context.Context, userID int64, grou
 			CreatedAt: debts[i].CreatedAt,
 			Note:      debts[i].Note,
 			PartyName: debts[i].PartyName,
+			Tags:      tagNames(debts[i].Tags),
 		}
 		groupsMap[key] = append(groupsMap[key], item)
 	}
@@ -122,6 +141,26 @@ func (s *ActivityService) ListActivities(ctx context.Context, userID int64, grou
 			CreatedAt: receivables[i].CreatedAt,
 			Note:      receivables[i].Note,
 			PartyName: receivables[i].PartyName,
+			Tags:      tagNames(receivables[i].Tags),
+		}
+		groupsMap[key] = append(groupsMap[key], item)
+	}
+
+	for i := range transfers {
+		key := groupKey(transfers[i].Date, groupBy)
+		item := port.ActivityItem{
+			Type:      "transfer",
+			UUID:      transfers[i].UUID,
+			Amount:    transfers[i].Amount,
+			Date:      transfers[i].Date,
+			CreatedAt: transfers[i].CreatedAt,
+			Note:      transfers[i].Note,
+		}
+		if transfers[i].FromAsset != nil {
+			item.FromAssetUUID = transfers[i].FromAsset.UUID
+		}
+		if transfers[i].ToAsset != nil {
+			item.ToAssetUUID = transfers[i].ToAsset.UUID
 		}
 		groupsMap[key] = append(groupsMap[key], item)
 	}
@@ -208,6 +247,30 @@ func filterReceivablesByDate(receivables []models.Receivable, dateFilter string,
 	return out
 }
 
+func filterTransfersByDate(transfers []models.Transfer, dateFilter string, loc *time.Location) []models.Transfer {
+	if dateFilter == "" {
+		return transfers
+	}
+	out := make([]models.Transfer, 0, len(transfers))
+	for i := range transfers {
+		if dateMatches(transfers[i].Date, dateFilter, loc) {
+			out = append(out, transfers[i])
+		}
+	}
+	return out
+}
+
+func tagNames(tags []models.Tag) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	names := make([]string, len(tags))
+	for i, t := range tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
 func normalizeGroupBy(g string) string {
 	normalized := strings.ToLower(strings.TrimSpace(g))
 	switch normalized {
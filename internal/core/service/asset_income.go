@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func (s *PerformanceService) GetAssetIncome(ctx context.Context, userID int64, assetUUID string, currency string) (*port.AssetIncomeResponse, error) {
+	asset, err := s.assetRepo.GetByUUID(ctx, assetUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get asset: %w", err)
+	}
+	if asset == nil {
+		return nil, fmt.Errorf("asset not found")
+	}
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency == "" {
+		currency = assetCurrency(asset)
+	}
+
+	incomes, err := s.incomeRepo.ListBySourceAssetID(ctx, asset.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list asset income: %w", err)
+	}
+	txs, err := s.txRepo.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list asset transactions: %w", err)
+	}
+	entries, err := s.incomeEntries(ctx, asset, incomes, txs, currency)
+	if err != nil {
+		return nil, err
+	}
+	costBasis, err := s.fx.Convert(ctx, costBasisAt(asset, txs, time.Now()), assetCurrency(asset), currency, time.Time{})
+	if err != nil {
+		return nil, fmt.Errorf("convert cost basis: %w", err)
+	}
+	years, total, ttm := summarizeIncome(entries, time.Now())
+
+	return &port.AssetIncomeResponse{
+		AssetUUID:      asset.UUID,
+		AssetName:      asset.Name,
+		Currency:       currency,
+		CostBasis:      costBasis,
+		TotalIncome:    total,
+		TTMIncome:      ttm,
+		TTMYieldOnCost: percentOf(ttm, costBasis),
+		EstimatedYield: asset.EstimatedYield,
+		YieldPeriod:    asset.YieldPeriod,
+		Years:          years,
+		Entries:        entries,
+	}, nil
+}
+
+// incomeEntries converts the incomes an asset paid into currency, each at the rate of its date, next to the
+// cost basis held that day.
+func (s *PerformanceService) incomeEntries(ctx context.Context, asset *models.Asset, incomes []models.Income, txs []models.AssetTransaction, currency string) ([]port.AssetIncomeEntry, error) {
+	entries := make([]port.AssetIncomeEntry, 0, len(incomes))
+	for _, inc := range incomes {
+		cashCurrency := assetCurrency(asset)
+		if inc.Asset != nil {
+			cashCurrency = assetCurrency(inc.Asset)
+		}
+		rate, err := s.fx.RateAt(ctx, cashCurrency, currency, inc.Date)
+		if err != nil {
+			return nil, fmt.Errorf("convert income: %w", err)
+		}
+		cost, err := s.fx.Convert(ctx, costBasisAt(asset, txs, inc.Date), assetCurrency(asset), currency, inc.Date)
+		if err != nil {
+			return nil, fmt.Errorf("convert cost basis: %w", err)
+		}
+		incomeType := string(models.IncomeTypeOther)
+		if inc.Type != nil {
+			incomeType = string(*inc.Type)
+		}
+		amount := inc.Amount.Mul(decimal.NewFromFloat(rate))
+		entries = append(entries, port.AssetIncomeEntry{
+			IncomeUUID:   inc.UUID,
+			Date:         inc.Date,
+			Type:         incomeType,
+			Amount:       amount,
+			CashAmount:   inc.Amount,
+			CashCurrency: cashCurrency,
+			FXRate:       decimal.NewFromFloat(rate),
+			CostBasis:    cost,
+			YieldOnCost:  percentOf(amount, cost),
+		})
+	}
+	return entries, nil
+}
+
+// costBasisAt is the cost basis held at the given time, in the asset's currency. Assets held through
+// transactions replay those up to then; others hold their recorded total cost from the purchase date.
+func costBasisAt(asset *models.Asset, txs []models.AssetTransaction, at time.Time) decimal.Decimal {
+	if asset.Type == models.AssetTypeCash || len(txs) == 0 {
+		if asset.PurchaseDate.After(at) {
+			return decimal.Zero
+		}
+		return asset.TotalCost
+	}
+	var upTo []models.AssetTransaction
+	for _, tx := range txs {
+		if !tx.TransactionDate.After(at) {
+			upTo = append(upTo, tx)
+		}
+	}
+	book, err := buildLots(upTo, asset.CostMethod)
+	if err != nil {
+		return decimal.Zero
+	}
+	return book.Cost
+}
+
+// summarizeIncome totals entries per calendar year, overall, and over the twelve months up to now.
+func summarizeIncome(entries []port.AssetIncomeEntry, now time.Time) (years []port.IncomeYear, total, ttm decimal.Decimal) {
+	byYear := make(map[int]*port.IncomeYear)
+	since := now.AddDate(-1, 0, 0)
+	for _, e := range entries {
+		total = total.Add(e.Amount)
+		if e.Date.After(since) && !e.Date.After(now) {
+			ttm = ttm.Add(e.Amount)
+		}
+		y := byYear[e.Date.Year()]
+		if y == nil {
+			y = &port.IncomeYear{Year: e.Date.Year()}
+			byYear[e.Date.Year()] = y
+		}
+		y.Income = y.Income.Add(e.Amount)
+		y.YieldOnCost = y.YieldOnCost.Add(e.YieldOnCost)
+	}
+	years = make([]port.IncomeYear, 0, len(byYear))
+	for _, y := range byYear {
+		years = append(years, *y)
+	}
+	sort.Slice(years, func(i, j int) bool { return years[i].Year < years[j].Year })
+	return years, total, ttm
+}
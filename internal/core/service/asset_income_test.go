@@ -0,0 +1,43 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"monity/internal/core/port"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_summarizeIncome(t *testing.T) {
+	d := decimal.RequireFromString
+	day := func(s string) time.Time { tm, _ := time.Parse("2006-01-02", s); return tm }
+	// Quarterly dividends of a stock held at a cost of 1,000.
+	entries := []port.AssetIncomeEntry{
+		{Date: day("2024-11-15"), Amount: d("10"), YieldOnCost: d("1")},
+		{Date: day("2025-02-15"), Amount: d("12"), YieldOnCost: d("1.2")},
+		{Date: day("2025-05-15"), Amount: d("12"), YieldOnCost: d("1.2")},
+		{Date: day("2025-08-15"), Amount: d("15"), YieldOnCost: d("1.5")},
+	}
+	tests := []struct {
+		name      string
+		now       string
+		wantTotal string
+		wantTTM   string
+	}{
+		{"trailing year covers the last three", "2025-11-15", "49", "39"},
+		{"trailing year covers all four", "2025-09-01", "49", "49"},
+		{"nothing paid in the last year", "2027-01-01", "49", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			years, total, ttm := summarizeIncome(entries, day(tt.now))
+			if !total.Equal(d(tt.wantTotal)) || !ttm.Equal(d(tt.wantTTM)) {
+				t.Errorf("summarizeIncome() total %s ttm %s; want %s %s", total, ttm, tt.wantTotal, tt.wantTTM)
+			}
+			if len(years) != 2 || years[0].Year != 2024 || !years[1].Income.Equal(d("39")) || !years[1].YieldOnCost.Equal(d("3.9")) {
+				t.Errorf("summarizeIncome() years = %+v", years)
+			}
+		})
+	}
+}
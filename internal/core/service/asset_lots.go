@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// assetLot is what is still held from one BUY or TRANSFER_IN.
+type assetLot struct {
+	TransactionUUID string
+	AcquiredAt      time.Time
+	Quantity        decimal.Decimal
+	Cost            decimal.Decimal // remaining cost basis in the asset's currency
+}
+
+// realizedEntry is the profit or loss one transaction locked in, in the asset's currency.
+type realizedEntry struct {
+	TransactionUUID string
+	Date            time.Time
+	Amount          decimal.Decimal
+	Proceeds        decimal.Decimal // a SELL's proceeds less its fee; Proceeds - Amount is the cost of the units sold
+}
+
+// lotBook is the position left after replaying an asset's transactions.
+type lotBook struct {
+	Lots     []assetLot // open lots, oldest first
+	Quantity decimal.Decimal
+	Cost     decimal.Decimal
+	// Realized holds sale proceeds less the cost of the units sold, and the fees and units paid away.
+	Realized      []realizedEntry
+	RealizedTotal decimal.Decimal
+	FirstAcquired time.Time
+	LastSale      *models.AssetTransaction // latest SELL
+}
+
+// buildLots replays txs in date order. Acquisitions open lots; disposals take units from the oldest lots
+// first. With FIFO those units leave at their own lot's cost; with AVERAGE every lot carries the pool's
+// weighted-average unit cost, so disposals leave at that average. A disposal of more than is held at that
+// point is an error.
+func buildLots(txs []models.AssetTransaction, method models.CostMethod) (lotBook, error) {
+	sorted := make([]models.AssetTransaction, len(txs))
+	copy(sorted, txs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].TransactionDate.Before(sorted[j].TransactionDate)
+	})
+
+	var b lotBook
+	for _, tx := range sorted {
+		switch tx.Type {
+		case models.AssetTransactionBuy, models.AssetTransactionTransferIn:
+			b.Lots = append(b.Lots, assetLot{
+				TransactionUUID: tx.UUID,
+				AcquiredAt:      tx.TransactionDate,
+				Quantity:        tx.Quantity,
+				Cost:            tx.TotalAmount.Add(tx.Fee),
+			})
+			if b.FirstAcquired.IsZero() {
+				b.FirstAcquired = tx.TransactionDate
+			}
+		case models.AssetTransactionSell:
+			cost, err := b.dispose(tx)
+			if err != nil {
+				return lotBook{}, err
+			}
+			net := tx.TotalAmount.Sub(tx.Fee)
+			b.realize(tx, net.Sub(cost), net)
+			sale := tx
+			b.LastSale = &sale
+		case models.AssetTransactionTransferOut:
+			// The cost basis moves out with the units; only the fee is a loss
+			if _, err := b.dispose(tx); err != nil {
+				return lotBook{}, err
+			}
+			b.realize(tx, tx.Fee.Neg(), decimal.Zero)
+		case models.AssetTransactionFee:
+			cost, err := b.dispose(tx)
+			if err != nil {
+				return lotBook{}, err
+			}
+			loss := cost
+			if !b.spreadCost(tx.TotalAmount.Add(tx.Fee)) {
+				loss = loss.Add(tx.TotalAmount.Add(tx.Fee))
+			}
+			b.realize(tx, loss.Neg(), decimal.Zero)
+		default:
+			return lotBook{}, fmt.Errorf("unknown transaction type %q", tx.Type)
+		}
+		if method == models.CostMethodAverage {
+			b.average()
+		}
+	}
+	b.total()
+	return b, nil
+}
+
+func (b *lotBook) realize(tx models.AssetTransaction, amount, proceeds decimal.Decimal) {
+	if amount.IsZero() && proceeds.IsZero() {
+		return
+	}
+	b.Realized = append(b.Realized, realizedEntry{TransactionUUID: tx.UUID, Date: tx.TransactionDate, Amount: amount, Proceeds: proceeds})
+	b.RealizedTotal = b.RealizedTotal.Add(amount)
+}
+
+// realizedBy returns what the transaction locked in.
+func (b lotBook) realizedBy(txUUID string) decimal.Decimal {
+	total := decimal.Zero
+	for _, r := range b.Realized {
+		if r.TransactionUUID == txUUID {
+			total = total.Add(r.Amount)
+		}
+	}
+	return total
+}
+
+// dispose takes tx.Quantity units from the oldest lots and returns the cost basis that left with them.
+// Under AVERAGE the lots already carry the average unit cost, so the units leave at it.
+func (b *lotBook) dispose(tx models.AssetTransaction) (decimal.Decimal, error) {
+	qty := tx.Quantity
+	if !qty.IsPositive() {
+		return decimal.Zero, nil
+	}
+	b.total()
+	if qty.GreaterThan(b.Quantity) {
+		return decimal.Zero, fmt.Errorf("%s of %s on %s exceeds the %s units held", tx.Type, qty, tx.TransactionDate.Format("2006-01-02"), b.Quantity)
+	}
+	removed := decimal.Zero
+	for len(b.Lots) > 0 && qty.IsPositive() {
+		lot := &b.Lots[0]
+		take := decimal.Min(qty, lot.Quantity)
+		cost := lot.Cost
+		if take.LessThan(lot.Quantity) {
+			cost = lot.Cost.Mul(take).Div(lot.Quantity)
+		}
+		lot.Quantity = lot.Quantity.Sub(take)
+		lot.Cost = lot.Cost.Sub(cost)
+		removed = removed.Add(cost)
+		qty = qty.Sub(take)
+		if !lot.Quantity.IsPositive() {
+			b.Lots = b.Lots[1:]
+		}
+	}
+	return removed, nil
+}
+
+// spreadCost adds amount to the open lots in proportion to their quantity; false when nothing is held.
+func (b *lotBook) spreadCost(amount decimal.Decimal) bool {
+	if amount.IsZero() {
+		return true
+	}
+	b.total()
+	if !b.Quantity.IsPositive() {
+		return false
+	}
+	for i := range b.Lots {
+		b.Lots[i].Cost = b.Lots[i].Cost.Add(amount.Mul(b.Lots[i].Quantity).Div(b.Quantity))
+	}
+	return true
+}
+
+// average gives every open lot the pool's weighted-average unit cost.
+func (b *lotBook) average() {
+	b.total()
+	if !b.Quantity.IsPositive() {
+		return
+	}
+	unit := b.Cost.Div(b.Quantity)
+	for i := range b.Lots {
+		b.Lots[i].Cost = unit.Mul(b.Lots[i].Quantity)
+	}
+}
+
+func (b *lotBook) total() {
+	b.Quantity, b.Cost = decimal.Zero, decimal.Zero
+	for _, l := range b.Lots {
+		b.Quantity = b.Quantity.Add(l.Quantity)
+		b.Cost = b.Cost.Add(l.Cost)
+	}
+}
+
+// lotSize is the number of shares in one unit of the asset's quantity: the exchange lot size of stocks
+// listed in lots (IDX: 1 lot = 100 shares), else 1. Quantities are counted in lots while PurchasePrice and
+// PricePerUnit are per share, so an amount is quantity × lot size × price.
+func lotSize(ctx context.Context, instruments port.InstrumentRegistry, asset *models.Asset) decimal.Decimal {
+	if instruments != nil {
+		if lot := instruments.LotSize(ctx, asset); lot > 1 {
+			return decimal.NewFromInt(lot)
+		}
+	}
+	return decimal.NewFromInt(1)
+}
+
+// applyLotBook sets the asset fields derived from its transactions: quantity and total cost of the open
+// lots, their average cost per share (lot of lot shares), the date of the oldest one and the realized
+// profit/loss. A position sold down to zero becomes SOLD at its last sale's price per share, and goes back
+// to ACTIVE when units are held again.
+func applyLotBook(asset *models.Asset, b lotBook, lot decimal.Decimal) {
+	asset.Quantity = b.Quantity
+	asset.TotalCost = b.Cost.Round(8)
+	asset.RealizedProfitLoss = b.RealizedTotal.Round(8)
+	if b.Quantity.IsPositive() {
+		asset.PurchasePrice = b.Cost.Div(b.Quantity.Mul(lot)).Round(8)
+		asset.PurchaseDate = b.Lots[0].AcquiredAt
+	} else if !b.FirstAcquired.IsZero() {
+		asset.PurchaseDate = b.FirstAcquired
+	}
+
+	switch {
+	case !b.Quantity.IsPositive() && b.LastSale != nil:
+		asset.Status = models.AssetStatusSold
+		soldAt := b.LastSale.TransactionDate
+		soldPrice := b.LastSale.TotalAmount.Div(b.LastSale.Quantity.Mul(lot)).Round(8)
+		asset.SoldAt, asset.SoldPrice = &soldAt, &soldPrice
+	case b.Quantity.IsPositive() && asset.Status == models.AssetStatusSold:
+		asset.Status = models.AssetStatusActive
+		asset.SoldAt, asset.SoldPrice = nil, nil
+	}
+}
+
+// syncAssetPosition re-derives a non-cash asset's position from its transactions and saves it; lot is its
+// lotSize. The asset must be locked by the caller.
+func syncAssetPosition(ctx context.Context, repos port.Repositories, asset *models.Asset, lot decimal.Decimal) error {
+	_, err := replayAssetPosition(ctx, repos, asset, lot)
+	return err
+}
+
+// replayAssetPosition is syncAssetPosition returning the replayed book.
+func replayAssetPosition(ctx context.Context, repos port.Repositories, asset *models.Asset, lot decimal.Decimal) (lotBook, error) {
+	txs, err := repos.AssetTransactions.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return lotBook{}, err
+	}
+	book, err := buildLots(txs, asset.CostMethod)
+	if err != nil {
+		return lotBook{}, err
+	}
+	oldQty := asset.Quantity
+	applyLotBook(asset, book, lot)
+	if err := repos.Assets.Update(ctx, asset); err != nil {
+		return lotBook{}, fmt.Errorf("update asset position: %w", err)
+	}
+	slog.Info("position_updated", "asset_uuid", asset.UUID, "old", oldQty.String(), "new", asset.Quantity.String(), "cost", asset.TotalCost.String())
+	return book, nil
+}
+
+// assetSalePosting credits a SELL's proceeds, less its fee, to the CASH asset it names. Other
+// transactions, and sales without a CASH asset, move no cash.
+func assetSalePosting(asset *models.Asset, tx *models.AssetTransaction) ledgerPosting {
+	p := ledgerPosting{
+		UserID:        tx.UserID,
+		ReferenceType: models.LedgerRefAssetSale,
+		ReferenceUUID: tx.UUID,
+		Description:   "sale of " + asset.Name,
+		OccurredAt:    tx.TransactionDate,
+	}
+	if amount := saleCashAmount(tx); tx.Type == models.AssetTransactionSell && tx.CashAssetID != nil && amount.IsPositive() {
+		p.Lines = []ledgerLine{
+			assetLine(*tx.CashAssetID, amount),
+			accountLine(models.LedgerAccountAssetSale, amount.Neg()),
+		}
+	}
+	return p
+}
+
+// saleCashAmount is what a SELL credits to its CASH asset, in that asset's currency.
+func saleCashAmount(tx *models.AssetTransaction) decimal.Decimal {
+	net := tx.TotalAmount.Sub(tx.Fee)
+	if tx.ExchangeRate != nil {
+		net = net.Mul(*tx.ExchangeRate)
+	}
+	return net.Round(8)
+}
+
+// openingTransaction is the BUY that starts the transaction history of an asset entered with a quantity:
+// its whole TotalCost (which includes fees) at PurchasePrice on PurchaseDate.
+func openingTransaction(asset *models.Asset, lot decimal.Decimal) *models.AssetTransaction {
+	total := asset.TotalCost
+	if !total.IsPositive() {
+		total = asset.Quantity.Mul(lot).Mul(asset.PurchasePrice)
+	}
+	note := "opening position"
+	return &models.AssetTransaction{
+		AssetID:         asset.ID,
+		UserID:          asset.UserID,
+		Type:            models.AssetTransactionBuy,
+		Quantity:        asset.Quantity,
+		PricePerUnit:    asset.PurchasePrice,
+		TotalAmount:     total,
+		Currency:        assetCurrency(asset),
+		TransactionDate: asset.PurchaseDate,
+		Notes:           &note,
+	}
+}
+
+// tracksLots reports whether the asset's position comes from transactions: every asset except CASH, whose
+// balance comes from the ledger, and PLANNED ones, which are not held yet.
+func tracksLots(asset *models.Asset) bool {
+	return asset.Type != models.AssetTypeCash && asset.Status != models.AssetStatusPlanned
+}
+
+// startLotHistory records the opening BUY of a held asset that has no transactions yet.
+func startLotHistory(ctx context.Context, repos port.Repositories, asset *models.Asset, lot decimal.Decimal) error {
+	if !tracksLots(asset) || !asset.Quantity.IsPositive() {
+		return nil
+	}
+	txs, err := repos.AssetTransactions.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	if len(txs) > 0 {
+		return nil
+	}
+	if err := repos.AssetTransactions.Create(ctx, openingTransaction(asset, lot)); err != nil {
+		return err
+	}
+	return syncAssetPosition(ctx, repos, asset, lot)
+}
+
+func parseCostMethod(s string) (models.CostMethod, error) {
+	switch m := models.CostMethod(strings.ToUpper(strings.TrimSpace(s))); m {
+	case "":
+		return models.CostMethodFIFO, nil
+	case models.CostMethodFIFO, models.CostMethodAverage:
+		return m, nil
+	default:
+		return "", errors.New("costMethod must be FIFO or AVERAGE")
+	}
+}
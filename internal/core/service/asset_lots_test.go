@@ -0,0 +1,83 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_buildLots(t *testing.T) {
+	d := decimal.RequireFromString
+	day := func(n int) time.Time { return time.Date(2024, 1, n, 0, 0, 0, 0, time.UTC) }
+	tx := func(typ models.AssetTransactionType, n int, qty, total, fee string) models.AssetTransaction {
+		return models.AssetTransaction{Type: typ, TransactionDate: day(n), Quantity: d(qty), TotalAmount: d(total), Fee: d(fee)}
+	}
+	// 1 @ 100 + 1 fee, then 1 @ 200; sell 1 for 300 less 2 fee.
+	history := []models.AssetTransaction{
+		tx(models.AssetTransactionSell, 3, "1", "300", "2"),
+		tx(models.AssetTransactionBuy, 1, "1", "100", "1"),
+		tx(models.AssetTransactionBuy, 2, "1", "200", "0"),
+	}
+	tests := []struct {
+		name         string
+		txs          []models.AssetTransaction
+		method       models.CostMethod
+		wantQty      string
+		wantCost     string
+		wantRealized string
+		wantFirstLot time.Time
+		wantErr      bool
+	}{
+		{"FIFO sells the oldest lot", history, models.CostMethodFIFO, "1", "200", "197", day(2), false},
+		{"AVERAGE sells at the pooled cost", history, models.CostMethodAverage, "1", "150.5", "147.5", day(2), false},
+		{"partial lot keeps its unit cost", []models.AssetTransaction{
+			tx(models.AssetTransactionBuy, 1, "4", "400", "0"),
+			tx(models.AssetTransactionTransferOut, 2, "1", "0", "0"),
+		}, models.CostMethodFIFO, "3", "300", "0", day(1), false},
+		{"FEE in kind is a loss, in money adds to cost", []models.AssetTransaction{
+			tx(models.AssetTransactionBuy, 1, "2", "100", "0"),
+			tx(models.AssetTransactionFee, 2, "0.5", "10", "0"),
+		}, models.CostMethodFIFO, "1.5", "85", "-25", day(1), false},
+		{"selling more than held", []models.AssetTransaction{
+			tx(models.AssetTransactionBuy, 2, "1", "100", "0"),
+			tx(models.AssetTransactionSell, 1, "1", "100", "0"),
+		}, models.CostMethodFIFO, "", "", "", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := buildLots(tt.txs, tt.method)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("buildLots() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !b.Quantity.Equal(d(tt.wantQty)) || !b.Cost.Equal(d(tt.wantCost)) || !b.RealizedTotal.Equal(d(tt.wantRealized)) {
+				t.Errorf("buildLots() = qty %s cost %s realized %s; want %s %s %s", b.Quantity, b.Cost, b.RealizedTotal, tt.wantQty, tt.wantCost, tt.wantRealized)
+			}
+			if len(b.Lots) == 0 || !b.Lots[0].AcquiredAt.Equal(tt.wantFirstLot) {
+				t.Errorf("buildLots() oldest open lot = %v; want %v", b.Lots, tt.wantFirstLot)
+			}
+		})
+	}
+}
+
+func Test_openingTransaction(t *testing.T) {
+	asset := &models.Asset{Quantity: decimal.NewFromInt(3), PurchasePrice: decimal.NewFromInt(4000), PurchaseCurrency: "IDR"}
+	lot := decimal.NewFromInt(100)
+	tx := openingTransaction(asset, lot)
+	if !tx.TotalAmount.Equal(decimal.NewFromInt(1_200_000)) {
+		t.Errorf("opening total = %s, want 3 lots × 100 shares × 4000", tx.TotalAmount)
+	}
+	book, err := buildLots([]models.AssetTransaction{*tx}, models.CostMethodFIFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	applyLotBook(asset, book, lot)
+	if !asset.PurchasePrice.Equal(decimal.NewFromInt(4000)) || !asset.Quantity.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("replayed: quantity %s at %s, want 3 lots at 4000 per share", asset.Quantity, asset.PurchasePrice)
+	}
+}
@@ -60,6 +60,7 @@ func (s *AssetPriceHistoryService) RecordPrice(ctx context.Context, userID int64
 	history := &models.AssetPriceHistory{
 		AssetID:    asset.ID,
 		Price:      decimal.NewFromFloat(req.Price),
+		Currency:   assetCurrency(asset),
 		Source:     req.Source,
 		RecordedAt: recordedAt,
 	}
@@ -113,7 +114,7 @@ func (s *AssetPriceHistoryService) FetchAndRecordPrice(ctx context.Context, user
 	}
 
 	// Fetch price from external API
-	priceData, err := s.priceService.GetPrice(ctx, string(asset.Type), *asset.Symbol)
+	priceData, err := s.priceService.GetAssetPrice(ctx, asset, port.DefaultCurrency)
 	if err != nil {
 		return nil, fmt.Errorf("fetch price: %w", err)
 	}
@@ -122,6 +123,7 @@ func (s *AssetPriceHistoryService) FetchAndRecordPrice(ctx context.Context, user
 	history := &models.AssetPriceHistory{
 		AssetID:    asset.ID,
 		Price:      decimal.NewFromFloat(priceData.Price),
+		Currency:   priceData.Currency,
 		Source:     priceData.Source,
 		RecordedAt: priceData.FetchedAt,
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"monity/internal/core/port"
@@ -14,11 +15,13 @@ import (
 )
 
 type AssetService struct {
-	repo port.AssetRepository
+	repo        port.AssetRepository
+	instruments port.InstrumentRegistry
+	uow         port.UnitOfWork
 }
 
-func NewAssetService(repo port.AssetRepository) port.AssetService {
-	return &AssetService{repo: repo}
+func NewAssetService(repo port.AssetRepository, instruments port.InstrumentRegistry, uow port.UnitOfWork) port.AssetService {
+	return &AssetService{repo: repo, instruments: instruments, uow: uow}
 }
 
 func (s *AssetService) CreateAsset(ctx context.Context, userID int64, req port.CreateAssetRequest) (*models.Asset, error) {
@@ -30,6 +33,11 @@ func (s *AssetService) CreateAsset(ctx context.Context, userID int64, req port.C
 			return nil, fmt.Errorf("symbol %w", err)
 		}
 	}
+	if req.PriceProviderID != nil {
+		if err := validation.CheckMaxLen(*req.PriceProviderID, validation.MaxProviderIDLen); err != nil {
+			return nil, fmt.Errorf("priceProviderId %w", err)
+		}
+	}
 	if req.Description != nil {
 		if err := validation.CheckMaxLen(*req.Description, validation.MaxDescriptionLen); err != nil {
 			return nil, fmt.Errorf("description %w", err)
@@ -65,19 +73,25 @@ func (s *AssetService) CreateAsset(ctx context.Context, userID int64, req port.C
 	if req.Status != nil {
 		status = *req.Status
 	}
+	costMethod, err := parseCostMethod(req.CostMethod)
+	if err != nil {
+		return nil, err
+	}
 
 	asset := &models.Asset{
-		UserID:   userID,
-		Name:     req.Name,
-		Type:     req.Type,
-		Quantity: decimal.NewFromFloat(req.Quantity),
-		Symbol:   req.Symbol,
+		UserID:          userID,
+		Name:            req.Name,
+		Type:            req.Type,
+		Quantity:        decimal.NewFromFloat(req.Quantity),
+		Symbol:          req.Symbol,
+		PriceProviderID: normalizeProviderID(req.PriceProviderID),
 
 		// Purchase Information
 		PurchasePrice:    decimal.NewFromFloat(req.PurchasePrice),
 		PurchaseDate:     purchaseDate,
 		PurchaseCurrency: purchaseCurrency,
 		TotalCost:        decimal.NewFromFloat(req.TotalCost),
+		CostMethod:       costMethod,
 
 		// Documentation
 		Description: req.Description,
@@ -114,8 +128,19 @@ func (s *AssetService) CreateAsset(ctx context.Context, userID int64, req port.C
 		asset.YieldPeriod = req.YieldPeriod
 	}
 
-	if err := s.repo.Create(ctx, asset); err != nil {
-		return nil, fmt.Errorf("create asset: %w", err)
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		if err := repos.Assets.Create(ctx, asset); err != nil {
+			return fmt.Errorf("create asset: %w", err)
+		}
+		if asset.Type != models.AssetTypeCash {
+			// The entered holding becomes the first purchase lot
+			return startLotHistory(ctx, repos, asset, lotSize(ctx, s.instruments, asset))
+		}
+		// Opening balance of a CASH asset goes through the ledger like any other balance change
+		return adjustAssetLedger(ctx, repos, asset, models.LedgerRefOpeningBalance, models.LedgerAccountOpeningBalance, asset.Quantity, "opening balance")
+	})
+	if err != nil {
+		return nil, err
 	}
 	return asset, nil
 }
@@ -157,15 +182,59 @@ func (s *AssetService) ListAssets(ctx context.Context, userID int64, page, limit
 }
 
 func (s *AssetService) UpdateAsset(ctx context.Context, userID int64, uuid string, req port.UpdateAssetRequest) (*models.Asset, error) {
-	asset, err := s.GetAsset(ctx, userID, uuid)
+	var asset *models.Asset
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		asset, err = repos.Assets.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get asset: %w", err)
+		}
+		if asset == nil {
+			return errors.New("asset not found")
+		}
+		txs, err := repos.AssetTransactions.ListByAssetID(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+		if len(txs) > 0 {
+			if err := checkDerivedUnchanged(asset, req); err != nil {
+				return err
+			}
+		}
+		if err := applyAssetUpdate(asset, req); err != nil {
+			return err
+		}
+		if err := repos.Assets.Update(ctx, asset); err != nil {
+			return fmt.Errorf("update asset: %w", err)
+		}
+		if asset.Type != models.AssetTypeCash {
+			lot := lotSize(ctx, s.instruments, asset)
+			if len(txs) > 0 {
+				// The cost method may have changed
+				return syncAssetPosition(ctx, repos, asset, lot)
+			}
+			return startLotHistory(ctx, repos, asset, lot)
+		}
+
+		// A manual quantity edit on a CASH asset is recorded as a ledger adjustment
+		balance, err := repos.Ledger.BalanceByAssetID(ctx, asset.ID)
+		if err != nil {
+			return err
+		}
+		delta := asset.Quantity.Sub(balance)
+		return adjustAssetLedger(ctx, repos, asset, models.LedgerRefAdjustment, models.LedgerAccountAdjustment, delta, "manual adjustment")
+	})
 	if err != nil {
 		return nil, err
 	}
+	return asset, nil
+}
 
+func applyAssetUpdate(asset *models.Asset, req port.UpdateAssetRequest) error {
 	// Basic fields
 	if req.Name != nil {
 		if err := validation.CheckMaxLen(*req.Name, validation.MaxAssetNameLen); err != nil {
-			return nil, fmt.Errorf("name %w", err)
+			return fmt.Errorf("name %w", err)
 		}
 		asset.Name = *req.Name
 	}
@@ -177,10 +246,16 @@ func (s *AssetService) UpdateAsset(ctx context.Context, userID int64, uuid strin
 	}
 	if req.Symbol != nil {
 		if err := validation.CheckMaxLen(*req.Symbol, validation.MaxSymbolLen); err != nil {
-			return nil, fmt.Errorf("symbol %w", err)
+			return fmt.Errorf("symbol %w", err)
 		}
 		asset.Symbol = req.Symbol
 	}
+	if req.PriceProviderID != nil {
+		if err := validation.CheckMaxLen(*req.PriceProviderID, validation.MaxProviderIDLen); err != nil {
+			return fmt.Errorf("priceProviderId %w", err)
+		}
+		asset.PriceProviderID = normalizeProviderID(req.PriceProviderID)
+	}
 
 	// Purchase Information
 	if req.PurchasePrice != nil {
@@ -189,7 +264,7 @@ func (s *AssetService) UpdateAsset(ctx context.Context, userID int64, uuid strin
 	if req.PurchaseDate != nil {
 		parsed, err := time.Parse(time.RFC3339, *req.PurchaseDate)
 		if err != nil {
-			return nil, fmt.Errorf("invalid purchase date format: %w", err)
+			return fmt.Errorf("invalid purchase date format: %w", err)
 		}
 		asset.PurchaseDate = parsed
 	}
@@ -199,6 +274,13 @@ func (s *AssetService) UpdateAsset(ctx context.Context, userID int64, uuid strin
 	if req.TotalCost != nil {
 		asset.TotalCost = decimal.NewFromFloat(*req.TotalCost)
 	}
+	if req.CostMethod != nil {
+		method, err := parseCostMethod(*req.CostMethod)
+		if err != nil {
+			return err
+		}
+		asset.CostMethod = method
+	}
 
 	// Additional Costs
 	if req.TransactionFee != nil {
@@ -229,7 +311,7 @@ func (s *AssetService) UpdateAsset(ctx context.Context, userID int64, uuid strin
 	}
 	if req.YieldPeriod != nil {
 		if err := validation.CheckMaxLen(*req.YieldPeriod, validation.MaxYieldPeriodLen); err != nil {
-			return nil, fmt.Errorf("yieldPeriod %w", err)
+			return fmt.Errorf("yieldPeriod %w", err)
 		}
 		asset.YieldPeriod = req.YieldPeriod
 	}
@@ -237,13 +319,13 @@ func (s *AssetService) UpdateAsset(ctx context.Context, userID int64, uuid strin
 	// Documentation
 	if req.Description != nil {
 		if err := validation.CheckMaxLen(*req.Description, validation.MaxDescriptionLen); err != nil {
-			return nil, fmt.Errorf("description %w", err)
+			return fmt.Errorf("description %w", err)
 		}
 		asset.Description = req.Description
 	}
 	if req.Notes != nil {
 		if err := validation.CheckMaxLen(*req.Notes, validation.MaxNoteLen); err != nil {
-			return nil, fmt.Errorf("notes %w", err)
+			return fmt.Errorf("notes %w", err)
 		}
 		asset.Notes = req.Notes
 	}
@@ -263,15 +345,79 @@ func (s *AssetService) UpdateAsset(ctx context.Context, userID int64, uuid strin
 		asset.SoldPrice = &price
 	}
 
-	if err := s.repo.Update(ctx, asset); err != nil {
-		return nil, fmt.Errorf("update asset: %w", err)
+	return nil
+}
+
+// checkDerivedUnchanged rejects edits to the fields of an asset that are derived from its transactions,
+// including moving it in or out of SOLD. Sending them back unchanged is fine.
+func checkDerivedUnchanged(asset *models.Asset, req port.UpdateAssetRequest) error {
+	changed := (req.Quantity != nil && !decimal.NewFromFloat(*req.Quantity).Equal(asset.Quantity)) ||
+		(req.PurchasePrice != nil && !decimal.NewFromFloat(*req.PurchasePrice).Equal(asset.PurchasePrice)) ||
+		(req.TotalCost != nil && !decimal.NewFromFloat(*req.TotalCost).Equal(asset.TotalCost)) ||
+		(req.PurchaseCurrency != nil && normalizeCurrency(*req.PurchaseCurrency) != assetCurrency(asset)) ||
+		(req.Type != nil && *req.Type == models.AssetTypeCash)
+	if !changed && req.PurchaseDate != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.PurchaseDate)
+		changed = err != nil || !parsed.Equal(asset.PurchaseDate)
 	}
-	return asset, nil
+	if changed {
+		return errors.New("quantity, cost and purchase details cannot be edited once an asset has transactions; record a transaction instead")
+	}
+	soldChanged := req.Status != nil && *req.Status != asset.Status && (*req.Status == models.AssetStatusSold || asset.Status == models.AssetStatusSold)
+	if req.SoldPrice != nil {
+		soldChanged = soldChanged || asset.SoldPrice == nil || !decimal.NewFromFloat(*req.SoldPrice).Equal(*asset.SoldPrice)
+	}
+	if req.SoldAt != nil {
+		parsed, err := time.Parse(time.RFC3339, *req.SoldAt)
+		soldChanged = soldChanged || err != nil || asset.SoldAt == nil || !parsed.Equal(*asset.SoldAt)
+	}
+	if soldChanged {
+		return errors.New("status, soldAt and soldPrice must be changed by selling or buying units; they follow the asset's transactions")
+	}
+	return nil
 }
 
+// DeleteAsset removes an asset. A CASH asset takes its opening balance and manual adjustments out of the ledger
+// with it; any other posting on it (an expense, income, transfer, payment or sale) also moved another account,
+// so the asset cannot be deleted while one is left.
 func (s *AssetService) DeleteAsset(ctx context.Context, userID int64, uuid string) error {
-	if err := s.repo.Delete(ctx, uuid, userID); err != nil {
-		return fmt.Errorf("delete asset: %w", err)
+	return s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		asset, err := repos.Assets.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get asset: %w", err)
+		}
+		if asset == nil {
+			return errors.New("asset not found or not owned by user")
+		}
+		if asset.Type == models.AssetTypeCash {
+			for _, refType := range []models.LedgerReferenceType{models.LedgerRefOpeningBalance, models.LedgerRefAdjustment} {
+				if err := repos.Ledger.DeleteByReference(ctx, refType, asset.UUID); err != nil {
+					return err
+				}
+			}
+			_, remaining, err := repos.Ledger.ListByAssetID(ctx, asset.ID, 1, 1)
+			if err != nil {
+				return err
+			}
+			if remaining > 0 {
+				return errors.New("asset has cash movements in its ledger and cannot be deleted")
+			}
+		}
+		if err := repos.Assets.Delete(ctx, uuid, userID); err != nil {
+			return fmt.Errorf("delete asset: %w", err)
+		}
+		return nil
+	})
+}
+
+// normalizeProviderID trims a pinned provider ID; blank means not pinned.
+func normalizeProviderID(id *string) *string {
+	if id == nil {
+		return nil
 	}
-	return nil
+	trimmed := strings.ToLower(strings.TrimSpace(*id))
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
 }
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+	"monity/internal/pkg/validation"
+
+	"github.com/shopspring/decimal"
+)
+
+type AssetTransactionService struct {
+	repo        port.AssetTransactionRepository
+	assetRepo   port.AssetRepository
+	instruments port.InstrumentRegistry
+	fx          port.FXService
+	uow         port.UnitOfWork
+}
+
+func NewAssetTransactionService(repo port.AssetTransactionRepository, assetRepo port.AssetRepository, instruments port.InstrumentRegistry, fx port.FXService, uow port.UnitOfWork) port.AssetTransactionService {
+	return &AssetTransactionService{repo: repo, assetRepo: assetRepo, instruments: instruments, fx: fx, uow: uow}
+}
+
+// checkLotAsset returns the asset if its position can be recorded through transactions.
+func checkLotAsset(asset *models.Asset) (*models.Asset, error) {
+	if asset == nil {
+		return nil, errors.New("asset not found")
+	}
+	if asset.Type == models.AssetTypeCash {
+		return nil, errors.New("asset must be a non-cash asset; cash balances come from the ledger")
+	}
+	return asset, nil
+}
+
+func (s *AssetTransactionService) lookupAsset(ctx context.Context, userID int64, assetUUID string) (*models.Asset, error) {
+	asset, err := s.assetRepo.GetByUUID(ctx, assetUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get asset: %w", err)
+	}
+	return checkLotAsset(asset)
+}
+
+func lockLotAsset(ctx context.Context, repos port.Repositories, userID int64, assetUUID string) (*models.Asset, error) {
+	asset, err := repos.Assets.GetByUUIDForUpdate(ctx, assetUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get asset: %w", err)
+	}
+	return checkLotAsset(asset)
+}
+
+func (s *AssetTransactionService) ListTransactions(ctx context.Context, userID int64, assetUUID string) ([]models.AssetTransaction, error) {
+	asset, err := s.lookupAsset(ctx, userID, assetUUID)
+	if err != nil {
+		return nil, err
+	}
+	txs, err := s.repo.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		return nil, err
+	}
+	if txs == nil {
+		txs = []models.AssetTransaction{}
+	}
+	return txs, nil
+}
+
+func (s *AssetTransactionService) GetTransaction(ctx context.Context, userID int64, assetUUID, txUUID string) (*models.AssetTransaction, error) {
+	asset, err := s.lookupAsset(ctx, userID, assetUUID)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := s.repo.GetByUUID(ctx, asset.ID, txUUID)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		return nil, errors.New("transaction not found")
+	}
+	return tx, nil
+}
+
+func (s *AssetTransactionService) CreateTransaction(ctx context.Context, userID int64, assetUUID string, req port.CreateAssetTransactionRequest) (*models.AssetTransaction, error) {
+	date := time.Now()
+	if req.TransactionDate != nil {
+		date = *req.TransactionDate
+	}
+	tx := &models.AssetTransaction{
+		UserID:          userID,
+		Type:            req.Type,
+		Quantity:        decimal.NewFromFloat(req.Quantity),
+		PricePerUnit:    decimal.NewFromFloat(req.PricePerUnit),
+		Fee:             decimal.NewFromFloat(req.Fee),
+		Currency:        normalizeCurrency(req.Currency),
+		TransactionDate: date,
+		Notes:           req.Notes,
+	}
+	if req.TotalAmount != nil {
+		tx.TotalAmount = decimal.NewFromFloat(*req.TotalAmount)
+	}
+	if err := validateAssetTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		asset, err := lockLotAsset(ctx, repos, userID, assetUUID)
+		if err != nil {
+			return err
+		}
+		if tx.Currency == "" {
+			tx.Currency = assetCurrency(asset)
+		}
+		if tx.Currency != assetCurrency(asset) {
+			return fmt.Errorf("currency must be the asset's purchase currency %s", assetCurrency(asset))
+		}
+		lot := lotSize(ctx, s.instruments, asset)
+		if req.TotalAmount == nil {
+			tx.TotalAmount = tx.Quantity.Mul(lot).Mul(tx.PricePerUnit)
+		}
+		tx.AssetID = asset.ID
+		if err := repos.AssetTransactions.Create(ctx, tx); err != nil {
+			return err
+		}
+		return syncAssetPosition(ctx, repos, asset, lot)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// UpdateTransaction edits a transaction and replays the asset. When quantity or price change without a
+// new totalAmount, the total is recomputed from them.
+func (s *AssetTransactionService) UpdateTransaction(ctx context.Context, userID int64, assetUUID, txUUID string, req port.UpdateAssetTransactionRequest) (*models.AssetTransaction, error) {
+	var tx *models.AssetTransaction
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		asset, err := lockLotAsset(ctx, repos, userID, assetUUID)
+		if err != nil {
+			return err
+		}
+		if tx, err = repos.AssetTransactions.GetByUUID(ctx, asset.ID, txUUID); err != nil {
+			return err
+		}
+		if tx == nil {
+			return errors.New("transaction not found")
+		}
+		locked, err := lockSaleCash(ctx, repos, userID, tx)
+		if err != nil {
+			return err
+		}
+		lot := lotSize(ctx, s.instruments, asset)
+
+		if req.Type != nil {
+			tx.Type = *req.Type
+		}
+		if req.Quantity != nil {
+			tx.Quantity = decimal.NewFromFloat(*req.Quantity)
+		}
+		if req.PricePerUnit != nil {
+			tx.PricePerUnit = decimal.NewFromFloat(*req.PricePerUnit)
+		}
+		if req.TotalAmount != nil {
+			tx.TotalAmount = decimal.NewFromFloat(*req.TotalAmount)
+		} else if req.Quantity != nil || req.PricePerUnit != nil {
+			tx.TotalAmount = tx.Quantity.Mul(lot).Mul(tx.PricePerUnit)
+		}
+		if req.Fee != nil {
+			tx.Fee = decimal.NewFromFloat(*req.Fee)
+		}
+		if req.TransactionDate != nil {
+			tx.TransactionDate = *req.TransactionDate
+		}
+		if req.Notes != nil {
+			tx.Notes = req.Notes
+		}
+		if tx.Type != models.AssetTransactionSell {
+			tx.CashAssetID, tx.ExchangeRate = nil, nil
+		}
+		if err := validateAssetTransaction(tx); err != nil {
+			return err
+		}
+
+		if err := repos.AssetTransactions.Update(ctx, tx); err != nil {
+			return err
+		}
+		if err := syncAssetPosition(ctx, repos, asset, lot); err != nil {
+			return err
+		}
+		// A sale credited to cash moves the cash with it
+		return postLedgerWithinBalance(ctx, repos, assetSalePosting(asset, tx), locked, "taking back the sale proceeds exceeds the cash asset balance")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (s *AssetTransactionService) DeleteTransaction(ctx context.Context, userID int64, assetUUID, txUUID string) error {
+	return s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		asset, err := lockLotAsset(ctx, repos, userID, assetUUID)
+		if err != nil {
+			return err
+		}
+		tx, err := repos.AssetTransactions.GetByUUID(ctx, asset.ID, txUUID)
+		if err != nil {
+			return err
+		}
+		if tx == nil {
+			return errors.New("transaction not found")
+		}
+		locked, err := lockSaleCash(ctx, repos, userID, tx)
+		if err != nil {
+			return err
+		}
+		if err := repos.AssetTransactions.Delete(ctx, asset.ID, txUUID); err != nil {
+			return err
+		}
+		if err := syncAssetPosition(ctx, repos, asset, lotSize(ctx, s.instruments, asset)); err != nil {
+			return err
+		}
+		// Takes back the proceeds of a sale credited to cash
+		tx.CashAssetID = nil
+		return postLedgerWithinBalance(ctx, repos, assetSalePosting(asset, tx), locked, "taking back the sale proceeds exceeds the cash asset balance")
+	})
+}
+
+// lockSaleCash locks the CASH asset a sale's proceeds went to, after the sold asset like SellAsset does, and
+// checks it is still one of the user's CASH assets. A transaction without proceeds locks nothing.
+func lockSaleCash(ctx context.Context, repos port.Repositories, userID int64, tx *models.AssetTransaction) (map[int64]*models.Asset, error) {
+	if tx.CashAssetID == nil {
+		return nil, nil
+	}
+	locked, err := lockAssetsByID(ctx, repos.Assets, *tx.CashAssetID)
+	if err != nil {
+		return nil, err
+	}
+	cash := locked[*tx.CashAssetID]
+	if cash == nil || cash.UserID != userID {
+		return nil, errors.New("cash asset not found")
+	}
+	if _, err := checkCashAsset(cash); err != nil {
+		return nil, err
+	}
+	return locked, nil
+}
+
+func (s *AssetTransactionService) SellAsset(ctx context.Context, userID int64, assetUUID string, req port.SellAssetRequest) (*port.AssetSaleResponse, error) {
+	if req.PricePerUnit == nil && req.TotalAmount == nil {
+		return nil, errors.New("pricePerUnit or totalAmount must be given")
+	}
+	if req.Quantity != nil && *req.Quantity <= 0 {
+		return nil, errors.New("quantity must be positive")
+	}
+	if req.ExchangeRate != nil && *req.ExchangeRate <= 0 {
+		return nil, errors.New("exchangeRate must be positive")
+	}
+	date := time.Now()
+	if req.Date != nil {
+		date = *req.Date
+	}
+
+	var resp *port.AssetSaleResponse
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		asset, err := lockLotAsset(ctx, repos, userID, assetUUID)
+		if err != nil {
+			return err
+		}
+		var cash *models.Asset
+		if req.CashAssetUUID != nil {
+			if cash, err = lockCashAsset(ctx, repos.Assets, *req.CashAssetUUID, userID); err != nil {
+				if err.Error() == "asset not found" {
+					return errors.New("cash asset not found")
+				}
+				return err
+			}
+		}
+
+		// Without a quantity the whole position is sold
+		qty := asset.Quantity
+		if req.Quantity != nil {
+			qty = decimal.NewFromFloat(*req.Quantity)
+		}
+		if !qty.IsPositive() {
+			return errors.New("quantity must be positive; the asset holds no units")
+		}
+		tx := &models.AssetTransaction{
+			AssetID:         asset.ID,
+			UserID:          userID,
+			Type:            models.AssetTransactionSell,
+			Quantity:        qty,
+			Fee:             decimal.NewFromFloat(req.Fee),
+			Currency:        assetCurrency(asset),
+			TransactionDate: date,
+			Notes:           req.Notes,
+		}
+		lot := lotSize(ctx, s.instruments, asset)
+		if req.TotalAmount != nil {
+			tx.TotalAmount = decimal.NewFromFloat(*req.TotalAmount)
+			tx.PricePerUnit = tx.TotalAmount.Div(qty.Mul(lot)).Round(8)
+		}
+		if req.PricePerUnit != nil {
+			tx.PricePerUnit = decimal.NewFromFloat(*req.PricePerUnit)
+			if req.TotalAmount == nil {
+				tx.TotalAmount = qty.Mul(lot).Mul(tx.PricePerUnit)
+			}
+		}
+		if err := validateAssetTransaction(tx); err != nil {
+			return err
+		}
+		var locked map[int64]*models.Asset
+		if cash != nil {
+			rate, err := s.saleRate(ctx, assetCurrency(asset), assetCurrency(cash), date, req.ExchangeRate)
+			if err != nil {
+				return err
+			}
+			tx.CashAssetID, tx.ExchangeRate = &cash.ID, &rate
+			locked = map[int64]*models.Asset{cash.ID: cash}
+		}
+
+		if err := repos.AssetTransactions.Create(ctx, tx); err != nil {
+			return err
+		}
+		book, err := replayAssetPosition(ctx, repos, asset, lot)
+		if err != nil {
+			return err
+		}
+		if err := postLedger(ctx, repos, assetSalePosting(asset, tx), locked); err != nil {
+			return err
+		}
+		resp = &port.AssetSaleResponse{
+			Asset:              asset,
+			Transaction:        tx,
+			RealizedProfitLoss: book.realizedBy(tx.UUID),
+		}
+		if cash != nil {
+			resp.CashAmount, resp.CashCurrency = saleCashAmount(tx), assetCurrency(cash)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("asset_sold", "user_id", userID, "asset_uuid", assetUUID, "quantity", resp.Transaction.Quantity.String(), "realized", resp.RealizedProfitLoss.String())
+	return resp, nil
+}
+
+// saleRate converts sale proceeds into the CASH asset's currency: the caller's rate if given, otherwise the
+// market rate on the sale date.
+func (s *AssetTransactionService) saleRate(ctx context.Context, from, to string, date time.Time, override *float64) (decimal.Decimal, error) {
+	if override != nil {
+		return decimal.NewFromFloat(*override), nil
+	}
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+	rate, err := s.fx.RateAt(ctx, from, to, date)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("get exchange rate: %w", err)
+	}
+	return decimal.NewFromFloat(rate), nil
+}
+
+func validateAssetTransaction(tx *models.AssetTransaction) error {
+	switch tx.Type {
+	case models.AssetTransactionBuy, models.AssetTransactionSell, models.AssetTransactionTransferIn, models.AssetTransactionTransferOut:
+		if !tx.Quantity.IsPositive() {
+			return errors.New("quantity must be positive")
+		}
+	case models.AssetTransactionFee:
+		if tx.Quantity.IsNegative() {
+			return errors.New("quantity must be zero or more")
+		}
+		if tx.Quantity.IsZero() && tx.TotalAmount.Add(tx.Fee).IsZero() {
+			return errors.New("a FEE must be given a quantity or an amount")
+		}
+	default:
+		return errors.New("type must be one of BUY, SELL, TRANSFER_IN, TRANSFER_OUT, FEE")
+	}
+	if tx.PricePerUnit.IsNegative() || tx.TotalAmount.IsNegative() || tx.Fee.IsNegative() {
+		return errors.New("pricePerUnit, totalAmount and fee must be zero or more")
+	}
+	if tx.Type == models.AssetTransactionSell && tx.Fee.GreaterThan(tx.TotalAmount) {
+		return errors.New("fee must be at most the sale's totalAmount")
+	}
+	if tx.TransactionDate.After(time.Now().Add(24 * time.Hour)) {
+		return errors.New("transactionDate must be today or earlier")
+	}
+	if tx.Notes != nil {
+		if err := validation.CheckMaxLen(*tx.Notes, validation.MaxNoteLen); err != nil {
+			return fmt.Errorf("notes %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestAssetTransactionService_saleProceeds(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	cash := store.cash(1, "IDR", 0)
+	stock := store.lotAsset(1, models.AssetTypeStock, "IDR")
+	svc := NewAssetTransactionService(store.assetTxs, store.assets, nil, nil, store)
+
+	check := func(step string, wantCash, wantUnits float64) {
+		t.Helper()
+		if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromFloat(wantCash)) {
+			t.Errorf("%s: cash = %s, want %v", step, got, wantCash)
+		}
+		if got := store.ledger.balance(models.LedgerAccountAssetSale, 0); !got.Equal(decimal.NewFromFloat(-wantCash)) {
+			t.Errorf("%s: sale clearing account = %s, want %v", step, got, -wantCash)
+		}
+		if got := store.quantity(stock.ID); !got.Equal(decimal.NewFromFloat(wantUnits)) {
+			t.Errorf("%s: units = %s, want %v", step, got, wantUnits)
+		}
+	}
+	wantRefused := func(step string, err error) {
+		t.Helper()
+		if err == nil || !strings.Contains(err.Error(), "exceeds the cash asset balance") {
+			t.Errorf("%s: err = %v, want balance error", step, err)
+		}
+	}
+	float := func(v float64) *float64 { return &v }
+
+	bought := time.Now().Add(-24 * time.Hour)
+	if _, err := svc.CreateTransaction(ctx, 1, stock.UUID, port.CreateAssetTransactionRequest{Type: models.AssetTransactionBuy, Quantity: 10, PricePerUnit: 5, TransactionDate: &bought}); err != nil {
+		t.Fatalf("CreateTransaction: %v", err)
+	}
+
+	qty := 4.0
+	sale, err := svc.SellAsset(ctx, 1, stock.UUID, port.SellAssetRequest{Quantity: &qty, PricePerUnit: float(8), Fee: 2, CashAssetUUID: &cash.UUID})
+	if err != nil {
+		t.Fatalf("SellAsset: %v", err)
+	}
+	check("sell", 30, 6)
+	if !sale.CashAmount.Equal(decimal.NewFromInt(30)) || !sale.RealizedProfitLoss.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("sell: cash amount %s, realized %s; want 30 and 10", sale.CashAmount, sale.RealizedProfitLoss)
+	}
+
+	// Once the proceeds are spent, the sale cannot be shrunk or deleted
+	store.adjust(cash.ID, -25)
+	_, err = svc.UpdateTransaction(ctx, 1, stock.UUID, sale.Transaction.UUID, port.UpdateAssetTransactionRequest{TotalAmount: float(10)})
+	wantRefused("smaller sale", err)
+	wantRefused("delete", svc.DeleteTransaction(ctx, 1, stock.UUID, sale.Transaction.UUID))
+	if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("refused: cash = %s, want 5", got)
+	}
+	if got := store.quantity(stock.ID); !got.Equal(decimal.NewFromInt(6)) {
+		t.Errorf("refused: units = %s, want 6", got)
+	}
+
+	if _, err := svc.UpdateTransaction(ctx, 1, stock.UUID, sale.Transaction.UUID, port.UpdateAssetTransactionRequest{TotalAmount: float(30)}); err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("smaller sale: cash = %s, want 3", got)
+	}
+
+	store.adjust(cash.ID, 25)
+	if err := svc.DeleteTransaction(ctx, 1, stock.UUID, sale.Transaction.UUID); err != nil {
+		t.Fatalf("DeleteTransaction: %v", err)
+	}
+	if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromInt(0)) {
+		t.Errorf("delete: cash = %s, want 0", got)
+	}
+	if got := store.quantity(stock.ID); !got.Equal(decimal.NewFromInt(10)) {
+		t.Errorf("delete: units = %s, want 10", got)
+	}
+}
+
+func TestAssetTransactionService_lotSize(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	stock := store.lotAsset(1, models.AssetTypeStock, "IDR")
+	symbol := "BBRI"
+	store.assets.rows[stock.ID].Symbol = &symbol
+	registry := NewInstrumentRegistry(memInstrumentRepo{{Symbol: "BBRI", Exchange: "IDX", Currency: "IDR", ProviderSymbol: "BBRI.JK", LotSize: 100}})
+	svc := NewAssetTransactionService(store.assetTxs, store.assets, registry, nil, store)
+
+	check := func(step string, wantCost, wantPrice float64) {
+		t.Helper()
+		asset := store.assets.get(stock.ID)
+		if !asset.TotalCost.Equal(decimal.NewFromFloat(wantCost)) || !asset.PurchasePrice.Equal(decimal.NewFromFloat(wantPrice)) {
+			t.Errorf("%s: total cost %s, purchase price %s; want %v and %v per share", step, asset.TotalCost, asset.PurchasePrice, wantCost, wantPrice)
+		}
+	}
+
+	// Quantity is in lots of 100 shares and the price is per share
+	bought := time.Now().Add(-48 * time.Hour)
+	buy, err := svc.CreateTransaction(ctx, 1, stock.UUID, port.CreateAssetTransactionRequest{Type: models.AssetTransactionBuy, Quantity: 2, PricePerUnit: 5000, TransactionDate: &bought})
+	if err != nil {
+		t.Fatalf("CreateTransaction: %v", err)
+	}
+	if !buy.TotalAmount.Equal(decimal.NewFromInt(1_000_000)) {
+		t.Errorf("buy total = %s, want 1000000", buy.TotalAmount)
+	}
+	check("buy", 1_000_000, 5000)
+
+	price := 6500.0
+	if _, err := svc.UpdateTransaction(ctx, 1, stock.UUID, buy.UUID, port.UpdateAssetTransactionRequest{PricePerUnit: &price}); err != nil {
+		t.Fatalf("UpdateTransaction: %v", err)
+	}
+	check("repriced", 1_300_000, 6500)
+
+	qty, total := 1.0, 700_000.0
+	sale, err := svc.SellAsset(ctx, 1, stock.UUID, port.SellAssetRequest{Quantity: &qty, TotalAmount: &total})
+	if err != nil {
+		t.Fatalf("SellAsset: %v", err)
+	}
+	if !sale.Transaction.PricePerUnit.Equal(decimal.NewFromInt(7000)) || !sale.RealizedProfitLoss.Equal(decimal.NewFromInt(50_000)) {
+		t.Errorf("sale: price per unit %s, realized %s; want 7000 and 50000", sale.Transaction.PricePerUnit, sale.RealizedProfitLoss)
+	}
+	check("sell", 650_000, 6500)
+
+	price = 7200
+	if _, err := svc.SellAsset(ctx, 1, stock.UUID, port.SellAssetRequest{PricePerUnit: &price}); err != nil {
+		t.Fatalf("SellAsset: %v", err)
+	}
+	if asset := store.assets.get(stock.ID); asset.Status != models.AssetStatusSold || asset.SoldPrice == nil || !asset.SoldPrice.Equal(decimal.NewFromInt(7200)) {
+		t.Errorf("sold out: status %s, sold price %v; want SOLD at 7200 per share", asset.Status, asset.SoldPrice)
+	}
+}
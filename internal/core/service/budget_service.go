@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+type BudgetService struct {
+	repo     port.BudgetRepository
+	catRepo  port.CategoryRepository
+	insights port.InsightRepository
+}
+
+// NewBudgetService measures budgets against the same expense totals as the cash-flow insight.
+func NewBudgetService(repo port.BudgetRepository, catRepo port.CategoryRepository, insights port.InsightRepository) port.BudgetService {
+	return &BudgetService{repo: repo, catRepo: catRepo, insights: insights}
+}
+
+func (s *BudgetService) CreateBudget(ctx context.Context, userID int64, req port.CreateBudgetRequest) (*models.Budget, error) {
+	if req.Period == "" {
+		req.Period = models.BudgetPeriodMonthly
+	}
+	if req.Period != models.BudgetPeriodMonthly && req.Period != models.BudgetPeriodRolling {
+		return nil, errors.New("period must be MONTHLY or ROLLING")
+	}
+	if req.Rollover && req.Period != models.BudgetPeriodRolling {
+		return nil, errors.New("rollover must only be set on ROLLING budgets")
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	month, _, err := parseMonthRange(req.Month)
+	if err != nil {
+		return nil, err
+	}
+	category, err := resolveCategory(ctx, s.catRepo, userID, req.CategoryUUID, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByCategory(ctx, userID, category.ID, req.Period, month)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if req.Period == models.BudgetPeriodRolling {
+			return nil, fmt.Errorf("a ROLLING budget for %s already exists", category.Name)
+		}
+		return nil, fmt.Errorf("a MONTHLY budget for %s in %s already exists", category.Name, month.Format("2006-01"))
+	}
+
+	budget := &models.Budget{
+		UserID:     userID,
+		CategoryID: category.ID,
+		Category:   category,
+		Period:     req.Period,
+		Month:      month,
+		Amount:     decimal.NewFromFloat(req.Amount),
+		Rollover:   req.Rollover,
+	}
+	if err := s.repo.Create(ctx, budget); err != nil {
+		return nil, fmt.Errorf("create budget: %w", err)
+	}
+	slog.Info("budget_created", "user_id", userID, "category_uuid", category.UUID, "period", budget.Period, "month", month.Format("2006-01"))
+	return budget, nil
+}
+
+func (s *BudgetService) GetBudget(ctx context.Context, userID int64, uuid string) (*models.Budget, error) {
+	budget, err := s.repo.GetByUUID(ctx, uuid, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get budget: %w", err)
+	}
+	if budget == nil {
+		return nil, errors.New("budget not found")
+	}
+	return budget, nil
+}
+
+func (s *BudgetService) ListBudgets(ctx context.Context, userID int64, page, limit int) ([]models.Budget, port.ListMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	budgets, total, err := s.repo.ListByUserID(ctx, userID, page, limit)
+	if err != nil {
+		return nil, port.ListMeta{}, fmt.Errorf("list budgets: %w", err)
+	}
+	if budgets == nil {
+		budgets = []models.Budget{}
+	}
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	meta := port.ListMeta{Total: total, Page: page, Limit: limit, TotalPages: totalPages}
+	return budgets, meta, nil
+}
+
+// UpdateBudget changes the amount or rollover. A ROLLING budget's new amount also applies to the earlier
+// months its rollover is computed from.
+func (s *BudgetService) UpdateBudget(ctx context.Context, userID int64, uuid string, req port.UpdateBudgetRequest) (*models.Budget, error) {
+	budget, err := s.GetBudget(ctx, userID, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			return nil, errors.New("amount must be positive")
+		}
+		budget.Amount = decimal.NewFromFloat(*req.Amount)
+	}
+	if req.Rollover != nil {
+		if *req.Rollover && budget.Period != models.BudgetPeriodRolling {
+			return nil, errors.New("rollover must only be set on ROLLING budgets")
+		}
+		budget.Rollover = *req.Rollover
+	}
+	if err := s.repo.Update(ctx, budget); err != nil {
+		return nil, fmt.Errorf("update budget: %w", err)
+	}
+	return budget, nil
+}
+
+func (s *BudgetService) DeleteBudget(ctx context.Context, userID int64, uuid string) error {
+	return s.repo.Delete(ctx, uuid, userID)
+}
+
+func (s *BudgetService) GetStatus(ctx context.Context, userID int64, month string) (*port.BudgetStatusResponse, error) {
+	start, end, err := parseMonthRange(month)
+	if err != nil {
+		return nil, err
+	}
+	budgets, err := s.repo.ListForMonth(ctx, userID, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// A MONTHLY budget replaces the ROLLING one of its category for its month.
+	applied := make(map[int64]models.Budget, len(budgets))
+	for _, b := range budgets {
+		if cur, ok := applied[b.CategoryID]; !ok || cur.Period == models.BudgetPeriodRolling {
+			applied[b.CategoryID] = b
+		}
+	}
+	// Rollover needs the spending of every month since the earliest rolling budget began.
+	from := start
+	for _, b := range applied {
+		if b.Rollover && b.Month.Before(from) {
+			from = b.Month
+		}
+	}
+	totals, err := s.insights.GetMonthlyExpensesByCategory(ctx, userID, from, end)
+	if err != nil {
+		return nil, fmt.Errorf("get monthly expenses: %w", err)
+	}
+	// A subcategory's spending also counts against its parent's budget.
+	type spendKey struct {
+		categoryID int64
+		month      string
+	}
+	spentIn := make(map[spendKey]decimal.Decimal, len(totals))
+	for _, t := range totals {
+		k := spendKey{t.CategoryID, t.Month}
+		spentIn[k] = spentIn[k].Add(t.Total)
+		if t.ParentID != nil {
+			k = spendKey{*t.ParentID, t.Month}
+			spentIn[k] = spentIn[k].Add(t.Total)
+		}
+	}
+
+	res := &port.BudgetStatusResponse{Month: start.Format("2006-01"), Budgets: []port.BudgetStatus{}}
+	for _, b := range budgets {
+		if applied[b.CategoryID].ID != b.ID {
+			continue
+		}
+		rolledOver := decimal.Zero
+		if b.Rollover {
+			var earlier []decimal.Decimal
+			for m := b.Month; m.Before(start); m = m.AddDate(0, 1, 0) {
+				earlier = append(earlier, spentIn[spendKey{b.CategoryID, m.Format("2006-01")}])
+			}
+			rolledOver = carryOver(b.Amount, earlier)
+		}
+		spent := spentIn[spendKey{b.CategoryID, res.Month}]
+		projected, elapsed, days := projectSpend(spent, start, time.Now())
+		res.DaysElapsed, res.DaysInMonth = elapsed, days
+
+		available := b.Amount.Add(rolledOver)
+		percent, _ := percentOf(spent, available).Round(2).Float64()
+		status := port.BudgetStatus{
+			BudgetUUID:         b.UUID,
+			Period:             b.Period,
+			Amount:             b.Amount,
+			RolledOver:         rolledOver,
+			Available:          available,
+			Spent:              spent,
+			Remaining:          available.Sub(spent),
+			Percent:            percent,
+			Projected:          projected,
+			ProjectedOverspend: decimal.Max(projected.Sub(available), decimal.Zero),
+		}
+		if b.Category != nil {
+			status.CategoryUUID, status.Category = b.Category.UUID, b.Category.Name
+		}
+		res.Budgets = append(res.Budgets, status)
+		res.TotalBudget = res.TotalBudget.Add(available)
+		res.TotalSpent = res.TotalSpent.Add(spent)
+	}
+	res.TotalRemaining = res.TotalBudget.Sub(res.TotalSpent)
+	if len(res.Budgets) == 0 {
+		_, res.DaysElapsed, res.DaysInMonth = projectSpend(decimal.Zero, start, time.Now())
+	}
+	return res, nil
+}
+
+// carryOver is what a rolling budget of amount brings into a month after the given months' spending, oldest
+// first. Each month passes on what it left unspent of its amount plus its own carry; an overspent month
+// passes on nothing.
+func carryOver(amount decimal.Decimal, spent []decimal.Decimal) decimal.Decimal {
+	carry := decimal.Zero
+	for _, s := range spent {
+		carry = decimal.Max(amount.Add(carry).Sub(s), decimal.Zero)
+	}
+	return carry
+}
+
+// projectSpend extends the spending so far in the month starting at start to the whole month, counting
+// today as a spending day. Past months keep what was spent; months not begun yet have no pace.
+func projectSpend(spent decimal.Decimal, start, now time.Time) (projected decimal.Decimal, elapsed, days int) {
+	end := start.AddDate(0, 1, 0)
+	days = int(end.Sub(start).Hours() / 24)
+	if !now.Before(end) {
+		return spent, days, days
+	}
+	if now.Before(start) {
+		return spent, 0, days
+	}
+	elapsed = int(now.Sub(start).Hours()/24) + 1
+	projected = spent.Mul(decimal.NewFromInt(int64(days))).Div(decimal.NewFromInt(int64(elapsed))).Round(2)
+	return projected, elapsed, days
+}
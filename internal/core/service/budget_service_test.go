@@ -0,0 +1,58 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_carryOver(t *testing.T) {
+	d := decimal.RequireFromString
+	tests := []struct {
+		name  string
+		spent []string
+		want  string
+	}{
+		{"first month carries nothing", nil, "0"},
+		{"unspent amount carries", []string{"700"}, "300"},
+		{"carry accumulates", []string{"700", "900"}, "400"},
+		{"overspent month resets the carry", []string{"700", "1500"}, "0"},
+		{"carry covers part of an overspend", []string{"500", "1200", "1000"}, "300"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spent := make([]decimal.Decimal, len(tt.spent))
+			for i, s := range tt.spent {
+				spent[i] = d(s)
+			}
+			if got := carryOver(d("1000"), spent); !got.Equal(d(tt.want)) {
+				t.Errorf("carryOver() = %s; want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_projectSpend(t *testing.T) {
+	d := decimal.RequireFromString
+	start := time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		now           time.Time
+		wantProjected string
+		wantElapsed   int
+	}{
+		{"first day", time.Date(2025, 4, 1, 9, 0, 0, 0, time.UTC), "9000", 1},
+		{"a third of the way", time.Date(2025, 4, 10, 18, 0, 0, 0, time.UTC), "900", 10},
+		{"month over", time.Date(2025, 5, 3, 0, 0, 0, 0, time.UTC), "300", 30},
+		{"month not begun", time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC), "300", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projected, elapsed, days := projectSpend(d("300"), start, tt.now)
+			if !projected.Equal(d(tt.wantProjected)) || elapsed != tt.wantElapsed || days != 30 {
+				t.Errorf("projectSpend() = %s, %d, %d; want %s, %d, 30", projected, elapsed, days, tt.wantProjected, tt.wantElapsed)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+)
+
+// lookupCashAsset validates that the asset exists, belongs to the user, and is type CASH.
+func lookupCashAsset(ctx context.Context, assets port.AssetRepository, assetUUID string, userID int64) (*models.Asset, error) {
+	if strings.TrimSpace(assetUUID) == "" {
+		return nil, errors.New("assetUuid is required")
+	}
+	asset, err := assets.GetByUUID(ctx, assetUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get asset: %w", err)
+	}
+	return checkCashAsset(asset)
+}
+
+// lockCashAsset is lookupCashAsset with a row lock held until the surrounding transaction ends,
+// so two concurrent writers cannot both pass a balance check against the same quantity.
+func lockCashAsset(ctx context.Context, assets port.AssetRepository, assetUUID string, userID int64) (*models.Asset, error) {
+	if strings.TrimSpace(assetUUID) == "" {
+		return nil, errors.New("assetUuid is required")
+	}
+	asset, err := assets.GetByUUIDForUpdate(ctx, assetUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get asset: %w", err)
+	}
+	return checkCashAsset(asset)
+}
+
+func checkCashAsset(asset *models.Asset) (*models.Asset, error) {
+	if asset == nil {
+		return nil, errors.New("asset not found")
+	}
+	if asset.Type != models.AssetTypeCash {
+		return nil, errors.New("asset must be of type CASH")
+	}
+	return asset, nil
+}
+
+// lockAssetsByID locks the given asset rows in ascending ID order (so concurrent transactions touching
+// the same pair cannot deadlock) and returns them keyed by ID. Missing assets are left out of the map.
+func lockAssetsByID(ctx context.Context, assets port.AssetRepository, ids ...int64) (map[int64]*models.Asset, error) {
+	sorted := make([]int64, 0, len(ids))
+	seen := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			sorted = append(sorted, id)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	locked := make(map[int64]*models.Asset, len(sorted))
+	for _, id := range sorted {
+		asset, err := assets.GetByIDForUpdate(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("get asset: %w", err)
+		}
+		if asset != nil {
+			locked[id] = asset
+		}
+	}
+	return locked, nil
+}
+
+// assetIDs collects the non-nil IDs, for lockAssetsByID with optional asset links.
+func assetIDs(ids ...*int64) []int64 {
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id != nil {
+			out = append(out, *id)
+		}
+	}
+	return out
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+	"monity/internal/pkg/validation"
+)
+
+var colorRegex = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// defaultCategories are the categories every user starts with: the former fixed expense categories, keyed
+// by their code. migrations/022_categories.up.sql seeds the same set for existing users.
+var defaultCategories = []models.Category{
+	defaultCategory(models.ExpenseCategoryFood, "Food", "utensils", "#F97316"),
+	defaultCategory(models.ExpenseCategoryTransport, "Transport", "car", "#3B82F6"),
+	defaultCategory(models.ExpenseCategoryHousing, "Housing", "home", "#8B5CF6"),
+	defaultCategory(models.ExpenseCategoryUtilities, "Utilities", "bolt", "#EAB308"),
+	defaultCategory(models.ExpenseCategoryHealth, "Health", "heart-pulse", "#EF4444"),
+	defaultCategory(models.ExpenseCategoryEntertainment, "Entertainment", "film", "#EC4899"),
+	defaultCategory(models.ExpenseCategoryShopping, "Shopping", "shopping-bag", "#14B8A6"),
+	defaultCategory(models.ExpenseCategoryOther, "Other", "ellipsis", "#6B7280"),
+}
+
+func defaultCategory(code models.ExpenseCategory, name, icon, color string) models.Category {
+	c := string(code)
+	return models.Category{Code: &c, Name: name, Icon: &icon, Color: &color}
+}
+
+type CategoryService struct {
+	repo port.CategoryRepository
+}
+
+func NewCategoryService(repo port.CategoryRepository) port.CategoryService {
+	return &CategoryService{repo: repo}
+}
+
+func (s *CategoryService) CreateCategory(ctx context.Context, userID int64, req port.CreateCategoryRequest) (*models.Category, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errors.New("name is required")
+	}
+	if err := validation.CheckMaxLen(name, validation.MaxCategoryNameLen); err != nil {
+		return nil, fmt.Errorf("name %w", err)
+	}
+	if err := checkCategoryLook(req.Icon, req.Color); err != nil {
+		return nil, err
+	}
+
+	category := &models.Category{UserID: userID, Name: name, Icon: req.Icon, Color: req.Color}
+	if req.ParentUUID != nil && *req.ParentUUID != "" {
+		parent, err := s.lookupParent(ctx, userID, *req.ParentUUID)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID, category.Parent = &parent.ID, parent
+	}
+	if err := s.checkNameFree(ctx, userID, category.ParentID, name, 0); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, category); err != nil {
+		return nil, fmt.Errorf("create category: %w", err)
+	}
+	slog.Info("category_created", "user_id", userID, "category_uuid", category.UUID, "name", name)
+	return category, nil
+}
+
+func (s *CategoryService) GetCategory(ctx context.Context, userID int64, uuid string) (*models.Category, error) {
+	category, err := s.repo.GetByUUID(ctx, uuid, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get category: %w", err)
+	}
+	if category == nil {
+		return nil, errors.New("category not found")
+	}
+	return category, nil
+}
+
+// ListCategories also gives users who have none yet their default categories.
+func (s *CategoryService) ListCategories(ctx context.Context, userID int64, includeArchived bool) ([]models.Category, error) {
+	categories, err := s.repo.ListByUserID(ctx, userID, includeArchived)
+	if err != nil {
+		return nil, err
+	}
+	if len(categories) == 0 && !includeArchived {
+		if err := s.repo.CreateDefaults(ctx, userID, defaultCategories); err != nil {
+			return nil, err
+		}
+		if categories, err = s.repo.ListByUserID(ctx, userID, includeArchived); err != nil {
+			return nil, err
+		}
+	}
+	if categories == nil {
+		categories = []models.Category{}
+	}
+	return categories, nil
+}
+
+func (s *CategoryService) UpdateCategory(ctx context.Context, userID int64, uuid string, req port.UpdateCategoryRequest) (*models.Category, error) {
+	category, err := s.GetCategory(ctx, userID, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCategoryLook(req.Icon, req.Color); err != nil {
+		return nil, err
+	}
+
+	if req.ParentUUID != nil {
+		if *req.ParentUUID == "" {
+			category.ParentID, category.Parent = nil, nil
+		} else {
+			parent, err := s.lookupParent(ctx, userID, *req.ParentUUID)
+			if err != nil {
+				return nil, err
+			}
+			if parent.ID == category.ID {
+				return nil, errors.New("parentUuid must not be the category itself")
+			}
+			children, err := s.repo.CountChildren(ctx, category.ID)
+			if err != nil {
+				return nil, err
+			}
+			if children > 0 {
+				return nil, errors.New("a category with subcategories must stay at the top level")
+			}
+			category.ParentID, category.Parent = &parent.ID, parent
+		}
+	}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		if err := validation.CheckMaxLen(name, validation.MaxCategoryNameLen); err != nil {
+			return nil, fmt.Errorf("name %w", err)
+		}
+		category.Name = name
+	}
+	if req.Name != nil || req.ParentUUID != nil {
+		if err := s.checkNameFree(ctx, userID, category.ParentID, category.Name, category.ID); err != nil {
+			return nil, err
+		}
+	}
+	if req.Icon != nil {
+		category.Icon = req.Icon
+	}
+	if req.Color != nil {
+		category.Color = req.Color
+	}
+	if req.Archived != nil {
+		if !*req.Archived {
+			category.ArchivedAt = nil
+		} else if category.ArchivedAt == nil {
+			now := time.Now()
+			category.ArchivedAt = &now
+		}
+	}
+	if err := s.repo.Update(ctx, category); err != nil {
+		return nil, fmt.Errorf("update category: %w", err)
+	}
+	return category, nil
+}
+
+func (s *CategoryService) DeleteCategory(ctx context.Context, userID int64, uuid string) error {
+	category, err := s.GetCategory(ctx, userID, uuid)
+	if err != nil {
+		return err
+	}
+	if category.Code != nil {
+		return errors.New("default categories cannot be deleted; archive them instead")
+	}
+	children, err := s.repo.CountChildren(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	used, err := s.repo.InUse(ctx, category.ID)
+	if err != nil {
+		return err
+	}
+	if children > 0 || used {
+		return errors.New("category is in use; archive it instead")
+	}
+	if err := s.repo.Delete(ctx, category.ID); err != nil {
+		return err
+	}
+	slog.Info("category_deleted", "user_id", userID, "category_uuid", uuid)
+	return nil
+}
+
+// lookupParent returns the user's category to file a subcategory under; it must be top-level.
+func (s *CategoryService) lookupParent(ctx context.Context, userID int64, parentUUID string) (*models.Category, error) {
+	parent, err := s.repo.GetByUUID(ctx, parentUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get parent category: %w", err)
+	}
+	if parent == nil {
+		return nil, errors.New("parentUuid must be one of your categories")
+	}
+	if parent.ParentID != nil {
+		return nil, errors.New("parentUuid must be a top-level category")
+	}
+	return parent, nil
+}
+
+func (s *CategoryService) checkNameFree(ctx context.Context, userID int64, parentID *int64, name string, selfID int64) error {
+	existing, err := s.repo.GetByName(ctx, userID, parentID, name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != selfID {
+		return fmt.Errorf("a category named %q already exists there", name)
+	}
+	return nil
+}
+
+func checkCategoryLook(icon, color *string) error {
+	if icon != nil {
+		if err := validation.CheckMaxLen(*icon, validation.MaxIconLen); err != nil {
+			return fmt.Errorf("icon %w", err)
+		}
+	}
+	if color != nil && !colorRegex.MatchString(*color) {
+		return errors.New("color must be a hex colour like #1A2B3C")
+	}
+	return nil
+}
+
+// resolveCategory returns the user's category for an expense, by categoryUuid or else by the code of a
+// default category (FOOD, TRANSPORT, ...), creating the defaults if the user has none yet. Archived
+// categories take no new expenses.
+func resolveCategory(ctx context.Context, categories port.CategoryRepository, userID int64, categoryUUID *string, code models.ExpenseCategory) (*models.Category, error) {
+	var category *models.Category
+	var err error
+	switch {
+	case categoryUUID != nil && *categoryUUID != "":
+		if category, err = categories.GetByUUID(ctx, *categoryUUID, userID); err != nil {
+			return nil, fmt.Errorf("get category: %w", err)
+		}
+		if category == nil {
+			return nil, errors.New("categoryUuid must be one of your categories")
+		}
+	case code != "":
+		if category, err = defaultCategoryByCode(ctx, categories, userID, code); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errors.New("categoryUuid or category is required")
+	}
+	if category.ArchivedAt != nil || (category.Parent != nil && category.Parent.ArchivedAt != nil) {
+		return nil, errors.New("category must not be archived")
+	}
+	return category, nil
+}
+
+// categoryCode is the default category code of an optional request field.
+func categoryCode(code *models.ExpenseCategory) models.ExpenseCategory {
+	if code == nil {
+		return ""
+	}
+	return *code
+}
+
+func defaultCategoryByCode(ctx context.Context, categories port.CategoryRepository, userID int64, code models.ExpenseCategory) (*models.Category, error) {
+	if !isValidExpenseCategory(code) {
+		return nil, errors.New("invalid expense category")
+	}
+	category, err := categories.GetByCode(ctx, userID, string(code))
+	if err != nil {
+		return nil, err
+	}
+	if category == nil {
+		if err := categories.CreateDefaults(ctx, userID, defaultCategories); err != nil {
+			return nil, err
+		}
+		if category, err = categories.GetByCode(ctx, userID, string(code)); err != nil {
+			return nil, err
+		}
+		if category == nil {
+			return nil, errors.New("invalid expense category")
+		}
+	}
+	return category, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -15,13 +16,14 @@ import (
 )
 
 type DebtService struct {
-	repo      port.DebtRepository
+	repo        port.DebtRepository
 	paymentRepo port.DebtPaymentRepository
-	assetRepo port.AssetRepository
+	assetRepo   port.AssetRepository
+	uow         port.UnitOfWork
 }
 
-func NewDebtService(repo port.DebtRepository, paymentRepo port.DebtPaymentRepository, assetRepo port.AssetRepository) port.DebtService {
-	return &DebtService{repo: repo, paymentRepo: paymentRepo, assetRepo: assetRepo}
+func NewDebtService(repo port.DebtRepository, paymentRepo port.DebtPaymentRepository, assetRepo port.AssetRepository, uow port.UnitOfWork) port.DebtService {
+	return &DebtService{repo: repo, paymentRepo: paymentRepo, assetRepo: assetRepo, uow: uow}
 }
 
 func (s *DebtService) resolveAssetID(ctx context.Context, assetUUID *string, userID int64) (*int64, error) {
@@ -41,6 +43,14 @@ func (s *DebtService) resolveAssetID(ctx context.Context, assetUUID *string, use
 	return &asset.ID, nil
 }
 
+// markOverdue brings the user's debts up to date before a read, so status filters never lag the background sweep.
+func (s *DebtService) markOverdue(ctx context.Context, userID int64) error {
+	if _, err := s.repo.MarkOverdue(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("mark overdue debts: %w", err)
+	}
+	return nil
+}
+
 func (s *DebtService) CreateDebt(ctx context.Context, userID int64, req port.CreateDebtRequest) (*models.Debt, error) {
 	if strings.TrimSpace(req.PartyName) == "" {
 		return nil, errors.New("party name is required")
@@ -53,31 +63,60 @@ func (s *DebtService) CreateDebt(ctx context.Context, userID int64, req port.Cre
 			return nil, fmt.Errorf("note %w", err)
 		}
 	}
+	tagNames, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
 	assetID, err := s.resolveAssetID(ctx, req.AssetUUID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if req.RecordDisbursement && assetID == nil {
+		return nil, errors.New("assetUuid is required to record the disbursement")
+	}
 
 	now := time.Now()
 	debt := &models.Debt{
-		UserID:     userID,
-		PartyName:  strings.TrimSpace(req.PartyName),
-		Amount:     decimal.NewFromFloat(req.Amount),
-		PaidAmount: decimal.Zero,
-		DueDate:    req.DueDate,
-		Status:     models.ObligationStatusPending,
-		Note:       req.Note,
-		AssetID:    assetID,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-	}
-	if err := s.repo.Create(ctx, debt); err != nil {
-		return nil, fmt.Errorf("create debt: %w", err)
+		UserID:          userID,
+		PartyName:       strings.TrimSpace(req.PartyName),
+		Amount:          decimal.NewFromFloat(req.Amount),
+		PaidAmount:      decimal.Zero,
+		DueDate:         req.DueDate,
+		Status:          obligationStatus(decimal.NewFromFloat(req.Amount), decimal.Zero, req.DueDate, now),
+		StatusChangedAt: &now,
+		Note:            req.Note,
+		AssetID:         assetID,
+		Disbursed:       req.RecordDisbursement,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		if err := repos.Debts.Create(ctx, debt); err != nil {
+			return fmt.Errorf("create debt: %w", err)
+		}
+		if len(tagNames) > 0 {
+			tags, err := resolveTags(ctx, repos.Tags, userID, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := repos.Debts.ReplaceTags(ctx, debt, tags); err != nil {
+				return fmt.Errorf("tag debt: %w", err)
+			}
+			debt.Tags = tags
+		}
+		// Borrowed cash goes into the CASH asset
+		return postLedger(ctx, repos, debtDisbursementPosting(debt), nil)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return debt, nil
 }
 
 func (s *DebtService) GetDebt(ctx context.Context, userID int64, uuid string) (*models.Debt, error) {
+	if err := s.markOverdue(ctx, userID); err != nil {
+		return nil, err
+	}
 	debt, err := s.repo.GetByUUID(ctx, uuid, userID)
 	if err != nil {
 		return nil, fmt.Errorf("get debt: %w", err)
@@ -88,7 +127,7 @@ func (s *DebtService) GetDebt(ctx context.Context, userID int64, uuid string) (*
 	return debt, nil
 }
 
-func (s *DebtService) ListDebts(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, page, limit int) ([]models.Debt, port.ListMeta, error) {
+func (s *DebtService) ListDebts(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, tags []string, page, limit int) ([]models.Debt, port.ListMeta, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -98,7 +137,14 @@ func (s *DebtService) ListDebts(ctx context.Context, userID int64, status *strin
 	if limit > 100 {
 		limit = 100
 	}
-	debts, total, err := s.repo.ListByUserID(ctx, userID, status, dueFrom, dueTo, page, limit)
+	if err := s.markOverdue(ctx, userID); err != nil {
+		return nil, port.ListMeta{}, err
+	}
+	tags, err := normalizeTagNames(tags)
+	if err != nil {
+		return nil, port.ListMeta{}, err
+	}
+	debts, total, err := s.repo.ListByUserID(ctx, userID, status, dueFrom, dueTo, tags, page, limit)
 	if err != nil {
 		return nil, port.ListMeta{}, fmt.Errorf("list debts: %w", err)
 	}
@@ -114,109 +160,204 @@ func (s *DebtService) ListDebts(ctx context.Context, userID int64, status *strin
 }
 
 func (s *DebtService) UpdateDebt(ctx context.Context, userID int64, uuid string, req port.UpdateDebtRequest) (*models.Debt, error) {
-	debt, err := s.repo.GetByUUID(ctx, uuid, userID)
-	if err != nil {
-		return nil, fmt.Errorf("get debt: %w", err)
-	}
-	if debt == nil {
-		return nil, errors.New("debt not found")
-	}
-	if req.PartyName != nil {
-		if strings.TrimSpace(*req.PartyName) == "" {
-			return nil, errors.New("party name cannot be empty")
-		}
-		debt.PartyName = strings.TrimSpace(*req.PartyName)
+	if req.PartyName != nil && strings.TrimSpace(*req.PartyName) == "" {
+		return nil, errors.New("party name cannot be empty")
 	}
-	if req.Amount != nil {
-		if *req.Amount <= 0 {
-			return nil, errors.New("amount must be positive")
-		}
-		debt.Amount = decimal.NewFromFloat(*req.Amount)
-	}
-	if req.DueDate != nil {
-		debt.DueDate = req.DueDate
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
 	}
 	if req.Note != nil {
 		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
 			return nil, fmt.Errorf("note %w", err)
 		}
-		debt.Note = req.Note
 	}
+	var tagNames []string
+	if req.Tags != nil {
+		var err error
+		if tagNames, err = normalizeTagNames(*req.Tags); err != nil {
+			return nil, err
+		}
+	}
+	var newAssetID *int64
 	if req.AssetUUID != nil {
-		assetID, err := s.resolveAssetID(ctx, req.AssetUUID, userID)
-		if err != nil {
+		var err error
+		if newAssetID, err = s.resolveAssetID(ctx, req.AssetUUID, userID); err != nil {
 			return nil, err
 		}
-		debt.AssetID = assetID
 	}
-	debt.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, debt); err != nil {
-		return nil, fmt.Errorf("update debt: %w", err)
+
+	var debt *models.Debt
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		debt, err = repos.Debts.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get debt: %w", err)
+		}
+		if debt == nil {
+			return errors.New("debt not found")
+		}
+		oldAssetID := debt.AssetID
+
+		if req.PartyName != nil {
+			debt.PartyName = strings.TrimSpace(*req.PartyName)
+		}
+		if req.Amount != nil {
+			debt.Amount = decimal.NewFromFloat(*req.Amount)
+		}
+		if req.DueDate != nil {
+			debt.DueDate = req.DueDate
+		}
+		if req.Note != nil {
+			debt.Note = req.Note
+		}
+		if req.AssetUUID != nil {
+			debt.AssetID = newAssetID
+		}
+		if req.RecordDisbursement != nil {
+			debt.Disbursed = *req.RecordDisbursement
+		}
+		if debt.Disbursed && debt.AssetID == nil {
+			return errors.New("assetUuid is required to record the disbursement")
+		}
+		if err := applyDebtPayments(ctx, repos, debt); err != nil {
+			return err
+		}
+
+		if req.Tags != nil {
+			tags, err := resolveTags(ctx, repos.Tags, userID, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := repos.Debts.ReplaceTags(ctx, debt, tags); err != nil {
+				return fmt.Errorf("tag debt: %w", err)
+			}
+			debt.Tags = tags
+		}
+
+		// Move the recorded disbursement along with amount / asset changes; cash already spent cannot be taken back
+		locked, err := lockAssetsByID(ctx, repos.Assets, assetIDs(oldAssetID, debt.AssetID)...)
+		if err != nil {
+			return err
+		}
+		return postLedgerWithinBalance(ctx, repos, debtDisbursementPosting(debt), locked, "borrowed cash to take back cannot exceed the asset balance")
+	})
+	if err != nil {
+		return nil, err
 	}
 	return debt, nil
 }
 
 func (s *DebtService) DeleteDebt(ctx context.Context, userID int64, uuid string) error {
-	if err := s.repo.Delete(ctx, uuid, userID); err != nil {
-		if err.Error() == "debt not found or not owned by user" {
+	return s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		debt, err := repos.Debts.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get debt: %w", err)
+		}
+		if debt == nil {
 			return errors.New("debt not found")
 		}
-		return fmt.Errorf("delete debt: %w", err)
-	}
-	return nil
+
+		// Undo the cash movements of the disbursement and every payment; payments are removed with the debt
+		payments, err := repos.DebtPayments.ListByDebtID(ctx, debt.ID)
+		if err != nil {
+			return fmt.Errorf("list debt payments: %w", err)
+		}
+		ids := []int64{}
+		if debt.AssetID != nil {
+			ids = append(ids, *debt.AssetID)
+		}
+		for _, p := range payments {
+			ids = append(ids, assetIDs(p.AssetID)...)
+		}
+		locked, err := lockAssetsByID(ctx, repos.Assets, ids...)
+		if err != nil {
+			return err
+		}
+		for i := range payments {
+			reversal := debtPaymentPosting(debt, &payments[i])
+			reversal.Description = "debt payment deleted"
+			reversal.Lines = nil
+			if err := postLedger(ctx, repos, reversal, locked); err != nil {
+				return err
+			}
+		}
+		reversal := debtDisbursementPosting(debt)
+		reversal.Description = "debt deleted"
+		reversal.Lines = nil
+		if err := postLedgerWithinBalance(ctx, repos, reversal, locked, "borrowed cash to take back cannot exceed the asset balance"); err != nil {
+			return err
+		}
+
+		if err := repos.Debts.Delete(ctx, uuid, userID); err != nil {
+			if err.Error() == "debt not found or not owned by user" {
+				return errors.New("debt not found")
+			}
+			return fmt.Errorf("delete debt: %w", err)
+		}
+		return nil
+	})
 }
 
 func (s *DebtService) RecordDebtPayment(ctx context.Context, userID int64, debtUUID string, req port.CreateDebtPaymentRequest) (*models.DebtPayment, error) {
 	if req.Amount <= 0 {
 		return nil, errors.New("amount must be positive")
 	}
-	debt, err := s.repo.GetByUUID(ctx, debtUUID, userID)
-	if err != nil {
-		return nil, fmt.Errorf("get debt: %w", err)
-	}
-	if debt == nil {
-		return nil, errors.New("debt not found")
-	}
-	if debt.Status == models.ObligationStatusPaid {
-		return nil, errors.New("debt is already fully paid")
-	}
 	assetID, err := s.resolveAssetID(ctx, req.AssetUUID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	payment := &models.DebtPayment{
-		DebtID:   debt.ID,
-		Amount:   decimal.NewFromFloat(req.Amount),
-		Date:     req.Date,
-		Note:     req.Note,
-		AssetID:  assetID,
-		CreatedAt: time.Now(),
-	}
-	if err := s.paymentRepo.Create(ctx, payment); err != nil {
-		return nil, fmt.Errorf("create debt payment: %w", err)
-	}
+	amount := decimal.NewFromFloat(req.Amount)
+	var payment *models.DebtPayment
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		debt, err := repos.Debts.GetByUUIDForUpdate(ctx, debtUUID, userID)
+		if err != nil {
+			return fmt.Errorf("get debt: %w", err)
+		}
+		if debt == nil {
+			return errors.New("debt not found")
+		}
+		if debt.Status == models.ObligationStatusPaid {
+			return errors.New("debt is already fully paid")
+		}
 
-	payments, err := s.paymentRepo.ListByDebtID(ctx, debt.ID)
+		// Pay from the given CASH asset, falling back to the one linked to the debt
+		if assetID == nil {
+			assetID = debt.AssetID
+		}
+		var locked map[int64]*models.Asset
+		if assetID != nil {
+			if locked, err = lockAssetsByID(ctx, repos.Assets, *assetID); err != nil {
+				return err
+			}
+			asset, err := checkCashAsset(locked[*assetID])
+			if err != nil {
+				return err
+			}
+			if amount.GreaterThan(asset.Quantity) {
+				return errors.New("payment amount cannot exceed the selected asset balance")
+			}
+		}
+
+		payment = &models.DebtPayment{
+			DebtID:    debt.ID,
+			Amount:    amount,
+			Date:      req.Date,
+			Note:      req.Note,
+			AssetID:   assetID,
+			CreatedAt: time.Now(),
+		}
+		if err := repos.DebtPayments.Create(ctx, payment); err != nil {
+			return fmt.Errorf("create debt payment: %w", err)
+		}
+
+		if err := applyDebtPayments(ctx, repos, debt); err != nil {
+			return err
+		}
+		return postLedger(ctx, repos, debtPaymentPosting(debt, payment), locked)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("list debt payments: %w", err)
-	}
-	var sum decimal.Decimal
-	for _, p := range payments {
-		sum = sum.Add(p.Amount)
-	}
-	if sum.GreaterThan(debt.Amount) {
-		return nil, errors.New("total payments cannot exceed debt amount")
-	}
-	debt.PaidAmount = sum
-	if sum.GreaterThanOrEqual(debt.Amount) {
-		debt.Status = models.ObligationStatusPaid
-	} else {
-		debt.Status = models.ObligationStatusPartial
-	}
-	debt.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, debt); err != nil {
-		return nil, fmt.Errorf("update debt after payment: %w", err)
+		return nil, err
 	}
 	return payment, nil
 }
@@ -238,3 +379,210 @@ func (s *DebtService) ListDebtPayments(ctx context.Context, userID int64, debtUU
 	}
 	return payments, nil
 }
+
+func (s *DebtService) GetDebtPayment(ctx context.Context, userID int64, debtUUID, paymentUUID string) (*models.DebtPayment, error) {
+	debt, err := s.repo.GetByUUID(ctx, debtUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get debt: %w", err)
+	}
+	if debt == nil {
+		return nil, errors.New("debt not found")
+	}
+	payment, err := s.paymentRepo.GetByUUID(ctx, debt.ID, paymentUUID)
+	if err != nil {
+		return nil, fmt.Errorf("get debt payment: %w", err)
+	}
+	if payment == nil {
+		return nil, errors.New("payment not found")
+	}
+	return payment, nil
+}
+
+func (s *DebtService) UpdateDebtPayment(ctx context.Context, userID int64, debtUUID, paymentUUID string, req port.UpdateDebtPaymentRequest) (*models.DebtPayment, error) {
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if req.Note != nil {
+		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
+			return nil, fmt.Errorf("note %w", err)
+		}
+	}
+	var newAssetID *int64
+	if req.AssetUUID != nil {
+		var err error
+		if newAssetID, err = s.resolveAssetID(ctx, req.AssetUUID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	var payment *models.DebtPayment
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		debt, err := repos.Debts.GetByUUIDForUpdate(ctx, debtUUID, userID)
+		if err != nil {
+			return fmt.Errorf("get debt: %w", err)
+		}
+		if debt == nil {
+			return errors.New("debt not found")
+		}
+		payment, err = repos.DebtPayments.GetByUUIDForUpdate(ctx, debt.ID, paymentUUID)
+		if err != nil {
+			return fmt.Errorf("get debt payment: %w", err)
+		}
+		if payment == nil {
+			return errors.New("payment not found")
+		}
+		if payment.VoidedAt != nil {
+			return errors.New("payment is voided and cannot be changed")
+		}
+		oldAssetID, oldAmount := payment.AssetID, payment.Amount
+
+		if req.Amount != nil {
+			payment.Amount = decimal.NewFromFloat(*req.Amount)
+		}
+		if req.Date != nil {
+			payment.Date = *req.Date
+		}
+		if req.Note != nil {
+			payment.Note = req.Note
+		}
+		if req.AssetUUID != nil {
+			payment.AssetID = newAssetID
+		}
+
+		locked, err := lockAssetsByID(ctx, repos.Assets, assetIDs(oldAssetID, payment.AssetID)...)
+		if err != nil {
+			return err
+		}
+		if payment.AssetID != nil {
+			asset, err := checkCashAsset(locked[*payment.AssetID])
+			if err != nil {
+				return err
+			}
+			available := asset.Quantity
+			if oldAssetID != nil && *oldAssetID == asset.ID {
+				available = available.Add(oldAmount)
+			}
+			if payment.Amount.GreaterThan(available) {
+				return errors.New("payment amount cannot exceed the selected asset balance")
+			}
+		}
+
+		if err := repos.DebtPayments.Update(ctx, payment); err != nil {
+			return fmt.Errorf("update debt payment: %w", err)
+		}
+		if err := applyDebtPayments(ctx, repos, debt); err != nil {
+			return err
+		}
+		return postLedger(ctx, repos, debtPaymentPosting(debt, payment), locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *DebtService) VoidDebtPayment(ctx context.Context, userID int64, debtUUID, paymentUUID string) (*models.DebtPayment, error) {
+	var payment *models.DebtPayment
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		debt, err := repos.Debts.GetByUUIDForUpdate(ctx, debtUUID, userID)
+		if err != nil {
+			return fmt.Errorf("get debt: %w", err)
+		}
+		if debt == nil {
+			return errors.New("debt not found")
+		}
+		payment, err = repos.DebtPayments.GetByUUIDForUpdate(ctx, debt.ID, paymentUUID)
+		if err != nil {
+			return fmt.Errorf("get debt payment: %w", err)
+		}
+		if payment == nil {
+			return errors.New("payment not found")
+		}
+		if payment.VoidedAt != nil {
+			return errors.New("payment is already voided")
+		}
+		locked, err := lockAssetsByID(ctx, repos.Assets, assetIDs(payment.AssetID)...)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		payment.VoidedAt = &now
+		if err := repos.DebtPayments.Update(ctx, payment); err != nil {
+			return fmt.Errorf("void debt payment: %w", err)
+		}
+		if err := applyDebtPayments(ctx, repos, debt); err != nil {
+			return err
+		}
+		reversal := debtPaymentPosting(debt, payment)
+		reversal.Description = "debt payment voided"
+		return postLedger(ctx, repos, reversal, locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("debt_payment_voided", "user_id", userID, "debt_uuid", debtUUID, "payment_uuid", paymentUUID, "amount", payment.Amount.String())
+	return payment, nil
+}
+
+// applyDebtPayments recomputes PaidAmount and Status from the debt's non-voided payments and saves the debt.
+func applyDebtPayments(ctx context.Context, repos port.Repositories, debt *models.Debt) error {
+	payments, err := repos.DebtPayments.ListByDebtID(ctx, debt.ID)
+	if err != nil {
+		return fmt.Errorf("list debt payments: %w", err)
+	}
+	var sum decimal.Decimal
+	for _, p := range payments {
+		if p.VoidedAt == nil {
+			sum = sum.Add(p.Amount)
+		}
+	}
+	if sum.GreaterThan(debt.Amount) {
+		return errors.New("total payments cannot exceed debt amount")
+	}
+	debt.PaidAmount = sum
+	now := time.Now()
+	setObligationStatus(&debt.Status, &debt.StatusChangedAt, obligationStatus(debt.Amount, debt.PaidAmount, debt.DueDate, now), now)
+	debt.UpdatedAt = now
+	if err := repos.Debts.Update(ctx, debt); err != nil {
+		return fmt.Errorf("update debt after payment: %w", err)
+	}
+	return nil
+}
+
+// debtDisbursementPosting records the borrowed cash arriving in the debt's CASH asset (when Disbursed).
+func debtDisbursementPosting(d *models.Debt) ledgerPosting {
+	p := ledgerPosting{
+		UserID:        d.UserID,
+		ReferenceType: models.LedgerRefDebt,
+		ReferenceUUID: d.UUID,
+		Description:   "borrowed from " + d.PartyName,
+		OccurredAt:    d.CreatedAt,
+	}
+	if d.Disbursed && d.AssetID != nil {
+		p.Lines = []ledgerLine{
+			assetLine(*d.AssetID, d.Amount),
+			accountLine(models.LedgerAccountDebt, d.Amount.Neg()),
+		}
+	}
+	return p
+}
+
+// debtPaymentPosting moves a payment out of its CASH asset and reduces the liability.
+// Payments without an asset, and voided payments, do not move cash.
+func debtPaymentPosting(d *models.Debt, pay *models.DebtPayment) ledgerPosting {
+	p := ledgerPosting{
+		UserID:        d.UserID,
+		ReferenceType: models.LedgerRefDebtPayment,
+		ReferenceUUID: pay.UUID,
+		Description:   "debt payment to " + d.PartyName,
+		OccurredAt:    pay.Date,
+	}
+	if pay.AssetID != nil && pay.VoidedAt == nil {
+		p.Lines = []ledgerLine{
+			assetLine(*pay.AssetID, pay.Amount.Neg()),
+			accountLine(models.LedgerAccountDebt, pay.Amount),
+		}
+	}
+	return p
+}
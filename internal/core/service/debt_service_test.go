@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestDebtService_cashMovements(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	cash := store.cash(1, "IDR", 100)
+	svc := NewDebtService(store.debts, store.debtPayments, store.assets, store)
+
+	check := func(step string, wantCash, wantLiability float64) {
+		t.Helper()
+		if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromFloat(wantCash)) {
+			t.Errorf("%s: cash = %s, want %v", step, got, wantCash)
+		}
+		if got := store.ledger.balance(models.LedgerAccountDebt, 0); !got.Equal(decimal.NewFromFloat(wantLiability)) {
+			t.Errorf("%s: liability = %s, want %v", step, got, wantLiability)
+		}
+	}
+
+	debt, err := svc.CreateDebt(ctx, 1, port.CreateDebtRequest{PartyName: "Bank", Amount: 50, AssetUUID: &cash.UUID, RecordDisbursement: true})
+	if err != nil {
+		t.Fatalf("CreateDebt: %v", err)
+	}
+	check("disbursement", 150, -50)
+
+	payment, err := svc.RecordDebtPayment(ctx, 1, debt.UUID, port.CreateDebtPaymentRequest{Amount: 20, Date: time.Now()})
+	if err != nil {
+		t.Fatalf("RecordDebtPayment: %v", err)
+	}
+	check("payment", 130, -30)
+	if _, err := svc.RecordDebtPayment(ctx, 1, debt.UUID, port.CreateDebtPaymentRequest{Amount: 131, Date: time.Now()}); err == nil || !strings.Contains(err.Error(), "cannot exceed") {
+		t.Errorf("overdrawing payment: err = %v, want balance error", err)
+	}
+
+	amount := 10.0
+	if _, err := svc.UpdateDebtPayment(ctx, 1, debt.UUID, payment.UUID, port.UpdateDebtPaymentRequest{Amount: &amount}); err != nil {
+		t.Fatalf("UpdateDebtPayment: %v", err)
+	}
+	check("smaller payment", 140, -40)
+
+	// With the borrowed cash spent, shrinking or deleting the debt cannot take it back
+	store.adjust(cash.ID, -125)
+	check("spent", 15, -40)
+	amount = 30
+	if _, err := svc.UpdateDebt(ctx, 1, debt.UUID, port.UpdateDebtRequest{Amount: &amount}); err == nil || !strings.Contains(err.Error(), "cannot exceed") {
+		t.Errorf("smaller disbursement: err = %v, want balance error", err)
+	}
+	check("smaller disbursement refused", 15, -40)
+	if err := svc.DeleteDebt(ctx, 1, debt.UUID); err == nil || !strings.Contains(err.Error(), "cannot exceed") {
+		t.Errorf("delete: err = %v, want balance error", err)
+	}
+	check("delete refused", 15, -40)
+
+	amount = 45
+	if _, err := svc.UpdateDebt(ctx, 1, debt.UUID, port.UpdateDebtRequest{Amount: &amount}); err != nil {
+		t.Fatalf("UpdateDebt: %v", err)
+	}
+	check("smaller disbursement", 10, -35)
+
+	store.adjust(cash.ID, 40)
+	if err := svc.DeleteDebt(ctx, 1, debt.UUID); err != nil {
+		t.Fatalf("DeleteDebt: %v", err)
+	}
+	check("delete", 15, 0)
+}
@@ -18,74 +18,93 @@ import (
 type ExpenseService struct {
 	repo      port.ExpenseRepository
 	assetRepo port.AssetRepository
+	uow       port.UnitOfWork
 }
 
-func NewExpenseService(repo port.ExpenseRepository, assetRepo port.AssetRepository) port.ExpenseService {
-	return &ExpenseService{repo: repo, assetRepo: assetRepo}
-}
-
-// lookupCashAsset validates that the asset exists, belongs to the user, and is type CASH.
-func (s *ExpenseService) lookupCashAsset(ctx context.Context, assetUUID string, userID int64) (*models.Asset, error) {
-	if strings.TrimSpace(assetUUID) == "" {
-		return nil, errors.New("assetUuid is required")
-	}
-	asset, err := s.assetRepo.GetByUUID(ctx, assetUUID, userID)
-	if err != nil {
-		return nil, fmt.Errorf("get asset: %w", err)
-	}
-	if asset == nil {
-		return nil, errors.New("asset not found")
-	}
-	if asset.Type != models.AssetTypeCash {
-		return nil, errors.New("asset must be of type CASH")
-	}
-	return asset, nil
+func NewExpenseService(repo port.ExpenseRepository, assetRepo port.AssetRepository, uow port.UnitOfWork) port.ExpenseService {
+	return &ExpenseService{repo: repo, assetRepo: assetRepo, uow: uow}
 }
 
 func (s *ExpenseService) CreateExpense(ctx context.Context, userID int64, req port.CreateExpenseRequest) (*models.Expense, error) {
 	if req.Amount <= 0 {
 		return nil, errors.New("amount must be positive")
 	}
-	if !isValidExpenseCategory(req.Category) {
-		return nil, errors.New("invalid expense category")
-	}
 	if req.Note != nil {
 		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
 			return nil, fmt.Errorf("note %w", err)
 		}
 	}
-
-	asset, err := s.lookupCashAsset(ctx, req.AssetUUID, userID)
+	tagNames, err := normalizeTagNames(req.Tags)
 	if err != nil {
 		return nil, err
 	}
 
 	amount := decimal.NewFromFloat(req.Amount)
-	if amount.GreaterThan(asset.Quantity) {
-		return nil, errors.New("expense amount cannot exceed the selected asset balance")
-	}
-
-	// Deduct from CASH asset
-	oldQty := asset.Quantity
-	asset.Quantity = asset.Quantity.Sub(amount)
-	if err := s.assetRepo.Update(ctx, asset); err != nil {
-		return nil, fmt.Errorf("update asset balance: %w", err)
+	if len(req.Splits) > 0 {
+		if (req.CategoryUUID != nil && *req.CategoryUUID != "") || req.Category != "" {
+			return nil, errors.New("category must be left out of a split expense; each split names its own")
+		}
+		if err := checkSplits(amount, req.Splits); err != nil {
+			return nil, err
+		}
 	}
-	slog.Info("balance_updated", "asset_uuid", asset.UUID, "old", oldQty.String(), "new", asset.Quantity.String())
+	var expense *models.Expense
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var category *models.Category
+		var splits []models.ExpenseSplit
+		var err error
+		if len(req.Splits) > 0 {
+			if splits, err = resolveSplits(ctx, repos.Categories, userID, req.Splits); err != nil {
+				return err
+			}
+			category = splits[0].Category
+		} else if category, err = resolveCategory(ctx, repos.Categories, userID, req.CategoryUUID, req.Category); err != nil {
+			return err
+		}
+		asset, err := lockCashAsset(ctx, repos.Assets, req.AssetUUID, userID)
+		if err != nil {
+			return err
+		}
+		if amount.GreaterThan(asset.Quantity) {
+			return errors.New("expense amount cannot exceed the selected asset balance")
+		}
 
-	expense := &models.Expense{
-		UserID:   userID,
-		AssetID:  asset.ID,
-		Amount:   amount,
-		Category: req.Category,
-		Note:     req.Note,
-		Date:     req.Date,
-	}
+		expense = &models.Expense{
+			UserID:     userID,
+			AssetID:    asset.ID,
+			Amount:     amount,
+			CategoryID: category.ID,
+			Category:   category,
+			Note:       req.Note,
+			Date:       req.Date,
+		}
+		if err := repos.Expenses.Create(ctx, expense); err != nil {
+			return fmt.Errorf("create expense: %w", err)
+		}
+		if len(splits) > 0 {
+			if err := repos.Expenses.ReplaceSplits(ctx, expense, splits); err != nil {
+				return err
+			}
+			expense.Splits = splits
+		}
 
-	if err := s.repo.Create(ctx, expense); err != nil {
-		return nil, fmt.Errorf("create expense: %w", err)
+		if len(tagNames) > 0 {
+			tags, err := resolveTags(ctx, repos.Tags, userID, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := repos.Expenses.ReplaceTags(ctx, expense, tags); err != nil {
+				return fmt.Errorf("tag expense: %w", err)
+			}
+			expense.Tags = tags
+		}
+		// Deduct from CASH asset
+		return postLedger(ctx, repos, expensePosting(expense), map[int64]*models.Asset{asset.ID: asset})
+	})
+	if err != nil {
+		return nil, err
 	}
-	slog.Info("expense_created", "user_id", userID, "amount", req.Amount, "category", req.Category, "asset_uuid", req.AssetUUID)
+	slog.Info("expense_created", "user_id", userID, "amount", req.Amount, "category_uuid", expense.Category.UUID, "asset_uuid", req.AssetUUID)
 	return expense, nil
 }
 
@@ -100,7 +119,7 @@ func (s *ExpenseService) GetExpense(ctx context.Context, userID int64, uuid stri
 	return expense, nil
 }
 
-func (s *ExpenseService) ListExpenses(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Expense, port.ListMeta, error) {
+func (s *ExpenseService) ListExpenses(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string, page, limit int) ([]models.Expense, port.ListMeta, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -110,7 +129,11 @@ func (s *ExpenseService) ListExpenses(ctx context.Context, userID int64, dateFro
 	if limit > 100 {
 		limit = 100
 	}
-	expenses, total, err := s.repo.ListByUserID(ctx, userID, dateFrom, dateTo, page, limit)
+	tags, err := normalizeTagNames(tags)
+	if err != nil {
+		return nil, port.ListMeta{}, err
+	}
+	expenses, total, err := s.repo.ListByUserID(ctx, userID, dateFrom, dateTo, tags, page, limit)
 	if err != nil {
 		return nil, port.ListMeta{}, fmt.Errorf("list expenses: %w", err)
 	}
@@ -126,130 +149,236 @@ func (s *ExpenseService) ListExpenses(ctx context.Context, userID int64, dateFro
 }
 
 func (s *ExpenseService) UpdateExpense(ctx context.Context, userID int64, uuid string, req port.UpdateExpenseRequest) (*models.Expense, error) {
-	expense, err := s.GetExpense(ctx, userID, uuid)
-	if err != nil {
-		return nil, err
-	}
-
-	oldAmount := expense.Amount
-	oldAssetID := expense.AssetID
-
-	// Update fields
-	if req.Amount != nil {
-		if *req.Amount <= 0 {
-			return nil, errors.New("amount must be positive")
-		}
-		expense.Amount = decimal.NewFromFloat(*req.Amount)
-	}
-	if req.Category != nil {
-		if !isValidExpenseCategory(*req.Category) {
-			return nil, errors.New("invalid expense category")
-		}
-		expense.Category = *req.Category
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
 	}
 	if req.Note != nil {
 		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
 			return nil, fmt.Errorf("note %w", err)
 		}
-		expense.Note = req.Note
 	}
-	if req.Date != nil {
-		expense.Date = *req.Date
+	var tagNames []string
+	if req.Tags != nil {
+		var err error
+		if tagNames, err = normalizeTagNames(*req.Tags); err != nil {
+			return nil, err
+		}
 	}
 
-	// Adjust CASH asset balances
-	if req.AssetUUID != nil {
-		newAsset, err := s.lookupCashAsset(ctx, *req.AssetUUID, userID)
+	var expense *models.Expense
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		expense, err = repos.Expenses.GetByUUIDForUpdate(ctx, uuid, userID)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("get expense: %w", err)
+		}
+		if expense == nil {
+			return errors.New("expense not found")
 		}
 
-		if newAsset.ID != oldAssetID {
-			if newAsset.Quantity.LessThan(expense.Amount) {
-				return nil, errors.New("expense amount cannot exceed the selected asset balance")
+		oldAmount := expense.Amount
+		oldAssetID := expense.AssetID
+
+		// Update fields
+		if req.Amount != nil {
+			expense.Amount = decimal.NewFromFloat(*req.Amount)
+		}
+		// A split expense changes its amount or categories only through new splits.
+		hasCategory := req.CategoryUUID != nil || req.Category != nil
+		switch {
+		case req.Splits != nil && len(*req.Splits) > 0:
+			if hasCategory {
+				return errors.New("category must be left out of a split expense; each split names its own")
+			}
+			if err := checkSplits(expense.Amount, *req.Splits); err != nil {
+				return err
 			}
-			// Restore old asset: add back old amount
-			oldAsset, err := s.assetRepo.GetByID(ctx, oldAssetID)
+			splits, err := resolveSplits(ctx, repos.Categories, userID, *req.Splits)
 			if err != nil {
-				return nil, fmt.Errorf("get old asset: %w", err)
+				return err
 			}
-			if oldAsset != nil {
-				oldAsset.Quantity = oldAsset.Quantity.Add(oldAmount)
-				if err := s.assetRepo.Update(ctx, oldAsset); err != nil {
-					return nil, fmt.Errorf("restore old asset balance: %w", err)
-				}
+			if err := repos.Expenses.ReplaceSplits(ctx, expense, splits); err != nil {
+				return err
 			}
-
-			// Deduct new amount from new asset
-			newAsset.Quantity = newAsset.Quantity.Sub(expense.Amount)
-			if err := s.assetRepo.Update(ctx, newAsset); err != nil {
-				return nil, fmt.Errorf("update new asset balance: %w", err)
+			expense.Splits = splits
+			expense.CategoryID, expense.Category = splits[0].CategoryID, splits[0].Category
+		case len(expense.Splits) > 0 && req.Splits == nil:
+			if hasCategory {
+				return errors.New("category of a split expense must be changed through its splits")
 			}
-			expense.AssetID = newAsset.ID
-		} else {
-			// Same asset, adjust difference
-			diff := expense.Amount.Sub(oldAmount)
-			if !diff.IsZero() {
-				if diff.IsPositive() && newAsset.Quantity.LessThan(diff) {
-					return nil, errors.New("expense amount cannot exceed the selected asset balance")
+			if req.Amount != nil && !expense.Amount.Equal(oldAmount) {
+				return errors.New("amount of a split expense must be changed together with its splits")
+			}
+		default:
+			// Not split, or splits: [] to undo the split; the expense keeps the first line's category
+			// unless a category is given.
+			if req.Splits != nil && len(expense.Splits) > 0 {
+				if err := repos.Expenses.ReplaceSplits(ctx, expense, nil); err != nil {
+					return err
 				}
-				newAsset.Quantity = newAsset.Quantity.Sub(diff)
-				if err := s.assetRepo.Update(ctx, newAsset); err != nil {
-					return nil, fmt.Errorf("update asset balance: %w", err)
+				expense.Splits = nil
+			}
+			if hasCategory {
+				category, err := resolveCategory(ctx, repos.Categories, userID, req.CategoryUUID, categoryCode(req.Category))
+				if err != nil {
+					return err
 				}
+				expense.CategoryID, expense.Category = category.ID, category
 			}
 		}
-	} else if req.Amount != nil {
-		// Only amount changed, same asset
-		diff := expense.Amount.Sub(oldAmount)
-		if !diff.IsZero() {
-			asset, err := s.assetRepo.GetByID(ctx, oldAssetID)
+		if req.Note != nil {
+			expense.Note = req.Note
+		}
+		if req.Date != nil {
+			expense.Date = *req.Date
+		}
+
+		newAssetID := oldAssetID
+		if req.AssetUUID != nil {
+			newAsset, err := lookupCashAsset(ctx, repos.Assets, *req.AssetUUID, userID)
 			if err != nil {
-				return nil, fmt.Errorf("get asset: %w", err)
+				return err
 			}
-			if asset != nil {
-				if diff.IsPositive() && asset.Quantity.LessThan(diff) {
-					return nil, errors.New("expense amount cannot exceed the selected asset balance")
-				}
-				asset.Quantity = asset.Quantity.Sub(diff)
-				if err := s.assetRepo.Update(ctx, asset); err != nil {
-					return nil, fmt.Errorf("update asset balance: %w", err)
-				}
+			newAssetID = newAsset.ID
+		}
+
+		// Adjust CASH asset balances
+		locked, err := lockAssetsByID(ctx, repos.Assets, oldAssetID, newAssetID)
+		if err != nil {
+			return err
+		}
+		if newAssetID != oldAssetID {
+			newAsset := locked[newAssetID]
+			if newAsset == nil {
+				return errors.New("asset not found")
+			}
+			if newAsset.Quantity.LessThan(expense.Amount) {
+				return errors.New("expense amount cannot exceed the selected asset balance")
+			}
+			expense.AssetID = newAssetID
+		} else if diff := expense.Amount.Sub(oldAmount); diff.IsPositive() {
+			if asset := locked[oldAssetID]; asset != nil && asset.Quantity.LessThan(diff) {
+				return errors.New("expense amount cannot exceed the selected asset balance")
 			}
 		}
-	}
 
-	if err := s.repo.Update(ctx, expense); err != nil {
-		return nil, fmt.Errorf("update expense: %w", err)
+		if err := repos.Expenses.Update(ctx, expense); err != nil {
+			return fmt.Errorf("update expense: %w", err)
+		}
+		if req.Tags != nil {
+			tags, err := resolveTags(ctx, repos.Tags, userID, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := repos.Expenses.ReplaceTags(ctx, expense, tags); err != nil {
+				return fmt.Errorf("tag expense: %w", err)
+			}
+			expense.Tags = tags
+		}
+		return postLedger(ctx, repos, expensePosting(expense), locked)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return expense, nil
 }
 
 func (s *ExpenseService) DeleteExpense(ctx context.Context, userID int64, uuid string) error {
-	expense, err := s.GetExpense(ctx, userID, uuid)
+	var expense *models.Expense
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		expense, err = repos.Expenses.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get expense: %w", err)
+		}
+		if expense == nil {
+			return errors.New("expense not found")
+		}
+
+		// Restore CASH asset balance
+		locked, err := lockAssetsByID(ctx, repos.Assets, expense.AssetID)
+		if err != nil {
+			return err
+		}
+		reversal := expensePosting(expense)
+		reversal.Description = "expense deleted"
+		reversal.Lines = nil
+		if err := postLedger(ctx, repos, reversal, locked); err != nil {
+			return err
+		}
+
+		if err := repos.Expenses.Delete(ctx, uuid, userID); err != nil {
+			return fmt.Errorf("delete expense: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	slog.Info("expense_deleted", "user_id", userID, "uuid", uuid, "amount", expense.Amount.String())
+	return nil
+}
 
-	// Restore CASH asset balance
-	asset, err := s.assetRepo.GetByID(ctx, expense.AssetID)
-	if err != nil {
-		return fmt.Errorf("get asset: %w", err)
-	}
-	if asset != nil {
-		asset.Quantity = asset.Quantity.Add(expense.Amount)
-		if err := s.assetRepo.Update(ctx, asset); err != nil {
-			return fmt.Errorf("restore asset balance: %w", err)
+// expensePosting moves the expense amount from its CASH asset into the category's expense account, or for a
+// split expense into each line's.
+func expensePosting(e *models.Expense) ledgerPosting {
+	p := ledgerPosting{
+		UserID:        e.UserID,
+		ReferenceType: models.LedgerRefExpense,
+		ReferenceUUID: e.UUID,
+		Description:   "expense " + e.Category.Name,
+		OccurredAt:    e.Date,
+		Lines:         []ledgerLine{assetLine(e.AssetID, e.Amount.Neg())},
+	}
+	if len(e.Splits) == 0 {
+		p.Lines = append(p.Lines, accountLine(expenseAccount(e.Category), e.Amount))
+		return p
+	}
+	names := make([]string, len(e.Splits))
+	for i, l := range e.Splits {
+		names[i] = l.Category.Name
+		p.Lines = append(p.Lines, accountLine(expenseAccount(l.Category), l.Amount))
+	}
+	p.Description = "expense " + strings.Join(names, ", ")
+	return p
+}
+
+// checkSplits checks the lines of a split expense of amount: at least two, each positive, adding up to amount.
+func checkSplits(amount decimal.Decimal, lines []port.ExpenseSplitRequest) error {
+	if len(lines) < 2 {
+		return errors.New("splits must have at least two lines")
+	}
+	total := decimal.Zero
+	for _, l := range lines {
+		if l.Amount <= 0 {
+			return errors.New("split amount must be positive")
 		}
+		if l.Note != nil {
+			if err := validation.CheckMaxLen(*l.Note, validation.MaxNoteLen); err != nil {
+				return fmt.Errorf("split note %w", err)
+			}
+		}
+		total = total.Add(decimal.NewFromFloat(l.Amount))
 	}
-
-	if err := s.repo.Delete(ctx, uuid, userID); err != nil {
-		return fmt.Errorf("delete expense: %w", err)
+	if !total.Equal(amount) {
+		return fmt.Errorf("splits must add up to the amount %s, not %s", amount, total)
 	}
-	slog.Info("expense_deleted", "user_id", userID, "uuid", uuid, "amount", expense.Amount.String())
 	return nil
 }
 
+// resolveSplits returns the split lines with the user's categories, resolved like the category of an expense.
+func resolveSplits(ctx context.Context, categories port.CategoryRepository, userID int64, lines []port.ExpenseSplitRequest) ([]models.ExpenseSplit, error) {
+	splits := make([]models.ExpenseSplit, len(lines))
+	for i, l := range lines {
+		category, err := resolveCategory(ctx, categories, userID, l.CategoryUUID, l.Category)
+		if err != nil {
+			return nil, fmt.Errorf("split %d: %w", i+1, err)
+		}
+		splits[i] = models.ExpenseSplit{CategoryID: category.ID, Category: category, Amount: decimal.NewFromFloat(l.Amount), Note: l.Note}
+	}
+	return splits, nil
+}
+
 func isValidExpenseCategory(category models.ExpenseCategory) bool {
 	validCategories := []models.ExpenseCategory{
 		models.ExpenseCategoryFood,
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"monity/internal/core/port"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_checkSplits(t *testing.T) {
+	line := func(amount float64) port.ExpenseSplitRequest {
+		return port.ExpenseSplitRequest{Amount: amount}
+	}
+	long := strings.Repeat("x", 501)
+	tests := []struct {
+		name    string
+		lines   []port.ExpenseSplitRequest
+		wantErr string
+	}{
+		{"lines add up", []port.ExpenseSplitRequest{line(60000.5), line(25000), line(14999.5)}, ""},
+		{"decimal lines add up exactly", []port.ExpenseSplitRequest{line(0.1), line(0.2), line(99999.7)}, ""},
+		{"one line is not a split", []port.ExpenseSplitRequest{line(100000)}, "at least two lines"},
+		{"lines short of the amount", []port.ExpenseSplitRequest{line(60000), line(30000)}, "add up to the amount 100000, not 90000"},
+		{"lines over the amount", []port.ExpenseSplitRequest{line(60000), line(50000)}, "add up to the amount"},
+		{"zero line", []port.ExpenseSplitRequest{line(100000), line(0)}, "positive"},
+		{"negative line", []port.ExpenseSplitRequest{line(110000), line(-10000)}, "positive"},
+		{"long note", []port.ExpenseSplitRequest{line(50000), {Amount: 50000, Note: &long}}, "split note must be at most 500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSplits(decimal.NewFromInt(100000), tt.lines)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("checkSplits() error = %v; want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkSplits() error = %v; want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
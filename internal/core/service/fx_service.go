@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"monity/internal/config"
+	"monity/internal/core/port"
+	"monity/internal/models"
+	"monity/internal/pkg/cache"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// fxHistoryTTL caches rates of past days, which no longer change once stored.
+	fxHistoryTTL = 24 * time.Hour
+	// fxBackfillDays is how far before the asked date history is fetched when the table has no recent row;
+	// weekends and holidays have no close of their own.
+	fxBackfillDays = 10
+)
+
+// FXService serves port.FXService. Every currency is held as a daily USD leg (units per USD) in the fx_rates
+// table; a pair is the ratio of its two legs. Today's legs come from the FX providers and are cached for
+// CacheTTL; past legs come from the table, backfilled from the providers' daily history when missing.
+type FXService struct {
+	cfg       *config.PriceAPIConfig
+	cache     cache.Cache
+	repo      port.FXRateRepository
+	providers []port.PriceProvider // FX providers in fallback order
+	now       func() time.Time
+}
+
+func NewFXService(cfg *config.PriceAPIConfig, c cache.Cache, repo port.FXRateRepository, providers ...port.PriceProvider) port.FXService {
+	if c == nil {
+		c = cache.NewMemoryCache()
+	}
+	return &FXService{
+		cfg:       cfg,
+		cache:     c,
+		repo:      repo,
+		providers: orderProviders(providers, port.PriceKindFX, cfg.FXProviders),
+		now:       time.Now,
+	}
+}
+
+func normalizeCurrency(c string) string {
+	return strings.ToUpper(strings.TrimSpace(c))
+}
+
+func (s *FXService) Rate(ctx context.Context, from, to string) (float64, error) {
+	from, to = normalizeCurrency(from), normalizeCurrency(to)
+	if from == to {
+		return 1, nil
+	}
+	fromLeg, err := s.currentLeg(ctx, from)
+	if err != nil {
+		return 0, err
+	}
+	toLeg, err := s.currentLeg(ctx, to)
+	if err != nil {
+		return 0, err
+	}
+	return toLeg / fromLeg, nil
+}
+
+func (s *FXService) RateAt(ctx context.Context, from, to string, date time.Time) (float64, error) {
+	rate, _, err := s.HistoricalRate(ctx, from, to, date)
+	return rate, err
+}
+
+func (s *FXService) HistoricalRate(ctx context.Context, from, to string, date time.Time) (float64, bool, error) {
+	from, to = normalizeCurrency(from), normalizeCurrency(to)
+	if from == to {
+		return 1, false, nil
+	}
+	day := truncateUTCDay(date)
+	if !day.Before(truncateUTCDay(s.now())) {
+		rate, err := s.Rate(ctx, from, to)
+		return rate, false, err
+	}
+	fromLeg, fromEstimated, err := s.legAt(ctx, from, day)
+	if err != nil {
+		return 0, false, err
+	}
+	toLeg, toEstimated, err := s.legAt(ctx, to, day)
+	if err != nil {
+		return 0, false, err
+	}
+	return toLeg / fromLeg, fromEstimated || toEstimated, nil
+}
+
+func (s *FXService) Convert(ctx context.Context, amount decimal.Decimal, from, to string, at time.Time) (decimal.Decimal, error) {
+	var rate float64
+	var err error
+	if at.IsZero() {
+		rate, err = s.Rate(ctx, from, to)
+	} else {
+		rate, err = s.RateAt(ctx, from, to, at)
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return amount.Mul(decimal.NewFromFloat(rate)), nil
+}
+
+func (s *FXService) RefreshRates(ctx context.Context) (int, error) {
+	currencies, err := s.repo.ListQuotes(ctx, port.CurrencyUSD)
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool)
+	var rates []models.FXRate
+	var errs []error
+	today := truncateUTCDay(s.now())
+	for _, cur := range append(append(currencies, s.cfg.FXCurrencies...), port.DefaultCurrency) {
+		cur = normalizeCurrency(cur)
+		if cur == "" || cur == port.CurrencyUSD || seen[cur] {
+			continue
+		}
+		seen[cur] = true
+		rate, source, err := s.fetchLeg(ctx, cur)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", cur, err))
+			continue
+		}
+		s.cacheLeg(ctx, fxLegKey(cur, time.Time{}), rate, s.currentTTL())
+		rates = append(rates, fxRateRow(cur, today, rate, source))
+	}
+	if len(rates) == 0 && len(errs) > 0 {
+		return 0, errors.Join(errs...)
+	}
+	for _, err := range errs {
+		slog.Warn("fx_refresh_failed", "error", err)
+	}
+	if err := s.repo.Upsert(ctx, rates); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+// currentLeg returns today's units of cur per USD. It is fetched when not cached and stored as today's row;
+// when every provider fails the latest stored row is used.
+func (s *FXService) currentLeg(ctx context.Context, cur string) (float64, error) {
+	if cur == port.CurrencyUSD {
+		return 1, nil
+	}
+	key := fxLegKey(cur, time.Time{})
+	if rate, ok := s.cachedLeg(ctx, key); ok {
+		return rate, nil
+	}
+	today := truncateUTCDay(s.now())
+	rate, source, err := s.fetchLeg(ctx, cur)
+	if err != nil {
+		if row, rowErr := s.repo.GetOnOrBefore(ctx, port.CurrencyUSD, cur, today); rowErr == nil && row != nil {
+			slog.Warn("fx_rate_fallback", "currency", cur, "date", row.RateDate.Format("2006-01-02"), "error", err)
+			return row.Rate.InexactFloat64(), nil
+		}
+		return 0, fmt.Errorf("fetch exchange rate: %w", err)
+	}
+	s.cacheLeg(ctx, key, rate, s.currentTTL())
+	if err := s.repo.Upsert(ctx, []models.FXRate{fxRateRow(cur, today, rate, source)}); err != nil {
+		slog.Warn("fx_rate_store_failed", "currency", cur, "error", err)
+	}
+	return rate, nil
+}
+
+// legAt returns the units of cur per USD as of a past day. Without a stored row close to the day the
+// providers' daily history is fetched and stored; without any history the current rate is used and
+// estimated is true.
+func (s *FXService) legAt(ctx context.Context, cur string, day time.Time) (rate float64, estimated bool, err error) {
+	if cur == port.CurrencyUSD {
+		return 1, false, nil
+	}
+	key := fxLegKey(cur, day)
+	if rate, ok := s.cachedLeg(ctx, key); ok {
+		return rate, false, nil
+	}
+	row, err := s.repo.GetOnOrBefore(ctx, port.CurrencyUSD, cur, day)
+	if err != nil {
+		slog.Warn("fx_history_read_failed", "currency", cur, "error", err)
+	}
+	if row == nil || day.Sub(truncateUTCDay(row.RateDate)) > fxBackfillDays*24*time.Hour {
+		if filled := s.backfill(ctx, cur, day); filled != nil {
+			row = filled
+		}
+	}
+	if row == nil {
+		slog.Warn("fx_history_unavailable", "currency", cur, "date", day.Format("2006-01-02"))
+		rate, err = s.currentLeg(ctx, cur)
+		return rate, err == nil, err
+	}
+	rate = row.Rate.InexactFloat64()
+	s.cacheLeg(ctx, key, rate, fxHistoryTTL)
+	return rate, false, nil
+}
+
+// backfill stores the daily closes of USD/cur for the days up to day and returns the latest of them.
+func (s *FXService) backfill(ctx context.Context, cur string, day time.Time) *models.FXRate {
+	pair := port.CurrencyUSD + cur
+	candles, source, err := firstAnswer(s.providers, port.PriceKindFX, pair, func(p port.PriceProvider) ([]port.OHLCVData, error) {
+		return p.OHLCV(ctx, port.PriceKindFX, pair, cur, day.AddDate(0, 0, -fxBackfillDays), day)
+	})
+	if err != nil {
+		return nil
+	}
+	var rows []models.FXRate
+	for _, c := range candles {
+		if c.Close <= 0 || truncateUTCDay(c.TimeOpen).After(day) {
+			continue
+		}
+		rows = append(rows, fxRateRow(cur, truncateUTCDay(c.TimeOpen), c.Close, source))
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	if err := s.repo.Upsert(ctx, rows); err != nil {
+		slog.Warn("fx_rate_store_failed", "currency", cur, "error", err)
+	}
+	latest := rows[0]
+	for _, r := range rows[1:] {
+		if r.RateDate.After(latest.RateDate) {
+			latest = r
+		}
+	}
+	return &latest
+}
+
+func (s *FXService) fetchLeg(ctx context.Context, cur string) (float64, string, error) {
+	rate, source, err := firstAnswer(s.providers, port.PriceKindFX, port.CurrencyUSD+cur, func(p port.PriceProvider) (float64, error) {
+		return p.ExchangeRate(ctx, port.CurrencyUSD, cur)
+	})
+	if err == nil && rate <= 0 {
+		err = fmt.Errorf("exchange rate not found for %s to %s", port.CurrencyUSD, cur)
+	}
+	return rate, source, err
+}
+
+func (s *FXService) currentTTL() time.Duration {
+	if ttl := time.Duration(s.cfg.CacheTTL) * time.Second; ttl > 0 {
+		return ttl
+	}
+	return 60 * time.Second
+}
+
+// fxLegKey is the cache key of a USD leg; a zero day is the current rate.
+func fxLegKey(cur string, day time.Time) string {
+	if day.IsZero() {
+		return "fx:USD:" + cur
+	}
+	return "fx:USD:" + cur + ":" + day.Format("2006-01-02")
+}
+
+func (s *FXService) cachedLeg(ctx context.Context, key string) (float64, bool) {
+	raw, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return 0, false
+	}
+	rate, err := strconv.ParseFloat(string(raw), 64)
+	return rate, err == nil && rate > 0
+}
+
+func (s *FXService) cacheLeg(ctx context.Context, key string, rate float64, ttl time.Duration) {
+	_ = s.cache.Set(ctx, key, []byte(strconv.FormatFloat(rate, 'g', -1, 64)), ttl)
+}
+
+func fxRateRow(cur string, day time.Time, rate float64, source string) models.FXRate {
+	now := time.Now()
+	return models.FXRate{
+		Base:      port.CurrencyUSD,
+		Quote:     cur,
+		RateDate:  day,
+		Rate:      decimal.NewFromFloat(rate),
+		Source:    source,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func truncateUTCDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"monity/internal/config"
+	"monity/internal/core/port"
+	"monity/internal/models"
+)
+
+// memFXRepo is an in-memory port.FXRateRepository.
+type memFXRepo struct{ rows []models.FXRate }
+
+func (r *memFXRepo) Upsert(ctx context.Context, rates []models.FXRate) error {
+	for _, in := range rates {
+		replaced := false
+		for i, row := range r.rows {
+			if row.Base == in.Base && row.Quote == in.Quote && row.RateDate.Equal(in.RateDate) {
+				r.rows[i], replaced = in, true
+			}
+		}
+		if !replaced {
+			r.rows = append(r.rows, in)
+		}
+	}
+	return nil
+}
+
+func (r *memFXRepo) GetOnOrBefore(ctx context.Context, base, quote string, date time.Time) (*models.FXRate, error) {
+	var best *models.FXRate
+	for i, row := range r.rows {
+		if row.Base == base && row.Quote == quote && !row.RateDate.After(date) && (best == nil || row.RateDate.After(best.RateDate)) {
+			best = &r.rows[i]
+		}
+	}
+	return best, nil
+}
+
+func (r *memFXRepo) ListQuotes(ctx context.Context, base string) ([]string, error) {
+	var out []string
+	for _, row := range r.rows {
+		out = append(out, row.Quote)
+	}
+	return out, nil
+}
+
+// fxStub quotes USD legs from rates and answers daily history from history (by date).
+type fxStub struct {
+	stubProvider
+	rates   map[string]float64
+	history map[string]map[string]float64 // currency -> YYYY-MM-DD -> units per USD
+}
+
+func (p *fxStub) ExchangeRate(ctx context.Context, from, to string) (float64, error) {
+	if rate, ok := p.rates[to]; ok && from == port.CurrencyUSD {
+		return rate, nil
+	}
+	return 0, p.err
+}
+
+func (p *fxStub) OHLCV(ctx context.Context, kind, symbol, currency string, from, to time.Time) ([]port.OHLCVData, error) {
+	var out []port.OHLCVData
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if rate, ok := p.history[symbol[3:]][day.Format("2006-01-02")]; ok {
+			out = append(out, port.OHLCVData{Symbol: symbol, TimeOpen: day, Close: rate, Currency: currency})
+		}
+	}
+	return out, nil
+}
+
+func TestFXService(t *testing.T) {
+	provider := &fxStub{
+		stubProvider: stubProvider{name: "fx", kinds: []string{port.PriceKindFX}},
+		rates:        map[string]float64{"IDR": 16000, "EUR": 0.8},
+		history:      map[string]map[string]float64{"IDR": {"2025-06-02": 15000}, "EUR": {"2025-06-02": 0.9}},
+	}
+	repo := &memFXRepo{}
+	svc := NewFXService(&config.PriceAPIConfig{}, nil, repo, provider).(*FXService)
+	svc.now = func() time.Time { return time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC) }
+	ctx := context.Background()
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-9*math.Max(1, math.Abs(want)) }
+
+	// Current rates triangulate through USD and store today's legs.
+	if got, err := svc.Rate(ctx, "eur", "IDR"); err != nil || !near(got, 20000) {
+		t.Errorf("Rate(EUR, IDR) = %v, %v; want 20000", got, err)
+	}
+	if got, err := svc.Rate(ctx, "IDR", "USD"); err != nil || !near(got, 1.0/16000) {
+		t.Errorf("Rate(IDR, USD) = %v, %v; want 1/16000", got, err)
+	}
+	if len(repo.rows) != 2 {
+		t.Errorf("stored %d rows, want today's IDR and EUR legs", len(repo.rows))
+	}
+
+	// A past date is backfilled from the provider's history; the weekend uses Friday's close.
+	if got, err := svc.RateAt(ctx, "EUR", "IDR", time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)); err != nil || !near(got, 15000/0.9) {
+		t.Errorf("RateAt(EUR, IDR, 2025-06-03) = %v, %v; want %v", got, err, 15000/0.9)
+	}
+	if row, _ := repo.GetOnOrBefore(ctx, port.CurrencyUSD, "IDR", time.Date(2025, 6, 5, 0, 0, 0, 0, time.UTC)); row == nil || row.Rate.InexactFloat64() != 15000 {
+		t.Errorf("backfilled IDR row = %+v, want 15000", row)
+	}
+
+	// Without history and without a provider, the latest stored leg answers.
+	provider.rates, provider.history, provider.err = nil, nil, errors.New("fx down")
+	svc2 := NewFXService(&config.PriceAPIConfig{}, nil, repo, provider).(*FXService)
+	svc2.now = svc.now
+	if got, err := svc2.Rate(ctx, "USD", "IDR"); err != nil || got != 16000 {
+		t.Errorf("Rate with providers down = %v, %v; want the stored 16000", got, err)
+	}
+	if _, err := svc2.Rate(ctx, "USD", "JPY"); err == nil {
+		t.Error("Rate(USD, JPY) with nothing stored: expected an error")
+	}
+
+	// A past date with stored history is exact; one before any history is today's rate, flagged as estimated.
+	if got, estimated, err := svc2.HistoricalRate(ctx, "USD", "IDR", time.Date(2025, 6, 4, 0, 0, 0, 0, time.UTC)); err != nil || got != 15000 || estimated {
+		t.Errorf("HistoricalRate(USD, IDR, 2025-06-04) = %v, %v, %v; want 15000, not estimated", got, estimated, err)
+	}
+	if got, estimated, err := svc2.HistoricalRate(ctx, "USD", "IDR", time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)); err != nil || got != 16000 || !estimated {
+		t.Errorf("HistoricalRate(USD, IDR, 2024-01-02) = %v, %v, %v; want 16000, estimated", got, estimated, err)
+	}
+}
@@ -18,28 +18,11 @@ import (
 type IncomeService struct {
 	repo      port.IncomeRepository
 	assetRepo port.AssetRepository
+	uow       port.UnitOfWork
 }
 
-func NewIncomeService(repo port.IncomeRepository, assetRepo port.AssetRepository) port.IncomeService {
-	return &IncomeService{repo: repo, assetRepo: assetRepo}
-}
-
-// lookupCashAsset validates that the asset exists, belongs to the user, and is type CASH.
-func (s *IncomeService) lookupCashAsset(ctx context.Context, assetUUID string, userID int64) (*models.Asset, error) {
-	if strings.TrimSpace(assetUUID) == "" {
-		return nil, errors.New("assetUuid is required")
-	}
-	asset, err := s.assetRepo.GetByUUID(ctx, assetUUID, userID)
-	if err != nil {
-		return nil, fmt.Errorf("get asset: %w", err)
-	}
-	if asset == nil {
-		return nil, errors.New("asset not found")
-	}
-	if asset.Type != models.AssetTypeCash {
-		return nil, errors.New("asset must be of type CASH")
-	}
-	return asset, nil
+func NewIncomeService(repo port.IncomeRepository, assetRepo port.AssetRepository, uow port.UnitOfWork) port.IncomeService {
+	return &IncomeService{repo: repo, assetRepo: assetRepo, uow: uow}
 }
 
 func (s *IncomeService) CreateIncome(ctx context.Context, userID int64, req port.CreateIncomeRequest) (*models.Income, error) {
@@ -57,33 +40,59 @@ func (s *IncomeService) CreateIncome(ctx context.Context, userID int64, req port
 			return nil, fmt.Errorf("note %w", err)
 		}
 	}
+	tagNames, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
 
-	asset, err := s.lookupCashAsset(ctx, req.AssetUUID, userID)
+	incomeType, err := parseIncomeType(req.Type)
 	if err != nil {
 		return nil, err
 	}
 
 	amount := decimal.NewFromFloat(req.Amount)
+	var income *models.Income
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		asset, err := lockCashAsset(ctx, repos.Assets, req.AssetUUID, userID)
+		if err != nil {
+			return err
+		}
 
-	// Add to CASH asset
-	oldQty := asset.Quantity
-	asset.Quantity = asset.Quantity.Add(amount)
-	if err := s.assetRepo.Update(ctx, asset); err != nil {
-		return nil, fmt.Errorf("update asset balance: %w", err)
-	}
-	slog.Info("balance_updated", "asset_uuid", asset.UUID, "old", oldQty.String(), "new", asset.Quantity.String())
-
-	income := &models.Income{
-		UserID:  userID,
-		AssetID: asset.ID,
-		Amount:  amount,
-		Source:  req.Source,
-		Note:    req.Note,
-		Date:    req.Date,
-	}
+		income = &models.Income{
+			UserID:  userID,
+			AssetID: asset.ID,
+			Amount:  amount,
+			Source:  req.Source,
+			Note:    req.Note,
+			Date:    req.Date,
+		}
+		if req.SourceAssetUUID != nil && *req.SourceAssetUUID != "" {
+			source, err := lookupIncomeSource(ctx, repos.Assets, *req.SourceAssetUUID, userID)
+			if err != nil {
+				return err
+			}
+			income.SourceAssetID, income.SourceAsset = &source.ID, source
+			income.Type = &incomeType
+		}
+		if err := repos.Incomes.Create(ctx, income); err != nil {
+			return fmt.Errorf("create income: %w", err)
+		}
 
-	if err := s.repo.Create(ctx, income); err != nil {
-		return nil, fmt.Errorf("create income: %w", err)
+		if len(tagNames) > 0 {
+			tags, err := resolveTags(ctx, repos.Tags, userID, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := repos.Incomes.ReplaceTags(ctx, income, tags); err != nil {
+				return fmt.Errorf("tag income: %w", err)
+			}
+			income.Tags = tags
+		}
+		// Add to CASH asset
+		return postLedger(ctx, repos, incomePosting(income), map[int64]*models.Asset{asset.ID: asset})
+	})
+	if err != nil {
+		return nil, err
 	}
 	slog.Info("income_created", "user_id", userID, "amount", req.Amount, "source", req.Source, "asset_uuid", req.AssetUUID)
 	return income, nil
@@ -100,7 +109,7 @@ func (s *IncomeService) GetIncome(ctx context.Context, userID int64, uuid string
 	return income, nil
 }
 
-func (s *IncomeService) ListIncomes(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Income, port.ListMeta, error) {
+func (s *IncomeService) ListIncomes(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string, page, limit int) ([]models.Income, port.ListMeta, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -110,7 +119,11 @@ func (s *IncomeService) ListIncomes(ctx context.Context, userID int64, dateFrom,
 	if limit > 100 {
 		limit = 100
 	}
-	incomes, total, err := s.repo.ListByUserID(ctx, userID, dateFrom, dateTo, page, limit)
+	tags, err := normalizeTagNames(tags)
+	if err != nil {
+		return nil, port.ListMeta{}, err
+	}
+	incomes, total, err := s.repo.ListByUserID(ctx, userID, dateFrom, dateTo, tags, page, limit)
 	if err != nil {
 		return nil, port.ListMeta{}, fmt.Errorf("list incomes: %w", err)
 	}
@@ -126,20 +139,8 @@ func (s *IncomeService) ListIncomes(ctx context.Context, userID int64, dateFrom,
 }
 
 func (s *IncomeService) UpdateIncome(ctx context.Context, userID int64, uuid string, req port.UpdateIncomeRequest) (*models.Income, error) {
-	income, err := s.GetIncome(ctx, userID, uuid)
-	if err != nil {
-		return nil, err
-	}
-
-	oldAmount := income.Amount
-	oldAssetID := income.AssetID
-
-	// Update fields
-	if req.Amount != nil {
-		if *req.Amount <= 0 {
-			return nil, errors.New("amount must be positive")
-		}
-		income.Amount = decimal.NewFromFloat(*req.Amount)
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
 	}
 	if req.Source != nil {
 		if strings.TrimSpace(*req.Source) == "" {
@@ -148,98 +149,191 @@ func (s *IncomeService) UpdateIncome(ctx context.Context, userID int64, uuid str
 		if err := validation.CheckMaxLen(*req.Source, validation.MaxSourceLen); err != nil {
 			return nil, fmt.Errorf("source %w", err)
 		}
-		income.Source = *req.Source
 	}
 	if req.Note != nil {
 		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
 			return nil, fmt.Errorf("note %w", err)
 		}
-		income.Note = req.Note
 	}
-	if req.Date != nil {
-		income.Date = *req.Date
+	var tagNames []string
+	if req.Tags != nil {
+		var err error
+		if tagNames, err = normalizeTagNames(*req.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if req.Type != nil {
+		if _, err := parseIncomeType(*req.Type); err != nil {
+			return nil, err
+		}
 	}
 
-	// Adjust CASH asset balances
-	if req.AssetUUID != nil {
-		newAsset, err := s.lookupCashAsset(ctx, *req.AssetUUID, userID)
+	var income *models.Income
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		income, err = repos.Incomes.GetByUUIDForUpdate(ctx, uuid, userID)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("get income: %w", err)
+		}
+		if income == nil {
+			return errors.New("income not found")
 		}
 
-		if newAsset.ID != oldAssetID {
-			// Reverse old asset: subtract old amount
-			oldAsset, err := s.assetRepo.GetByID(ctx, oldAssetID)
-			if err != nil {
-				return nil, fmt.Errorf("get old asset: %w", err)
-			}
-			if oldAsset != nil {
-				oldAsset.Quantity = oldAsset.Quantity.Sub(oldAmount)
-				if err := s.assetRepo.Update(ctx, oldAsset); err != nil {
-					return nil, fmt.Errorf("reverse old asset balance: %w", err)
-				}
-			}
+		oldAssetID := income.AssetID
 
-			// Add new amount to new asset
-			newAsset.Quantity = newAsset.Quantity.Add(income.Amount)
-			if err := s.assetRepo.Update(ctx, newAsset); err != nil {
-				return nil, fmt.Errorf("update new asset balance: %w", err)
+		// Update fields
+		if req.Amount != nil {
+			income.Amount = decimal.NewFromFloat(*req.Amount)
+		}
+		if req.Source != nil {
+			income.Source = *req.Source
+		}
+		if req.Note != nil {
+			income.Note = req.Note
+		}
+		if req.Date != nil {
+			income.Date = *req.Date
+		}
+		if req.SourceAssetUUID != nil {
+			income.SourceAssetID, income.SourceAsset = nil, nil
+			if *req.SourceAssetUUID != "" {
+				source, err := lookupIncomeSource(ctx, repos.Assets, *req.SourceAssetUUID, userID)
+				if err != nil {
+					return err
+				}
+				income.SourceAssetID, income.SourceAsset = &source.ID, source
 			}
-			income.AssetID = newAsset.ID
+		}
+		if req.Type != nil {
+			t, _ := parseIncomeType(*req.Type)
+			income.Type = &t
+		}
+		if income.SourceAssetID == nil {
+			income.Type = nil
 		} else {
-			// Same asset, adjust difference
-			diff := income.Amount.Sub(oldAmount) // positive = more income
-			if !diff.IsZero() {
-				newAsset.Quantity = newAsset.Quantity.Add(diff)
-				if err := s.assetRepo.Update(ctx, newAsset); err != nil {
-					return nil, fmt.Errorf("update asset balance: %w", err)
+			if income.Type == nil {
+				t := models.IncomeTypeOther
+				income.Type = &t
+			}
+			if income.SourceAsset == nil {
+				if income.SourceAsset, err = repos.Assets.GetByID(ctx, *income.SourceAssetID); err != nil {
+					return fmt.Errorf("get source asset: %w", err)
 				}
 			}
 		}
-	} else if req.Amount != nil {
-		// Only amount changed, same asset
-		diff := income.Amount.Sub(oldAmount)
-		if !diff.IsZero() {
-			asset, err := s.assetRepo.GetByID(ctx, oldAssetID)
+
+		newAssetID := oldAssetID
+		if req.AssetUUID != nil {
+			newAsset, err := lookupCashAsset(ctx, repos.Assets, *req.AssetUUID, userID)
 			if err != nil {
-				return nil, fmt.Errorf("get asset: %w", err)
+				return err
 			}
-			if asset != nil {
-				asset.Quantity = asset.Quantity.Add(diff)
-				if err := s.assetRepo.Update(ctx, asset); err != nil {
-					return nil, fmt.Errorf("update asset balance: %w", err)
-				}
+			newAssetID = newAsset.ID
+		}
+
+		// Adjust CASH asset balances
+		locked, err := lockAssetsByID(ctx, repos.Assets, oldAssetID, newAssetID)
+		if err != nil {
+			return err
+		}
+		if newAssetID != oldAssetID {
+			if locked[newAssetID] == nil {
+				return errors.New("asset not found")
 			}
+			income.AssetID = newAssetID
 		}
-	}
 
-	if err := s.repo.Update(ctx, income); err != nil {
-		return nil, fmt.Errorf("update income: %w", err)
+		if err := repos.Incomes.Update(ctx, income); err != nil {
+			return fmt.Errorf("update income: %w", err)
+		}
+		if req.Tags != nil {
+			tags, err := resolveTags(ctx, repos.Tags, userID, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := repos.Incomes.ReplaceTags(ctx, income, tags); err != nil {
+				return fmt.Errorf("tag income: %w", err)
+			}
+			income.Tags = tags
+		}
+		return postLedger(ctx, repos, incomePosting(income), locked)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return income, nil
 }
 
 func (s *IncomeService) DeleteIncome(ctx context.Context, userID int64, uuid string) error {
-	income, err := s.GetIncome(ctx, userID, uuid)
+	var income *models.Income
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		income, err = repos.Incomes.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get income: %w", err)
+		}
+		if income == nil {
+			return errors.New("income not found")
+		}
+
+		// Reverse CASH asset balance
+		locked, err := lockAssetsByID(ctx, repos.Assets, income.AssetID)
+		if err != nil {
+			return err
+		}
+		reversal := incomePosting(income)
+		reversal.Description = "income deleted"
+		reversal.Lines = nil
+		if err := postLedger(ctx, repos, reversal, locked); err != nil {
+			return err
+		}
+
+		if err := repos.Incomes.Delete(ctx, uuid, userID); err != nil {
+			return fmt.Errorf("delete income: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
 		return err
 	}
+	slog.Info("income_deleted", "user_id", userID, "uuid", uuid, "amount", income.Amount.String())
+	return nil
+}
 
-	// Reverse CASH asset balance
-	asset, err := s.assetRepo.GetByID(ctx, income.AssetID)
+// lookupIncomeSource returns the asset an income is paid by; any of the user's assets can be one.
+func lookupIncomeSource(ctx context.Context, assets port.AssetRepository, assetUUID string, userID int64) (*models.Asset, error) {
+	asset, err := assets.GetByUUID(ctx, assetUUID, userID)
 	if err != nil {
-		return fmt.Errorf("get asset: %w", err)
+		return nil, fmt.Errorf("get source asset: %w", err)
 	}
-	if asset != nil {
-		asset.Quantity = asset.Quantity.Sub(income.Amount)
-		if err := s.assetRepo.Update(ctx, asset); err != nil {
-			return fmt.Errorf("reverse asset balance: %w", err)
-		}
+	if asset == nil {
+		return nil, errors.New("sourceAssetUuid must be one of your assets")
 	}
+	return asset, nil
+}
 
-	if err := s.repo.Delete(ctx, uuid, userID); err != nil {
-		return fmt.Errorf("delete income: %w", err)
+func parseIncomeType(s string) (models.IncomeType, error) {
+	switch t := models.IncomeType(strings.ToUpper(strings.TrimSpace(s))); t {
+	case "":
+		return models.IncomeTypeOther, nil
+	case models.IncomeTypeDividend, models.IncomeTypeCoupon, models.IncomeTypeInterest, models.IncomeTypeRent, models.IncomeTypeOther:
+		return t, nil
+	default:
+		return "", errors.New("type must be DIVIDEND, COUPON, INTEREST, RENT or OTHER")
+	}
+}
+
+// incomePosting moves the income amount from the income account into its CASH asset.
+func incomePosting(i *models.Income) ledgerPosting {
+	return ledgerPosting{
+		UserID:        i.UserID,
+		ReferenceType: models.LedgerRefIncome,
+		ReferenceUUID: i.UUID,
+		Description:   "income " + i.Source,
+		OccurredAt:    i.Date,
+		Lines: []ledgerLine{
+			assetLine(i.AssetID, i.Amount),
+			accountLine(models.LedgerAccountIncome, i.Amount.Neg()),
+		},
 	}
-	slog.Info("income_deleted", "user_id", userID, "uuid", uuid, "amount", income.Amount.String())
-	return nil
 }
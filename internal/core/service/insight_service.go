@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"monity/internal/core/port"
@@ -19,7 +20,7 @@ func NewInsightService(repo port.InsightRepository) port.InsightService {
 	return &InsightService{repo: repo}
 }
 
-func (s *InsightService) GetCashflowSummary(ctx context.Context, userID int64, month string) (*port.CashflowSummary, error) {
+func (s *InsightService) GetCashflowSummary(ctx context.Context, userID int64, month string, rollup bool) (*port.CashflowSummary, error) {
 	// Parse month (format: YYYY-MM)
 	startDate, endDate, err := parseMonthRange(month)
 	if err != nil {
@@ -53,6 +54,9 @@ func (s *InsightService) GetCashflowSummary(ctx context.Context, userID int64, m
 		return nil, fmt.Errorf("get expenses by category: %w", err)
 	}
 
+	if rollup {
+		expenseByCategory = rollUpCategories(expenseByCategory)
+	}
 	if expenseByCategory == nil {
 		expenseByCategory = []port.CategoryTotal{}
 	}
@@ -155,6 +159,85 @@ func (s *InsightService) GetFinancialOverview(ctx context.Context, userID int64)
 	}, nil
 }
 
+func (s *InsightService) GetTagSummary(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, tags []string) ([]port.TagSummary, error) {
+	tags, err := normalizeTagNames(tags)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.repo.GetExpensesByTag(ctx, userID, dateFrom, dateTo, tags)
+	if err != nil {
+		return nil, fmt.Errorf("get expenses by tag: %w", err)
+	}
+	incomes, err := s.repo.GetIncomeByTag(ctx, userID, dateFrom, dateTo, tags)
+	if err != nil {
+		return nil, fmt.Errorf("get income by tag: %w", err)
+	}
+	return summarizeTags(expenses, incomes), nil
+}
+
+// summarizeTags gathers the per-tag totals into one summary per tag, the biggest spending first, with each
+// tag's categories ordered by spending and their percentages taken of the tag's spending.
+func summarizeTags(expenses []port.TagCategoryTotal, incomes []port.TagIncomeTotal) []port.TagSummary {
+	index := make(map[string]int)
+	out := []port.TagSummary{}
+	summary := func(uuid, name string) *port.TagSummary {
+		i, ok := index[uuid]
+		if !ok {
+			i = len(out)
+			index[uuid] = i
+			out = append(out, port.TagSummary{TagUUID: uuid, Tag: name, ExpenseByCategory: []port.CategoryTotal{}})
+		}
+		return &out[i]
+	}
+	for _, e := range expenses {
+		t := summary(e.TagUUID, e.Tag)
+		t.TotalExpense = t.TotalExpense.Add(e.Total)
+		t.ExpenseByCategory = append(t.ExpenseByCategory, e.CategoryTotal)
+	}
+	for _, in := range incomes {
+		t := summary(in.TagUUID, in.Tag)
+		t.TotalIncome = t.TotalIncome.Add(in.Total)
+	}
+	for i := range out {
+		t := &out[i]
+		t.Net = t.TotalIncome.Sub(t.TotalExpense)
+		for j := range t.ExpenseByCategory {
+			c := &t.ExpenseByCategory[j]
+			c.Percentage, _ = percentOf(c.Total, t.TotalExpense).Round(2).Float64()
+		}
+		sort.SliceStable(t.ExpenseByCategory, func(a, b int) bool {
+			return t.ExpenseByCategory[a].Total.GreaterThan(t.ExpenseByCategory[b].Total)
+		})
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		if !out[i].TotalExpense.Equal(out[j].TotalExpense) {
+			return out[i].TotalExpense.GreaterThan(out[j].TotalExpense)
+		}
+		return out[i].Tag < out[j].Tag
+	})
+	return out
+}
+
+// rollUpCategories merges every subcategory's total into its parent's, keeping the largest total first.
+func rollUpCategories(totals []port.CategoryTotal) []port.CategoryTotal {
+	index := make(map[string]int, len(totals))
+	var out []port.CategoryTotal
+	for _, t := range totals {
+		if t.ParentUUID != nil {
+			t = port.CategoryTotal{CategoryUUID: *t.ParentUUID, Category: *t.Parent, Total: t.Total, Percentage: t.Percentage}
+		}
+		if i, ok := index[t.CategoryUUID]; ok {
+			out[i].Total = out[i].Total.Add(t.Total)
+			out[i].Percentage += t.Percentage
+			continue
+		}
+		index[t.CategoryUUID] = len(out)
+		out = append(out, t)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Total.GreaterThan(out[j].Total) })
+	return out
+}
+
 // parseMonthRange parses a month string (YYYY-MM) and returns start and end dates
 func parseMonthRange(month string) (time.Time, time.Time, error) {
 	if month == "" {
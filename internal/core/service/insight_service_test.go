@@ -0,0 +1,84 @@
+package service
+
+import (
+	"testing"
+
+	"monity/internal/core/port"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_rollUpCategories(t *testing.T) {
+	d := decimal.RequireFromString
+	food, foodName, transport := "food-uuid", "Food", "transport-uuid"
+	totals := []port.CategoryTotal{
+		{CategoryUUID: transport, Category: "Transport", Total: d("300"), Percentage: 30},
+		{CategoryUUID: "groceries-uuid", Category: "Groceries", ParentUUID: &food, Parent: &foodName, Total: d("250"), Percentage: 25},
+		{CategoryUUID: food, Category: "Food", Total: d("200"), Percentage: 20},
+		{CategoryUUID: "dining-uuid", Category: "Dining out", ParentUUID: &food, Parent: &foodName, Total: d("150"), Percentage: 15},
+		{CategoryUUID: "rent-uuid", Category: "Rent", Total: d("100"), Percentage: 10},
+	}
+
+	got := rollUpCategories(totals)
+	want := []struct {
+		uuid, name, total string
+		pct               float64
+	}{
+		{food, "Food", "600", 60},
+		{transport, "Transport", "300", 30},
+		{"rent-uuid", "Rent", "100", 10},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("rollUpCategories() returned %d categories; want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.CategoryUUID != w.uuid || g.Category != w.name || !g.Total.Equal(d(w.total)) || g.Percentage != w.pct || g.ParentUUID != nil {
+			t.Errorf("rollUpCategories()[%d] = %s %s %s %v; want %s %s %s %v", i, g.CategoryUUID, g.Category, g.Total, g.Percentage, w.uuid, w.name, w.total, w.pct)
+		}
+	}
+}
+
+func Test_summarizeTags(t *testing.T) {
+	d := decimal.RequireFromString
+	category := func(uuid, name, total string) port.CategoryTotal {
+		return port.CategoryTotal{CategoryUUID: uuid, Category: name, Total: d(total)}
+	}
+	expenses := []port.TagCategoryTotal{
+		{TagUUID: "bali-uuid", Tag: "trip-bali", CategoryTotal: category("food-uuid", "Food", "250")},
+		{TagUUID: "bali-uuid", Tag: "trip-bali", CategoryTotal: category("transport-uuid", "Transport", "750")},
+		{TagUUID: "work-uuid", Tag: "work", CategoryTotal: category("food-uuid", "Food", "40")},
+	}
+	incomes := []port.TagIncomeTotal{
+		{TagUUID: "work-uuid", Tag: "work", Total: d("100")},
+		{TagUUID: "gift-uuid", Tag: "gift", Total: d("50")},
+	}
+
+	got := summarizeTags(expenses, incomes)
+	want := []struct {
+		tag, income, expense, net string
+		categories                []string
+		pcts                      []float64
+	}{
+		{"trip-bali", "0", "1000", "-1000", []string{"Transport", "Food"}, []float64{75, 25}},
+		{"work", "100", "40", "60", []string{"Food"}, []float64{100}},
+		{"gift", "50", "0", "50", nil, nil},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("summarizeTags() returned %d tags; want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.Tag != w.tag || !g.TotalIncome.Equal(d(w.income)) || !g.TotalExpense.Equal(d(w.expense)) || !g.Net.Equal(d(w.net)) {
+			t.Errorf("summarizeTags()[%d] = %s %s %s %s; want %s %s %s %s", i, g.Tag, g.TotalIncome, g.TotalExpense, g.Net, w.tag, w.income, w.expense, w.net)
+		}
+		if len(g.ExpenseByCategory) != len(w.categories) {
+			t.Fatalf("summarizeTags()[%d] has %d categories; want %d", i, len(g.ExpenseByCategory), len(w.categories))
+		}
+		for j, c := range g.ExpenseByCategory {
+			if c.Category != w.categories[j] || c.Percentage != w.pcts[j] {
+				t.Errorf("summarizeTags()[%d].ExpenseByCategory[%d] = %s %v; want %s %v", i, j, c.Category, c.Percentage, w.categories[j], w.pcts[j])
+			}
+		}
+	}
+}
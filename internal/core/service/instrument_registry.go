@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+)
+
+// instrumentReload is how long the in-memory registry is used before the table is read again, so rows
+// added to instruments are picked up without a restart.
+const instrumentReload = 10 * time.Minute
+
+// InstrumentRegistry serves port.InstrumentRegistry from the instruments table, held in memory.
+type InstrumentRegistry struct {
+	repo port.InstrumentRepository
+
+	mu       sync.RWMutex
+	bySymbol map[string][]models.Instrument
+	loadedAt time.Time
+}
+
+func NewInstrumentRegistry(repo port.InstrumentRepository) port.InstrumentRegistry {
+	return &InstrumentRegistry{repo: repo}
+}
+
+func (r *InstrumentRegistry) Lookup(ctx context.Context, ticker, currency string) *models.Instrument {
+	listings := r.listings(ctx, strings.ToUpper(strings.TrimSpace(ticker)))
+	return pickListing(listings, currency)
+}
+
+func (r *InstrumentRegistry) ProviderSymbol(ctx context.Context, ticker, currency string) string {
+	ticker = strings.ToUpper(strings.TrimSpace(ticker))
+	if inst := pickListing(r.listings(ctx, ticker), currency); inst != nil && inst.ProviderSymbol != "" {
+		return inst.ProviderSymbol
+	}
+	return ticker
+}
+
+func (r *InstrumentRegistry) LotSize(ctx context.Context, asset *models.Asset) int64 {
+	if asset.Type != models.AssetTypeStock || asset.Symbol == nil {
+		return 1
+	}
+	inst := r.Lookup(ctx, *asset.Symbol, assetCurrency(asset))
+	if inst == nil || inst.LotSize < 1 {
+		return 1
+	}
+	return inst.LotSize
+}
+
+// listings returns the instruments listed under ticker, reloading the table when the copy is old. When a
+// reload fails the previous copy keeps being used.
+func (r *InstrumentRegistry) listings(ctx context.Context, ticker string) []models.Instrument {
+	r.mu.RLock()
+	fresh := r.bySymbol != nil && time.Since(r.loadedAt) < instrumentReload
+	listings := r.bySymbol[ticker]
+	r.mu.RUnlock()
+	if fresh {
+		return listings
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.loadedAt) >= instrumentReload {
+		// Set first so a failing database is retried once per interval, not on every lookup.
+		r.loadedAt = time.Now()
+		instruments, err := r.repo.ListAll(ctx)
+		if err != nil {
+			slog.Warn("instrument_registry_load_failed", "error", err)
+		} else {
+			bySymbol := make(map[string][]models.Instrument, len(instruments))
+			for _, inst := range instruments {
+				sym := strings.ToUpper(inst.Symbol)
+				bySymbol[sym] = append(bySymbol[sym], inst)
+			}
+			r.bySymbol = bySymbol
+		}
+	}
+	return r.bySymbol[ticker]
+}
+
+// pickListing chooses among the listings of one ticker: the one traded in currency when there is one,
+// otherwise the first (listings are ordered by exchange).
+func pickListing(listings []models.Instrument, currency string) *models.Instrument {
+	if len(listings) == 0 {
+		return nil
+	}
+	for i := range listings {
+		if currency != "" && strings.EqualFold(listings[i].Currency, currency) {
+			return &listings[i]
+		}
+	}
+	return &listings[0]
+}
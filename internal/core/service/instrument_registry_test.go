@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"monity/internal/models"
+)
+
+func Test_pickListing(t *testing.T) {
+	listings := []models.Instrument{
+		{Symbol: "ABCD", Exchange: "IDX", Currency: "IDR", ProviderSymbol: "ABCD.JK"},
+		{Symbol: "ABCD", Exchange: "NYSE", Currency: "USD", ProviderSymbol: "ABCD"},
+	}
+	tests := []struct {
+		name     string
+		currency string
+		want     string
+	}{
+		{"matching currency", "USD", "NYSE"},
+		{"currency case-insensitive", "idr", "IDX"},
+		{"no match falls back to first", "EUR", "IDX"},
+		{"no currency", "", "IDX"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pickListing(listings, tt.currency)
+			if got == nil || got.Exchange != tt.want {
+				t.Errorf("pickListing(%q) = %v, want exchange %s", tt.currency, got, tt.want)
+			}
+		})
+	}
+	if got := pickListing(nil, "IDR"); got != nil {
+		t.Errorf("pickListing(nil) = %v, want nil", got)
+	}
+}
+
+type memInstrumentRepo []models.Instrument
+
+func (r memInstrumentRepo) ListAll(ctx context.Context) ([]models.Instrument, error) { return r, nil }
+
+func TestInstrumentRegistry_ProviderSymbol(t *testing.T) {
+	registry := NewInstrumentRegistry(memInstrumentRepo{
+		{Symbol: "ABCD", Exchange: "IDX", Currency: "IDR", ProviderSymbol: "ABCD.JK"},
+		{Symbol: "ABCD", Exchange: "NYSE", Currency: "USD", ProviderSymbol: "ABCD"},
+	})
+	tests := []struct {
+		ticker, currency, want string
+	}{
+		{"abcd", "USD", "ABCD"},
+		{"ABCD", "IDR", "ABCD.JK"},
+		{"ABCD", "", "ABCD.JK"},
+		{"wxyz", "IDR", "WXYZ"},
+	}
+	for _, tt := range tests {
+		if got := registry.ProviderSymbol(context.Background(), tt.ticker, tt.currency); got != tt.want {
+			t.Errorf("ProviderSymbol(%q, %q) = %q, want %q", tt.ticker, tt.currency, got, tt.want)
+		}
+	}
+}
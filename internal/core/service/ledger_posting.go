@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// ledgerPosting is the net effect a single record (expense, income, payment, ...) should have on the ledger.
+type ledgerPosting struct {
+	UserID        int64
+	ReferenceType models.LedgerReferenceType
+	ReferenceUUID string
+	Description   string
+	OccurredAt    time.Time
+	Lines         []ledgerLine
+}
+
+type ledgerLine struct {
+	AssetID int64 // 0 for non-asset accounts
+	Account string
+	Amount  decimal.Decimal
+}
+
+func assetLine(assetID int64, amount decimal.Decimal) ledgerLine {
+	return ledgerLine{AssetID: assetID, Account: models.LedgerAccountAsset, Amount: amount}
+}
+
+func accountLine(account string, amount decimal.Decimal) ledgerLine {
+	return ledgerLine{Account: account, Amount: amount}
+}
+
+// expenseAccount keeps default categories on their code (EXPENSE:FOOD) and gives the user's own categories
+// an account named after their UUID, so renaming a category does not move its ledger history.
+func expenseAccount(category *models.Category) string {
+	if category.Code != nil {
+		return models.LedgerAccountExpense + ":" + *category.Code
+	}
+	return models.LedgerAccountExpense + ":" + category.UUID
+}
+
+type ledgerKey struct {
+	account string
+	assetID int64
+}
+
+// postLedger brings the ledger for p's reference to exactly p.Lines: it posts one balanced transaction holding
+// the difference from what was recorded before (so creating, editing and deleting a record all go through here;
+// deleting is a posting with no lines). Afterwards every touched CASH asset gets its Quantity re-derived from
+// its ledger balance. locked holds the assets already row-locked by the caller; others are locked here.
+func postLedger(ctx context.Context, repos port.Repositories, p ledgerPosting, locked map[int64]*models.Asset) error {
+	want := make(map[ledgerKey]decimal.Decimal)
+	sum := decimal.Zero
+	for _, l := range p.Lines {
+		k := ledgerKey{account: l.Account, assetID: l.AssetID}
+		want[k] = want[k].Add(l.Amount)
+		sum = sum.Add(l.Amount)
+	}
+	if !sum.IsZero() {
+		return errors.New("unbalanced ledger posting")
+	}
+
+	posted, err := repos.Ledger.NetByReference(ctx, p.ReferenceType, p.ReferenceUUID)
+	if err != nil {
+		return err
+	}
+	diff := make(map[ledgerKey]decimal.Decimal, len(want))
+	for k, v := range want {
+		diff[k] = v
+	}
+	for _, e := range posted {
+		k := ledgerKey{account: e.Account}
+		if e.AssetID != nil {
+			k.assetID = *e.AssetID
+		}
+		diff[k] = diff[k].Sub(e.Amount)
+	}
+
+	keys := make([]ledgerKey, 0, len(diff))
+	for k, v := range diff {
+		if !v.IsZero() {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].assetID != keys[j].assetID {
+			return keys[i].assetID < keys[j].assetID
+		}
+		return keys[i].account < keys[j].account
+	})
+
+	txn := &models.LedgerTransaction{
+		UserID:        p.UserID,
+		ReferenceType: p.ReferenceType,
+		ReferenceUUID: p.ReferenceUUID,
+		Description:   p.Description,
+		OccurredAt:    p.OccurredAt,
+	}
+	var assetIDs []int64
+	for _, k := range keys {
+		entry := models.LedgerEntry{Account: k.account, Amount: diff[k]}
+		if k.assetID != 0 {
+			id := k.assetID
+			entry.AssetID = &id
+			assetIDs = append(assetIDs, id)
+		}
+		txn.Entries = append(txn.Entries, entry)
+	}
+	if err := repos.Ledger.CreateTransaction(ctx, txn); err != nil {
+		return err
+	}
+
+	for _, id := range assetIDs {
+		asset := locked[id]
+		if asset == nil {
+			if asset, err = repos.Assets.GetByIDForUpdate(ctx, id); err != nil {
+				return fmt.Errorf("get asset: %w", err)
+			}
+			if asset == nil {
+				return errors.New("asset not found")
+			}
+		}
+		if err := syncAssetQuantity(ctx, repos, asset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// postLedgerWithinBalance is postLedger for postings that can take cash back out of an asset, such as undoing
+// or reducing money that was received: it fails with msg when a CASH asset it lowers ends up below zero.
+// Every asset the posting touches must be in locked.
+func postLedgerWithinBalance(ctx context.Context, repos port.Repositories, p ledgerPosting, locked map[int64]*models.Asset, msg string) error {
+	before := make(map[int64]decimal.Decimal, len(locked))
+	for id, asset := range locked {
+		before[id] = asset.Quantity
+	}
+	if err := postLedger(ctx, repos, p, locked); err != nil {
+		return err
+	}
+	for id, asset := range locked {
+		if asset.Quantity.IsNegative() && asset.Quantity.LessThan(before[id]) {
+			return errors.New(msg)
+		}
+	}
+	return nil
+}
+
+// syncAssetQuantity sets asset.Quantity to its ledger balance and saves it.
+func syncAssetQuantity(ctx context.Context, repos port.Repositories, asset *models.Asset) error {
+	balance, err := repos.Ledger.BalanceByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	if balance.Equal(asset.Quantity) {
+		return nil
+	}
+	oldQty := asset.Quantity
+	asset.Quantity = balance
+	if err := repos.Assets.Update(ctx, asset); err != nil {
+		return fmt.Errorf("update asset balance: %w", err)
+	}
+	slog.Info("balance_updated", "asset_uuid", asset.UUID, "old", oldQty.String(), "new", asset.Quantity.String())
+	return nil
+}
+
+// adjustAssetLedger posts delta to a CASH asset against an equity account (opening balances, manual corrections).
+// Unlike postLedger these postings accumulate: each call records a new transaction referencing the asset.
+func adjustAssetLedger(ctx context.Context, repos port.Repositories, asset *models.Asset, refType models.LedgerReferenceType, account string, delta decimal.Decimal, description string) error {
+	if delta.IsZero() {
+		return nil
+	}
+	assetID := asset.ID
+	txn := &models.LedgerTransaction{
+		UserID:        asset.UserID,
+		ReferenceType: refType,
+		ReferenceUUID: asset.UUID,
+		Description:   description,
+		OccurredAt:    time.Now(),
+		Entries: []models.LedgerEntry{
+			{AssetID: &assetID, Account: models.LedgerAccountAsset, Amount: delta},
+			{Account: account, Amount: delta.Neg()},
+		},
+	}
+	if err := repos.Ledger.CreateTransaction(ctx, txn); err != nil {
+		return err
+	}
+	return syncAssetQuantity(ctx, repos, asset)
+}
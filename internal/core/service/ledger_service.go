@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"monity/internal/core/port"
+)
+
+type LedgerService struct {
+	repo      port.LedgerRepository
+	assetRepo port.AssetRepository
+}
+
+func NewLedgerService(repo port.LedgerRepository, assetRepo port.AssetRepository) port.LedgerService {
+	return &LedgerService{repo: repo, assetRepo: assetRepo}
+}
+
+func (s *LedgerService) GetAssetLedger(ctx context.Context, userID int64, assetUUID string, page, limit int) (*port.AssetLedgerResponse, error) {
+	asset, err := s.assetRepo.GetByUUID(ctx, assetUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get asset: %w", err)
+	}
+	if asset == nil {
+		return nil, errors.New("asset not found")
+	}
+	if _, err := checkCashAsset(asset); err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	items, total, err := s.repo.ListByAssetID(ctx, asset.ID, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list ledger: %w", err)
+	}
+	if items == nil {
+		items = []port.AssetLedgerEntry{}
+	}
+	balance, err := s.repo.BalanceByAssetID(ctx, asset.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get ledger balance: %w", err)
+	}
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	if totalPages < 0 {
+		totalPages = 0
+	}
+	return &port.AssetLedgerResponse{
+		AssetUUID:     asset.UUID,
+		Quantity:      asset.Quantity,
+		LedgerBalance: balance,
+		InSync:        balance.Equal(asset.Quantity),
+		Items:         items,
+		Meta:          port.ListMeta{Total: total, Page: page, Limit: limit, TotalPages: totalPages},
+	}, nil
+}
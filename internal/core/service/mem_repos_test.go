@@ -0,0 +1,705 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// In-memory repositories for service tests that go through a unit of work. Each embeds its port interface
+// and implements only what the services under test call; anything else panics on the nil interface.
+
+// memStore holds every in-memory repository and hands them out as one port.Repositories.
+type memStore struct {
+	assets       *memAssetRepo
+	assetTxs     *memAssetTransactionRepo
+	ledger       *memLedgerRepo
+	transfers    *memTransferRepo
+	expenses     *memExpenseRepo
+	cats         *memCategoryRepo
+	debts        *memDebtRepo
+	debtPayments *memDebtPaymentRepo
+	recs         *memReceivableRepo
+	recPayments  *memReceivablePaymentRepo
+	rules        *memRecurringRuleRepo
+	occurrences  *memRecurringOccurrenceRepo
+	nextID       int64
+}
+
+func newMemStore() *memStore {
+	s := &memStore{}
+	s.assets = &memAssetRepo{store: s, rows: map[int64]*models.Asset{}}
+	s.assetTxs = &memAssetTransactionRepo{store: s, rows: map[string]*models.AssetTransaction{}}
+	s.ledger = &memLedgerRepo{store: s}
+	s.transfers = &memTransferRepo{store: s, rows: map[string]*models.Transfer{}}
+	s.expenses = &memExpenseRepo{store: s, rows: map[int64]*models.Expense{}}
+	s.cats = &memCategoryRepo{store: s}
+	s.debts = &memDebtRepo{store: s, rows: map[string]*models.Debt{}}
+	s.debtPayments = &memDebtPaymentRepo{store: s, rows: map[string]*models.DebtPayment{}}
+	s.recs = &memReceivableRepo{store: s, rows: map[string]*models.Receivable{}}
+	s.recPayments = &memReceivablePaymentRepo{store: s, rows: map[string]*models.ReceivablePayment{}}
+	s.rules = &memRecurringRuleRepo{store: s, rows: map[int64]*models.RecurringRule{}}
+	s.occurrences = &memRecurringOccurrenceRepo{store: s, rows: map[int64]*models.RecurringOccurrence{}}
+	return s
+}
+
+// id returns the next ID and a UUID derived from it, standing in for the database defaults.
+func (s *memStore) id() (int64, string) {
+	s.nextID++
+	return s.nextID, fmt.Sprintf("00000000-0000-0000-0000-%012d", s.nextID)
+}
+
+func (s *memStore) repos() port.Repositories {
+	return port.Repositories{
+		Assets:               s.assets,
+		AssetTransactions:    s.assetTxs,
+		Expenses:             s.expenses,
+		Debts:                s.debts,
+		DebtPayments:         s.debtPayments,
+		Receivables:          s.recs,
+		ReceivablePayments:   s.recPayments,
+		Ledger:               s.ledger,
+		Transfers:            s.transfers,
+		Categories:           s.cats,
+		RecurringRules:       s.rules,
+		RecurringOccurrences: s.occurrences,
+	}
+}
+
+// Do rolls every repository back when fn fails, like the database transaction would. Repositories only ever
+// store copies, so cloning their maps is enough to snapshot them.
+func (s *memStore) Do(ctx context.Context, fn func(ctx context.Context, repos port.Repositories) error) error {
+	assets, txns, transfers, expenses := maps.Clone(s.assets.rows), slices.Clone(s.ledger.txns), maps.Clone(s.transfers.rows), maps.Clone(s.expenses.rows)
+	cats, debts, debtPayments := slices.Clone(s.cats.rows), maps.Clone(s.debts.rows), maps.Clone(s.debtPayments.rows)
+	recs, recPayments, assetTxs := maps.Clone(s.recs.rows), maps.Clone(s.recPayments.rows), maps.Clone(s.assetTxs.rows)
+	rules, occurrences := maps.Clone(s.rules.rows), maps.Clone(s.occurrences.rows)
+	if err := fn(ctx, s.repos()); err != nil {
+		s.assets.rows, s.ledger.txns, s.transfers.rows, s.expenses.rows = assets, txns, transfers, expenses
+		s.cats.rows, s.debts.rows, s.debtPayments.rows = cats, debts, debtPayments
+		s.recs.rows, s.recPayments.rows, s.assetTxs.rows = recs, recPayments, assetTxs
+		s.rules.rows, s.occurrences.rows = rules, occurrences
+		return err
+	}
+	return nil
+}
+
+// cash adds a CASH asset holding qty through an opening balance posting, as AssetService does.
+func (s *memStore) cash(userID int64, currency string, qty float64) *models.Asset {
+	id, uuid := s.id()
+	asset := &models.Asset{ID: id, UUID: uuid, UserID: userID, Name: "cash", Type: models.AssetTypeCash, PurchaseCurrency: currency}
+	s.assets.rows[id] = asset
+	err := adjustAssetLedger(context.Background(), s.repos(), asset, models.LedgerRefOpeningBalance,
+		models.LedgerAccountOpeningBalance, decimal.NewFromFloat(qty), "opening balance")
+	if err != nil {
+		panic(err)
+	}
+	return s.assets.get(id)
+}
+
+// lotAsset adds an empty non-cash asset whose position comes from its transactions.
+func (s *memStore) lotAsset(userID int64, typ models.AssetType, currency string) *models.Asset {
+	id, uuid := s.id()
+	s.assets.rows[id] = &models.Asset{ID: id, UUID: uuid, UserID: userID, Name: "lot", Type: typ, PurchaseCurrency: currency, CostMethod: models.CostMethodFIFO}
+	return s.assets.get(id)
+}
+
+// adjust moves an asset's balance by delta outside any service, standing in for spending or topping it up.
+func (s *memStore) adjust(id int64, delta float64) {
+	err := adjustAssetLedger(context.Background(), s.repos(), s.assets.get(id), models.LedgerRefAdjustment,
+		models.LedgerAccountAdjustment, decimal.NewFromFloat(delta), "manual adjustment")
+	if err != nil {
+		panic(err)
+	}
+}
+
+// quantity is the stored quantity of an asset.
+func (s *memStore) quantity(id int64) decimal.Decimal {
+	return s.assets.rows[id].Quantity
+}
+
+type memAssetRepo struct {
+	port.AssetRepository
+	store *memStore
+	rows  map[int64]*models.Asset
+}
+
+func (r *memAssetRepo) get(id int64) *models.Asset {
+	a, ok := r.rows[id]
+	if !ok {
+		return nil
+	}
+	cp := *a
+	return &cp
+}
+
+func (r *memAssetRepo) GetByID(ctx context.Context, id int64) (*models.Asset, error) {
+	return r.get(id), nil
+}
+
+func (r *memAssetRepo) GetByIDForUpdate(ctx context.Context, id int64) (*models.Asset, error) {
+	return r.get(id), nil
+}
+
+func (r *memAssetRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Asset, error) {
+	for id, a := range r.rows {
+		if a.UUID == uuid && a.UserID == userID {
+			return r.get(id), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memAssetRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Asset, error) {
+	return r.GetByUUID(ctx, uuid, userID)
+}
+
+func (r *memAssetRepo) Update(ctx context.Context, asset *models.Asset) error {
+	cp := *asset
+	r.rows[asset.ID] = &cp
+	return nil
+}
+
+type memLedgerRepo struct {
+	port.LedgerRepository
+	store *memStore
+	txns  []models.LedgerTransaction
+}
+
+func (r *memLedgerRepo) CreateTransaction(ctx context.Context, txn *models.LedgerTransaction) error {
+	sum := decimal.Zero
+	for _, e := range txn.Entries {
+		sum = sum.Add(e.Amount)
+	}
+	if !sum.IsZero() {
+		return fmt.Errorf("unbalanced transaction %s %s: %s", txn.ReferenceType, txn.ReferenceUUID, sum)
+	}
+	txn.ID, txn.UUID = r.store.id()
+	r.txns = append(r.txns, *txn)
+	return nil
+}
+
+func (r *memLedgerRepo) NetByReference(ctx context.Context, refType models.LedgerReferenceType, refUUID string) ([]models.LedgerEntry, error) {
+	net := map[ledgerKey]decimal.Decimal{}
+	for _, txn := range r.txns {
+		if txn.ReferenceType != refType || txn.ReferenceUUID != refUUID {
+			continue
+		}
+		for _, e := range txn.Entries {
+			k := ledgerKey{account: e.Account}
+			if e.AssetID != nil {
+				k.assetID = *e.AssetID
+			}
+			net[k] = net[k].Add(e.Amount)
+		}
+	}
+	var out []models.LedgerEntry
+	for k, v := range net {
+		if v.IsZero() {
+			continue
+		}
+		e := models.LedgerEntry{Account: k.account, Amount: v}
+		if k.assetID != 0 {
+			id := k.assetID
+			e.AssetID = &id
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (r *memLedgerRepo) BalanceByAssetID(ctx context.Context, assetID int64) (decimal.Decimal, error) {
+	return r.balance(models.LedgerAccountAsset, assetID), nil
+}
+
+// balance sums every entry on account (and assetID, when non-zero).
+func (r *memLedgerRepo) balance(account string, assetID int64) decimal.Decimal {
+	sum := decimal.Zero
+	for _, txn := range r.txns {
+		for _, e := range txn.Entries {
+			if e.Account != account || (assetID != 0 && (e.AssetID == nil || *e.AssetID != assetID)) {
+				continue
+			}
+			sum = sum.Add(e.Amount)
+		}
+	}
+	return sum
+}
+
+type memTransferRepo struct {
+	port.TransferRepository
+	store *memStore
+	rows  map[string]*models.Transfer
+}
+
+func (r *memTransferRepo) Create(ctx context.Context, t *models.Transfer) error {
+	t.ID, t.UUID = r.store.id()
+	cp := *t
+	r.rows[t.UUID] = &cp
+	return nil
+}
+
+func (r *memTransferRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Transfer, error) {
+	t, ok := r.rows[uuid]
+	if !ok || t.UserID != userID {
+		return nil, nil
+	}
+	cp := *t
+	cp.FromAsset = r.store.assets.get(t.FromAssetID)
+	cp.ToAsset = r.store.assets.get(t.ToAssetID)
+	return &cp, nil
+}
+
+func (r *memTransferRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Transfer, error) {
+	return r.GetByUUID(ctx, uuid, userID)
+}
+
+func (r *memTransferRepo) Update(ctx context.Context, t *models.Transfer) error {
+	cp := *t
+	r.rows[t.UUID] = &cp
+	return nil
+}
+
+func (r *memTransferRepo) Delete(ctx context.Context, uuid string, userID int64) error {
+	delete(r.rows, uuid)
+	return nil
+}
+
+type memExpenseRepo struct {
+	port.ExpenseRepository
+	store *memStore
+	rows  map[int64]*models.Expense
+}
+
+func (r *memExpenseRepo) Create(ctx context.Context, e *models.Expense) error {
+	e.ID, e.UUID = r.store.id()
+	cp := *e
+	r.rows[e.ID] = &cp
+	return nil
+}
+
+func (r *memExpenseRepo) GetByIDForUpdate(ctx context.Context, id int64) (*models.Expense, error) {
+	e, ok := r.rows[id]
+	if !ok {
+		return nil, nil
+	}
+	cp := *e
+	return &cp, nil
+}
+
+func (r *memExpenseRepo) Update(ctx context.Context, e *models.Expense) error {
+	cp := *e
+	r.rows[e.ID] = &cp
+	return nil
+}
+
+func (r *memExpenseRepo) Delete(ctx context.Context, uuid string, userID int64) error {
+	for id, e := range r.rows {
+		if e.UUID == uuid && e.UserID == userID {
+			delete(r.rows, id)
+		}
+	}
+	return nil
+}
+
+type memCategoryRepo struct {
+	port.CategoryRepository
+	store *memStore
+	rows  []models.Category
+}
+
+func (r *memCategoryRepo) CreateDefaults(ctx context.Context, userID int64, defaults []models.Category) error {
+	for _, d := range defaults {
+		d.ID, d.UUID = r.store.id()
+		d.UserID = userID
+		r.rows = append(r.rows, d)
+	}
+	return nil
+}
+
+func (r *memCategoryRepo) GetByID(ctx context.Context, id int64) (*models.Category, error) {
+	for i, c := range r.rows {
+		if c.ID == id {
+			cp := r.rows[i]
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memCategoryRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Category, error) {
+	for i, c := range r.rows {
+		if c.UUID == uuid && c.UserID == userID {
+			cp := r.rows[i]
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memCategoryRepo) GetByCode(ctx context.Context, userID int64, code string) (*models.Category, error) {
+	for i, c := range r.rows {
+		if c.UserID == userID && c.Code != nil && *c.Code == code {
+			cp := r.rows[i]
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+type memDebtRepo struct {
+	port.DebtRepository
+	store *memStore
+	rows  map[string]*models.Debt
+}
+
+func (r *memDebtRepo) Create(ctx context.Context, d *models.Debt) error {
+	d.ID, d.UUID = r.store.id()
+	cp := *d
+	r.rows[d.UUID] = &cp
+	return nil
+}
+
+func (r *memDebtRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Debt, error) {
+	d, ok := r.rows[uuid]
+	if !ok || d.UserID != userID {
+		return nil, nil
+	}
+	cp := *d
+	return &cp, nil
+}
+
+func (r *memDebtRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Debt, error) {
+	return r.GetByUUID(ctx, uuid, userID)
+}
+
+func (r *memDebtRepo) Update(ctx context.Context, d *models.Debt) error {
+	cp := *d
+	r.rows[d.UUID] = &cp
+	return nil
+}
+
+func (r *memDebtRepo) Delete(ctx context.Context, uuid string, userID int64) error {
+	d, ok := r.rows[uuid]
+	if !ok || d.UserID != userID {
+		return fmt.Errorf("debt not found or not owned by user")
+	}
+	delete(r.rows, uuid)
+	for key, p := range r.store.debtPayments.rows {
+		if p.DebtID == d.ID {
+			delete(r.store.debtPayments.rows, key)
+		}
+	}
+	return nil
+}
+
+type memDebtPaymentRepo struct {
+	port.DebtPaymentRepository
+	store *memStore
+	rows  map[string]*models.DebtPayment
+}
+
+func (r *memDebtPaymentRepo) Create(ctx context.Context, p *models.DebtPayment) error {
+	p.ID, p.UUID = r.store.id()
+	cp := *p
+	r.rows[p.UUID] = &cp
+	return nil
+}
+
+func (r *memDebtPaymentRepo) ListByDebtID(ctx context.Context, debtID int64) ([]models.DebtPayment, error) {
+	var out []models.DebtPayment
+	for _, p := range r.rows {
+		if p.DebtID == debtID {
+			out = append(out, *p)
+		}
+	}
+	slices.SortFunc(out, func(a, b models.DebtPayment) int { return int(a.ID - b.ID) })
+	return out, nil
+}
+
+func (r *memDebtPaymentRepo) GetByUUIDForUpdate(ctx context.Context, debtID int64, uuid string) (*models.DebtPayment, error) {
+	p, ok := r.rows[uuid]
+	if !ok || p.DebtID != debtID {
+		return nil, nil
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (r *memDebtPaymentRepo) Update(ctx context.Context, p *models.DebtPayment) error {
+	cp := *p
+	r.rows[p.UUID] = &cp
+	return nil
+}
+
+type memReceivableRepo struct {
+	port.ReceivableRepository
+	store *memStore
+	rows  map[string]*models.Receivable
+}
+
+func (r *memReceivableRepo) Create(ctx context.Context, rec *models.Receivable) error {
+	rec.ID, rec.UUID = r.store.id()
+	cp := *rec
+	r.rows[rec.UUID] = &cp
+	return nil
+}
+
+func (r *memReceivableRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.Receivable, error) {
+	rec, ok := r.rows[uuid]
+	if !ok || rec.UserID != userID {
+		return nil, nil
+	}
+	cp := *rec
+	return &cp, nil
+}
+
+func (r *memReceivableRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.Receivable, error) {
+	return r.GetByUUID(ctx, uuid, userID)
+}
+
+func (r *memReceivableRepo) Update(ctx context.Context, rec *models.Receivable) error {
+	cp := *rec
+	r.rows[rec.UUID] = &cp
+	return nil
+}
+
+func (r *memReceivableRepo) Delete(ctx context.Context, uuid string, userID int64) error {
+	rec, ok := r.rows[uuid]
+	if !ok || rec.UserID != userID {
+		return fmt.Errorf("receivable not found or not owned by user")
+	}
+	delete(r.rows, uuid)
+	for key, p := range r.store.recPayments.rows {
+		if p.ReceivableID == rec.ID {
+			delete(r.store.recPayments.rows, key)
+		}
+	}
+	return nil
+}
+
+type memReceivablePaymentRepo struct {
+	port.ReceivablePaymentRepository
+	store *memStore
+	rows  map[string]*models.ReceivablePayment
+}
+
+func (r *memReceivablePaymentRepo) Create(ctx context.Context, p *models.ReceivablePayment) error {
+	p.ID, p.UUID = r.store.id()
+	cp := *p
+	r.rows[p.UUID] = &cp
+	return nil
+}
+
+func (r *memReceivablePaymentRepo) ListByReceivableID(ctx context.Context, receivableID int64) ([]models.ReceivablePayment, error) {
+	var out []models.ReceivablePayment
+	for _, p := range r.rows {
+		if p.ReceivableID == receivableID {
+			out = append(out, *p)
+		}
+	}
+	slices.SortFunc(out, func(a, b models.ReceivablePayment) int { return int(a.ID - b.ID) })
+	return out, nil
+}
+
+func (r *memReceivablePaymentRepo) GetByUUIDForUpdate(ctx context.Context, receivableID int64, uuid string) (*models.ReceivablePayment, error) {
+	p, ok := r.rows[uuid]
+	if !ok || p.ReceivableID != receivableID {
+		return nil, nil
+	}
+	cp := *p
+	return &cp, nil
+}
+
+func (r *memReceivablePaymentRepo) Update(ctx context.Context, p *models.ReceivablePayment) error {
+	cp := *p
+	r.rows[p.UUID] = &cp
+	return nil
+}
+
+type memAssetTransactionRepo struct {
+	port.AssetTransactionRepository
+	store *memStore
+	rows  map[string]*models.AssetTransaction
+}
+
+func (r *memAssetTransactionRepo) Create(ctx context.Context, tx *models.AssetTransaction) error {
+	tx.ID, tx.UUID = r.store.id()
+	cp := *tx
+	r.rows[tx.UUID] = &cp
+	return nil
+}
+
+func (r *memAssetTransactionRepo) GetByUUID(ctx context.Context, assetID int64, uuid string) (*models.AssetTransaction, error) {
+	tx, ok := r.rows[uuid]
+	if !ok || tx.AssetID != assetID {
+		return nil, nil
+	}
+	cp := *tx
+	return &cp, nil
+}
+
+func (r *memAssetTransactionRepo) ListByAssetID(ctx context.Context, assetID int64) ([]models.AssetTransaction, error) {
+	var out []models.AssetTransaction
+	for _, tx := range r.rows {
+		if tx.AssetID == assetID {
+			out = append(out, *tx)
+		}
+	}
+	slices.SortFunc(out, func(a, b models.AssetTransaction) int {
+		if c := a.TransactionDate.Compare(b.TransactionDate); c != 0 {
+			return c
+		}
+		return int(a.ID - b.ID)
+	})
+	return out, nil
+}
+
+func (r *memAssetTransactionRepo) Update(ctx context.Context, tx *models.AssetTransaction) error {
+	cp := *tx
+	r.rows[tx.UUID] = &cp
+	return nil
+}
+
+func (r *memAssetTransactionRepo) Delete(ctx context.Context, assetID int64, uuid string) error {
+	delete(r.rows, uuid)
+	return nil
+}
+
+type memRecurringRuleRepo struct {
+	port.RecurringRuleRepository
+	store *memStore
+	rows  map[int64]*models.RecurringRule
+}
+
+// get returns a copy of the rule with its asset and category loaded, as the preloading queries do.
+func (r *memRecurringRuleRepo) get(id int64) *models.RecurringRule {
+	rule, ok := r.rows[id]
+	if !ok {
+		return nil
+	}
+	cp := *rule
+	cp.Asset = r.store.assets.get(rule.AssetID)
+	if rule.CategoryID != nil {
+		cp.Category, _ = r.store.cats.GetByID(context.Background(), *rule.CategoryID)
+	}
+	return &cp
+}
+
+func (r *memRecurringRuleRepo) Create(ctx context.Context, rule *models.RecurringRule) error {
+	rule.ID, rule.UUID = r.store.id()
+	return r.Update(ctx, rule)
+}
+
+func (r *memRecurringRuleRepo) GetByUUID(ctx context.Context, uuid string, userID int64) (*models.RecurringRule, error) {
+	for id, rule := range r.rows {
+		if rule.UUID == uuid && rule.UserID == userID {
+			return r.get(id), nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRecurringRuleRepo) GetByUUIDForUpdate(ctx context.Context, uuid string, userID int64) (*models.RecurringRule, error) {
+	return r.GetByUUID(ctx, uuid, userID)
+}
+
+func (r *memRecurringRuleRepo) GetByIDForUpdate(ctx context.Context, id int64) (*models.RecurringRule, error) {
+	return r.get(id), nil
+}
+
+func (r *memRecurringRuleRepo) ListAllByUserID(ctx context.Context, userID int64) ([]models.RecurringRule, error) {
+	var out []models.RecurringRule
+	for id, rule := range r.rows {
+		if rule.UserID == userID {
+			out = append(out, *r.get(id))
+		}
+	}
+	slices.SortFunc(out, func(a, b models.RecurringRule) int { return int(a.ID - b.ID) })
+	return out, nil
+}
+
+func (r *memRecurringRuleRepo) ListDueIDs(ctx context.Context, now time.Time, limit int) ([]int64, error) {
+	var due []*models.RecurringRule
+	for _, rule := range r.rows {
+		if rule.NextDue != nil && !rule.NextDue.After(now) {
+			due = append(due, rule)
+		}
+	}
+	slices.SortFunc(due, func(a, b *models.RecurringRule) int {
+		if c := a.NextDue.Compare(*b.NextDue); c != 0 {
+			return c
+		}
+		return int(a.ID - b.ID)
+	})
+	var ids []int64
+	for _, rule := range due[:min(limit, len(due))] {
+		ids = append(ids, rule.ID)
+	}
+	return ids, nil
+}
+
+func (r *memRecurringRuleRepo) Update(ctx context.Context, rule *models.RecurringRule) error {
+	cp := *rule
+	cp.Asset, cp.Category = nil, nil
+	r.rows[rule.ID] = &cp
+	return nil
+}
+
+type memRecurringOccurrenceRepo struct {
+	port.RecurringOccurrenceRepository
+	store *memStore
+	rows  map[int64]*models.RecurringOccurrence
+}
+
+func (r *memRecurringOccurrenceRepo) Get(ctx context.Context, ruleID int64, index int) (*models.RecurringOccurrence, error) {
+	for _, occ := range r.rows {
+		if occ.RuleID == ruleID && occ.Index == index {
+			cp := *occ
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memRecurringOccurrenceRepo) ListByRuleID(ctx context.Context, ruleID int64) ([]models.RecurringOccurrence, error) {
+	var out []models.RecurringOccurrence
+	for _, occ := range r.rows {
+		if occ.RuleID == ruleID {
+			out = append(out, *occ)
+		}
+	}
+	slices.SortFunc(out, func(a, b models.RecurringOccurrence) int { return a.Index - b.Index })
+	return out, nil
+}
+
+func (r *memRecurringOccurrenceRepo) ListByUserID(ctx context.Context, userID int64, statuses []models.OccurrenceStatus) ([]models.RecurringOccurrence, error) {
+	var out []models.RecurringOccurrence
+	for _, occ := range r.rows {
+		if occ.UserID == userID && slices.Contains(statuses, occ.Status) {
+			out = append(out, *occ)
+		}
+	}
+	slices.SortFunc(out, func(a, b models.RecurringOccurrence) int {
+		if c := a.DueDate.Compare(b.DueDate); c != 0 {
+			return c
+		}
+		return int(a.ID - b.ID)
+	})
+	return out, nil
+}
+
+func (r *memRecurringOccurrenceRepo) Save(ctx context.Context, occ *models.RecurringOccurrence) error {
+	if occ.ID == 0 {
+		occ.ID, occ.UUID = r.store.id()
+	}
+	cp := *occ
+	r.rows[occ.ID] = &cp
+	return nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+// obligationStatus derives the status of a debt or receivable from how much of it has been paid
+// and whether its due date has passed. An unpaid balance past dueDate is OVERDUE until it is settled
+// or the due date is moved.
+func obligationStatus(amount, paid decimal.Decimal, dueDate *time.Time, now time.Time) models.ObligationStatus {
+	switch {
+	case paid.GreaterThanOrEqual(amount):
+		return models.ObligationStatusPaid
+	case dueDate != nil && dueDate.Before(now):
+		return models.ObligationStatusOverdue
+	case paid.IsPositive():
+		return models.ObligationStatusPartial
+	default:
+		return models.ObligationStatusPending
+	}
+}
+
+// setObligationStatus updates *status and stamps *changedAt only when the status actually changes,
+// so StatusChangedAt always marks the last transition.
+func setObligationStatus(status *models.ObligationStatus, changedAt **time.Time, next models.ObligationStatus, now time.Time) {
+	if *status == next {
+		return
+	}
+	*status = next
+	*changedAt = &now
+}
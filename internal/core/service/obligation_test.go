@@ -0,0 +1,40 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_obligationStatus(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	past := now.AddDate(0, 0, -1)
+	future := now.AddDate(0, 0, 1)
+	tests := []struct {
+		name    string
+		amount  string
+		paid    string
+		dueDate *time.Time
+		want    models.ObligationStatus
+	}{
+		{"nothing paid", "100", "0", nil, models.ObligationStatusPending},
+		{"partly paid", "100", "40", nil, models.ObligationStatusPartial},
+		{"fully paid", "100", "100", nil, models.ObligationStatusPaid},
+		{"back to pending after void", "100", "0.00", nil, models.ObligationStatusPending},
+		{"not yet due", "100", "40", &future, models.ObligationStatusPartial},
+		{"past due unpaid", "100", "0", &past, models.ObligationStatusOverdue},
+		{"past due partly paid", "100", "40", &past, models.ObligationStatusOverdue},
+		{"past due settled", "100", "100", &past, models.ObligationStatusPaid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := obligationStatus(decimal.RequireFromString(tt.amount), decimal.RequireFromString(tt.paid), tt.dueDate, now)
+			if got != tt.want {
+				t.Errorf("obligationStatus(%s, %s) = %s, want %s", tt.amount, tt.paid, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"monity/internal/core/port"
+)
+
+type OverdueService struct {
+	debtRepo       port.DebtRepository
+	receivableRepo port.ReceivableRepository
+}
+
+func NewOverdueService(debtRepo port.DebtRepository, receivableRepo port.ReceivableRepository) port.OverdueService {
+	return &OverdueService{debtRepo: debtRepo, receivableRepo: receivableRepo}
+}
+
+func (s *OverdueService) SweepOverdue(ctx context.Context) (port.OverdueSweepResult, error) {
+	now := time.Now()
+	var res port.OverdueSweepResult
+	var err error
+	if res.Debts, err = s.debtRepo.MarkAllOverdue(ctx, now); err != nil {
+		return res, fmt.Errorf("sweep overdue debts: %w", err)
+	}
+	if res.Receivables, err = s.receivableRepo.MarkAllOverdue(ctx, now); err != nil {
+		return res, fmt.Errorf("sweep overdue receivables: %w", err)
+	}
+	if res.Debts > 0 || res.Receivables > 0 {
+		slog.Info("obligations_marked_overdue", "debts", res.Debts, "receivables", res.Receivables)
+	}
+	return res, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sort"
 	"strings"
 	"time"
@@ -15,16 +16,222 @@ import (
 
 type PerformanceService struct {
 	assetRepo    port.AssetRepository
+	txRepo       port.AssetTransactionRepository
+	incomeRepo   port.IncomeRepository
 	priceService port.PriceService
+	instruments  port.InstrumentRegistry
+	fx           port.FXService
 }
 
-func NewPerformanceService(assetRepo port.AssetRepository, priceService port.PriceService) port.AssetPerformanceService {
+func NewPerformanceService(assetRepo port.AssetRepository, txRepo port.AssetTransactionRepository, incomeRepo port.IncomeRepository, priceService port.PriceService, instruments port.InstrumentRegistry, fx port.FXService) port.AssetPerformanceService {
 	return &PerformanceService{
 		assetRepo:    assetRepo,
+		txRepo:       txRepo,
+		incomeRepo:   incomeRepo,
 		priceService: priceService,
+		instruments:  instruments,
+		fx:           fx,
 	}
 }
 
+// fxRates convert an asset's recorded prices, kept in its purchase currency, to the display currency.
+type fxRates struct {
+	purchase decimal.Decimal // on PurchaseDate: converts the cost basis
+	current  decimal.Decimal // now: converts prices that are not quoted in the display currency
+	// estimated is set when no history was found for PurchaseDate and purchase is today's rate, so the
+	// currency return is reported as zero.
+	estimated bool
+}
+
+func (s *PerformanceService) assetFXRates(ctx context.Context, asset *models.Asset, currency string) (fxRates, error) {
+	from := assetCurrency(asset)
+	if from == currency {
+		one := decimal.NewFromInt(1)
+		return fxRates{purchase: one, current: one}, nil
+	}
+	current, err := s.fx.Rate(ctx, from, currency)
+	if err != nil {
+		return fxRates{}, err
+	}
+	purchase, estimated := current, false
+	if !asset.PurchaseDate.IsZero() {
+		if purchase, estimated, err = s.fx.HistoricalRate(ctx, from, currency, asset.PurchaseDate); err != nil {
+			return fxRates{}, err
+		}
+	}
+	return fxRates{purchase: decimal.NewFromFloat(purchase), current: decimal.NewFromFloat(current), estimated: estimated}, nil
+}
+
+// splitReturn splits profit/loss in the display currency into the asset's own price move, valued at the
+// purchase rate, and the part the exchange rate added since: currency return is the value in the asset's
+// currency times the change of the rate.
+func splitReturn(currentValue, totalCost decimal.Decimal, rates fxRates) (assetReturn, currencyReturn decimal.Decimal) {
+	profitLoss := currentValue.Sub(totalCost)
+	if rates.current.IsZero() || rates.current.Equal(rates.purchase) {
+		return profitLoss, decimal.Zero
+	}
+	localValue := currentValue.Div(rates.current)
+	currencyReturn = localValue.Mul(rates.current.Sub(rates.purchase))
+	return profitLoss.Sub(currencyReturn), currencyReturn
+}
+
+// percentOf returns part as a percentage of whole, or zero when whole is zero.
+func percentOf(part, whole decimal.Decimal) decimal.Decimal {
+	if whole.IsZero() {
+		return decimal.Zero
+	}
+	return part.Div(whole).Mul(decimal.NewFromInt(100))
+}
+
+// holdingDays is the number of whole days since acquired, at least one.
+func holdingDays(acquired, now time.Time) int {
+	days := int(now.Sub(acquired).Hours() / 24)
+	if days < 1 {
+		days = 1
+	}
+	return days
+}
+
+// annualize spreads a return percentage over a holding period given in days.
+func annualize(percent decimal.Decimal, days int) decimal.Decimal {
+	years := decimal.NewFromFloat(float64(days) / 365.0)
+	if years.IsZero() {
+		return decimal.Zero
+	}
+	return percent.Div(years)
+}
+
+// openLots replays an asset's transactions; ok is false when its position does not come from them.
+func (s *PerformanceService) openLots(asset *models.Asset, txs []models.AssetTransaction) (lotBook, bool) {
+	if asset.Type == models.AssetTypeCash || len(txs) == 0 {
+		return lotBook{}, false
+	}
+	book, err := buildLots(txs, asset.CostMethod)
+	if err != nil {
+		slog.Warn("performance_lots_invalid", "asset", asset.UUID, "error", err)
+		return lotBook{}, false
+	}
+	return book, true
+}
+
+// frozenPrice is the price per share that a sold asset went for, converted at the rate of its sale date; ok
+// is false for assets that are not SOLD.
+func (s *PerformanceService) frozenPrice(ctx context.Context, asset *models.Asset, currency string) (decimal.Decimal, bool) {
+	if asset.Status != models.AssetStatusSold || asset.SoldPrice == nil {
+		return decimal.Zero, false
+	}
+	price := *asset.SoldPrice
+	if from := assetCurrency(asset); from != currency {
+		at := asset.PurchaseDate
+		if asset.SoldAt != nil {
+			at = *asset.SoldAt
+		}
+		rate, err := s.fx.RateAt(ctx, from, currency, at)
+		if err != nil {
+			slog.Warn("performance_fx_unavailable", "asset", asset.UUID, "to", currency, "error", err)
+			return decimal.Zero, false
+		}
+		price = price.Mul(decimal.NewFromFloat(rate))
+	}
+	return price, true
+}
+
+// lotValuation is an asset's open lots valued in the display currency.
+type lotValuation struct {
+	lots           []port.LotPerformance
+	totalCost      decimal.Decimal
+	localCost      decimal.Decimal // totalCost in the purchase currency
+	currentValue   decimal.Decimal
+	assetReturn    decimal.Decimal
+	currencyReturn decimal.Decimal
+	holdingDays    int  // quantity-weighted across the lots
+	fxEstimated    bool // today's rate stood in for a lot or sale date without history
+	// realized is what the book's sales and disposals locked in; saleProceeds and saleCost are the net
+	// proceeds of its sales and the cost of the units they sold, each converted at the rate of the sale date.
+	realized     decimal.Decimal
+	saleProceeds decimal.Decimal
+	saleCost     decimal.Decimal
+}
+
+// closed reports whether nothing is held any more.
+func (v lotValuation) closed() bool {
+	return len(v.lots) == 0
+}
+
+// valueLots converts each open lot's cost at the rate of the day it was acquired and values it at qtyPrice,
+// the current price of one unit of asset quantity (lot sizes applied). Realized results are converted at the
+// rate of the day they were locked in.
+func (s *PerformanceService) valueLots(ctx context.Context, asset *models.Asset, book lotBook, currency string, current, qtyPrice decimal.Decimal, now time.Time) (lotValuation, error) {
+	from := assetCurrency(asset)
+	shares := s.lotMultiplier(ctx, asset)
+	var v lotValuation
+	rateAt := func(at time.Time) (decimal.Decimal, bool, error) {
+		if from == currency {
+			return decimal.NewFromInt(1), false, nil
+		}
+		r, estimated, err := s.fx.HistoricalRate(ctx, from, currency, at)
+		if err != nil {
+			return decimal.Zero, false, err
+		}
+		v.fxEstimated = v.fxEstimated || estimated
+		return decimal.NewFromFloat(r), estimated, nil
+	}
+	for _, r := range book.Realized {
+		rate, _, err := rateAt(r.Date)
+		if err != nil {
+			return lotValuation{}, err
+		}
+		v.realized = v.realized.Add(r.Amount.Mul(rate))
+		if r.Proceeds.IsPositive() {
+			v.saleProceeds = v.saleProceeds.Add(r.Proceeds.Mul(rate))
+			v.saleCost = v.saleCost.Add(r.Proceeds.Sub(r.Amount).Mul(rate))
+		}
+	}
+	weightedDays, totalQty := decimal.Zero, decimal.Zero
+	for _, lot := range book.Lots {
+		rate, estimated, err := rateAt(lot.AcquiredAt)
+		if err != nil {
+			return lotValuation{}, err
+		}
+		cost := lot.Cost.Mul(rate)
+		value := lot.Quantity.Mul(qtyPrice)
+		assetReturn, currencyReturn := splitReturn(value, cost, fxRates{purchase: rate, current: current, estimated: estimated})
+		days := holdingDays(lot.AcquiredAt, now)
+		profitLoss := value.Sub(cost)
+		percent := percentOf(profitLoss, cost)
+		unitCost := decimal.Zero
+		if lot.Quantity.IsPositive() {
+			unitCost = cost.Div(lot.Quantity.Mul(shares))
+		}
+		v.lots = append(v.lots, port.LotPerformance{
+			TransactionUUID:   lot.TransactionUUID,
+			AcquiredAt:        lot.AcquiredAt,
+			Quantity:          lot.Quantity,
+			UnitCost:          unitCost,
+			CostBasis:         cost,
+			FXRate:            rate,
+			FXEstimated:       estimated,
+			CurrentValue:      value,
+			ProfitLoss:        profitLoss,
+			ProfitLossPercent: percent,
+			HoldingDays:       days,
+			AnnualizedReturn:  annualize(percent, days),
+		})
+		v.totalCost = v.totalCost.Add(cost)
+		v.localCost = v.localCost.Add(lot.Cost)
+		v.currentValue = v.currentValue.Add(value)
+		v.assetReturn = v.assetReturn.Add(assetReturn)
+		v.currencyReturn = v.currencyReturn.Add(currencyReturn)
+		weightedDays = weightedDays.Add(lot.Quantity.Mul(decimal.NewFromInt(int64(days))))
+		totalQty = totalQty.Add(lot.Quantity)
+	}
+	v.holdingDays = 1
+	if totalQty.IsPositive() {
+		v.holdingDays = max(1, int(weightedDays.Div(totalQty).IntPart()))
+	}
+	return v, nil
+}
+
 func (s *PerformanceService) GetAssetPerformance(ctx context.Context, userID int64, assetUUID string, currency string) (*port.AssetPerformanceResponse, error) {
 	// Get asset
 	asset, err := s.assetRepo.GetByUUID(ctx, assetUUID, userID)
@@ -36,61 +243,120 @@ func (s *PerformanceService) GetAssetPerformance(ctx context.Context, userID int
 	}
 
 	// Default currency
+	currency = strings.ToUpper(strings.TrimSpace(currency))
 	if currency == "" {
-		currency = asset.PurchaseCurrency
-		if currency == "" {
-			currency = port.DefaultCurrency
-		}
+		currency = assetCurrency(asset)
+	}
+
+	// Purchase price, cost and fee are converted at the rate of the purchase date, the target at today's
+	rates, rateErr := s.assetFXRates(ctx, asset, currency)
+	if rateErr != nil {
+		slog.Warn("performance_fx_unavailable", "asset", asset.UUID, "to", currency, "error", rateErr)
+		currency = assetCurrency(asset)
+		rates, _ = s.assetFXRates(ctx, asset, currency)
+	}
+	purchasePrice := asset.PurchasePrice.Mul(rates.purchase)
+	totalCost := asset.TotalCost.Mul(rates.purchase)
+	var targetPrice *decimal.Decimal
+	if asset.TargetPrice != nil {
+		t := asset.TargetPrice.Mul(rates.current)
+		targetPrice = &t
 	}
 
-	// Calculate current value
+	// Calculate current value; a sold asset keeps the price it went for
 	currentPrice := decimal.Zero
 	currentValue := decimal.Zero
 	priceChange24h := 0.0
+	frozen, isFrozen := s.frozenPrice(ctx, asset, currency)
 
-	if asset.Symbol != nil && *asset.Symbol != "" {
+	if isFrozen {
+		currentPrice = frozen
+		currentValue = s.effectiveQuantity(ctx, asset).Mul(currentPrice)
+	} else if asset.Symbol != nil && *asset.Symbol != "" {
 		var priceData *port.PriceData
-		switch asset.Type {
-		case models.AssetTypeCrypto:
-			priceData, err = s.priceService.GetCryptoPriceWithCurrency(ctx, *asset.Symbol, currency)
-		case models.AssetTypeStock:
-			priceData, err = s.priceService.GetStockPriceWithCurrency(ctx, *asset.Symbol, currency)
+		if asset.Type == models.AssetTypeCrypto || asset.Type == models.AssetTypeStock {
+			priceData, err = s.priceService.GetAssetPrice(ctx, asset, currency)
 		}
 
 		if err == nil && priceData != nil {
 			currentPrice = decimal.NewFromFloat(priceData.Price)
-			effectiveQty := s.effectiveQuantity(asset)
+			effectiveQty := s.effectiveQuantity(ctx, asset)
 			currentValue = effectiveQty.Mul(currentPrice)
 		}
 	}
 
-	// If no current price available, use purchase price
+	// If no current price available, use purchase price (at today's rate, so only the currency moved)
 	if currentPrice.IsZero() {
-		currentPrice = asset.PurchasePrice
-		effectiveQty := s.effectiveQuantity(asset)
+		currentPrice = asset.PurchasePrice.Mul(rates.current)
+		effectiveQty := s.effectiveQuantity(ctx, asset)
 		currentValue = effectiveQty.Mul(currentPrice)
 	}
 
-	// Calculate performance metrics
-	profitLoss := currentValue.Sub(asset.TotalCost)
-	profitLossPercent := decimal.Zero
-	if !asset.TotalCost.IsZero() {
-		profitLossPercent = profitLoss.Div(asset.TotalCost).Mul(decimal.NewFromInt(100))
-	}
+	assetReturn, currencyReturn := splitReturn(currentValue, totalCost, rates)
+	holdingPeriod := holdingDays(asset.PurchaseDate, time.Now())
+	realized := asset.RealizedProfitLoss.Mul(rates.current)
+	closed := false
+	soldCost := decimal.Zero // of the units already sold, while some are still held
 
-	// Holding period in days
-	holdingPeriod := int(time.Since(asset.PurchaseDate).Hours() / 24)
-	if holdingPeriod < 1 {
-		holdingPeriod = 1
+	// Held through transactions: cost, returns and holding period add up per lot, each lot converted at the
+	// rate of the day it was bought
+	var lots []port.LotPerformance
+	txs, err := s.txRepo.ListByAssetID(ctx, asset.ID)
+	if err != nil {
+		slog.Warn("performance_lots_unavailable", "asset", asset.UUID, "error", err)
+	} else if book, ok := s.openLots(asset, txs); ok {
+		qtyPrice := currentPrice.Mul(s.lotMultiplier(ctx, asset))
+		if val, err := s.valueLots(ctx, asset, book, currency, rates.current, qtyPrice, time.Now()); err != nil {
+			slog.Warn("performance_lots_unavailable", "asset", asset.UUID, "error", err)
+		} else {
+			lots = val.lots
+			totalCost, currentValue = val.totalCost, val.currentValue
+			assetReturn, currencyReturn = val.assetReturn, val.currencyReturn
+			holdingPeriod = val.holdingDays
+			realized = val.realized
+			soldCost = val.saleCost
+			if !val.localCost.IsZero() {
+				rates.purchase = val.totalCost.Div(val.localCost) // cost-weighted across the lots
+			}
+			rates.estimated = val.fxEstimated
+			if asset.Quantity.IsPositive() {
+				purchasePrice = totalCost.Div(s.effectiveQuantity(ctx, asset)) // per share, like currentPrice
+			}
+			// Sold down to zero: the position is valued at what it was sold for, against what the units cost
+			if val.closed() && isFrozen {
+				closed, soldCost = true, decimal.Zero
+				totalCost, currentValue = val.saleCost, val.saleProceeds
+				assetReturn, currencyReturn = realized, decimal.Zero
+				if asset.SoldAt != nil {
+					holdingPeriod = holdingDays(book.FirstAcquired, *asset.SoldAt)
+				}
+			}
+		}
 	}
 
-	// Annualized return
-	annualizedReturn := decimal.Zero
-	if holdingPeriod > 0 {
-		years := decimal.NewFromFloat(float64(holdingPeriod) / 365.0)
-		if !years.IsZero() {
-			annualizedReturn = profitLossPercent.Div(years)
-		}
+	// Calculate performance metrics
+	unrealized := currentValue.Sub(totalCost)
+	profitLoss := unrealized
+	if closed {
+		unrealized, profitLoss = decimal.Zero, realized
+	}
+	profitLossPercent := percentOf(profitLoss, totalCost)
+	annualizedReturn := annualize(profitLossPercent, holdingPeriod)
+
+	// Total return adds what the asset paid out to its price result
+	var incomeReceived, ttmIncome decimal.Decimal
+	if incomes, err := s.incomeRepo.ListBySourceAssetID(ctx, asset.ID, userID); err != nil {
+		slog.Warn("performance_income_unavailable", "asset", asset.UUID, "error", err)
+	} else if entries, err := s.incomeEntries(ctx, asset, incomes, txs, currency); err != nil {
+		slog.Warn("performance_income_unavailable", "asset", asset.UUID, "error", err)
+	} else {
+		_, incomeReceived, ttmIncome = summarizeIncome(entries, time.Now())
+	}
+	totalReturn := unrealized.Add(realized).Add(incomeReceived)
+	invested := totalCost.Add(soldCost)
+	ttmYieldOnCost := percentOf(ttmIncome, totalCost)
+	if closed {
+		ttmYieldOnCost = decimal.Zero // nothing is held to yield on
 	}
 
 	// Performance status
@@ -103,16 +369,20 @@ func (s *PerformanceService) GetAssetPerformance(ctx context.Context, userID int
 
 	// Analysis
 	message := s.generatePerformanceMessage(asset.Name, profitLossPercent, status)
-	recommendation := s.generateRecommendation(asset, currentPrice, profitLossPercent)
+	recommendation := s.generateRecommendation(targetPrice, currentPrice, profitLossPercent)
+	if closed {
+		message = s.generateSoldMessage(asset.Name, profitLossPercent, status)
+		recommendation = ""
+	}
 	targetReached := false
-	if asset.TargetPrice != nil && currentPrice.GreaterThanOrEqual(*asset.TargetPrice) {
+	if targetPrice != nil && currentPrice.GreaterThanOrEqual(*targetPrice) {
 		targetReached = true
 	}
 
 	// Transaction fee
 	transactionFee := decimal.Zero
 	if asset.TransactionFee != nil {
-		transactionFee = *asset.TransactionFee
+		transactionFee = asset.TransactionFee.Mul(rates.purchase)
 	}
 
 	return &port.AssetPerformanceResponse{
@@ -122,32 +392,49 @@ func (s *PerformanceService) GetAssetPerformance(ctx context.Context, userID int
 		Symbol:    s.getSymbolString(asset.Symbol),
 		Investment: port.InvestmentInfo{
 			Quantity:         asset.Quantity,
-			PurchasePrice:    asset.PurchasePrice,
+			PurchasePrice:    purchasePrice,
 			PurchaseDate:     asset.PurchaseDate,
-			TotalCost:        asset.TotalCost,
+			TotalCost:        totalCost,
 			Currency:         currency,
+			PurchaseCurrency: assetCurrency(asset),
+			PurchaseFXRate:   rates.purchase,
+			FXEstimated:      rates.estimated,
 			TransactionFee:   transactionFee,
 			DaysSinceHolding: holdingPeriod,
+			CostMethod:       string(asset.CostMethod),
 		},
 		CurrentData: port.CurrentValueInfo{
 			CurrentPrice:   currentPrice,
 			CurrentValue:   currentValue,
+			FXRate:         rates.current,
 			PriceChange24h: priceChange24h,
 			LastUpdated:    time.Now(),
 		},
 		Performance: port.PerformanceMetrics{
-			ProfitLoss:        profitLoss,
-			ProfitLossPercent: profitLossPercent,
-			ROI:               profitLossPercent,
-			Status:            status,
-			HoldingPeriod:     holdingPeriod,
-			AnnualizedReturn:  annualizedReturn,
+			ProfitLoss:            profitLoss,
+			ProfitLossPercent:     profitLossPercent,
+			ROI:                   profitLossPercent,
+			AssetReturn:           assetReturn,
+			AssetReturnPercent:    percentOf(assetReturn, totalCost),
+			CurrencyReturn:        currencyReturn,
+			CurrencyReturnPercent: percentOf(currencyReturn, totalCost),
+			Status:                status,
+			HoldingPeriod:         holdingPeriod,
+			AnnualizedReturn:      annualizedReturn,
+			RealizedProfitLoss:    realized,
+			UnrealizedProfitLoss:  unrealized,
+			IncomeReceived:        incomeReceived,
+			TTMIncome:             ttmIncome,
+			TTMYieldOnCost:        ttmYieldOnCost,
+			TotalReturn:           totalReturn,
+			TotalReturnPercent:    percentOf(totalReturn, invested),
 		},
 		Analysis: port.PerformanceAnalysis{
 			Message:        message,
 			Recommendation: recommendation,
 			TargetReached:  targetReached,
 		},
+		Lots: lots,
 	}, nil
 }
 
@@ -158,6 +445,7 @@ func (s *PerformanceService) GetPortfolioPerformance(ctx context.Context, userID
 		return nil, fmt.Errorf("list assets: %w", err)
 	}
 
+	currency = strings.ToUpper(strings.TrimSpace(currency))
 	if currency == "" {
 		currency = port.DefaultCurrency
 	}
@@ -165,10 +453,46 @@ func (s *PerformanceService) GetPortfolioPerformance(ctx context.Context, userID
 	// Initialize aggregates
 	totalInvested := decimal.Zero
 	totalCurrentValue := decimal.Zero
+	totalAssetReturn := decimal.Zero
+	totalCurrencyReturn := decimal.Zero
+	totalRealized := decimal.Zero
+	fxEstimated := false
 	allocationMap := make(map[string]port.AssetTypeAllocation)
 	var performers []port.PerformerSummary
 	statusSummary := port.StatusSummary{}
 
+	// Quote every priced asset in one batch
+	var reqs []port.SymbolRequest
+	var quoted []int64
+	for i := range assets {
+		if assets[i].Status == models.AssetStatusPlanned || assets[i].Status == models.AssetStatusSold {
+			continue
+		}
+		if req, ok := assetSymbolRequest(&assets[i]); ok {
+			reqs = append(reqs, req)
+			quoted = append(quoted, assets[i].ID)
+		}
+	}
+	// Positions held through transactions are valued lot by lot
+	txsByAsset := make(map[int64][]models.AssetTransaction)
+	if txs, err := s.txRepo.ListByUserID(ctx, userID); err != nil {
+		slog.Warn("performance_lots_unavailable", "user_id", userID, "error", err)
+	} else {
+		for _, tx := range txs {
+			txsByAsset[tx.AssetID] = append(txsByAsset[tx.AssetID], tx)
+		}
+	}
+
+	quotes := make(map[int64]*port.PriceData, len(quoted))
+	if len(reqs) > 0 {
+		results, _ := s.priceService.GetPrices(ctx, reqs, currency)
+		for i, id := range quoted {
+			if i < len(results) && results[i].Data != nil {
+				quotes[id] = results[i].Data
+			}
+		}
+	}
+
 	// Process each asset
 	for _, asset := range assets {
 		// Count by status
@@ -186,38 +510,59 @@ func (s *PerformanceService) GetPortfolioPerformance(ctx context.Context, userID
 			continue
 		}
 
-		// Calculate current value
-		currentPrice := asset.PurchasePrice
-		if asset.Symbol != nil && *asset.Symbol != "" {
-			var priceData *port.PriceData
-			switch asset.Type {
-			case models.AssetTypeCrypto:
-				priceData, _ = s.priceService.GetCryptoPriceWithCurrency(ctx, *asset.Symbol, currency)
-			case models.AssetTypeStock:
-				priceData, _ = s.priceService.GetStockPriceWithCurrency(ctx, *asset.Symbol, currency)
-			}
-			if priceData != nil {
-				currentPrice = decimal.NewFromFloat(priceData.Price)
-			}
+		// Cost is converted at the rate of the purchase date; an asset that cannot be converted would skew the totals
+		rates, err := s.assetFXRates(ctx, &asset, currency)
+		if err != nil {
+			slog.Warn("performance_fx_unavailable", "asset", asset.UUID, "to", currency, "error", err)
+			continue
+		}
+		totalCost := asset.TotalCost.Mul(rates.purchase)
+
+		// Calculate current value; a sold asset keeps the price it went for
+		currentPrice := asset.PurchasePrice.Mul(rates.current)
+		if frozen, ok := s.frozenPrice(ctx, &asset, currency); ok {
+			currentPrice = frozen
+		} else if priceData := quotes[asset.ID]; priceData != nil {
+			currentPrice = decimal.NewFromFloat(priceData.Price)
 		}
 
-		effectiveQty := s.effectiveQuantity(&asset)
+		effectiveQty := s.effectiveQuantity(ctx, &asset)
 		currentValue := effectiveQty.Mul(currentPrice)
-		profitLoss := currentValue.Sub(asset.TotalCost)
-		profitLossPercent := decimal.Zero
-		if !asset.TotalCost.IsZero() {
-			profitLossPercent = profitLoss.Div(asset.TotalCost).Mul(decimal.NewFromInt(100))
+		assetReturn, currencyReturn := splitReturn(currentValue, totalCost, rates)
+		realized := asset.RealizedProfitLoss.Mul(rates.current)
+		if book, ok := s.openLots(&asset, txsByAsset[asset.ID]); ok {
+			qtyPrice := currentPrice.Mul(s.lotMultiplier(ctx, &asset))
+			val, err := s.valueLots(ctx, &asset, book, currency, rates.current, qtyPrice, time.Now())
+			if err != nil {
+				slog.Warn("performance_fx_unavailable", "asset", asset.UUID, "to", currency, "error", err)
+				continue
+			}
+			totalCost, currentValue = val.totalCost, val.currentValue
+			assetReturn, currencyReturn = val.assetReturn, val.currencyReturn
+			realized = val.realized
+			rates.estimated = val.fxEstimated
+		}
+		totalRealized = totalRealized.Add(realized)
+		fxEstimated = fxEstimated || rates.estimated
+
+		// Sold down to zero: only its realized result counts; the proceeds are in a CASH asset
+		if totalCost.IsZero() && currentValue.IsZero() {
+			continue
 		}
+		profitLoss := currentValue.Sub(totalCost)
+		profitLossPercent := percentOf(profitLoss, totalCost)
 
 		// Aggregate totals
-		totalInvested = totalInvested.Add(asset.TotalCost)
+		totalInvested = totalInvested.Add(totalCost)
 		totalCurrentValue = totalCurrentValue.Add(currentValue)
+		totalAssetReturn = totalAssetReturn.Add(assetReturn)
+		totalCurrencyReturn = totalCurrencyReturn.Add(currencyReturn)
 
 		// Asset type allocation
 		assetType := string(asset.Type)
 		allocation := allocationMap[assetType]
 		allocation.Count++
-		allocation.TotalInvested = allocation.TotalInvested.Add(asset.TotalCost)
+		allocation.TotalInvested = allocation.TotalInvested.Add(totalCost)
 		allocation.CurrentValue = allocation.CurrentValue.Add(currentValue)
 		allocation.ProfitLoss = allocation.ProfitLoss.Add(profitLoss)
 		allocationMap[assetType] = allocation
@@ -278,12 +623,17 @@ func (s *PerformanceService) GetPortfolioPerformance(ctx context.Context, userID
 
 	return &port.PortfolioPerformanceResponse{
 		Overview: port.PortfolioOverview{
-			TotalInvested:          totalInvested,
-			CurrentValue:           totalCurrentValue,
-			TotalProfitLoss:        totalProfitLoss,
-			TotalProfitLossPercent: totalProfitLossPercent,
-			TotalROI:               totalProfitLossPercent,
-			Currency:               currency,
+			TotalInvested:             totalInvested,
+			CurrentValue:              totalCurrentValue,
+			TotalProfitLoss:           totalProfitLoss,
+			TotalProfitLossPercent:    totalProfitLossPercent,
+			TotalROI:                  totalProfitLossPercent,
+			TotalAssetReturn:          totalAssetReturn,
+			TotalCurrencyReturn:       totalCurrencyReturn,
+			FXEstimated:               fxEstimated,
+			TotalRealizedProfitLoss:   totalRealized,
+			TotalUnrealizedProfitLoss: totalProfitLoss,
+			Currency:                  currency,
 		},
 		AssetAllocation: allocationMap,
 		TopPerformers: port.PerformersInfo{
@@ -314,9 +664,20 @@ func (s *PerformanceService) generatePerformanceMessage(assetName string, profit
 	return fmt.Sprintf("Your %s investment is %s %.2f%% %s", assetName, verb, profitLossPercent.Abs().InexactFloat64(), emoji)
 }
 
-func (s *PerformanceService) generateRecommendation(asset *models.Asset, currentPrice decimal.Decimal, profitLossPercent decimal.Decimal) string {
+func (s *PerformanceService) generateSoldMessage(assetName string, profitLossPercent decimal.Decimal, status string) string {
+	switch status {
+	case "profit":
+		return fmt.Sprintf("Your %s investment was sold at a %.2f%% gain 🎉", assetName, profitLossPercent.InexactFloat64())
+	case "loss":
+		return fmt.Sprintf("Your %s investment was sold at a %.2f%% loss 📉", assetName, profitLossPercent.Abs().InexactFloat64())
+	default:
+		return fmt.Sprintf("Your %s investment was sold at break-even ⚪", assetName)
+	}
+}
+
+func (s *PerformanceService) generateRecommendation(targetPrice *decimal.Decimal, currentPrice decimal.Decimal, profitLossPercent decimal.Decimal) string {
 	// Target price reached
-	if asset.TargetPrice != nil && currentPrice.GreaterThanOrEqual(*asset.TargetPrice) {
+	if targetPrice != nil && currentPrice.GreaterThanOrEqual(*targetPrice) {
 		return "Target price reached! Consider taking profit."
 	}
 
@@ -344,12 +705,15 @@ func (s *PerformanceService) generateRecommendation(asset *models.Asset, current
 }
 
 // effectiveQuantity returns the actual number of units for value calculation.
-// For IDX stocks, quantity is in lots (1 lot = 100 shares), so we multiply by 100.
-func (s *PerformanceService) effectiveQuantity(asset *models.Asset) decimal.Decimal {
-	if asset.Type == models.AssetTypeStock && asset.Symbol != nil && IsIDXStock(strings.ToUpper(*asset.Symbol)) {
-		return asset.Quantity.Mul(decimal.NewFromInt(IDXLotSize))
-	}
-	return asset.Quantity
+// Stocks listed in lots (e.g. IDX, 1 lot = 100 shares) hold their quantity in lots, so it is multiplied
+// by the instrument's lot size.
+func (s *PerformanceService) effectiveQuantity(ctx context.Context, asset *models.Asset) decimal.Decimal {
+	return asset.Quantity.Mul(s.lotMultiplier(ctx, asset))
+}
+
+// lotMultiplier is the number of units in one unit of the asset's quantity: the exchange lot size, or 1.
+func (s *PerformanceService) lotMultiplier(ctx context.Context, asset *models.Asset) decimal.Decimal {
+	return lotSize(ctx, s.instruments, asset)
 }
 
 func (s *PerformanceService) getSymbolString(symbol *string) string {
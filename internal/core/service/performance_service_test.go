@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_splitReturn(t *testing.T) {
+	d := decimal.RequireFromString
+	tests := []struct {
+		name                 string
+		value, cost          string
+		purchase, current    string
+		wantAsset, wantCurry string
+	}{
+		// 10 shares bought at 100 USD when 1 USD = 15,000 IDR, now 120 USD at 16,000 IDR.
+		{"price and currency both moved", "19200000", "15000000", "15000", "16000", "3000000", "1200000"},
+		// Flat price: the whole gain is the rupiah weakening.
+		{"only currency moved", "16000000", "15000000", "15000", "16000", "0", "1000000"},
+		{"same currency", "1200", "1000", "1", "1", "200", "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rates := fxRates{purchase: d(tt.purchase), current: d(tt.current)}
+			gotAsset, gotCurrency := splitReturn(d(tt.value), d(tt.cost), rates)
+			if !gotAsset.Equal(d(tt.wantAsset)) || !gotCurrency.Equal(d(tt.wantCurry)) {
+				t.Errorf("splitReturn() = %s, %s; want %s, %s", gotAsset, gotCurrency, tt.wantAsset, tt.wantCurry)
+			}
+		})
+	}
+}
+
+func TestPerformanceService_valueLotsPerShare(t *testing.T) {
+	symbol := "BBRI"
+	asset := &models.Asset{Type: models.AssetTypeStock, Symbol: &symbol, PurchaseCurrency: "IDR"}
+	s := &PerformanceService{instruments: NewInstrumentRegistry(memInstrumentRepo{{Symbol: "BBRI", Exchange: "IDX", Currency: "IDR", ProviderSymbol: "BBRI.JK", LotSize: 100}})}
+	book := lotBook{Lots: []assetLot{{Quantity: decimal.NewFromInt(2), Cost: decimal.NewFromInt(1_000_000), AcquiredAt: time.Now().AddDate(0, 0, -30)}}}
+
+	// A current price of 6000 per share values each lot of 100 shares at 600000
+	v, err := s.valueLots(context.Background(), asset, book, "IDR", decimal.NewFromInt(1), decimal.NewFromInt(600_000), time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.lots) != 1 || !v.lots[0].UnitCost.Equal(decimal.NewFromInt(5000)) || !v.currentValue.Equal(decimal.NewFromInt(1_200_000)) {
+		t.Errorf("lots = %+v, value %s; want unit cost 5000 per share and value 1200000", v.lots, v.currentValue)
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -16,13 +18,17 @@ type PortfolioService struct {
 	assetRepo    port.AssetRepository
 	priceService port.PriceService
 	historyRepo  port.AssetPriceHistoryRepository
+	instruments  port.InstrumentRegistry
+	fx           port.FXService
 }
 
-func NewPortfolioService(assetRepo port.AssetRepository, priceService port.PriceService, historyRepo port.AssetPriceHistoryRepository) port.PortfolioService {
+func NewPortfolioService(assetRepo port.AssetRepository, priceService port.PriceService, historyRepo port.AssetPriceHistoryRepository, instruments port.InstrumentRegistry, fx port.FXService) port.PortfolioService {
 	return &PortfolioService{
 		assetRepo:    assetRepo,
 		priceService: priceService,
 		historyRepo:  historyRepo,
+		instruments:  instruments,
+		fx:           fx,
 	}
 }
 
@@ -38,10 +44,13 @@ func (s *PortfolioService) GetPortfolio(ctx context.Context, userID int64, curre
 
 	var assetValues []port.AssetValueResponse
 	totalValue := decimal.Zero
+	inputs := s.loadValuationInputs(ctx, assets, currency)
 
 	for _, asset := range assets {
-		assetValue, err := s.calculateAssetValue(ctx, &asset, currency)
-		if err != nil {
+		assetValue, err := s.calculateAssetValue(ctx, &asset, currency, inputs)
+		if err == nil {
+			s.convertAssetValue(ctx, assetValue, currency)
+		} else {
 			assetValue = &port.AssetValueResponse{
 				UUID:         asset.UUID,
 				Name:         asset.Name,
@@ -55,7 +64,7 @@ func (s *PortfolioService) GetPortfolio(ctx context.Context, userID int64, curre
 			}
 		}
 		assetValues = append(assetValues, *assetValue)
-		// Only sum into total when asset value is in the requested currency (avoid mixing IDR+USD)
+		// Only sum into total when asset value is in the requested currency (conversion failed: avoid mixing IDR+USD)
 		if assetValue.Currency == currency {
 			totalValue = totalValue.Add(assetValue.Value)
 		}
@@ -82,10 +91,71 @@ func (s *PortfolioService) GetAssetValue(ctx context.Context, userID int64, asse
 		return nil, fmt.Errorf("asset not found")
 	}
 
-	return s.calculateAssetValue(ctx, asset, currency)
+	assetValue, err := s.calculateAssetValue(ctx, asset, currency, s.loadValuationInputs(ctx, []models.Asset{*asset}, currency))
+	if err != nil {
+		return nil, err
+	}
+	s.convertAssetValue(ctx, assetValue, currency)
+	return assetValue, nil
+}
+
+// convertAssetValue converts a value priced in another currency (cash, manual prices, purchase price
+// fallbacks) to currency at the current rate. When no rate is available the value is left as it is.
+func (s *PortfolioService) convertAssetValue(ctx context.Context, v *port.AssetValueResponse, currency string) {
+	from := strings.ToUpper(v.Currency)
+	if from == "" || from == currency {
+		return
+	}
+	rate, err := s.fx.Rate(ctx, from, currency)
+	if err != nil {
+		slog.Warn("portfolio_fx_unavailable", "asset", v.UUID, "from", from, "to", currency, "error", err)
+		return
+	}
+	r := decimal.NewFromFloat(rate)
+	v.CurrentPrice = v.CurrentPrice.Mul(r)
+	v.Value = v.Value.Mul(r)
+	v.Currency = currency
+}
+
+// valuationInputs are the prices calculateAssetValue reads, loaded for all assets up front: one query for
+// the latest recorded prices and one batched lookup for the live quotes.
+type valuationInputs struct {
+	quotes map[int64]port.PriceResult          // live quotes of CRYPTO/STOCK assets with a symbol, by asset ID
+	latest map[int64]*models.AssetPriceHistory // latest recorded price, by asset ID
+}
+
+func (s *PortfolioService) loadValuationInputs(ctx context.Context, assets []models.Asset, currency string) *valuationInputs {
+	in := &valuationInputs{quotes: make(map[int64]port.PriceResult)}
+
+	ids := make([]int64, len(assets))
+	var reqs []port.SymbolRequest
+	var quoted []int64
+	for i := range assets {
+		ids[i] = assets[i].ID
+		if req, ok := assetSymbolRequest(&assets[i]); ok {
+			reqs = append(reqs, req)
+			quoted = append(quoted, assets[i].ID)
+		}
+	}
+
+	latest, err := s.historyRepo.GetLatestByAssetIDs(ctx, ids)
+	if err != nil {
+		slog.Warn("portfolio_latest_prices_failed", "error", err)
+	}
+	in.latest = latest
+
+	if len(reqs) > 0 {
+		results, _ := s.priceService.GetPrices(ctx, reqs, currency)
+		for i, id := range quoted {
+			if i < len(results) {
+				in.quotes[id] = results[i]
+			}
+		}
+	}
+	return in
 }
 
-func (s *PortfolioService) calculateAssetValue(ctx context.Context, asset *models.Asset, currency string) (*port.AssetValueResponse, error) {
+func (s *PortfolioService) calculateAssetValue(ctx context.Context, asset *models.Asset, currency string, in *valuationInputs) (*port.AssetValueResponse, error) {
 	assetCurrency := asset.PurchaseCurrency
 	if assetCurrency == "" {
 		assetCurrency = port.DefaultCurrency
@@ -98,7 +168,7 @@ func (s *PortfolioService) calculateAssetValue(ctx context.Context, asset *model
 		// CASH: check price history first (user may update total cash amount via RecordPrice)
 		unitPrice := decimal.NewFromInt(1)
 		source := "cash_unit"
-		if latest, _ := s.historyRepo.GetLatestByAssetID(ctx, asset.ID); latest != nil {
+		if latest := in.latest[asset.ID]; latest != nil {
 			unitPrice = latest.Price
 			source = "manual_update"
 		}
@@ -119,7 +189,7 @@ func (s *PortfolioService) calculateAssetValue(ctx context.Context, asset *model
 		// Priority: latest price history → purchase price → zero
 		unitPrice := decimal.Zero
 		source := "no_price"
-		if latest, _ := s.historyRepo.GetLatestByAssetID(ctx, asset.ID); latest != nil {
+		if latest := in.latest[asset.ID]; latest != nil {
 			unitPrice = latest.Price
 			source = "manual_update"
 		} else if !asset.PurchasePrice.IsZero() {
@@ -159,10 +229,12 @@ func (s *PortfolioService) calculateAssetValue(ctx context.Context, asset *model
 	var err error
 
 	switch asset.Type {
-	case models.AssetTypeCrypto:
-		priceData, err = s.priceService.GetCryptoPriceWithCurrency(ctx, *asset.Symbol, currency)
-	case models.AssetTypeStock:
-		priceData, err = s.priceService.GetStockPriceWithCurrency(ctx, *asset.Symbol, currency)
+	case models.AssetTypeCrypto, models.AssetTypeStock:
+		quote, ok := in.quotes[asset.ID]
+		priceData, err = quote.Data, quote.Err
+		if !ok || (priceData == nil && err == nil) {
+			err = errors.New("price not fetched")
+		}
 	default:
 		return &port.AssetValueResponse{
 			UUID:         asset.UUID,
@@ -178,9 +250,25 @@ func (s *PortfolioService) calculateAssetValue(ctx context.Context, asset *model
 	}
 
 	if err != nil {
-		// Fallback: use purchase price when external price is unavailable (e.g. API down, no key)
+		// Fallback 1: latest recorded price (e.g. the daily snapshot), converted by the caller when it is in
+		// another currency
+		if latest := in.latest[asset.ID]; latest != nil && latest.Currency != "" {
+			effectiveQty := s.effectiveQuantity(ctx, asset)
+			return &port.AssetValueResponse{
+				UUID:         asset.UUID,
+				Name:         asset.Name,
+				Type:         string(asset.Type),
+				Symbol:       *asset.Symbol,
+				Quantity:     asset.Quantity,
+				CurrentPrice: latest.Price,
+				Value:        effectiveQty.Mul(latest.Price),
+				Currency:     strings.ToUpper(latest.Currency),
+				PriceSource:  "price_history",
+			}, nil
+		}
+		// Fallback 2: use purchase price when external price is unavailable (e.g. API down, no key)
 		if !asset.PurchasePrice.IsZero() {
-			effectiveQty := s.effectiveQuantity(asset)
+			effectiveQty := s.effectiveQuantity(ctx, asset)
 			value := effectiveQty.Mul(asset.PurchasePrice)
 			return &port.AssetValueResponse{
 				UUID:         asset.UUID,
@@ -198,7 +286,7 @@ func (s *PortfolioService) calculateAssetValue(ctx context.Context, asset *model
 	}
 
 	currentPrice := decimal.NewFromFloat(priceData.Price)
-	effectiveQty := s.effectiveQuantity(asset)
+	effectiveQty := s.effectiveQuantity(ctx, asset)
 	value := effectiveQty.Mul(currentPrice)
 
 	return &port.AssetValueResponse{
@@ -215,10 +303,11 @@ func (s *PortfolioService) calculateAssetValue(ctx context.Context, asset *model
 }
 
 // effectiveQuantity returns the actual number of units for value calculation.
-// For IDX stocks, quantity is in lots (1 lot = 100 shares), so we multiply by 100.
-func (s *PortfolioService) effectiveQuantity(asset *models.Asset) decimal.Decimal {
-	if asset.Type == models.AssetTypeStock && asset.Symbol != nil && IsIDXStock(strings.ToUpper(*asset.Symbol)) {
-		return asset.Quantity.Mul(decimal.NewFromInt(IDXLotSize))
+// Stocks listed in lots (e.g. IDX, 1 lot = 100 shares) hold their quantity in lots, so it is multiplied
+// by the instrument's lot size.
+func (s *PortfolioService) effectiveQuantity(ctx context.Context, asset *models.Asset) decimal.Decimal {
+	if lot := s.instruments.LotSize(ctx, asset); lot > 1 {
+		return asset.Quantity.Mul(decimal.NewFromInt(lot))
 	}
 	return asset.Quantity
 }
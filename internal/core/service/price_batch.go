@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+)
+
+// quoteWorkers bounds how many single-symbol quotes run at once against a provider without batch support.
+const quoteWorkers = 8
+
+// pendingQuote is one distinct lookup of a GetPrices call, shared by every request for the same
+// kind, symbol and pinned provider ID.
+type pendingQuote struct {
+	kind     string
+	sym      port.PriceSymbol
+	cacheKey string
+	data     *port.PriceData
+	errs     []error
+	lastGood *port.PriceData // expired cache entry, served when every provider fails
+}
+
+func (q *pendingQuote) err() error {
+	if len(q.errs) == 0 {
+		return fmt.Errorf("fetch %s price: no %s price provider available for %s", strings.ToLower(q.kind), strings.ToLower(q.kind), q.sym.Ticker)
+	}
+	return fmt.Errorf("fetch %s price: %w", strings.ToLower(q.kind), errors.Join(q.errs...))
+}
+
+// assetSymbolRequest builds the price lookup for a CRYPTO or STOCK asset with a symbol.
+func assetSymbolRequest(asset *models.Asset) (port.SymbolRequest, bool) {
+	if asset.Symbol == nil || strings.TrimSpace(*asset.Symbol) == "" {
+		return port.SymbolRequest{}, false
+	}
+	var kind string
+	switch asset.Type {
+	case models.AssetTypeCrypto:
+		kind = port.PriceKindCrypto
+	case models.AssetTypeStock:
+		kind = port.PriceKindStock
+	default:
+		return port.SymbolRequest{}, false
+	}
+	req := port.SymbolRequest{Kind: kind, Symbol: *asset.Symbol, Listing: assetCurrency(asset)}
+	if asset.PriceProviderID != nil {
+		req.ProviderID = *asset.PriceProviderID
+	}
+	return req, true
+}
+
+// GetPrices answers fresh and stale symbols from the cache, refreshing stale ones in the background, and
+// fetches the rest. Each kind's providers are tried in order on whatever is still unanswered: a BatchQuoter
+// gets all of it in one call, any other provider is called per symbol through a pool of quoteWorkers.
+// A symbol no provider could price falls back to its last good quote, flagged stale.
+func (s *PriceService) GetPrices(ctx context.Context, reqs []port.SymbolRequest, currency string) ([]port.PriceResult, error) {
+	currency = strings.ToUpper(currency)
+	if currency == "" {
+		currency = port.DefaultCurrency
+	}
+
+	results := make([]port.PriceResult, len(reqs))
+	byKey := make(map[string]*pendingQuote)
+	slots := make([]*pendingQuote, len(reqs))
+	misses := make(map[string][]*pendingQuote)
+	stale := make(map[string][]*pendingQuote)
+	for i, r := range reqs {
+		kind := strings.ToUpper(r.Kind)
+		if kind != port.PriceKindCrypto && kind != port.PriceKindStock {
+			results[i].Err = errors.New("unsupported asset type for price lookup")
+			continue
+		}
+		sym := s.priceSymbol(ctx, kind, r)
+		if sym.Ticker == "" {
+			results[i].Err = errors.New("asset has no symbol for price lookup")
+			continue
+		}
+		key := quoteCacheKey(kind, sym, currency)
+		if q, ok := byKey[key]; ok {
+			slots[i] = q
+			continue
+		}
+		q := &pendingQuote{kind: kind, sym: sym, cacheKey: key}
+		switch cached, state := s.getQuoteEntry(ctx, key); state {
+		case quoteFresh:
+			slog.Debug("cache_hit", "key", key)
+			q.data = cached
+		case quoteStale:
+			slog.Debug("cache_stale", "key", key)
+			q.data = cached
+			stale[kind] = append(stale[kind], q)
+		default:
+			slog.Debug("cache_miss", "key", key)
+			q.lastGood = cached
+			misses[kind] = append(misses[kind], q)
+		}
+		byKey[key] = q
+		slots[i] = q
+	}
+
+	for _, kind := range []string{port.PriceKindCrypto, port.PriceKindStock} {
+		if len(stale[kind]) > 0 {
+			s.refreshInBackground(kind, stale[kind], currency)
+		}
+		if len(misses[kind]) > 0 {
+			s.fetchCoalesced(ctx, kind, misses[kind], currency)
+		}
+	}
+
+	for i, q := range slots {
+		if q == nil {
+			continue
+		}
+		if q.data == nil && q.lastGood != nil {
+			slog.Warn("price_last_good", "symbol", q.sym.Ticker, "fetched_at", q.lastGood.FetchedAt, "error", q.err())
+			q.data = q.lastGood
+		}
+		if q.data == nil {
+			results[i].Err = q.err()
+			continue
+		}
+		data := *q.data
+		results[i].Data = &data
+	}
+	return results, ctx.Err()
+}
+
+// priceSymbol normalises a request; a stock without a pinned ID gets its listing's provider symbol.
+func (s *PriceService) priceSymbol(ctx context.Context, kind string, r port.SymbolRequest) port.PriceSymbol {
+	sym := port.PriceSymbol{Ticker: strings.ToUpper(strings.TrimSpace(r.Symbol)), ProviderID: strings.TrimSpace(r.ProviderID)}
+	if kind == port.PriceKindStock && sym.ProviderID == "" && sym.Ticker != "" && s.instruments != nil {
+		if inst := s.instruments.Lookup(ctx, sym.Ticker, r.Listing); inst != nil {
+			sym.ProviderID = inst.ProviderSymbol
+		}
+	}
+	return sym
+}
+
+func quoteCacheKey(kind string, sym port.PriceSymbol, currency string) string {
+	if sym.ProviderID != "" {
+		return fmt.Sprintf("%s:%s@%s:%s", strings.ToLower(kind), sym.Ticker, sym.ProviderID, currency)
+	}
+	return fmt.Sprintf("%s:%s:%s", strings.ToLower(kind), sym.Ticker, currency)
+}
+
+// fetchQuotes walks the kind's providers in fallback order until every quote is answered.
+func (s *PriceService) fetchQuotes(ctx context.Context, kind string, pending []*pendingQuote, currency string) {
+	for _, p := range s.providers[kind] {
+		if len(pending) == 0 || ctx.Err() != nil {
+			return
+		}
+		if bq, ok := p.(port.BatchQuoter); ok {
+			s.quoteBatch(ctx, kind, p, bq, pending, currency)
+		} else {
+			s.quoteEach(ctx, kind, p, pending, currency)
+		}
+		unanswered := pending[:0:0]
+		for _, q := range pending {
+			if q.data == nil {
+				unanswered = append(unanswered, q)
+			}
+		}
+		pending = unanswered
+	}
+}
+
+func (s *PriceService) quoteBatch(ctx context.Context, kind string, p port.PriceProvider, bq port.BatchQuoter, pending []*pendingQuote, currency string) {
+	syms := make([]port.PriceSymbol, len(pending))
+	for i, q := range pending {
+		syms[i] = q.sym
+	}
+	found, err := bq.QuoteMany(ctx, kind, syms, currency)
+	if errors.Is(err, port.ErrProviderUnsupported) {
+		return
+	}
+	if err != nil {
+		slog.Warn("price_api_error", "kind", kind, "symbols", len(syms), "source", p.Name(), "error", err)
+		for _, q := range pending {
+			q.errs = append(q.errs, fmt.Errorf("%s: %w", p.Name(), err))
+		}
+		return
+	}
+	for _, q := range pending {
+		if data := found[q.sym]; data != nil {
+			s.answer(ctx, q, p, data, currency)
+		} else {
+			q.errs = append(q.errs, fmt.Errorf("%s: price not found for %s", p.Name(), q.sym.Ticker))
+		}
+	}
+}
+
+func (s *PriceService) quoteEach(ctx context.Context, kind string, p port.PriceProvider, pending []*pendingQuote, currency string) {
+	sem := make(chan struct{}, quoteWorkers)
+	var wg sync.WaitGroup
+	for _, q := range pending {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			data, err := p.Quote(ctx, kind, q.sym, currency)
+			switch {
+			case err == nil:
+				s.answer(ctx, q, p, data, currency)
+			case errors.Is(err, port.ErrProviderUnsupported):
+			default:
+				slog.Warn("price_api_error", "kind", kind, "symbol", q.sym.Ticker, "source", p.Name(), "error", err)
+				q.errs = append(q.errs, fmt.Errorf("%s: %w", p.Name(), err))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// answer settles q with a provider's quote. A quote in another currency (e.g. BBRI in IDR when USD was
+// asked) is converted; if that conversion fails it is kept, uncached, in the provider's currency.
+func (s *PriceService) answer(ctx context.Context, q *pendingQuote, p port.PriceProvider, data *port.PriceData, currency string) {
+	if len(q.errs) > 0 {
+		slog.Info("price_provider_fallback", "kind", q.kind, "symbol", q.sym.Ticker, "provider", p.Name(), "failed", len(q.errs))
+	}
+	q.data = data
+	if from := strings.ToUpper(data.Currency); from != "" && from != currency {
+		rate, err := s.fx.Rate(ctx, from, currency)
+		if err != nil {
+			return
+		}
+		data.Price = data.Price * rate
+		data.Currency = currency
+	}
+	s.setQuoteEntry(ctx, q.cacheKey, data)
+	slog.Info("price_fetched", "symbol", q.sym.Ticker, "price", data.Price, "source", data.Source)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"monity/internal/core/port"
+)
+
+// Quote cache entries live for the last-known-good TTL and carry when they were stored. By age an entry is
+// fresh (within CacheTTL), stale (served flagged, with a background refresh, within CacheStaleTTL) or
+// expired (refetched; served flagged only when every provider fails).
+type cachedQuote struct {
+	Data     port.PriceData `json:"data"`
+	StoredAt time.Time      `json:"storedAt"`
+}
+
+type quoteFreshness int
+
+const (
+	quoteMissing quoteFreshness = iota
+	quoteExpired
+	quoteStale
+	quoteFresh
+)
+
+const (
+	defaultQuoteTTL    = 60 * time.Second
+	defaultStaleTTL    = 15 * time.Minute
+	defaultLastGoodTTL = 7 * 24 * time.Hour
+	// refreshTimeout bounds a background refresh, which outlives the request that started it.
+	refreshTimeout = 30 * time.Second
+)
+
+func (s *PriceService) quoteTTLs() (fresh, stale, lastGood time.Duration) {
+	fresh = time.Duration(s.cfg.CacheTTL) * time.Second
+	if fresh <= 0 {
+		fresh = defaultQuoteTTL
+	}
+	stale = time.Duration(s.cfg.CacheStaleTTL) * time.Second
+	if stale <= 0 {
+		stale = defaultStaleTTL
+	}
+	lastGood = time.Duration(s.cfg.LastGoodTTL) * time.Second
+	if lastGood <= 0 {
+		lastGood = defaultLastGoodTTL
+	}
+	return fresh, max(stale, fresh), max(lastGood, stale, fresh)
+}
+
+func (s *PriceService) getQuoteEntry(ctx context.Context, key string) (*port.PriceData, quoteFreshness) {
+	raw, err := s.cache.Get(ctx, key)
+	if err != nil {
+		return nil, quoteMissing
+	}
+	var entry cachedQuote
+	if json.Unmarshal(raw, &entry) != nil || entry.StoredAt.IsZero() {
+		return nil, quoteMissing
+	}
+	fresh, stale, _ := s.quoteTTLs()
+	data := entry.Data
+	switch age := time.Since(entry.StoredAt); {
+	case age < fresh:
+		return &data, quoteFresh
+	case age < stale:
+		data.Stale = true
+		return &data, quoteStale
+	default:
+		data.Stale = true
+		return &data, quoteExpired
+	}
+}
+
+func (s *PriceService) setQuoteEntry(ctx context.Context, key string, data *port.PriceData) {
+	_, _, lastGood := s.quoteTTLs()
+	entry := cachedQuote{Data: *data, StoredAt: time.Now()}
+	entry.Data.Stale = false
+	raw, _ := json.Marshal(entry)
+	_ = s.cache.Set(ctx, key, raw, lastGood)
+}
+
+// quoteFlight is one in-progress fetch of a cache key; waiters read data and errs after done is closed.
+type quoteFlight struct {
+	done chan struct{}
+	data *port.PriceData
+	errs []error
+}
+
+// flightGroup coalesces concurrent fetches of the same cache key within this process.
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*quoteFlight
+}
+
+// claim returns the flight for key and whether the caller started it and so must finish it.
+func (g *flightGroup) claim(key string) (*quoteFlight, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if f, ok := g.flights[key]; ok {
+		return f, false
+	}
+	if g.flights == nil {
+		g.flights = make(map[string]*quoteFlight)
+	}
+	f := &quoteFlight{done: make(chan struct{})}
+	g.flights[key] = f
+	return f, true
+}
+
+func (g *flightGroup) finish(key string, f *quoteFlight, data *port.PriceData, errs []error) {
+	g.mu.Lock()
+	delete(g.flights, key)
+	g.mu.Unlock()
+	f.data, f.errs = data, errs
+	close(f.done)
+}
+
+// fetchCoalesced fetches the pending quotes, joining fetches of the same key already running elsewhere in
+// the process instead of starting another one.
+func (s *PriceService) fetchCoalesced(ctx context.Context, kind string, pending []*pendingQuote, currency string) {
+	var lead []*pendingQuote
+	var leadFlights []*quoteFlight
+	waits := make(map[*pendingQuote]*quoteFlight)
+	for _, q := range pending {
+		if f, leader := s.flights.claim(q.cacheKey); leader {
+			lead = append(lead, q)
+			leadFlights = append(leadFlights, f)
+		} else {
+			waits[q] = f
+		}
+	}
+
+	if len(lead) > 0 {
+		s.fetchQuotes(ctx, kind, lead, currency)
+		for i, q := range lead {
+			s.flights.finish(q.cacheKey, leadFlights[i], q.data, q.errs)
+		}
+	}
+
+	for q, f := range waits {
+		select {
+		case <-f.done:
+			if f.data != nil {
+				data := *f.data
+				q.data = &data
+			}
+			q.errs = append(q.errs, f.errs...)
+		case <-ctx.Done():
+			q.errs = append(q.errs, ctx.Err())
+		}
+	}
+}
+
+// refreshInBackground refetches stale quotes after the request that found them has been answered. Keys
+// already being fetched are skipped.
+func (s *PriceService) refreshInBackground(kind string, stale []*pendingQuote, currency string) {
+	var lead []*pendingQuote
+	var flights []*quoteFlight
+	for _, q := range stale {
+		if f, leader := s.flights.claim(q.cacheKey); leader {
+			lead = append(lead, &pendingQuote{kind: q.kind, sym: q.sym, cacheKey: q.cacheKey})
+			flights = append(flights, f)
+		}
+	}
+	if len(lead) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+		defer cancel()
+		s.fetchQuotes(ctx, kind, lead, currency)
+		for i, q := range lead {
+			s.flights.finish(q.cacheKey, flights[i], q.data, q.errs)
+		}
+	}()
+}
@@ -6,66 +6,96 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"net"
-	"net/http"
 	"strings"
 	"time"
 
 	"monity/internal/config"
 	"monity/internal/core/port"
+	"monity/internal/models"
 	"monity/internal/pkg/cache"
 )
 
-// cryptoIDMap maps ticker symbols to CoinGecko IDs.
-// Add more entries as needed.
-var cryptoIDMap = map[string]string{
-	"BTC":   "bitcoin",
-	"ETH":   "ethereum",
-	"SOL":   "solana",
-	"USDT":  "tether",
-	"BNB":   "binancecoin",
-	"XRP":   "ripple",
-	"ADA":   "cardano",
-	"DOGE":  "dogecoin",
-	"AVAX":  "avalanche-2",
-	"DOT":   "polkadot",
-	"MATIC": "matic-network",
-	"LINK":  "chainlink",
-	"ATOM":  "cosmos",
-	"UNI":   "uniswap",
-	"LTC":   "litecoin",
-}
-
+// PriceService answers price and chart lookups from a chain of port.PriceProvider per kind,
+// trying the next provider when one fails, and caches the answers. Currency conversion goes through fx.
 type PriceService struct {
-	cfg        *config.PriceAPIConfig
-	httpClient *http.Client
-	cache      cache.Cache
+	cfg         *config.PriceAPIConfig
+	cache       cache.Cache
+	instruments port.InstrumentRegistry
+	fx          port.FXService
+	providers   map[string][]port.PriceProvider // by kind, in fallback order
+	flights     flightGroup
 }
 
-func NewPriceService(cfg *config.PriceAPIConfig, c cache.Cache) port.PriceService {
+func NewPriceService(cfg *config.PriceAPIConfig, c cache.Cache, instruments port.InstrumentRegistry, fx port.FXService, providers ...port.PriceProvider) port.PriceService {
 	if c == nil {
 		c = cache.NewMemoryCache()
 	}
-	// Force IPv4 to avoid IPv6 connection issues with some API providers (e.g. CoinGecko/Cloudflare)
-	dialer := &net.Dialer{Timeout: 10 * time.Second}
-	transport := &http.Transport{
-		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-			return dialer.DialContext(ctx, "tcp4", addr)
+	return &PriceService{
+		cfg:         cfg,
+		cache:       c,
+		instruments: instruments,
+		fx:          fx,
+		providers: map[string][]port.PriceProvider{
+			port.PriceKindCrypto: orderProviders(providers, port.PriceKindCrypto, cfg.CryptoProviders),
+			port.PriceKindStock:  orderProviders(providers, port.PriceKindStock, cfg.StockProviders),
+			port.PriceKindFX:     orderProviders(providers, port.PriceKindFX, cfg.FXProviders),
 		},
-		MaxIdleConns:        10,
-		IdleConnTimeout:     30 * time.Second,
-		DisableCompression:  false,
-		TLSHandshakeTimeout: 10 * time.Second,
 	}
+}
 
-	return &PriceService{
-		cfg: cfg,
-		httpClient: &http.Client{
-			Timeout:   15 * time.Second,
-			Transport: transport,
-		},
-		cache: c,
+// orderProviders returns the providers that support kind. With a configured order only the named
+// providers are used, in that order; without one every supporting provider is used in registration order.
+func orderProviders(providers []port.PriceProvider, kind string, order []string) []port.PriceProvider {
+	var out []port.PriceProvider
+	if len(order) == 0 {
+		for _, p := range providers {
+			if p.Supports(kind) {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	for _, name := range order {
+		found := false
+		for _, p := range providers {
+			if strings.EqualFold(p.Name(), name) {
+				found = true
+				if p.Supports(kind) {
+					out = append(out, p)
+				}
+				break
+			}
+		}
+		if !found {
+			slog.Warn("unknown price provider in config", "kind", kind, "provider", name)
+		}
 	}
+	return out
+}
+
+// firstAnswer calls providers in order until one succeeds and returns its result with the provider's name.
+// Failures are logged and joined into the returned error; ErrProviderUnsupported is skipped quietly.
+func firstAnswer[T any](providers []port.PriceProvider, kind, symbol string, call func(p port.PriceProvider) (T, error)) (T, string, error) {
+	var zero T
+	var errs []error
+	for _, p := range providers {
+		out, err := call(p)
+		if err == nil {
+			if len(errs) > 0 {
+				slog.Info("price_provider_fallback", "kind", kind, "symbol", symbol, "provider", p.Name(), "failed", len(errs))
+			}
+			return out, p.Name(), nil
+		}
+		if errors.Is(err, port.ErrProviderUnsupported) {
+			continue
+		}
+		slog.Warn("price_api_error", "kind", kind, "symbol", symbol, "source", p.Name(), "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name(), err))
+	}
+	if len(errs) == 0 {
+		return zero, "", fmt.Errorf("no %s price provider available for %s", strings.ToLower(kind), symbol)
+	}
+	return zero, "", errors.Join(errs...)
 }
 
 func (s *PriceService) GetPrice(ctx context.Context, assetType string, symbol string) (*port.PriceData, error) {
@@ -74,421 +104,128 @@ func (s *PriceService) GetPrice(ctx context.Context, assetType string, symbol st
 
 func (s *PriceService) GetPriceWithCurrency(ctx context.Context, assetType string, symbol string, currency string) (*port.PriceData, error) {
 	switch strings.ToUpper(assetType) {
-	case "CRYPTO":
+	case port.PriceKindCrypto:
 		return s.GetCryptoPriceWithCurrency(ctx, symbol, currency)
-	case "STOCK":
+	case port.PriceKindStock:
 		return s.GetStockPriceWithCurrency(ctx, symbol, currency)
 	default:
 		return nil, errors.New("unsupported asset type for price lookup")
 	}
 }
 
-// ---------------------------------------------------------------------------
-// Crypto — CoinGecko (free, no API key needed)
-// https://api.coingecko.com/api/v3/simple/price?ids=solana&vs_currencies=usd
-// ---------------------------------------------------------------------------
+// GetAssetPrice quotes a CRYPTO or STOCK asset in currency. A provider ID pinned on the asset is passed to
+// the providers so an ambiguous ticker is quoted for the right instrument; a stock without one is quoted
+// under the provider symbol of its listing in the asset's currency.
+func (s *PriceService) GetAssetPrice(ctx context.Context, asset *models.Asset, currency string) (*port.PriceData, error) {
+	if asset == nil || asset.Symbol == nil || *asset.Symbol == "" {
+		return nil, errors.New("asset has no symbol for price lookup")
+	}
+	req, ok := assetSymbolRequest(asset)
+	if !ok {
+		return nil, errors.New("unsupported asset type for price lookup")
+	}
+	return s.quote(ctx, req, currency)
+}
 
 func (s *PriceService) GetCryptoPrice(ctx context.Context, symbol string) (*port.PriceData, error) {
 	return s.GetCryptoPriceWithCurrency(ctx, symbol, port.DefaultCurrency)
 }
 
 func (s *PriceService) GetCryptoPriceWithCurrency(ctx context.Context, symbol string, currency string) (*port.PriceData, error) {
-	symbol = strings.ToUpper(symbol)
-	currency = strings.ToUpper(currency)
-	if currency == "" {
-		currency = port.DefaultCurrency
-	}
-
-	cacheKey := fmt.Sprintf("crypto:%s:%s", symbol, currency)
-	if cached := s.getFromCache(ctx, cacheKey); cached != nil {
-		slog.Debug("cache_hit", "key", cacheKey)
-		return cached, nil
-	}
-	slog.Debug("cache_miss", "key", cacheKey)
-
-	// Map ticker to CoinGecko id (e.g. SOL -> solana)
-	coinID, ok := cryptoIDMap[symbol]
-	if !ok {
-		return nil, fmt.Errorf("unsupported crypto symbol: %s (add to cryptoIDMap)", symbol)
-	}
-
-	vsCurrency := strings.ToLower(currency)
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/simple/price?ids=%s&vs_currencies=%s", coinID, vsCurrency)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		slog.Warn("price_api_error", "symbol", symbol, "source", "coingecko", "error", err)
-		return nil, fmt.Errorf("fetch crypto price: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		slog.Warn("price_api_error", "symbol", symbol, "source", "coingecko", "status", resp.StatusCode)
-		return nil, fmt.Errorf("coingecko API returned status %d", resp.StatusCode)
-	}
-
-	// Response: {"solana":{"usd":86.59}}
-	var result map[string]map[string]float64
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-
-	coinData, ok := result[coinID]
-	if !ok {
-		return nil, fmt.Errorf("price not found for %s", symbol)
-	}
-
-	price, ok := coinData[vsCurrency]
-	if !ok {
-		return nil, fmt.Errorf("price in %s not found for %s", currency, symbol)
-	}
-
-	priceData := &port.PriceData{
-		Symbol:    symbol,
-		Price:     price,
-		Currency:  currency,
-		Source:    "coingecko",
-		FetchedAt: time.Now(),
-	}
-
-	s.setCache(ctx, cacheKey, priceData)
-	slog.Info("price_fetched", "symbol", symbol, "price", price, "source", "coingecko")
-	return priceData, nil
+	return s.quote(ctx, port.SymbolRequest{Kind: port.PriceKindCrypto, Symbol: symbol}, currency)
 }
 
 // ---------------------------------------------------------------------------
-// Stock — Yahoo Finance (free, no API key needed)
-// https://query1.finance.yahoo.com/v8/finance/chart/BBRI.JK?interval=1d&range=1d
-// IDX stocks need .JK suffix (e.g. BBRI -> BBRI.JK, BBCA -> BBCA.JK)
+// Stocks
+// Where a ticker is listed, and the symbol Yahoo quotes it under, comes from the instrument registry.
 // ---------------------------------------------------------------------------
 
-// IDXStocks lists common IDX (Jakarta) stock tickers that need .JK suffix.
-// Exported so other services (portfolio) can check lot-based calculation.
-var IDXStocks = map[string]bool{
-	"BBRI": true, "BBCA": true, "BMRI": true, "BBNI": true, "BRIS": true,
-	"TLKM": true, "ASII": true, "UNVR": true, "HMSP": true, "GGRM": true,
-	"ICBP": true, "INDF": true, "KLBF": true, "PGAS": true, "SMGR": true,
-	"ANTM": true, "PTBA": true, "ADRO": true, "ITMG": true, "INCO": true,
-	"EXCL": true, "ISAT": true, "TOWR": true, "MNCN": true, "SIDO": true,
-	"EMTK": true, "BUKA": true, "GOTO": true, "ACES": true, "MDKA": true,
-}
-
-// IDXLotSize is the number of shares per lot in the Indonesian stock exchange.
-const IDXLotSize = 100
-
-// IsIDXStock checks if a ticker is a known IDX stock.
-func IsIDXStock(symbol string) bool {
-	return IDXStocks[strings.ToUpper(symbol)]
-}
-
 func (s *PriceService) GetStockPrice(ctx context.Context, symbol string) (*port.PriceData, error) {
 	return s.GetStockPriceWithCurrency(ctx, symbol, port.DefaultCurrency)
 }
 
 func (s *PriceService) GetStockPriceWithCurrency(ctx context.Context, symbol string, currency string) (*port.PriceData, error) {
-	symbol = strings.ToUpper(symbol)
-	currency = strings.ToUpper(currency)
-	if currency == "" {
-		currency = port.DefaultCurrency
-	}
-
-	cacheKey := fmt.Sprintf("stock:%s:%s", symbol, currency)
-	if cached := s.getFromCache(ctx, cacheKey); cached != nil {
-		slog.Debug("cache_hit", "key", cacheKey)
-		return cached, nil
-	}
-	slog.Debug("cache_miss", "key", cacheKey)
-
-	// Auto-append .JK for known IDX stocks
-	yahooSymbol := symbol
-	if IDXStocks[symbol] {
-		yahooSymbol = symbol + ".JK"
-	}
-
-	url := fmt.Sprintf("%s/v8/finance/chart/%s?interval=1d&range=1d", s.cfg.StockAPI, yahooSymbol)
+	return s.quote(ctx, port.SymbolRequest{Kind: port.PriceKindStock, Symbol: symbol}, currency)
+}
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+// quote is GetPrices for a single symbol.
+func (s *PriceService) quote(ctx context.Context, req port.SymbolRequest, currency string) (*port.PriceData, error) {
+	results, err := s.GetPrices(ctx, []port.SymbolRequest{req}, currency)
+	if len(results) == 1 && results[0].Data != nil {
+		return results[0].Data, nil
 	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "Mozilla/5.0")
-
-	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		slog.Warn("price_api_error", "symbol", yahooSymbol, "source", "yahoo", "error", err)
-		return nil, fmt.Errorf("fetch stock price: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		slog.Warn("price_api_error", "symbol", yahooSymbol, "source", "yahoo", "status", resp.StatusCode)
-		return nil, fmt.Errorf("stock API returned status %d for %s", resp.StatusCode, yahooSymbol)
-	}
-
-	var result struct {
-		Chart struct {
-			Result []struct {
-				Meta struct {
-					RegularMarketPrice float64 `json:"regularMarketPrice"`
-					Currency           string  `json:"currency"`
-				} `json:"meta"`
-			} `json:"result"`
-			Error *struct {
-				Code        string `json:"code"`
-				Description string `json:"description"`
-			} `json:"error"`
-		} `json:"chart"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-
-	if result.Chart.Error != nil {
-		return nil, fmt.Errorf("yahoo finance error: %s", result.Chart.Error.Description)
-	}
-
-	if len(result.Chart.Result) == 0 {
-		return nil, fmt.Errorf("no price data found for %s", yahooSymbol)
+		return nil, err
 	}
-
-	meta := result.Chart.Result[0].Meta
-	price := meta.RegularMarketPrice
-	sourceCurrency := strings.ToUpper(meta.Currency)
-
-	// Convert currency if needed (e.g. BBRI returns IDR, but user wants USD)
-	if currency != sourceCurrency {
-		exchangeRate, err := s.getExchangeRate(ctx, sourceCurrency, currency)
-		if err != nil {
-			// If conversion fails, return in source currency
-			return &port.PriceData{
-				Symbol:    symbol,
-				Price:     price,
-				Currency:  sourceCurrency,
-				Source:    "yahoo",
-				FetchedAt: time.Now(),
-			}, nil
-		}
-		price = price * exchangeRate
-	}
-
-	priceData := &port.PriceData{
-		Symbol:    symbol,
-		Price:     price,
-		Currency:  currency,
-		Source:    "yahoo",
-		FetchedAt: time.Now(),
-	}
-
-	s.setCache(ctx, cacheKey, priceData)
-	slog.Info("price_fetched", "symbol", symbol, "price", price, "source", "yahoo")
-	return priceData, nil
+	return nil, results[0].Err
 }
 
 // ---------------------------------------------------------------------------
-// Exchange rate — Yahoo Finance (e.g. USDIDR=X)
+// Exchange rate
 // ---------------------------------------------------------------------------
 
-func (s *PriceService) getExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (float64, error) {
-	cacheKey := fmt.Sprintf("fx:%s:%s", fromCurrency, toCurrency)
-
-	if cached := s.getFromCache(ctx, cacheKey); cached != nil {
-		return cached.Price, nil
-	}
-
-	fxSymbol := fmt.Sprintf("%s%s=X", fromCurrency, toCurrency)
-	url := fmt.Sprintf("%s/v8/finance/chart/%s?interval=1d&range=1d", s.cfg.StockAPI, fxSymbol)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return 0, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "Mozilla/5.0")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return 0, fmt.Errorf("fetch exchange rate: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return 0, fmt.Errorf("exchange rate API returned status %d", resp.StatusCode)
-	}
-
-	var result struct {
-		Chart struct {
-			Result []struct {
-				Meta struct {
-					RegularMarketPrice float64 `json:"regularMarketPrice"`
-				} `json:"meta"`
-			} `json:"result"`
-			Error *struct {
-				Code        string `json:"code"`
-				Description string `json:"description"`
-			} `json:"error"`
-		} `json:"chart"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return 0, fmt.Errorf("decode response: %w", err)
-	}
-
-	if result.Chart.Error != nil {
-		return 0, fmt.Errorf("exchange rate error: %s", result.Chart.Error.Description)
-	}
-
-	if len(result.Chart.Result) == 0 {
-		return 0, fmt.Errorf("exchange rate not found for %s to %s", fromCurrency, toCurrency)
-	}
-
-	rate := result.Chart.Result[0].Meta.RegularMarketPrice
-
-	s.setCache(ctx, cacheKey, &port.PriceData{
-		Symbol:    fxSymbol,
-		Price:     rate,
-		Currency:  toCurrency,
-		Source:    "yahoo",
-		FetchedAt: time.Now(),
-	})
-
-	return rate, nil
+// GetExchangeRate returns how many units of toCurrency one unit of fromCurrency buys.
+func (s *PriceService) GetExchangeRate(ctx context.Context, fromCurrency, toCurrency string) (float64, error) {
+	return s.fx.Rate(ctx, fromCurrency, toCurrency)
 }
 
 // ---------------------------------------------------------------------------
-// Historical — CoinGecko (simplified, no CMC key needed)
+// Historical crypto prices
 // ---------------------------------------------------------------------------
 
-func (s *PriceService) GetHistoricalCryptoPrice(ctx context.Context, symbol string, timestamp time.Time) (*port.PriceData, error) {
+func (s *PriceService) GetHistoricalCryptoPrice(ctx context.Context, symbol, currency string, timestamp time.Time) (*port.PriceData, error) {
 	symbol = strings.ToUpper(symbol)
-
-	coinID, ok := cryptoIDMap[symbol]
-	if !ok {
-		return nil, fmt.Errorf("unsupported crypto symbol: %s", symbol)
-	}
-
-	// CoinGecko history endpoint: /coins/{id}/history?date=dd-mm-yyyy
-	dateStr := timestamp.UTC().Format("02-01-2006")
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/%s/history?date=%s&localization=false", coinID, dateStr)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := s.httpClient.Do(req)
+	currency = chartCurrency(currency)
+	priceData, _, err := firstAnswer(s.providers[port.PriceKindCrypto], port.PriceKindCrypto, symbol, func(p port.PriceProvider) (*port.PriceData, error) {
+		return p.HistoricalPrice(ctx, port.PriceKindCrypto, symbol, currency, timestamp)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("fetch historical crypto price: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("coingecko history API returned status %d", resp.StatusCode)
-	}
-
-	var result struct {
-		MarketData struct {
-			CurrentPrice map[string]float64 `json:"current_price"`
-		} `json:"market_data"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-
-	price, ok := result.MarketData.CurrentPrice["idr"]
-	if !ok {
-		return nil, fmt.Errorf("historical price not found for %s at %s", symbol, dateStr)
+	if from := strings.ToUpper(priceData.Currency); from != "" && from != currency {
+		rate, err := s.fx.RateAt(ctx, from, currency, timestamp)
+		if err != nil {
+			return nil, fmt.Errorf("convert historical crypto price: %w", err)
+		}
+		priceData.Price *= rate
+		priceData.Currency = currency
 	}
-
-	return &port.PriceData{
-		Symbol:    symbol,
-		Price:     price,
-		Currency:  port.DefaultCurrency,
-		Source:    "coingecko",
-		FetchedAt: timestamp,
-	}, nil
+	return priceData, nil
 }
 
-func (s *PriceService) GetHistoricalCryptoOHLCV(ctx context.Context, symbol string, timeStart, timeEnd time.Time, interval string) ([]port.OHLCVData, error) {
+func (s *PriceService) GetHistoricalCryptoOHLCV(ctx context.Context, symbol, currency string, timeStart, timeEnd time.Time, interval string) ([]port.OHLCVData, error) {
 	symbol = strings.ToUpper(symbol)
-
-	coinID, ok := cryptoIDMap[symbol]
-	if !ok {
-		return nil, fmt.Errorf("unsupported crypto symbol: %s", symbol)
-	}
-
-	// CoinGecko OHLC endpoint: /coins/{id}/ohlc?vs_currency=usd&days=30
-	days := int(timeEnd.Sub(timeStart).Hours()/24) + 1
-	if days < 1 {
-		days = 1
-	}
-	if days > 365 {
-		days = 365
-	}
-
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/%s/ohlc?vs_currency=idr&days=%d", coinID, days)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-
-	resp, err := s.httpClient.Do(req)
+	currency = chartCurrency(currency)
+	candles, _, err := firstAnswer(s.providers[port.PriceKindCrypto], port.PriceKindCrypto, symbol, func(p port.PriceProvider) ([]port.OHLCVData, error) {
+		return p.OHLCV(ctx, port.PriceKindCrypto, symbol, currency, timeStart, timeEnd)
+	})
 	if err != nil {
 		return nil, fmt.Errorf("fetch historical OHLCV: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("coingecko OHLC API returned status %d", resp.StatusCode)
-	}
-
-	// Response: [[timestamp, open, high, low, close], ...]
-	var rawData [][]float64
-	if err := json.NewDecoder(resp.Body).Decode(&rawData); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
-	}
-
-	ohlcvData := make([]port.OHLCVData, 0, len(rawData))
-	for _, candle := range rawData {
-		if len(candle) < 5 {
+	for i := range candles {
+		c := &candles[i]
+		from := strings.ToUpper(c.Currency)
+		if from == "" || from == currency {
 			continue
 		}
-		ts := time.UnixMilli(int64(candle[0]))
-		ohlcvData = append(ohlcvData, port.OHLCVData{
-			Symbol:    symbol,
-			TimeOpen:  ts,
-			TimeClose: ts,
-			Open:      candle[1],
-			High:      candle[2],
-			Low:       candle[3],
-			Close:     candle[4],
-			Volume:    0, // CoinGecko OHLC doesn't include volume
-			Currency:  port.DefaultCurrency,
-			Source:    "coingecko",
-		})
-	}
-
-	return ohlcvData, nil
+		rate, err := s.fx.RateAt(ctx, from, currency, c.TimeOpen)
+		if err != nil {
+			return nil, fmt.Errorf("convert historical OHLCV: %w", err)
+		}
+		c.Open, c.High, c.Low, c.Close = c.Open*rate, c.High*rate, c.Low*rate, c.Close*rate
+		c.MarketCap *= rate
+		c.Currency = currency
+	}
+	return candles, nil
 }
 
 // ---------------------------------------------------------------------------
-// Crypto chart — CoinGecko market_chart
+// Charts
 // ---------------------------------------------------------------------------
 
 const (
-	chartCacheTTL   = 15 * time.Minute
-	maxChartPoints  = 200
+	chartCacheTTL  = 15 * time.Minute
+	maxChartPoints = 200
 )
 
 func (s *PriceService) GetCryptoChart(ctx context.Context, symbol string, currency string, days int) (*port.ChartResponse, error) {
@@ -504,49 +241,80 @@ func (s *PriceService) GetCryptoChart(ctx context.Context, symbol string, curren
 		return cached, nil
 	}
 
-	coinID, ok := cryptoIDMap[symbol]
-	if !ok {
-		return nil, fmt.Errorf("unsupported crypto symbol: %s", symbol)
+	req := port.ChartRequest{Currency: currency, Days: days}
+	out, _, err := firstAnswer(s.providers[port.PriceKindCrypto], port.PriceKindCrypto, symbol, func(p port.PriceProvider) (*port.ChartResponse, error) {
+		return p.Chart(ctx, port.PriceKindCrypto, symbol, req)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("fetch crypto chart: %w", err)
 	}
+	if err := s.convertChart(ctx, out, currency); err != nil {
+		return nil, fmt.Errorf("convert crypto chart: %w", err)
+	}
+	out.Data = downsampleChartData(out.Data, maxChartPoints)
+	s.setChartCache(ctx, cacheKey, out)
+	return out, nil
+}
 
-	vsCurrency := strings.ToLower(currency)
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/%s/market_chart?vs_currency=%s&days=%d", coinID, vsCurrency, days)
+// GetStockChart charts symbol in its listing currency, or in currency when one is given.
+func (s *PriceService) GetStockChart(ctx context.Context, symbol string, rangeParam string, interval string, currency string) (*port.ChartResponse, error) {
+	symbol = strings.ToUpper(symbol)
+	currency = strings.ToUpper(strings.TrimSpace(currency))
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+	cacheKey := fmt.Sprintf("chart:stock:%s:%s:%s:%s", symbol, rangeParam, interval, currency)
+	if cached := s.getChartFromCache(ctx, cacheKey); cached != nil {
+		slog.Debug("cache_hit", "key", cacheKey)
+		return cached, nil
 	}
-	req.Header.Set("Accept", "application/json")
 
-	resp, err := s.httpClient.Do(req)
+	// currency also picks the listing of a ticker traded on several exchanges.
+	req := port.ChartRequest{Currency: currency, Range: rangeParam, Interval: interval}
+	out, _, err := firstAnswer(s.providers[port.PriceKindStock], port.PriceKindStock, symbol, func(p port.PriceProvider) (*port.ChartResponse, error) {
+		return p.Chart(ctx, port.PriceKindStock, symbol, req)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("fetch crypto chart: %w", err)
+		return nil, fmt.Errorf("fetch stock chart: %w", err)
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("coingecko market_chart API returned status %d", resp.StatusCode)
+	if currency != "" {
+		if err := s.convertChart(ctx, out, currency); err != nil {
+			return nil, fmt.Errorf("convert stock chart: %w", err)
+		}
 	}
+	out.Data = downsampleChartData(out.Data, maxChartPoints)
+	s.setChartCache(ctx, cacheKey, out)
+	return out, nil
+}
 
-	var result struct {
-		Prices [][]float64 `json:"prices"`
+// convertChart converts every point to currency at the rate of its day.
+func (s *PriceService) convertChart(ctx context.Context, chart *port.ChartResponse, currency string) error {
+	from := strings.ToUpper(chart.Currency)
+	if from == "" || from == currency {
+		return nil
 	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode chart response: %w", err)
+	rates := make(map[string]float64)
+	for i := range chart.Data {
+		at := time.Unix(chart.Data[i].T, 0).UTC()
+		day := at.Format("2006-01-02")
+		rate, ok := rates[day]
+		if !ok {
+			var err error
+			if rate, err = s.fx.RateAt(ctx, from, currency, at); err != nil {
+				return err
+			}
+			rates[day] = rate
+		}
+		chart.Data[i].P *= rate
 	}
+	chart.Currency = currency
+	return nil
+}
 
-	data := make([]port.ChartDataPoint, 0, len(result.Prices))
-	for _, pair := range result.Prices {
-		if len(pair) < 2 {
-			continue
-		}
-		ms := int64(pair[0])
-		data = append(data, port.ChartDataPoint{T: ms / 1000, P: pair[1]})
+// chartCurrency defaults an empty currency to port.DefaultCurrency.
+func chartCurrency(currency string) string {
+	if currency = strings.ToUpper(strings.TrimSpace(currency)); currency == "" {
+		return port.DefaultCurrency
 	}
-	data = downsampleChartData(data, maxChartPoints)
-	out := &port.ChartResponse{Symbol: symbol, Currency: currency, Data: data}
-	s.setChartCache(ctx, cacheKey, out)
-	return out, nil
+	return currency
 }
 
 // downsampleChartData returns at most max points with even stride (keeps first and last).
@@ -566,102 +334,6 @@ func downsampleChartData(data []port.ChartDataPoint, max int) []port.ChartDataPo
 	return out
 }
 
-// ---------------------------------------------------------------------------
-// Stock chart — Yahoo Finance chart
-// ---------------------------------------------------------------------------
-
-func (s *PriceService) GetStockChart(ctx context.Context, symbol string, rangeParam string, interval string) (*port.ChartResponse, error) {
-	symbol = strings.ToUpper(symbol)
-
-	yahooSymbol := symbol
-	if IDXStocks[symbol] {
-		yahooSymbol = symbol + ".JK"
-	}
-
-	cacheKey := fmt.Sprintf("chart:stock:%s:%s:%s", yahooSymbol, rangeParam, interval)
-	if cached := s.getChartFromCache(ctx, cacheKey); cached != nil {
-		slog.Debug("cache_hit", "key", cacheKey)
-		return cached, nil
-	}
-
-	url := fmt.Sprintf("%s/v8/finance/chart/%s?range=%s&interval=%s", s.cfg.StockAPI, yahooSymbol, rangeParam, interval)
-
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
-	}
-	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "Mozilla/5.0")
-
-	resp, err := s.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("fetch stock chart: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("stock chart API returned status %d", resp.StatusCode)
-	}
-
-	var result struct {
-		Chart struct {
-			Result []struct {
-				Meta struct {
-					Currency string `json:"currency"`
-				} `json:"meta"`
-				Timestamp []int64   `json:"timestamp"`
-				Indicators struct {
-					Quote []struct {
-						Close []float64 `json:"close"`
-					} `json:"quote"`
-				} `json:"indicators"`
-			} `json:"result"`
-			Error *struct {
-				Description string `json:"description"`
-			} `json:"error"`
-		} `json:"chart"`
-	}
-
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode chart response: %w", err)
-	}
-
-	if result.Chart.Error != nil {
-		return nil, fmt.Errorf("yahoo chart error: %s", result.Chart.Error.Description)
-	}
-
-	if len(result.Chart.Result) == 0 {
-		return nil, fmt.Errorf("no chart data found for %s", yahooSymbol)
-	}
-
-	r := result.Chart.Result[0]
-	currency := strings.ToUpper(r.Meta.Currency)
-	if currency == "" {
-		currency = port.DefaultCurrency
-	}
-
-	times := r.Timestamp
-	quotes := r.Indicators.Quote
-	if len(quotes) == 0 || len(quotes[0].Close) == 0 {
-		return nil, fmt.Errorf("no quote data for %s", yahooSymbol)
-	}
-	closes := quotes[0].Close
-
-	n := len(times)
-	if len(closes) < n {
-		n = len(closes)
-	}
-
-	data := make([]port.ChartDataPoint, 0, n)
-	for i := 0; i < n; i++ {
-		data = append(data, port.ChartDataPoint{T: times[i], P: closes[i]})
-	}
-	data = downsampleChartData(data, maxChartPoints)
-	out := &port.ChartResponse{Symbol: symbol, Currency: currency, Data: data}
-	s.setChartCache(ctx, cacheKey, out)
-	return out, nil
-}
-
 // ---------------------------------------------------------------------------
 // Cache helpers
 // ---------------------------------------------------------------------------
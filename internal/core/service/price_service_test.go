@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"monity/internal/config"
+	"monity/internal/core/port"
+	"monity/internal/pkg/cache"
+)
+
+// stubProvider is a PriceProvider whose Quote returns a fixed price or error.
+type stubProvider struct {
+	name  string
+	kinds []string
+	price float64
+	err   error
+}
+
+func (p *stubProvider) Name() string { return p.name }
+
+func (p *stubProvider) Supports(kind string) bool {
+	for _, k := range p.kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *stubProvider) Quote(ctx context.Context, kind string, sym port.PriceSymbol, currency string) (*port.PriceData, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &port.PriceData{Symbol: sym.Ticker, Price: p.price, Currency: currency, Source: p.name}, nil
+}
+
+func (p *stubProvider) HistoricalPrice(ctx context.Context, kind, symbol, currency string, at time.Time) (*port.PriceData, error) {
+	return nil, port.ErrProviderUnsupported
+}
+
+func (p *stubProvider) OHLCV(ctx context.Context, kind, symbol, currency string, from, to time.Time) ([]port.OHLCVData, error) {
+	return nil, port.ErrProviderUnsupported
+}
+
+func (p *stubProvider) Chart(ctx context.Context, kind, symbol string, req port.ChartRequest) (*port.ChartResponse, error) {
+	return nil, port.ErrProviderUnsupported
+}
+
+func (p *stubProvider) ExchangeRate(ctx context.Context, from, to string) (float64, error) {
+	return 0, port.ErrProviderUnsupported
+}
+
+func providerNames(ps []port.PriceProvider) []string {
+	names := make([]string, len(ps))
+	for i, p := range ps {
+		names[i] = p.Name()
+	}
+	return names
+}
+
+func Test_orderProviders(t *testing.T) {
+	gecko := &stubProvider{name: "coingecko", kinds: []string{port.PriceKindCrypto}}
+	yahoo := &stubProvider{name: "yahoo", kinds: []string{port.PriceKindStock, port.PriceKindFX}}
+	cmc := &stubProvider{name: "coinmarketcap", kinds: []string{port.PriceKindCrypto}}
+	all := []port.PriceProvider{gecko, yahoo, cmc}
+
+	tests := []struct {
+		name  string
+		kind  string
+		order []string
+		want  []string
+	}{
+		{"registration order without config", port.PriceKindCrypto, nil, []string{"coingecko", "coinmarketcap"}},
+		{"configured order", port.PriceKindCrypto, []string{"coinmarketcap", "coingecko"}, []string{"coinmarketcap", "coingecko"}},
+		{"configured subset", port.PriceKindCrypto, []string{"CoinMarketCap"}, []string{"coinmarketcap"}},
+		{"unsupported and unknown names skipped", port.PriceKindStock, []string{"coingecko", "nope", "yahoo"}, []string{"yahoo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := providerNames(orderProviders(all, tt.kind, tt.order))
+			if len(got) != len(tt.want) {
+				t.Fatalf("orderProviders() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("orderProviders() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_firstAnswer(t *testing.T) {
+	down := &stubProvider{name: "down", err: errors.New("status 503")}
+	skip := &stubProvider{name: "skip", err: port.ErrProviderUnsupported}
+	up := &stubProvider{name: "up", price: 42}
+	quote := func(p port.PriceProvider) (*port.PriceData, error) {
+		return p.Quote(context.Background(), port.PriceKindCrypto, port.PriceSymbol{Ticker: "BTC"}, "IDR")
+	}
+
+	data, source, err := firstAnswer([]port.PriceProvider{down, skip, up}, port.PriceKindCrypto, "BTC", quote)
+	if err != nil || source != "up" || data.Price != 42 {
+		t.Errorf("fallback: got %v, %q, %v; want price 42 from up", data, source, err)
+	}
+
+	if _, _, err := firstAnswer([]port.PriceProvider{down, skip}, port.PriceKindCrypto, "BTC", quote); err == nil || !errors.Is(err, down.err) {
+		t.Errorf("all failing: got err %v, want it to wrap %v", err, down.err)
+	}
+
+	if _, _, err := firstAnswer([]port.PriceProvider{skip}, port.PriceKindCrypto, "BTC", quote); err == nil {
+		t.Error("only unsupported providers: expected an error")
+	}
+}
+
+// batchStub is a BatchQuoter that knows a fixed set of tickers and counts its calls.
+type batchStub struct {
+	stubProvider
+	prices map[string]float64
+	calls  int
+}
+
+func (p *batchStub) QuoteMany(ctx context.Context, kind string, syms []port.PriceSymbol, currency string) (map[port.PriceSymbol]*port.PriceData, error) {
+	p.calls++
+	out := make(map[port.PriceSymbol]*port.PriceData)
+	for _, sym := range syms {
+		if price, ok := p.prices[sym.Ticker]; ok {
+			out[sym] = &port.PriceData{Symbol: sym.Ticker, Price: price, Currency: currency, Source: p.name}
+		}
+	}
+	return out, nil
+}
+
+func TestPriceService_GetPrices(t *testing.T) {
+	batch := &batchStub{
+		stubProvider: stubProvider{name: "batch", kinds: []string{port.PriceKindCrypto}},
+		prices:       map[string]float64{"BTC": 100, "ETH": 10},
+	}
+	single := &stubProvider{name: "single", kinds: []string{port.PriceKindCrypto, port.PriceKindStock}, price: 5}
+	svc := NewPriceService(&config.PriceAPIConfig{}, nil, nil, nil, batch, single)
+
+	reqs := []port.SymbolRequest{
+		{Kind: port.PriceKindCrypto, Symbol: "btc"},
+		{Kind: port.PriceKindCrypto, Symbol: "ETH"},
+		{Kind: port.PriceKindCrypto, Symbol: "BTC"},
+		{Kind: port.PriceKindCrypto, Symbol: "DOGE"},
+		{Kind: port.PriceKindStock, Symbol: "BBCA"},
+		{Kind: "CASH", Symbol: "IDR"},
+	}
+	results, err := svc.GetPrices(context.Background(), reqs, "idr")
+	if err != nil {
+		t.Fatalf("GetPrices() error = %v", err)
+	}
+	want := []struct {
+		price  float64
+		source string
+	}{{100, "batch"}, {10, "batch"}, {100, "batch"}, {5, "single"}, {5, "single"}}
+	for i, w := range want {
+		got := results[i]
+		if got.Err != nil || got.Data == nil || got.Data.Price != w.price || got.Data.Source != w.source {
+			t.Errorf("results[%d] = %+v, want price %v from %s", i, got, w.price, w.source)
+		}
+	}
+	if results[5].Err == nil {
+		t.Error("results[5]: expected an error for an unsupported kind")
+	}
+	if batch.calls != 1 {
+		t.Errorf("batch provider called %d times, want 1", batch.calls)
+	}
+
+	// A second call is answered from the cache.
+	if _, err := svc.GetPrices(context.Background(), reqs[:2], "IDR"); err != nil || batch.calls != 1 {
+		t.Errorf("cached call: err %v, batch calls %d; want no new calls", err, batch.calls)
+	}
+}
+
+func TestPriceService_GetPrices_staleAndLastGood(t *testing.T) {
+	provider := &stubProvider{name: "up", kinds: []string{port.PriceKindCrypto}, price: 200}
+	c := cache.NewMemoryCache()
+	svc := NewPriceService(&config.PriceAPIConfig{CacheTTL: 60, CacheStaleTTL: 600}, c, nil, nil, provider).(*PriceService)
+	req := []port.SymbolRequest{{Kind: port.PriceKindCrypto, Symbol: "BTC"}}
+	key := quoteCacheKey(port.PriceKindCrypto, port.PriceSymbol{Ticker: "BTC"}, "IDR")
+	store := func(age time.Duration) {
+		raw, _ := json.Marshal(cachedQuote{Data: port.PriceData{Symbol: "BTC", Price: 100, Currency: "IDR"}, StoredAt: time.Now().Add(-age)})
+		_ = c.Set(context.Background(), key, raw, time.Hour)
+	}
+
+	// Within the stale window the old quote is served, flagged, and refreshed in the background.
+	store(2 * time.Minute)
+	results, _ := svc.GetPrices(context.Background(), req, "IDR")
+	if d := results[0].Data; d == nil || d.Price != 100 || !d.Stale {
+		t.Fatalf("stale entry: got %+v, want price 100 flagged stale", results[0])
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if d, state := svc.getQuoteEntry(context.Background(), key); state == quoteFresh && d.Price == 200 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("background refresh did not update the cache")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Past the stale window the quote is refetched; when that fails the last good quote is served.
+	store(time.Hour)
+	provider.err = errors.New("status 503")
+	results, _ = svc.GetPrices(context.Background(), req, "IDR")
+	if d := results[0].Data; d == nil || d.Price != 100 || !d.Stale {
+		t.Errorf("last good: got %+v, want price 100 flagged stale", results[0])
+	}
+}
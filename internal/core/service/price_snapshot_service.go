@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+type PriceSnapshotService struct {
+	assetRepo    port.AssetRepository
+	historyRepo  port.AssetPriceHistoryRepository
+	priceService port.PriceService
+}
+
+func NewPriceSnapshotService(assetRepo port.AssetRepository, historyRepo port.AssetPriceHistoryRepository, priceService port.PriceService) port.PriceSnapshotService {
+	return &PriceSnapshotService{assetRepo: assetRepo, historyRepo: historyRepo, priceService: priceService}
+}
+
+// snapshotKey identifies one price lookup shared by every asset with the same type, symbol, pinned
+// provider ID and currency.
+type snapshotKey struct {
+	assetType  models.AssetType
+	symbol     string
+	providerID string
+	currency   string
+}
+
+// SnapshotPrices fetches one price per distinct symbol/currency, batched per currency, and writes today's snapshot row for every
+// asset that holds it. Running it again on the same day replaces that day's rows with fresher prices.
+func (s *PriceSnapshotService) SnapshotPrices(ctx context.Context) (*port.PriceSnapshotResult, error) {
+	assets, err := s.assetRepo.ListActiveWithSymbol(ctx, []models.AssetType{models.AssetTypeCrypto, models.AssetTypeStock})
+	if err != nil {
+		return nil, fmt.Errorf("list assets for snapshot: %w", err)
+	}
+	keys, groups := groupForSnapshot(assets)
+	res := &port.PriceSnapshotResult{Assets: len(assets), Quotes: len(keys)}
+
+	now := time.Now().UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// One batched lookup per currency.
+	byCurrency := make(map[string][]snapshotKey)
+	var currencies []string
+	for _, k := range keys {
+		if _, ok := byCurrency[k.currency]; !ok {
+			currencies = append(currencies, k.currency)
+		}
+		byCurrency[k.currency] = append(byCurrency[k.currency], k)
+	}
+
+	var rows []models.AssetPriceHistory
+	for _, currency := range currencies {
+		batch := byCurrency[currency]
+		reqs := make([]port.SymbolRequest, len(batch))
+		for i, k := range batch {
+			reqs[i] = port.SymbolRequest{Kind: string(k.assetType), Symbol: k.symbol, ProviderID: k.providerID, Listing: k.currency}
+		}
+		results, err := s.priceService.GetPrices(ctx, reqs, currency)
+		if err != nil {
+			return res, err
+		}
+		for i, k := range batch {
+			priceData := results[i].Data
+			if priceData == nil {
+				res.Failed += len(groups[k])
+				slog.Warn("price_snapshot_failed", "type", k.assetType, "symbol", k.symbol, "currency", k.currency, "error", results[i].Err)
+				continue
+			}
+			recordedAt := priceData.FetchedAt
+			if recordedAt.IsZero() {
+				recordedAt = now
+			}
+			for _, assetID := range groups[k] {
+				rows = append(rows, models.AssetPriceHistory{
+					AssetID:      assetID,
+					Price:        decimal.NewFromFloat(priceData.Price),
+					Currency:     k.currency,
+					Source:       priceData.Source,
+					RecordedAt:   recordedAt,
+					SnapshotDate: &day,
+				})
+			}
+		}
+	}
+
+	if err := s.historyRepo.UpsertSnapshots(ctx, rows); err != nil {
+		return res, err
+	}
+	res.Recorded = len(rows)
+	slog.Info("price_snapshot_done", "assets", res.Assets, "quotes", res.Quotes, "recorded", res.Recorded, "failed", res.Failed)
+	if res.Quotes > 0 && res.Recorded == 0 {
+		return res, errors.New("no prices could be fetched")
+	}
+	return res, nil
+}
+
+// groupForSnapshot maps each distinct lookup to the IDs of the assets sharing it, keeping first-seen order.
+func groupForSnapshot(assets []models.Asset) ([]snapshotKey, map[snapshotKey][]int64) {
+	var keys []snapshotKey
+	groups := make(map[snapshotKey][]int64)
+	for i := range assets {
+		a := &assets[i]
+		if a.Symbol == nil || strings.TrimSpace(*a.Symbol) == "" {
+			continue
+		}
+		k := snapshotKey{
+			assetType: a.Type,
+			symbol:    strings.ToUpper(strings.TrimSpace(*a.Symbol)),
+			currency:  assetCurrency(a),
+		}
+		if a.PriceProviderID != nil {
+			k.providerID = *a.PriceProviderID
+		}
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], a.ID)
+	}
+	return keys, groups
+}
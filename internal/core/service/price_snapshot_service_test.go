@@ -0,0 +1,49 @@
+package service
+
+import (
+	"testing"
+
+	"monity/internal/models"
+)
+
+func Test_groupForSnapshot(t *testing.T) {
+	sym := func(s string) *string { return &s }
+	assets := []models.Asset{
+		{ID: 1, Type: models.AssetTypeCrypto, Symbol: sym("btc"), PurchaseCurrency: "IDR"},
+		{ID: 2, Type: models.AssetTypeCrypto, Symbol: sym(" BTC "), PurchaseCurrency: "idr"},
+		{ID: 3, Type: models.AssetTypeCrypto, Symbol: sym("BTC"), PurchaseCurrency: "USD"},
+		{ID: 4, Type: models.AssetTypeStock, Symbol: sym("BBCA"), PurchaseCurrency: ""},
+		{ID: 5, Type: models.AssetTypeStock, Symbol: sym("")},
+		{ID: 6, Type: models.AssetTypeCrypto, Symbol: sym("UNI"), PriceProviderID: sym("uniswap"), PurchaseCurrency: "IDR"},
+	}
+	keys, groups := groupForSnapshot(assets)
+
+	want := []struct {
+		key snapshotKey
+		ids []int64
+	}{
+		{snapshotKey{models.AssetTypeCrypto, "BTC", "", "IDR"}, []int64{1, 2}},
+		{snapshotKey{models.AssetTypeCrypto, "BTC", "", "USD"}, []int64{3}},
+		{snapshotKey{models.AssetTypeStock, "BBCA", "", "IDR"}, []int64{4}},
+		{snapshotKey{models.AssetTypeCrypto, "UNI", "uniswap", "IDR"}, []int64{6}},
+	}
+	if len(keys) != len(want) {
+		t.Fatalf("got %d lookups, want %d: %v", len(keys), len(want), keys)
+	}
+	for i, w := range want {
+		if keys[i] != w.key {
+			t.Errorf("keys[%d] = %v, want %v", i, keys[i], w.key)
+		}
+		got := groups[w.key]
+		if len(got) != len(w.ids) {
+			t.Errorf("groups[%v] = %v, want %v", w.key, got, w.ids)
+			continue
+		}
+		for j := range got {
+			if got[j] != w.ids[j] {
+				t.Errorf("groups[%v] = %v, want %v", w.key, got, w.ids)
+				break
+			}
+		}
+	}
+}
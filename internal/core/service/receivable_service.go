@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 	"time"
 
@@ -15,13 +16,14 @@ import (
 )
 
 type ReceivableService struct {
-	repo       port.ReceivableRepository
+	repo        port.ReceivableRepository
 	paymentRepo port.ReceivablePaymentRepository
-	assetRepo  port.AssetRepository
+	assetRepo   port.AssetRepository
+	uow         port.UnitOfWork
 }
 
-func NewReceivableService(repo port.ReceivableRepository, paymentRepo port.ReceivablePaymentRepository, assetRepo port.AssetRepository) port.ReceivableService {
-	return &ReceivableService{repo: repo, paymentRepo: paymentRepo, assetRepo: assetRepo}
+func NewReceivableService(repo port.ReceivableRepository, paymentRepo port.ReceivablePaymentRepository, assetRepo port.AssetRepository, uow port.UnitOfWork) port.ReceivableService {
+	return &ReceivableService{repo: repo, paymentRepo: paymentRepo, assetRepo: assetRepo, uow: uow}
 }
 
 func (s *ReceivableService) resolveAssetID(ctx context.Context, assetUUID *string, userID int64) (*int64, error) {
@@ -41,6 +43,14 @@ func (s *ReceivableService) resolveAssetID(ctx context.Context, assetUUID *strin
 	return &asset.ID, nil
 }
 
+// markOverdue brings the user's receivables up to date before a read, so status filters never lag the background sweep.
+func (s *ReceivableService) markOverdue(ctx context.Context, userID int64) error {
+	if _, err := s.repo.MarkOverdue(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("mark overdue receivables: %w", err)
+	}
+	return nil
+}
+
 func (s *ReceivableService) CreateReceivable(ctx context.Context, userID int64, req port.CreateReceivableRequest) (*models.Receivable, error) {
 	if strings.TrimSpace(req.PartyName) == "" {
 		return nil, errors.New("party name is required")
@@ -53,31 +63,73 @@ func (s *ReceivableService) CreateReceivable(ctx context.Context, userID int64,
 			return nil, fmt.Errorf("note %w", err)
 		}
 	}
+	tagNames, err := normalizeTagNames(req.Tags)
+	if err != nil {
+		return nil, err
+	}
 	assetID, err := s.resolveAssetID(ctx, req.AssetUUID, userID)
 	if err != nil {
 		return nil, err
 	}
+	if req.RecordDisbursement && assetID == nil {
+		return nil, errors.New("assetUuid is required to record the disbursement")
+	}
 
 	now := time.Now()
 	rec := &models.Receivable{
-		UserID:     userID,
-		PartyName:  strings.TrimSpace(req.PartyName),
-		Amount:     decimal.NewFromFloat(req.Amount),
-		PaidAmount: decimal.Zero,
-		DueDate:    req.DueDate,
-		Status:     models.ObligationStatusPending,
-		Note:       req.Note,
-		AssetID:    assetID,
-		CreatedAt:  now,
-		UpdatedAt:  now,
-	}
-	if err := s.repo.Create(ctx, rec); err != nil {
-		return nil, fmt.Errorf("create receivable: %w", err)
+		UserID:          userID,
+		PartyName:       strings.TrimSpace(req.PartyName),
+		Amount:          decimal.NewFromFloat(req.Amount),
+		PaidAmount:      decimal.Zero,
+		DueDate:         req.DueDate,
+		Status:          obligationStatus(decimal.NewFromFloat(req.Amount), decimal.Zero, req.DueDate, now),
+		StatusChangedAt: &now,
+		Note:            req.Note,
+		AssetID:         assetID,
+		Disbursed:       req.RecordDisbursement,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		// Lent cash leaves the CASH asset
+		var locked map[int64]*models.Asset
+		if rec.Disbursed {
+			if locked, err = lockAssetsByID(ctx, repos.Assets, *assetID); err != nil {
+				return err
+			}
+			asset, err := checkCashAsset(locked[*assetID])
+			if err != nil {
+				return err
+			}
+			if rec.Amount.GreaterThan(asset.Quantity) {
+				return errors.New("receivable amount cannot exceed the selected asset balance")
+			}
+		}
+		if err := repos.Receivables.Create(ctx, rec); err != nil {
+			return fmt.Errorf("create receivable: %w", err)
+		}
+		if len(tagNames) > 0 {
+			tags, err := resolveTags(ctx, repos.Tags, userID, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := repos.Receivables.ReplaceTags(ctx, rec, tags); err != nil {
+				return fmt.Errorf("tag receivable: %w", err)
+			}
+			rec.Tags = tags
+		}
+		return postLedger(ctx, repos, receivableDisbursementPosting(rec), locked)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return rec, nil
 }
 
 func (s *ReceivableService) GetReceivable(ctx context.Context, userID int64, uuid string) (*models.Receivable, error) {
+	if err := s.markOverdue(ctx, userID); err != nil {
+		return nil, err
+	}
 	rec, err := s.repo.GetByUUID(ctx, uuid, userID)
 	if err != nil {
 		return nil, fmt.Errorf("get receivable: %w", err)
@@ -88,7 +140,7 @@ func (s *ReceivableService) GetReceivable(ctx context.Context, userID int64, uui
 	return rec, nil
 }
 
-func (s *ReceivableService) ListReceivables(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, page, limit int) ([]models.Receivable, port.ListMeta, error) {
+func (s *ReceivableService) ListReceivables(ctx context.Context, userID int64, status *string, dueFrom, dueTo *time.Time, tags []string, page, limit int) ([]models.Receivable, port.ListMeta, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -98,7 +150,14 @@ func (s *ReceivableService) ListReceivables(ctx context.Context, userID int64, s
 	if limit > 100 {
 		limit = 100
 	}
-	recs, total, err := s.repo.ListByUserID(ctx, userID, status, dueFrom, dueTo, page, limit)
+	if err := s.markOverdue(ctx, userID); err != nil {
+		return nil, port.ListMeta{}, err
+	}
+	tags, err := normalizeTagNames(tags)
+	if err != nil {
+		return nil, port.ListMeta{}, err
+	}
+	recs, total, err := s.repo.ListByUserID(ctx, userID, status, dueFrom, dueTo, tags, page, limit)
 	if err != nil {
 		return nil, port.ListMeta{}, fmt.Errorf("list receivables: %w", err)
 	}
@@ -114,109 +173,214 @@ func (s *ReceivableService) ListReceivables(ctx context.Context, userID int64, s
 }
 
 func (s *ReceivableService) UpdateReceivable(ctx context.Context, userID int64, uuid string, req port.UpdateReceivableRequest) (*models.Receivable, error) {
-	rec, err := s.repo.GetByUUID(ctx, uuid, userID)
-	if err != nil {
-		return nil, fmt.Errorf("get receivable: %w", err)
-	}
-	if rec == nil {
-		return nil, errors.New("receivable not found")
+	if req.PartyName != nil && strings.TrimSpace(*req.PartyName) == "" {
+		return nil, errors.New("party name cannot be empty")
 	}
-	if req.PartyName != nil {
-		if strings.TrimSpace(*req.PartyName) == "" {
-			return nil, errors.New("party name cannot be empty")
-		}
-		rec.PartyName = strings.TrimSpace(*req.PartyName)
-	}
-	if req.Amount != nil {
-		if *req.Amount <= 0 {
-			return nil, errors.New("amount must be positive")
-		}
-		rec.Amount = decimal.NewFromFloat(*req.Amount)
-	}
-	if req.DueDate != nil {
-		rec.DueDate = req.DueDate
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
 	}
 	if req.Note != nil {
 		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
 			return nil, fmt.Errorf("note %w", err)
 		}
-		rec.Note = req.Note
 	}
+	var tagNames []string
+	if req.Tags != nil {
+		var err error
+		if tagNames, err = normalizeTagNames(*req.Tags); err != nil {
+			return nil, err
+		}
+	}
+	var newAssetID *int64
 	if req.AssetUUID != nil {
-		assetID, err := s.resolveAssetID(ctx, req.AssetUUID, userID)
-		if err != nil {
+		var err error
+		if newAssetID, err = s.resolveAssetID(ctx, req.AssetUUID, userID); err != nil {
 			return nil, err
 		}
-		rec.AssetID = assetID
 	}
-	rec.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, rec); err != nil {
-		return nil, fmt.Errorf("update receivable: %w", err)
+
+	var rec *models.Receivable
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		rec, err = repos.Receivables.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get receivable: %w", err)
+		}
+		if rec == nil {
+			return errors.New("receivable not found")
+		}
+		oldAssetID, oldAmount, oldDisbursed := rec.AssetID, rec.Amount, rec.Disbursed
+
+		if req.PartyName != nil {
+			rec.PartyName = strings.TrimSpace(*req.PartyName)
+		}
+		if req.Amount != nil {
+			rec.Amount = decimal.NewFromFloat(*req.Amount)
+		}
+		if req.DueDate != nil {
+			rec.DueDate = req.DueDate
+		}
+		if req.Note != nil {
+			rec.Note = req.Note
+		}
+		if req.AssetUUID != nil {
+			rec.AssetID = newAssetID
+		}
+		if req.RecordDisbursement != nil {
+			rec.Disbursed = *req.RecordDisbursement
+		}
+		if rec.Disbursed && rec.AssetID == nil {
+			return errors.New("assetUuid is required to record the disbursement")
+		}
+
+		// Move the recorded disbursement along with amount / asset changes
+		locked, err := lockAssetsByID(ctx, repos.Assets, assetIDs(oldAssetID, rec.AssetID)...)
+		if err != nil {
+			return err
+		}
+		if rec.Disbursed {
+			asset, err := checkCashAsset(locked[*rec.AssetID])
+			if err != nil {
+				return err
+			}
+			available := asset.Quantity
+			if oldDisbursed && oldAssetID != nil && *oldAssetID == asset.ID {
+				available = available.Add(oldAmount)
+			}
+			if rec.Amount.GreaterThan(available) {
+				return errors.New("receivable amount cannot exceed the selected asset balance")
+			}
+		}
+
+		if err := applyReceivablePayments(ctx, repos, rec); err != nil {
+			return err
+		}
+		if req.Tags != nil {
+			tags, err := resolveTags(ctx, repos.Tags, userID, tagNames)
+			if err != nil {
+				return err
+			}
+			if err := repos.Receivables.ReplaceTags(ctx, rec, tags); err != nil {
+				return fmt.Errorf("tag receivable: %w", err)
+			}
+			rec.Tags = tags
+		}
+		return postLedger(ctx, repos, receivableDisbursementPosting(rec), locked)
+	})
+	if err != nil {
+		return nil, err
 	}
 	return rec, nil
 }
 
 func (s *ReceivableService) DeleteReceivable(ctx context.Context, userID int64, uuid string) error {
-	if err := s.repo.Delete(ctx, uuid, userID); err != nil {
-		if err.Error() == "receivable not found or not owned by user" {
+	return s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		rec, err := repos.Receivables.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get receivable: %w", err)
+		}
+		if rec == nil {
 			return errors.New("receivable not found")
 		}
-		return fmt.Errorf("delete receivable: %w", err)
-	}
-	return nil
+
+		// Undo the cash movements of the disbursement and every payment; payments are removed with the receivable
+		payments, err := repos.ReceivablePayments.ListByReceivableID(ctx, rec.ID)
+		if err != nil {
+			return fmt.Errorf("list receivable payments: %w", err)
+		}
+		ids := []int64{}
+		if rec.AssetID != nil {
+			ids = append(ids, *rec.AssetID)
+		}
+		for _, p := range payments {
+			ids = append(ids, assetIDs(p.AssetID)...)
+		}
+		locked, err := lockAssetsByID(ctx, repos.Assets, ids...)
+		if err != nil {
+			return err
+		}
+		// The lent cash comes back first, so the payments are taken out of everything the assets will hold
+		reversal := receivableDisbursementPosting(rec)
+		reversal.Description = "receivable deleted"
+		reversal.Lines = nil
+		if err := postLedger(ctx, repos, reversal, locked); err != nil {
+			return err
+		}
+		for i := range payments {
+			reversal := receivablePaymentPosting(rec, &payments[i])
+			reversal.Description = "receivable payment deleted"
+			reversal.Lines = nil
+			if err := postLedgerWithinBalance(ctx, repos, reversal, locked, "received cash to take back cannot exceed the asset balance"); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.Receivables.Delete(ctx, uuid, userID); err != nil {
+			if err.Error() == "receivable not found or not owned by user" {
+				return errors.New("receivable not found")
+			}
+			return fmt.Errorf("delete receivable: %w", err)
+		}
+		return nil
+	})
 }
 
 func (s *ReceivableService) RecordReceivablePayment(ctx context.Context, userID int64, receivableUUID string, req port.CreateReceivablePaymentRequest) (*models.ReceivablePayment, error) {
 	if req.Amount <= 0 {
 		return nil, errors.New("amount must be positive")
 	}
-	rec, err := s.repo.GetByUUID(ctx, receivableUUID, userID)
-	if err != nil {
-		return nil, fmt.Errorf("get receivable: %w", err)
-	}
-	if rec == nil {
-		return nil, errors.New("receivable not found")
-	}
-	if rec.Status == models.ObligationStatusPaid {
-		return nil, errors.New("receivable is already fully paid")
-	}
 	assetID, err := s.resolveAssetID(ctx, req.AssetUUID, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	payment := &models.ReceivablePayment{
-		ReceivableID: rec.ID,
-		Amount:       decimal.NewFromFloat(req.Amount),
-		Date:         req.Date,
-		Note:         req.Note,
-		AssetID:      assetID,
-		CreatedAt:    time.Now(),
-	}
-	if err := s.paymentRepo.Create(ctx, payment); err != nil {
-		return nil, fmt.Errorf("create receivable payment: %w", err)
-	}
+	amount := decimal.NewFromFloat(req.Amount)
+	var payment *models.ReceivablePayment
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		rec, err := repos.Receivables.GetByUUIDForUpdate(ctx, receivableUUID, userID)
+		if err != nil {
+			return fmt.Errorf("get receivable: %w", err)
+		}
+		if rec == nil {
+			return errors.New("receivable not found")
+		}
+		if rec.Status == models.ObligationStatusPaid {
+			return errors.New("receivable is already fully paid")
+		}
 
-	payments, err := s.paymentRepo.ListByReceivableID(ctx, rec.ID)
+		// Collect into the given CASH asset, falling back to the one linked to the receivable
+		if assetID == nil {
+			assetID = rec.AssetID
+		}
+		var locked map[int64]*models.Asset
+		if assetID != nil {
+			if locked, err = lockAssetsByID(ctx, repos.Assets, *assetID); err != nil {
+				return err
+			}
+			if _, err := checkCashAsset(locked[*assetID]); err != nil {
+				return err
+			}
+		}
+
+		payment = &models.ReceivablePayment{
+			ReceivableID: rec.ID,
+			Amount:       amount,
+			Date:         req.Date,
+			Note:         req.Note,
+			AssetID:      assetID,
+			CreatedAt:    time.Now(),
+		}
+		if err := repos.ReceivablePayments.Create(ctx, payment); err != nil {
+			return fmt.Errorf("create receivable payment: %w", err)
+		}
+
+		if err := applyReceivablePayments(ctx, repos, rec); err != nil {
+			return err
+		}
+		return postLedger(ctx, repos, receivablePaymentPosting(rec, payment), locked)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("list receivable payments: %w", err)
-	}
-	var sum decimal.Decimal
-	for _, p := range payments {
-		sum = sum.Add(p.Amount)
-	}
-	if sum.GreaterThan(rec.Amount) {
-		return nil, errors.New("total payments cannot exceed receivable amount")
-	}
-	rec.PaidAmount = sum
-	if sum.GreaterThanOrEqual(rec.Amount) {
-		rec.Status = models.ObligationStatusPaid
-	} else {
-		rec.Status = models.ObligationStatusPartial
-	}
-	rec.UpdatedAt = time.Now()
-	if err := s.repo.Update(ctx, rec); err != nil {
-		return nil, fmt.Errorf("update receivable after payment: %w", err)
+		return nil, err
 	}
 	return payment, nil
 }
@@ -238,3 +402,202 @@ func (s *ReceivableService) ListReceivablePayments(ctx context.Context, userID i
 	}
 	return payments, nil
 }
+
+func (s *ReceivableService) GetReceivablePayment(ctx context.Context, userID int64, receivableUUID, paymentUUID string) (*models.ReceivablePayment, error) {
+	rec, err := s.repo.GetByUUID(ctx, receivableUUID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get receivable: %w", err)
+	}
+	if rec == nil {
+		return nil, errors.New("receivable not found")
+	}
+	payment, err := s.paymentRepo.GetByUUID(ctx, rec.ID, paymentUUID)
+	if err != nil {
+		return nil, fmt.Errorf("get receivable payment: %w", err)
+	}
+	if payment == nil {
+		return nil, errors.New("payment not found")
+	}
+	return payment, nil
+}
+
+func (s *ReceivableService) UpdateReceivablePayment(ctx context.Context, userID int64, receivableUUID, paymentUUID string, req port.UpdateReceivablePaymentRequest) (*models.ReceivablePayment, error) {
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if req.Note != nil {
+		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
+			return nil, fmt.Errorf("note %w", err)
+		}
+	}
+	var newAssetID *int64
+	if req.AssetUUID != nil {
+		var err error
+		if newAssetID, err = s.resolveAssetID(ctx, req.AssetUUID, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	var payment *models.ReceivablePayment
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		rec, err := repos.Receivables.GetByUUIDForUpdate(ctx, receivableUUID, userID)
+		if err != nil {
+			return fmt.Errorf("get receivable: %w", err)
+		}
+		if rec == nil {
+			return errors.New("receivable not found")
+		}
+		payment, err = repos.ReceivablePayments.GetByUUIDForUpdate(ctx, rec.ID, paymentUUID)
+		if err != nil {
+			return fmt.Errorf("get receivable payment: %w", err)
+		}
+		if payment == nil {
+			return errors.New("payment not found")
+		}
+		if payment.VoidedAt != nil {
+			return errors.New("payment is voided and cannot be changed")
+		}
+		oldAssetID := payment.AssetID
+
+		if req.Amount != nil {
+			payment.Amount = decimal.NewFromFloat(*req.Amount)
+		}
+		if req.Date != nil {
+			payment.Date = *req.Date
+		}
+		if req.Note != nil {
+			payment.Note = req.Note
+		}
+		if req.AssetUUID != nil {
+			payment.AssetID = newAssetID
+		}
+
+		locked, err := lockAssetsByID(ctx, repos.Assets, assetIDs(oldAssetID, payment.AssetID)...)
+		if err != nil {
+			return err
+		}
+		if payment.AssetID != nil {
+			if _, err := checkCashAsset(locked[*payment.AssetID]); err != nil {
+				return err
+			}
+		}
+
+		if err := repos.ReceivablePayments.Update(ctx, payment); err != nil {
+			return fmt.Errorf("update receivable payment: %w", err)
+		}
+		if err := applyReceivablePayments(ctx, repos, rec); err != nil {
+			return err
+		}
+		return postLedgerWithinBalance(ctx, repos, receivablePaymentPosting(rec, payment), locked, "received cash to take back cannot exceed the asset balance")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+func (s *ReceivableService) VoidReceivablePayment(ctx context.Context, userID int64, receivableUUID, paymentUUID string) (*models.ReceivablePayment, error) {
+	var payment *models.ReceivablePayment
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		rec, err := repos.Receivables.GetByUUIDForUpdate(ctx, receivableUUID, userID)
+		if err != nil {
+			return fmt.Errorf("get receivable: %w", err)
+		}
+		if rec == nil {
+			return errors.New("receivable not found")
+		}
+		payment, err = repos.ReceivablePayments.GetByUUIDForUpdate(ctx, rec.ID, paymentUUID)
+		if err != nil {
+			return fmt.Errorf("get receivable payment: %w", err)
+		}
+		if payment == nil {
+			return errors.New("payment not found")
+		}
+		if payment.VoidedAt != nil {
+			return errors.New("payment is already voided")
+		}
+		locked, err := lockAssetsByID(ctx, repos.Assets, assetIDs(payment.AssetID)...)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		payment.VoidedAt = &now
+		if err := repos.ReceivablePayments.Update(ctx, payment); err != nil {
+			return fmt.Errorf("void receivable payment: %w", err)
+		}
+		if err := applyReceivablePayments(ctx, repos, rec); err != nil {
+			return err
+		}
+		reversal := receivablePaymentPosting(rec, payment)
+		reversal.Description = "receivable payment voided"
+		return postLedgerWithinBalance(ctx, repos, reversal, locked, "received cash to take back cannot exceed the asset balance")
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("receivable_payment_voided", "user_id", userID, "receivable_uuid", receivableUUID, "payment_uuid", paymentUUID, "amount", payment.Amount.String())
+	return payment, nil
+}
+
+// applyReceivablePayments recomputes PaidAmount and Status from the receivable's non-voided payments and saves the receivable.
+func applyReceivablePayments(ctx context.Context, repos port.Repositories, rec *models.Receivable) error {
+	payments, err := repos.ReceivablePayments.ListByReceivableID(ctx, rec.ID)
+	if err != nil {
+		return fmt.Errorf("list receivable payments: %w", err)
+	}
+	var sum decimal.Decimal
+	for _, p := range payments {
+		if p.VoidedAt == nil {
+			sum = sum.Add(p.Amount)
+		}
+	}
+	if sum.GreaterThan(rec.Amount) {
+		return errors.New("total payments cannot exceed receivable amount")
+	}
+	rec.PaidAmount = sum
+	now := time.Now()
+	setObligationStatus(&rec.Status, &rec.StatusChangedAt, obligationStatus(rec.Amount, rec.PaidAmount, rec.DueDate, now), now)
+	rec.UpdatedAt = now
+	if err := repos.Receivables.Update(ctx, rec); err != nil {
+		return fmt.Errorf("update receivable after payment: %w", err)
+	}
+	return nil
+}
+
+// receivableDisbursementPosting records the lent cash leaving the receivable's CASH asset (when Disbursed).
+func receivableDisbursementPosting(r *models.Receivable) ledgerPosting {
+	p := ledgerPosting{
+		UserID:        r.UserID,
+		ReferenceType: models.LedgerRefReceivable,
+		ReferenceUUID: r.UUID,
+		Description:   "lent to " + r.PartyName,
+		OccurredAt:    r.CreatedAt,
+	}
+	if r.Disbursed && r.AssetID != nil {
+		p.Lines = []ledgerLine{
+			assetLine(*r.AssetID, r.Amount.Neg()),
+			accountLine(models.LedgerAccountReceivable, r.Amount),
+		}
+	}
+	return p
+}
+
+// receivablePaymentPosting moves a collected payment into its CASH asset and reduces the receivable.
+// Payments without an asset, and voided payments, do not move cash.
+func receivablePaymentPosting(r *models.Receivable, pay *models.ReceivablePayment) ledgerPosting {
+	p := ledgerPosting{
+		UserID:        r.UserID,
+		ReferenceType: models.LedgerRefReceivablePayment,
+		ReferenceUUID: pay.UUID,
+		Description:   "receivable payment from " + r.PartyName,
+		OccurredAt:    pay.Date,
+	}
+	if pay.AssetID != nil && pay.VoidedAt == nil {
+		p.Lines = []ledgerLine{
+			assetLine(*pay.AssetID, pay.Amount),
+			accountLine(models.LedgerAccountReceivable, pay.Amount.Neg()),
+		}
+	}
+	return p
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestReceivableService_cashMovements(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	cash := store.cash(1, "IDR", 100)
+	svc := NewReceivableService(store.recs, store.recPayments, store.assets, store)
+
+	check := func(step string, wantCash, wantReceivable float64) {
+		t.Helper()
+		if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromFloat(wantCash)) {
+			t.Errorf("%s: cash = %s, want %v", step, got, wantCash)
+		}
+		if got := store.ledger.balance(models.LedgerAccountReceivable, 0); !got.Equal(decimal.NewFromFloat(wantReceivable)) {
+			t.Errorf("%s: receivable account = %s, want %v", step, got, wantReceivable)
+		}
+	}
+	wantRefused := func(step string, err error) {
+		t.Helper()
+		if err == nil || !strings.Contains(err.Error(), "cannot exceed") {
+			t.Errorf("%s: err = %v, want balance error", step, err)
+		}
+	}
+
+	lent, err := svc.CreateReceivable(ctx, 1, port.CreateReceivableRequest{PartyName: "Andi", Amount: 40, AssetUUID: &cash.UUID, RecordDisbursement: true})
+	if err != nil {
+		t.Fatalf("CreateReceivable: %v", err)
+	}
+	check("disbursement", 60, 40)
+	payment, err := svc.RecordReceivablePayment(ctx, 1, lent.UUID, port.CreateReceivablePaymentRequest{Amount: 30, Date: time.Now()})
+	if err != nil {
+		t.Fatalf("RecordReceivablePayment: %v", err)
+	}
+	check("payment", 90, 10)
+
+	// Once the repaid cash is spent, it cannot be taken back out by shrinking or voiding the payment
+	store.adjust(cash.ID, -85)
+	amount := 10.0
+	_, err = svc.UpdateReceivablePayment(ctx, 1, lent.UUID, payment.UUID, port.UpdateReceivablePaymentRequest{Amount: &amount})
+	wantRefused("smaller payment", err)
+	_, err = svc.VoidReceivablePayment(ctx, 1, lent.UUID, payment.UUID)
+	wantRefused("void", err)
+	check("refused", 5, 10)
+
+	// Deleting takes the payment back out of the asset only after returning the lent cash to it
+	if err := svc.DeleteReceivable(ctx, 1, lent.UUID); err != nil {
+		t.Fatalf("DeleteReceivable: %v", err)
+	}
+	check("delete", 15, 0)
+
+	owed, err := svc.CreateReceivable(ctx, 1, port.CreateReceivableRequest{PartyName: "Budi", Amount: 50, AssetUUID: &cash.UUID})
+	if err != nil {
+		t.Fatalf("CreateReceivable: %v", err)
+	}
+	check("no disbursement", 15, 0)
+	payment, err = svc.RecordReceivablePayment(ctx, 1, owed.UUID, port.CreateReceivablePaymentRequest{Amount: 50, Date: time.Now()})
+	if err != nil {
+		t.Fatalf("RecordReceivablePayment: %v", err)
+	}
+	check("full payment", 65, -50)
+
+	store.adjust(cash.ID, -60)
+	wantRefused("delete", svc.DeleteReceivable(ctx, 1, owed.UUID))
+	check("delete refused", 5, -50)
+
+	store.adjust(cash.ID, 100)
+	if _, err := svc.VoidReceivablePayment(ctx, 1, owed.UUID, payment.UUID); err != nil {
+		t.Fatalf("VoidReceivablePayment: %v", err)
+	}
+	check("void", 55, 0)
+}
@@ -0,0 +1,711 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+	"monity/internal/pkg/validation"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// recurringDueBatch is how many due rules one GenerateDue run works through.
+	recurringDueBatch = 500
+	// maxCatchUp bounds the occurrences one rule generates per run; a rule further behind stays due.
+	maxCatchUp = 366
+	// maxUpcomingDays bounds the Upcoming horizon.
+	maxUpcomingDays = 366
+)
+
+type RecurringService struct {
+	ruleRepo  port.RecurringRuleRepository
+	occRepo   port.RecurringOccurrenceRepository
+	assetRepo port.AssetRepository
+	catRepo   port.CategoryRepository
+	expenses  port.ExpenseService
+	incomes   port.IncomeService
+	uow       port.UnitOfWork
+}
+
+// NewRecurringService posts occurrences through the expense and income services, so they get the same
+// validation and ledger postings as ones entered by hand.
+func NewRecurringService(ruleRepo port.RecurringRuleRepository, occRepo port.RecurringOccurrenceRepository, assetRepo port.AssetRepository, catRepo port.CategoryRepository, expenses port.ExpenseService, incomes port.IncomeService, uow port.UnitOfWork) port.RecurringService {
+	return &RecurringService{ruleRepo: ruleRepo, occRepo: occRepo, assetRepo: assetRepo, catRepo: catRepo, expenses: expenses, incomes: incomes, uow: uow}
+}
+
+func (s *RecurringService) CreateRule(ctx context.Context, userID int64, req port.CreateRecurringRuleRequest) (*models.RecurringRule, error) {
+	if req.Interval == 0 {
+		req.Interval = 1
+	}
+	if err := checkSchedule(req.Frequency, req.Interval); err != nil {
+		return nil, err
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if err := checkRecurringKind(req.Kind, req.CategoryUUID != nil || req.Category != nil, req.Source); err != nil {
+		return nil, err
+	}
+	if req.Note != nil {
+		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
+			return nil, fmt.Errorf("note %w", err)
+		}
+	}
+	if req.StartDate.IsZero() {
+		return nil, errors.New("startDate is required")
+	}
+	if req.EndDate != nil && req.EndDate.Before(req.StartDate) {
+		return nil, errors.New("endDate must not be before startDate")
+	}
+	asset, err := lookupCashAsset(ctx, s.assetRepo, req.AssetUUID, userID)
+	if err != nil {
+		return nil, err
+	}
+	var categoryID *int64
+	if req.Kind == models.RecurringKindExpense {
+		category, err := resolveCategory(ctx, s.catRepo, userID, req.CategoryUUID, categoryCode(req.Category))
+		if err != nil {
+			return nil, err
+		}
+		categoryID = &category.ID
+	}
+
+	rule := &models.RecurringRule{
+		UserID:     userID,
+		AssetID:    asset.ID,
+		Kind:       req.Kind,
+		Frequency:  req.Frequency,
+		Interval:   req.Interval,
+		Amount:     decimal.NewFromFloat(req.Amount),
+		CategoryID: categoryID,
+		Source:     req.Source,
+		Note:       req.Note,
+		StartDate:  req.StartDate,
+		EndDate:    req.EndDate,
+		AutoPost:   req.AutoPost == nil || *req.AutoPost,
+	}
+	rule.NextDue = occurrenceAt(rule, 0)
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("create recurring rule: %w", err)
+	}
+	slog.Info("recurring_rule_created", "user_id", userID, "rule_uuid", rule.UUID, "kind", rule.Kind, "frequency", rule.Frequency)
+
+	s.catchUp(ctx, rule)
+	return s.GetRule(ctx, userID, rule.UUID)
+}
+
+func (s *RecurringService) GetRule(ctx context.Context, userID int64, uuid string) (*models.RecurringRule, error) {
+	rule, err := s.ruleRepo.GetByUUID(ctx, uuid, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get recurring rule: %w", err)
+	}
+	if rule == nil {
+		return nil, errors.New("recurring rule not found")
+	}
+	return rule, nil
+}
+
+func (s *RecurringService) ListRules(ctx context.Context, userID int64, page, limit int) ([]models.RecurringRule, port.ListMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	rules, total, err := s.ruleRepo.ListByUserID(ctx, userID, page, limit)
+	if err != nil {
+		return nil, port.ListMeta{}, fmt.Errorf("list recurring rules: %w", err)
+	}
+	if rules == nil {
+		rules = []models.RecurringRule{}
+	}
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	meta := port.ListMeta{Total: total, Page: page, Limit: limit, TotalPages: totalPages}
+	return rules, meta, nil
+}
+
+func (s *RecurringService) UpdateRule(ctx context.Context, userID int64, uuid string, req port.UpdateRecurringRuleRequest) (*models.RecurringRule, error) {
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if req.Note != nil {
+		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
+			return nil, fmt.Errorf("note %w", err)
+		}
+	}
+	var endDate *time.Time
+	if req.EndDate != nil && *req.EndDate != "" {
+		t, err := time.Parse(time.RFC3339, *req.EndDate)
+		if err != nil {
+			return nil, errors.New("endDate must be an RFC3339 time")
+		}
+		endDate = &t
+	}
+
+	var rule *models.RecurringRule
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		rule, err = repos.RecurringRules.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get recurring rule: %w", err)
+		}
+		if rule == nil {
+			return errors.New("recurring rule not found")
+		}
+
+		rescheduled := (req.Frequency != nil && *req.Frequency != rule.Frequency) ||
+			(req.Interval != nil && *req.Interval != rule.Interval) ||
+			(req.StartDate != nil && !req.StartDate.Equal(rule.StartDate))
+		if rescheduled {
+			if rule.NextIndex > 0 {
+				return errors.New("frequency, interval and startDate must stay the same once occurrences have been generated; end this rule and create a new one")
+			}
+			if req.Frequency != nil {
+				rule.Frequency = *req.Frequency
+			}
+			if req.Interval != nil {
+				rule.Interval = *req.Interval
+			}
+			if req.StartDate != nil {
+				rule.StartDate = *req.StartDate
+			}
+			if err := checkSchedule(rule.Frequency, rule.Interval); err != nil {
+				return err
+			}
+			// Edits to single occurrences were made against the old dates.
+			if err := repos.RecurringOccurrences.DeleteUnposted(ctx, rule.ID); err != nil {
+				return err
+			}
+		}
+		if req.AssetUUID != nil {
+			asset, err := lookupCashAsset(ctx, repos.Assets, *req.AssetUUID, userID)
+			if err != nil {
+				return err
+			}
+			rule.AssetID = asset.ID
+		}
+		if req.Amount != nil {
+			rule.Amount = decimal.NewFromFloat(*req.Amount)
+		}
+		if req.Source != nil {
+			rule.Source = req.Source
+		}
+		newCategory := req.CategoryUUID != nil || req.Category != nil
+		if err := checkRecurringKind(rule.Kind, rule.CategoryID != nil || newCategory, rule.Source); err != nil {
+			return err
+		}
+		if newCategory {
+			category, err := resolveCategory(ctx, repos.Categories, userID, req.CategoryUUID, categoryCode(req.Category))
+			if err != nil {
+				return err
+			}
+			rule.CategoryID, rule.Category = &category.ID, category
+		}
+		if req.Note != nil {
+			rule.Note = req.Note
+		}
+		if req.EndDate != nil {
+			rule.EndDate = endDate
+		}
+		if rule.EndDate != nil && rule.EndDate.Before(rule.StartDate) {
+			return errors.New("endDate must not be before startDate")
+		}
+		if req.AutoPost != nil {
+			rule.AutoPost = *req.AutoPost
+		}
+		rule.NextDue = occurrenceAt(rule, rule.NextIndex)
+		return repos.RecurringRules.Update(ctx, rule)
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("recurring_rule_updated", "user_id", userID, "rule_uuid", uuid)
+
+	s.catchUp(ctx, rule)
+	return s.GetRule(ctx, userID, uuid)
+}
+
+// DeleteRule stops the rule. Expenses and incomes it already posted stay.
+func (s *RecurringService) DeleteRule(ctx context.Context, userID int64, uuid string) error {
+	if err := s.ruleRepo.Delete(ctx, uuid, userID); err != nil {
+		return err
+	}
+	slog.Info("recurring_rule_deleted", "user_id", userID, "rule_uuid", uuid)
+	return nil
+}
+
+func (s *RecurringService) ListOccurrences(ctx context.Context, userID int64, uuid string) ([]models.RecurringOccurrence, error) {
+	rule, err := s.GetRule(ctx, userID, uuid)
+	if err != nil {
+		return nil, err
+	}
+	occs, err := s.occRepo.ListByRuleID(ctx, rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	if occs == nil {
+		occs = []models.RecurringOccurrence{}
+	}
+	return occs, nil
+}
+
+func (s *RecurringService) UpdateOccurrence(ctx context.Context, userID int64, uuid string, date time.Time, req port.UpdateOccurrenceRequest) (*models.RecurringOccurrence, error) {
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if req.Note != nil {
+		if err := validation.CheckMaxLen(*req.Note, validation.MaxNoteLen); err != nil {
+			return nil, fmt.Errorf("note %w", err)
+		}
+	}
+
+	var occ *models.RecurringOccurrence
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		rule, err := repos.RecurringRules.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get recurring rule: %w", err)
+		}
+		if rule == nil {
+			return errors.New("recurring rule not found")
+		}
+		occ, err = findOccurrence(ctx, repos, rule, date)
+		if err != nil {
+			return err
+		}
+		if occ.Status == models.OccurrencePosted {
+			return errors.New("occurrence must not be posted yet to be edited")
+		}
+		// Occurrences the generator has passed wait for confirmation; later ones are still scheduled.
+		active := models.OccurrenceScheduled
+		if occ.Index < rule.NextIndex {
+			active = models.OccurrencePending
+		}
+		if occ.ID == 0 {
+			occ.Status = active
+		}
+		if req.Skip != nil {
+			if *req.Skip {
+				occ.Status = models.OccurrenceSkipped
+			} else if occ.Status == models.OccurrenceSkipped {
+				occ.Status = active
+			}
+		}
+		if req.Amount != nil {
+			amount := decimal.NewFromFloat(*req.Amount)
+			occ.Amount = &amount
+		}
+		if req.Note != nil {
+			occ.Note = req.Note
+		}
+		return repos.RecurringOccurrences.Save(ctx, occ)
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("recurring_occurrence_updated", "user_id", userID, "rule_uuid", uuid, "due_date", occ.DueDate, "status", occ.Status)
+	return occ, nil
+}
+
+func (s *RecurringService) ConfirmOccurrence(ctx context.Context, userID int64, uuid string, date time.Time) (*models.RecurringOccurrence, error) {
+	var occ *models.RecurringOccurrence
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		rule, err := repos.RecurringRules.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get recurring rule: %w", err)
+		}
+		if rule == nil {
+			return errors.New("recurring rule not found")
+		}
+		occ, err = findOccurrence(ctx, repos, rule, date)
+		if err != nil {
+			return err
+		}
+		if occ.Status != models.OccurrencePending {
+			return errors.New("occurrence must be pending to be confirmed")
+		}
+		if err := loadRuleRefs(ctx, repos, rule); err != nil {
+			return err
+		}
+		if err := s.post(ctx, rule, occ); err != nil {
+			return err
+		}
+		return repos.RecurringOccurrences.Save(ctx, occ)
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("recurring_occurrence_confirmed", "user_id", userID, "rule_uuid", uuid, "due_date", occ.DueDate)
+	return occ, nil
+}
+
+func (s *RecurringService) Upcoming(ctx context.Context, userID int64, days int) ([]port.UpcomingOccurrence, error) {
+	if days < 0 {
+		return nil, errors.New("days must be positive")
+	}
+	if days == 0 {
+		days = 30
+	}
+	if days > maxUpcomingDays {
+		return nil, fmt.Errorf("days cannot exceed %d", maxUpcomingDays)
+	}
+	rules, err := s.ruleRepo.ListAllByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	recorded, err := s.occRepo.ListByUserID(ctx, userID, []models.OccurrenceStatus{models.OccurrencePending, models.OccurrenceScheduled, models.OccurrenceSkipped})
+	if err != nil {
+		return nil, err
+	}
+	type key struct {
+		ruleID int64
+		index  int
+	}
+	byIndex := make(map[key]*models.RecurringOccurrence, len(recorded))
+	for i := range recorded {
+		byIndex[key{recorded[i].RuleID, recorded[i].Index}] = &recorded[i]
+	}
+
+	until := time.Now().AddDate(0, 0, days)
+	out := []port.UpcomingOccurrence{}
+	for i := range rules {
+		rule := &rules[i]
+		for j := range recorded {
+			if occ := &recorded[j]; occ.RuleID == rule.ID && occ.Status == models.OccurrencePending {
+				out = append(out, upcomingOccurrence(rule, occ.DueDate, occ))
+			}
+		}
+		for n := rule.NextIndex; n < rule.NextIndex+maxCatchUp; n++ {
+			due := occurrenceAt(rule, n)
+			if due == nil || due.After(until) {
+				break
+			}
+			out = append(out, upcomingOccurrence(rule, *due, byIndex[key{rule.ID, n}]))
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].DueDate.Before(out[j].DueDate) })
+	return out, nil
+}
+
+// upcomingOccurrence applies an occurrence's own edits, if any, to its rule.
+func upcomingOccurrence(rule *models.RecurringRule, due time.Time, occ *models.RecurringOccurrence) port.UpcomingOccurrence {
+	u := port.UpcomingOccurrence{
+		RuleUUID: rule.UUID,
+		Kind:     rule.Kind,
+		DueDate:  due,
+		Status:   models.OccurrenceScheduled,
+		Amount:   rule.Amount,
+		Category: rule.Category,
+		Source:   rule.Source,
+		Note:     rule.Note,
+		AutoPost: rule.AutoPost,
+	}
+	if rule.Asset != nil {
+		u.AssetUUID = rule.Asset.UUID
+	}
+	if occ != nil {
+		u.Status = occ.Status
+		u.LastError = occ.LastError
+		if occ.Amount != nil {
+			u.Amount = *occ.Amount
+		}
+		if occ.Note != nil {
+			u.Note = occ.Note
+		}
+	}
+	return u
+}
+
+func (s *RecurringService) GenerateDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	ids, err := s.ruleRepo.ListDueIDs(ctx, now, recurringDueBatch)
+	if err != nil {
+		return 0, err
+	}
+	posted := 0
+	var errs []error
+	for _, id := range ids {
+		n, err := s.generateRule(ctx, id, now)
+		if err != nil {
+			slog.Error("recurring_generate_failed", "rule_id", id, "error", err)
+			errs = append(errs, fmt.Errorf("rule %d: %w", id, err))
+			continue
+		}
+		posted += n
+	}
+	if len(ids) > 0 {
+		slog.Info("recurring_generated", "rules", len(ids), "posted", posted)
+	}
+	return posted, errors.Join(errs...)
+}
+
+// catchUp generates a rule's due occurrences right after it was created or changed, instead of waiting for
+// the next GenerateDue run. The rule is saved either way, so a failure is only logged.
+func (s *RecurringService) catchUp(ctx context.Context, rule *models.RecurringRule) {
+	if rule.NextDue == nil || rule.NextDue.After(time.Now()) {
+		return
+	}
+	if _, err := s.generateRule(ctx, rule.ID, time.Now()); err != nil {
+		slog.Error("recurring_generate_failed", "rule_id", rule.ID, "error", err)
+	}
+}
+
+// generateRule works through the occurrences of one rule due by now, in a transaction holding the rule's
+// lock. Each posting runs in a nested transaction: one that fails (e.g. the CASH balance is too low) is
+// rolled back alone and its occurrence waits as PENDING with the error.
+func (s *RecurringService) generateRule(ctx context.Context, ruleID int64, now time.Time) (int, error) {
+	posted := 0
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		posted = 0
+		rule, err := repos.RecurringRules.GetByIDForUpdate(ctx, ruleID)
+		if err != nil {
+			return fmt.Errorf("get recurring rule: %w", err)
+		}
+		if rule == nil || rule.NextDue == nil || rule.NextDue.After(now) {
+			return nil
+		}
+		if err := loadRuleRefs(ctx, repos, rule); err != nil {
+			return err
+		}
+
+		for i := 0; i < maxCatchUp; i++ {
+			due := occurrenceAt(rule, rule.NextIndex)
+			if due == nil || due.After(now) {
+				break
+			}
+			occ, err := repos.RecurringOccurrences.Get(ctx, rule.ID, rule.NextIndex)
+			if err != nil {
+				return err
+			}
+			if occ == nil {
+				occ = &models.RecurringOccurrence{RuleID: rule.ID, UserID: rule.UserID, Index: rule.NextIndex, DueDate: *due}
+			}
+			rule.NextIndex++
+			if occ.Status == models.OccurrenceSkipped {
+				continue
+			}
+
+			occ.Status = models.OccurrencePending
+			if rule.AutoPost {
+				if err := s.post(ctx, rule, occ); err != nil {
+					msg := err.Error()
+					occ.LastError = &msg
+					slog.Warn("recurring_post_failed", "user_id", rule.UserID, "rule_uuid", rule.UUID, "due_date", occ.DueDate, "error", err)
+				} else {
+					posted++
+				}
+			}
+			if err := repos.RecurringOccurrences.Save(ctx, occ); err != nil {
+				return err
+			}
+		}
+		rule.NextDue = occurrenceAt(rule, rule.NextIndex)
+		return repos.RecurringRules.Update(ctx, rule)
+	})
+	return posted, err
+}
+
+// post creates the occurrence's expense or income and marks it POSTED.
+func (s *RecurringService) post(ctx context.Context, rule *models.RecurringRule, occ *models.RecurringOccurrence) error {
+	if rule.Asset == nil {
+		return errors.New("asset not found")
+	}
+	if rule.Kind == models.RecurringKindExpense && rule.Category == nil {
+		return errors.New("category not found")
+	}
+	amount := rule.Amount
+	if occ.Amount != nil {
+		amount = *occ.Amount
+	}
+	note := rule.Note
+	if occ.Note != nil {
+		note = occ.Note
+	}
+	value, _ := amount.Float64()
+
+	switch rule.Kind {
+	case models.RecurringKindExpense:
+		expense, err := s.expenses.CreateExpense(ctx, rule.UserID, port.CreateExpenseRequest{
+			AssetUUID:    rule.Asset.UUID,
+			Amount:       value,
+			CategoryUUID: &rule.Category.UUID,
+			Note:         note,
+			Date:         occ.DueDate,
+		})
+		if err != nil {
+			return err
+		}
+		occ.ExpenseID = &expense.ID
+	case models.RecurringKindIncome:
+		income, err := s.incomes.CreateIncome(ctx, rule.UserID, port.CreateIncomeRequest{
+			AssetUUID: rule.Asset.UUID,
+			Amount:    value,
+			Source:    *rule.Source,
+			Note:      note,
+			Date:      occ.DueDate,
+		})
+		if err != nil {
+			return err
+		}
+		occ.IncomeID = &income.ID
+	default:
+		return fmt.Errorf("unknown recurring kind %q", rule.Kind)
+	}
+	postedAt := time.Now()
+	occ.Status = models.OccurrencePosted
+	occ.PostedAt = &postedAt
+	occ.LastError = nil
+	return nil
+}
+
+// loadRuleRefs loads the asset and category a rule posts with, for a rule read under lock.
+func loadRuleRefs(ctx context.Context, repos port.Repositories, rule *models.RecurringRule) error {
+	var err error
+	if rule.Asset, err = repos.Assets.GetByID(ctx, rule.AssetID); err != nil {
+		return fmt.Errorf("get asset: %w", err)
+	}
+	if rule.CategoryID != nil {
+		if rule.Category, err = repos.Categories.GetByID(ctx, *rule.CategoryID); err != nil {
+			return fmt.Errorf("get category: %w", err)
+		}
+	}
+	return nil
+}
+
+// findOccurrence returns the rule's occurrence due on date's day, recorded or not yet.
+func findOccurrence(ctx context.Context, repos port.Repositories, rule *models.RecurringRule, date time.Time) (*models.RecurringOccurrence, error) {
+	n, ok := occurrenceIndex(rule.StartDate, rule.Frequency, rule.Interval, date)
+	if !ok {
+		return nil, errors.New("occurrence not found")
+	}
+	due := occurrenceAt(rule, n)
+	if due == nil {
+		return nil, errors.New("occurrence not found")
+	}
+	occ, err := repos.RecurringOccurrences.Get(ctx, rule.ID, n)
+	if err != nil {
+		return nil, err
+	}
+	if occ != nil {
+		return occ, nil
+	}
+	if n < rule.NextIndex {
+		// Generated occurrences are always recorded.
+		return nil, errors.New("occurrence not found")
+	}
+	return &models.RecurringOccurrence{RuleID: rule.ID, UserID: rule.UserID, Index: n, DueDate: *due}, nil
+}
+
+func checkSchedule(freq models.RecurringFrequency, interval int) error {
+	switch freq {
+	case models.RecurringDaily, models.RecurringWeekly, models.RecurringMonthly, models.RecurringYearly:
+	default:
+		return errors.New("frequency must be DAILY, WEEKLY, MONTHLY or YEARLY")
+	}
+	if interval < 1 {
+		return errors.New("interval must be positive")
+	}
+	return nil
+}
+
+// checkRecurringKind checks that an EXPENSE rule has a category and an INCOME rule a source, and not the other.
+func checkRecurringKind(kind models.RecurringKind, hasCategory bool, source *string) error {
+	switch kind {
+	case models.RecurringKindExpense:
+		if !hasCategory {
+			return errors.New("categoryUuid or category is required for EXPENSE rules")
+		}
+		if source != nil {
+			return errors.New("source must be empty for EXPENSE rules")
+		}
+	case models.RecurringKindIncome:
+		if source == nil || strings.TrimSpace(*source) == "" {
+			return errors.New("source is required for INCOME rules")
+		}
+		if err := validation.CheckMaxLen(*source, validation.MaxSourceLen); err != nil {
+			return fmt.Errorf("source %w", err)
+		}
+		if hasCategory {
+			return errors.New("category must be empty for INCOME rules")
+		}
+	default:
+		return errors.New("kind must be EXPENSE or INCOME")
+	}
+	return nil
+}
+
+// occurrenceAt is the due time of the rule's nth occurrence, or nil if it falls after EndDate.
+func occurrenceAt(rule *models.RecurringRule, n int) *time.Time {
+	t := occurrenceDate(rule.StartDate, rule.Frequency, rule.Interval, n)
+	if rule.EndDate != nil && t.After(*rule.EndDate) {
+		return nil
+	}
+	return &t
+}
+
+// occurrenceDate is the nth occurrence (from zero) of a schedule starting at start. Months and years are
+// counted from start rather than from the previous occurrence, so a rule on the 31st comes back to the 31st
+// after a shorter month.
+func occurrenceDate(start time.Time, freq models.RecurringFrequency, interval, n int) time.Time {
+	step := interval * n
+	switch freq {
+	case models.RecurringWeekly:
+		return start.AddDate(0, 0, 7*step)
+	case models.RecurringMonthly:
+		return addMonthsClamped(start, step)
+	case models.RecurringYearly:
+		return addMonthsClamped(start, 12*step)
+	default:
+		return start.AddDate(0, 0, step)
+	}
+}
+
+// occurrenceIndex finds which occurrence of the schedule falls on date's calendar day.
+func occurrenceIndex(start time.Time, freq models.RecurringFrequency, interval int, date time.Time) (int, bool) {
+	sy, sm, sd := start.Date()
+	dy, dm, dd := date.Date()
+	var n int
+	switch freq {
+	case models.RecurringDaily, models.RecurringWeekly:
+		days := int(time.Date(dy, dm, dd, 0, 0, 0, 0, time.UTC).Sub(time.Date(sy, sm, sd, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+		unit := interval
+		if freq == models.RecurringWeekly {
+			unit *= 7
+		}
+		n = days / unit
+	case models.RecurringMonthly:
+		n = ((dy-sy)*12 + int(dm-sm)) / interval
+	case models.RecurringYearly:
+		n = (dy - sy) / interval
+	default:
+		return 0, false
+	}
+	if n < 0 {
+		return 0, false
+	}
+	y, m, d := occurrenceDate(start, freq, interval, n).Date()
+	if y != dy || m != dm || d != dd {
+		return 0, false
+	}
+	return n, true
+}
+
+// addMonthsClamped adds months to t, keeping its day unless the target month is shorter.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	first := time.Date(y, m+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func Test_occurrenceDate(t *testing.T) {
+	day := func(s string) time.Time { tm, _ := time.Parse("2006-01-02", s); return tm }
+	tests := []struct {
+		name     string
+		start    string
+		freq     models.RecurringFrequency
+		interval int
+		n        int
+		want     string
+	}{
+		{"first occurrence is the start", "2025-01-31", models.RecurringMonthly, 1, 0, "2025-01-31"},
+		{"month end clamps to February", "2025-01-31", models.RecurringMonthly, 1, 1, "2025-02-28"},
+		{"month end clamps to leap February", "2024-01-31", models.RecurringMonthly, 1, 1, "2024-02-29"},
+		{"month end returns after February", "2025-01-31", models.RecurringMonthly, 1, 2, "2025-03-31"},
+		{"every other month", "2025-01-15", models.RecurringMonthly, 2, 3, "2025-07-15"},
+		{"every two weeks", "2025-01-06", models.RecurringWeekly, 2, 2, "2025-02-03"},
+		{"daily across a year end", "2025-12-30", models.RecurringDaily, 1, 3, "2026-01-02"},
+		{"leap day yearly", "2024-02-29", models.RecurringYearly, 1, 1, "2025-02-28"},
+		{"leap day yearly comes back", "2024-02-29", models.RecurringYearly, 1, 4, "2028-02-29"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := occurrenceDate(day(tt.start), tt.freq, tt.interval, tt.n)
+			if !got.Equal(day(tt.want)) {
+				t.Errorf("occurrenceDate() = %s; want %s", got.Format("2006-01-02"), tt.want)
+			}
+			n, ok := occurrenceIndex(day(tt.start), tt.freq, tt.interval, got)
+			if !ok || n != tt.n {
+				t.Errorf("occurrenceIndex(%s) = %d, %v; want %d", tt.want, n, ok, tt.n)
+			}
+		})
+	}
+	if _, ok := occurrenceIndex(day("2025-01-31"), models.RecurringMonthly, 1, day("2025-03-01")); ok {
+		t.Error("occurrenceIndex() found an occurrence off the schedule")
+	}
+}
+
+// expenseRule stores a daily EXPENSE rule of amount drawing from cash, with its first occurrence due
+// days ago. The rule is stored directly, so no occurrence is generated yet.
+func expenseRule(t *testing.T, store *memStore, cash *models.Asset, amount float64, days int, autoPost bool) *models.RecurringRule {
+	t.Helper()
+	category, err := defaultCategoryByCode(context.Background(), store.cats, cash.UserID, models.ExpenseCategoryOther)
+	if err != nil {
+		t.Fatalf("defaultCategoryByCode: %v", err)
+	}
+	rule := &models.RecurringRule{
+		UserID:     cash.UserID,
+		AssetID:    cash.ID,
+		Kind:       models.RecurringKindExpense,
+		Frequency:  models.RecurringDaily,
+		Interval:   1,
+		Amount:     decimal.NewFromFloat(amount),
+		CategoryID: &category.ID,
+		StartDate:  time.Now().AddDate(0, 0, -days).Add(-time.Hour),
+		AutoPost:   autoPost,
+	}
+	rule.NextDue = occurrenceAt(rule, 0)
+	if err := store.rules.Create(context.Background(), rule); err != nil {
+		t.Fatalf("create rule: %v", err)
+	}
+	return rule
+}
+
+func TestRecurringService_GenerateDue(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	cash := store.cash(1, "IDR", 25)
+	svc := NewRecurringService(store.rules, store.occurrences, store.assets, store.cats, NewExpenseService(store.expenses, store.assets, store), nil, store)
+	rule := expenseRule(t, store, cash, 10, 2, true)
+
+	occurrence := func(n int) *models.RecurringOccurrence {
+		t.Helper()
+		occ, _ := store.occurrences.Get(ctx, rule.ID, n)
+		if occ == nil {
+			t.Fatalf("occurrence %d not recorded", n)
+		}
+		return occ
+	}
+
+	// Skip the second occurrence and raise the third past what is left after the first
+	skip := true
+	if _, err := svc.UpdateOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 1), port.UpdateOccurrenceRequest{Skip: &skip}); err != nil {
+		t.Fatalf("UpdateOccurrence skip: %v", err)
+	}
+	amount := 20.0
+	occ, err := svc.UpdateOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 2), port.UpdateOccurrenceRequest{Amount: &amount})
+	if err != nil {
+		t.Fatalf("UpdateOccurrence amount: %v", err)
+	}
+	if occ.Status != models.OccurrenceScheduled {
+		t.Errorf("edited future occurrence status = %s, want %s", occ.Status, models.OccurrenceScheduled)
+	}
+
+	posted, err := svc.GenerateDue(ctx)
+	if err != nil {
+		t.Fatalf("GenerateDue: %v", err)
+	}
+	if posted != 1 {
+		t.Errorf("GenerateDue posted %d, want 1", posted)
+	}
+	if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromInt(15)) {
+		t.Errorf("cash = %s, want 15", got)
+	}
+	if occ := occurrence(0); occ.Status != models.OccurrencePosted || occ.ExpenseID == nil || store.expenses.rows[*occ.ExpenseID] == nil {
+		t.Errorf("first occurrence = %s with expense %v, want POSTED with its expense", occ.Status, occ.ExpenseID)
+	}
+	if occ := occurrence(1); occ.Status != models.OccurrenceSkipped || occ.ExpenseID != nil {
+		t.Errorf("skipped occurrence = %s with expense %v, want SKIPPED without one", occ.Status, occ.ExpenseID)
+	}
+	// The failed post is rolled back alone and waits for confirmation
+	if occ := occurrence(2); occ.Status != models.OccurrencePending || occ.LastError == nil || !strings.Contains(*occ.LastError, "cannot exceed") {
+		t.Errorf("overdrawing occurrence = %s with error %v, want PENDING with a balance error", occ.Status, occ.LastError)
+	}
+	stored := store.rules.get(rule.ID)
+	if stored.NextIndex != 3 || stored.NextDue == nil || !stored.NextDue.After(time.Now()) {
+		t.Errorf("rule next = %d at %v, want 3 in the future", stored.NextIndex, stored.NextDue)
+	}
+
+	// A second run finds nothing due
+	if posted, err := svc.GenerateDue(ctx); err != nil || posted != 0 {
+		t.Errorf("second GenerateDue = %d, %v; want 0, nil", posted, err)
+	}
+	if len(store.expenses.rows) != 1 || len(store.occurrences.rows) != 3 {
+		t.Errorf("second run: %d expenses and %d occurrences, want 1 and 3", len(store.expenses.rows), len(store.occurrences.rows))
+	}
+	if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromInt(15)) {
+		t.Errorf("second run: cash = %s, want 15", got)
+	}
+}
+
+func TestRecurringService_confirmAndUpcoming(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	cash := store.cash(1, "IDR", 100)
+	svc := NewRecurringService(store.rules, store.occurrences, store.assets, store.cats, NewExpenseService(store.expenses, store.assets, store), nil, store)
+	rule := expenseRule(t, store, cash, 10, 1, false)
+
+	// Without AutoPost, due occurrences wait as PENDING
+	if posted, err := svc.GenerateDue(ctx); err != nil || posted != 0 {
+		t.Fatalf("GenerateDue = %d, %v; want 0, nil", posted, err)
+	}
+	if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("generate: cash = %s, want 100", got)
+	}
+	amount := 7.0
+	if _, err := svc.UpdateOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 3), port.UpdateOccurrenceRequest{Amount: &amount}); err != nil {
+		t.Fatalf("UpdateOccurrence: %v", err)
+	}
+
+	// Two pending occurrences, then the rule's next seven days with the edited one applied
+	upcoming, err := svc.Upcoming(ctx, 1, 7)
+	if err != nil {
+		t.Fatalf("Upcoming: %v", err)
+	}
+	if len(upcoming) != 9 {
+		t.Fatalf("Upcoming returned %d occurrences, want 9", len(upcoming))
+	}
+	for i, u := range upcoming {
+		wantStatus, wantAmount := models.OccurrenceScheduled, decimal.NewFromInt(10)
+		if i < 2 {
+			wantStatus = models.OccurrencePending
+		}
+		if i == 3 {
+			wantAmount = decimal.NewFromInt(7)
+		}
+		if u.Status != wantStatus || !u.Amount.Equal(wantAmount) || u.AssetUUID != cash.UUID {
+			t.Errorf("upcoming[%d] = %s %s on %s, want %s %s on %s", i, u.Status, u.Amount, u.AssetUUID, wantStatus, wantAmount, cash.UUID)
+		}
+		if !u.DueDate.Equal(*occurrenceAt(rule, i)) {
+			t.Errorf("upcoming[%d] due %s, want %s", i, u.DueDate, *occurrenceAt(rule, i))
+		}
+	}
+	for _, days := range []int{-1, maxUpcomingDays + 1} {
+		if _, err := svc.Upcoming(ctx, 1, days); err == nil {
+			t.Errorf("Upcoming(%d) succeeded, want an error", days)
+		}
+	}
+
+	occ, err := svc.ConfirmOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 0))
+	if err != nil {
+		t.Fatalf("ConfirmOccurrence: %v", err)
+	}
+	if occ.Status != models.OccurrencePosted || occ.ExpenseID == nil {
+		t.Errorf("confirmed occurrence = %s with expense %v, want POSTED with one", occ.Status, occ.ExpenseID)
+	}
+	if got := store.quantity(cash.ID); !got.Equal(decimal.NewFromInt(90)) {
+		t.Errorf("confirm: cash = %s, want 90", got)
+	}
+	if _, err := svc.ConfirmOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 0)); err == nil || !strings.Contains(err.Error(), "must be pending") {
+		t.Errorf("second confirm: err = %v, want pending error", err)
+	}
+	if _, err := svc.ConfirmOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 2)); err == nil || !strings.Contains(err.Error(), "must be pending") {
+		t.Errorf("confirming a scheduled occurrence: err = %v, want pending error", err)
+	}
+	if _, err := svc.UpdateOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 0), port.UpdateOccurrenceRequest{Amount: &amount}); err == nil || !strings.Contains(err.Error(), "posted") {
+		t.Errorf("editing a posted occurrence: err = %v, want posted error", err)
+	}
+
+	// Skipping a pending occurrence and taking the skip back returns it to PENDING
+	skip := true
+	if occ, err := svc.UpdateOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 1), port.UpdateOccurrenceRequest{Skip: &skip}); err != nil || occ.Status != models.OccurrenceSkipped {
+		t.Errorf("skip: occurrence %v, err %v; want SKIPPED", occ, err)
+	}
+	skip = false
+	if occ, err := svc.UpdateOccurrence(ctx, 1, rule.UUID, *occurrenceAt(rule, 1), port.UpdateOccurrenceRequest{Skip: &skip}); err != nil || occ.Status != models.OccurrencePending {
+		t.Errorf("unskip: occurrence %v, err %v; want PENDING", occ, err)
+	}
+
+	upcoming, err = svc.Upcoming(ctx, 1, 7)
+	if err != nil {
+		t.Fatalf("Upcoming after confirm: %v", err)
+	}
+	if len(upcoming) != 8 || upcoming[0].Status != models.OccurrencePending || !upcoming[0].DueDate.Equal(*occurrenceAt(rule, 1)) {
+		t.Errorf("Upcoming after confirm = %d occurrences starting %v, want 8 starting with the pending second one", len(upcoming), upcoming)
+	}
+}
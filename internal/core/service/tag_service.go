@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+	"monity/internal/pkg/validation"
+)
+
+type TagService struct {
+	repo port.TagRepository
+}
+
+// NewTagService manages the user's tags. Tags are created by naming them on an expense, income, debt or
+// receivable; there is no separate create.
+func NewTagService(repo port.TagRepository) port.TagService {
+	return &TagService{repo: repo}
+}
+
+func (s *TagService) ListTags(ctx context.Context, userID int64) ([]models.Tag, error) {
+	tags, err := s.repo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+	return tags, nil
+}
+
+// RenameTag renames the tag everywhere it is used.
+func (s *TagService) RenameTag(ctx context.Context, userID int64, uuid string, req port.UpdateTagRequest) (*models.Tag, error) {
+	tag, err := s.repo.GetByUUID(ctx, uuid, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get tag: %w", err)
+	}
+	if tag == nil {
+		return nil, errors.New("tag not found")
+	}
+	names, err := normalizeTagNames([]string{req.Name})
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("name is required")
+	}
+	existing, err := s.repo.GetByName(ctx, userID, names[0])
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != tag.ID {
+		return nil, fmt.Errorf("a tag named %q already exists", names[0])
+	}
+	tag.Name = names[0]
+	if err := s.repo.Update(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// DeleteTag removes the tag from everything it labels; the tagged records stay.
+func (s *TagService) DeleteTag(ctx context.Context, userID int64, uuid string) error {
+	if err := s.repo.Delete(ctx, uuid, userID); err != nil {
+		return err
+	}
+	slog.Info("tag_deleted", "user_id", userID, "tag_uuid", uuid)
+	return nil
+}
+
+// normalizeTagNames trims and lower-cases tag names and drops blanks and repeats, keeping the first-seen order.
+func normalizeTagNames(names []string) ([]string, error) {
+	seen := make(map[string]bool, len(names))
+	out := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+		if strings.Contains(name, ",") {
+			return nil, errors.New("tags must be written without commas")
+		}
+		if err := validation.CheckMaxLen(name, validation.MaxTagLen); err != nil {
+			return nil, fmt.Errorf("tag %w", err)
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	if len(out) > validation.MaxTags {
+		return nil, fmt.Errorf("tags must be at most %d", validation.MaxTags)
+	}
+	return out, nil
+}
+
+// resolveTags returns the user's tags with the normalized names, creating the ones not used before.
+func resolveTags(ctx context.Context, tags port.TagRepository, userID int64, names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return []models.Tag{}, nil
+	}
+	found, err := tags.FindOrCreate(ctx, userID, names)
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_normalizeTagNames(t *testing.T) {
+	tooMany := make([]string, 21)
+	for i := range tooMany {
+		tooMany[i] = strings.Repeat("t", i+1)
+	}
+	tests := []struct {
+		name    string
+		in      []string
+		want    []string
+		wantErr bool
+	}{
+		{"nil stays empty", nil, []string{}, false},
+		{"trims and lower-cases", []string{"  Trip-Bali "}, []string{"trip-bali"}, false},
+		{"drops blanks and repeats", []string{"food", "", " FOOD", "reimbursable"}, []string{"food", "reimbursable"}, false},
+		{"rejects commas", []string{"food,drinks"}, nil, true},
+		{"rejects long names", []string{strings.Repeat("x", 51)}, nil, true},
+		{"rejects too many tags", tooMany, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTagNames(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeTagNames() error = %v; wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizeTagNames() = %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+	"monity/internal/pkg/validation"
+
+	"github.com/shopspring/decimal"
+)
+
+const transferFeeNote = "Transfer fee"
+
+type TransferService struct {
+	repo      port.TransferRepository
+	assetRepo port.AssetRepository
+	priceSvc  port.PriceService
+	uow       port.UnitOfWork
+}
+
+func NewTransferService(repo port.TransferRepository, assetRepo port.AssetRepository, priceSvc port.PriceService, uow port.UnitOfWork) port.TransferService {
+	return &TransferService{repo: repo, assetRepo: assetRepo, priceSvc: priceSvc, uow: uow}
+}
+
+func (s *TransferService) CreateTransfer(ctx context.Context, userID int64, req port.CreateTransferRequest) (*models.Transfer, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if err := validateTransferOptions(req.Fee, req.ExchangeRate, req.Note); err != nil {
+		return nil, err
+	}
+	from, err := lookupCashAsset(ctx, s.assetRepo, req.FromAssetUUID, userID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := lookupCashAsset(ctx, s.assetRepo, req.ToAssetUUID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if from.ID == to.ID {
+		return nil, errors.New("source and destination assets must be different")
+	}
+	rate, err := s.resolveRate(ctx, assetCurrency(from), assetCurrency(to), req.ExchangeRate)
+	if err != nil {
+		return nil, err
+	}
+
+	date := time.Now()
+	if req.Date != nil {
+		date = *req.Date
+	}
+	amount := decimal.NewFromFloat(req.Amount)
+	transfer := &models.Transfer{
+		UserID:       userID,
+		FromAssetID:  from.ID,
+		ToAssetID:    to.ID,
+		Amount:       amount,
+		FromCurrency: assetCurrency(from),
+		ToAmount:     amount.Mul(rate).Round(2),
+		ToCurrency:   assetCurrency(to),
+		ExchangeRate: rate,
+		Fee:          decimalPtr(req.Fee),
+		Note:         req.Note,
+		Date:         date,
+	}
+
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		locked, err := lockTransferAssets(ctx, repos.Assets, from.ID, to.ID)
+		if err != nil {
+			return err
+		}
+		if transferOutflow(transfer).GreaterThan(locked[from.ID].Quantity) {
+			return errors.New("transfer amount and fee cannot exceed the source asset balance")
+		}
+		if err := syncTransferFee(ctx, repos, transfer, locked); err != nil {
+			return err
+		}
+		if err := repos.Transfers.Create(ctx, transfer); err != nil {
+			return fmt.Errorf("create transfer: %w", err)
+		}
+		return postLedger(ctx, repos, transferPosting(transfer), locked)
+	})
+	if err != nil {
+		return nil, err
+	}
+	slog.Info("transfer_created", "user_id", userID, "amount", transfer.Amount.String(), "to_amount", transfer.ToAmount.String(),
+		"from_asset_uuid", from.UUID, "to_asset_uuid", to.UUID)
+	return s.GetTransfer(ctx, userID, transfer.UUID)
+}
+
+func (s *TransferService) GetTransfer(ctx context.Context, userID int64, uuid string) (*models.Transfer, error) {
+	transfer, err := s.repo.GetByUUID(ctx, uuid, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get transfer: %w", err)
+	}
+	if transfer == nil {
+		return nil, errors.New("transfer not found")
+	}
+	return transfer, nil
+}
+
+func (s *TransferService) ListTransfers(ctx context.Context, userID int64, dateFrom, dateTo *time.Time, page, limit int) ([]models.Transfer, port.ListMeta, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	transfers, total, err := s.repo.ListByUserID(ctx, userID, dateFrom, dateTo, page, limit)
+	if err != nil {
+		return nil, port.ListMeta{}, fmt.Errorf("list transfers: %w", err)
+	}
+	if transfers == nil {
+		transfers = []models.Transfer{}
+	}
+	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	if totalPages < 0 {
+		totalPages = 0
+	}
+	meta := port.ListMeta{Total: total, Page: page, Limit: limit, TotalPages: totalPages}
+	return transfers, meta, nil
+}
+
+func (s *TransferService) UpdateTransfer(ctx context.Context, userID int64, uuid string, req port.UpdateTransferRequest) (*models.Transfer, error) {
+	if req.Amount != nil && *req.Amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+	if err := validateTransferOptions(req.Fee, req.ExchangeRate, req.Note); err != nil {
+		return nil, err
+	}
+
+	// Resolve assets and the exchange rate before opening the transaction: the rate may need a network call.
+	existing, err := s.GetTransfer(ctx, userID, uuid)
+	if err != nil {
+		return nil, err
+	}
+	from, to := existing.FromAsset, existing.ToAsset
+	if req.FromAssetUUID != nil {
+		if from, err = lookupCashAsset(ctx, s.assetRepo, *req.FromAssetUUID, userID); err != nil {
+			return nil, err
+		}
+	}
+	if req.ToAssetUUID != nil {
+		if to, err = lookupCashAsset(ctx, s.assetRepo, *req.ToAssetUUID, userID); err != nil {
+			return nil, err
+		}
+	}
+	if from == nil || to == nil {
+		return nil, errors.New("asset not found")
+	}
+	if from.ID == to.ID {
+		return nil, errors.New("source and destination assets must be different")
+	}
+	rate := existing.ExchangeRate
+	if req.ExchangeRate != nil || assetCurrency(from) != existing.FromCurrency || assetCurrency(to) != existing.ToCurrency {
+		if rate, err = s.resolveRate(ctx, assetCurrency(from), assetCurrency(to), req.ExchangeRate); err != nil {
+			return nil, err
+		}
+	}
+
+	var transfer *models.Transfer
+	err = s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		transfer, err = repos.Transfers.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get transfer: %w", err)
+		}
+		if transfer == nil {
+			return errors.New("transfer not found")
+		}
+		locked, err := lockTransferAssets(ctx, repos.Assets, transfer.FromAssetID, transfer.ToAssetID, from.ID, to.ID)
+		if err != nil {
+			return err
+		}
+
+		// What the source asset would hold once this transfer's current postings are undone
+		available := locked[from.ID].Quantity
+		if from.ID == transfer.FromAssetID {
+			available = available.Add(transferOutflow(transfer))
+		}
+		if from.ID == transfer.ToAssetID {
+			available = available.Sub(transfer.ToAmount)
+		}
+
+		if req.Amount != nil {
+			transfer.Amount = decimal.NewFromFloat(*req.Amount)
+		}
+		if req.Fee != nil {
+			transfer.Fee = decimalPtr(req.Fee)
+		}
+		if req.Note != nil {
+			transfer.Note = req.Note
+		}
+		if req.Date != nil {
+			transfer.Date = *req.Date
+		}
+		transfer.FromAssetID = from.ID
+		transfer.FromCurrency = assetCurrency(from)
+		transfer.ToAssetID = to.ID
+		transfer.ToCurrency = assetCurrency(to)
+		transfer.ExchangeRate = rate
+		transfer.ToAmount = transfer.Amount.Mul(rate).Round(2)
+
+		if transferOutflow(transfer).GreaterThan(available) {
+			return errors.New("transfer amount and fee cannot exceed the source asset balance")
+		}
+		if err := syncTransferFee(ctx, repos, transfer, locked); err != nil {
+			return err
+		}
+		if err := repos.Transfers.Update(ctx, transfer); err != nil {
+			return fmt.Errorf("update transfer: %w", err)
+		}
+		return postLedgerWithinBalance(ctx, repos, transferPosting(transfer), locked, "transferred funds to take back cannot exceed the destination asset balance")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetTransfer(ctx, userID, uuid)
+}
+
+func (s *TransferService) DeleteTransfer(ctx context.Context, userID int64, uuid string) error {
+	var transfer *models.Transfer
+	err := s.uow.Do(ctx, func(ctx context.Context, repos port.Repositories) error {
+		var err error
+		transfer, err = repos.Transfers.GetByUUIDForUpdate(ctx, uuid, userID)
+		if err != nil {
+			return fmt.Errorf("get transfer: %w", err)
+		}
+		if transfer == nil {
+			return errors.New("transfer not found")
+		}
+		locked, err := lockAssetsByID(ctx, repos.Assets, transfer.FromAssetID, transfer.ToAssetID)
+		if err != nil {
+			return err
+		}
+
+		// Drop the fee expense and reverse both legs
+		transfer.Fee = nil
+		if err := syncTransferFee(ctx, repos, transfer, locked); err != nil {
+			return err
+		}
+		reversal := transferPosting(transfer)
+		reversal.Description = "transfer deleted"
+		reversal.Lines = nil
+		if err := postLedgerWithinBalance(ctx, repos, reversal, locked, "transferred funds to take back cannot exceed the destination asset balance"); err != nil {
+			return err
+		}
+
+		if err := repos.Transfers.Delete(ctx, uuid, userID); err != nil {
+			return fmt.Errorf("delete transfer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	slog.Info("transfer_deleted", "user_id", userID, "uuid", uuid, "amount", transfer.Amount.String())
+	return nil
+}
+
+// resolveRate returns the rate to convert fromCurrency into toCurrency: the caller's override if given,
+// 1 for same-currency transfers, otherwise the current market rate.
+func (s *TransferService) resolveRate(ctx context.Context, fromCurrency, toCurrency string, override *float64) (decimal.Decimal, error) {
+	if override != nil {
+		return decimal.NewFromFloat(*override), nil
+	}
+	if fromCurrency == toCurrency {
+		return decimal.NewFromInt(1), nil
+	}
+	rate, err := s.priceSvc.GetExchangeRate(ctx, fromCurrency, toCurrency)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("get exchange rate: %w", err)
+	}
+	if rate <= 0 {
+		return decimal.Zero, fmt.Errorf("invalid exchange rate for %s to %s", fromCurrency, toCurrency)
+	}
+	return decimal.NewFromFloat(rate), nil
+}
+
+func validateTransferOptions(fee, exchangeRate *float64, note *string) error {
+	if fee != nil && *fee < 0 {
+		return errors.New("fee must not be negative")
+	}
+	if exchangeRate != nil && *exchangeRate <= 0 {
+		return errors.New("exchangeRate must be positive")
+	}
+	if note != nil {
+		if err := validation.CheckMaxLen(*note, validation.MaxNoteLen); err != nil {
+			return fmt.Errorf("note %w", err)
+		}
+	}
+	return nil
+}
+
+// lockTransferAssets locks every asset involved in a transfer and checks they are still CASH assets.
+func lockTransferAssets(ctx context.Context, assets port.AssetRepository, ids ...int64) (map[int64]*models.Asset, error) {
+	locked, err := lockAssetsByID(ctx, assets, ids...)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		if _, err := checkCashAsset(locked[id]); err != nil {
+			return nil, err
+		}
+	}
+	return locked, nil
+}
+
+// syncTransferFee keeps the fee expense linked to a transfer in line with transfer.Fee:
+// it is created, updated, or removed (when the fee is nil or zero) and posted to the ledger.
+func syncTransferFee(ctx context.Context, repos port.Repositories, transfer *models.Transfer, locked map[int64]*models.Asset) error {
+	var fee *models.Expense
+	if transfer.FeeExpenseID != nil {
+		var err error
+		if fee, err = repos.Expenses.GetByIDForUpdate(ctx, *transfer.FeeExpenseID); err != nil {
+			return fmt.Errorf("get fee expense: %w", err)
+		}
+	}
+
+	if transfer.Fee == nil || transfer.Fee.IsZero() {
+		transfer.Fee = nil
+		transfer.FeeExpenseID = nil
+		if fee == nil {
+			return nil
+		}
+		reversal := expensePosting(fee)
+		reversal.Description = "expense deleted"
+		reversal.Lines = nil
+		if err := postLedgerWithinBalance(ctx, repos, reversal, locked, "transferred funds to take back cannot exceed the destination asset balance"); err != nil {
+			return err
+		}
+		if err := repos.Expenses.Delete(ctx, fee.UUID, fee.UserID); err != nil {
+			return fmt.Errorf("delete fee expense: %w", err)
+		}
+		return nil
+	}
+
+	if fee == nil {
+		category, err := defaultCategoryByCode(ctx, repos.Categories, transfer.UserID, models.ExpenseCategoryOther)
+		if err != nil {
+			return err
+		}
+		note := transferFeeNote
+		fee = &models.Expense{UserID: transfer.UserID, CategoryID: category.ID, Category: category, Note: &note}
+	}
+	fee.AssetID = transfer.FromAssetID
+	fee.Amount = *transfer.Fee
+	fee.Date = transfer.Date
+	if fee.ID == 0 {
+		if err := repos.Expenses.Create(ctx, fee); err != nil {
+			return fmt.Errorf("create fee expense: %w", err)
+		}
+	} else if err := repos.Expenses.Update(ctx, fee); err != nil {
+		return fmt.Errorf("update fee expense: %w", err)
+	}
+	transfer.FeeExpenseID = &fee.ID
+	return postLedger(ctx, repos, expensePosting(fee), locked)
+}
+
+// transferOutflow is everything a transfer takes out of its source asset (amount plus fee).
+func transferOutflow(t *models.Transfer) decimal.Decimal {
+	if t.Fee == nil {
+		return t.Amount
+	}
+	return t.Amount.Add(*t.Fee)
+}
+
+// transferPosting moves Amount out of the source asset and ToAmount into the destination asset.
+// The clearing account absorbs the currency difference and nets to zero for same-currency transfers.
+func transferPosting(t *models.Transfer) ledgerPosting {
+	return ledgerPosting{
+		UserID:        t.UserID,
+		ReferenceType: models.LedgerRefTransfer,
+		ReferenceUUID: t.UUID,
+		Description:   "transfer",
+		OccurredAt:    t.Date,
+		Lines: []ledgerLine{
+			assetLine(t.FromAssetID, t.Amount.Neg()),
+			accountLine(models.LedgerAccountTransfer, t.Amount),
+			accountLine(models.LedgerAccountTransfer, t.ToAmount.Neg()),
+			assetLine(t.ToAssetID, t.ToAmount),
+		},
+	}
+}
+
+// assetCurrency is the currency a CASH asset is held in.
+func assetCurrency(a *models.Asset) string {
+	if c := strings.ToUpper(strings.TrimSpace(a.PurchaseCurrency)); c != "" {
+		return c
+	}
+	return port.DefaultCurrency
+}
+
+func decimalPtr(v *float64) *decimal.Decimal {
+	if v == nil {
+		return nil
+	}
+	d := decimal.NewFromFloat(*v)
+	return &d
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestTransferService_createAndDelete(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	from := store.cash(1, "IDR", 100)
+	to := store.cash(1, "IDR", 0)
+	svc := NewTransferService(store.transfers, store.assets, nil, store)
+
+	checkBalances := func(step string, wantFrom, wantTo, wantFee float64) {
+		t.Helper()
+		if got := store.quantity(from.ID); !got.Equal(decimal.NewFromFloat(wantFrom)) {
+			t.Errorf("%s: source quantity = %s, want %v", step, got, wantFrom)
+		}
+		if got := store.quantity(to.ID); !got.Equal(decimal.NewFromFloat(wantTo)) {
+			t.Errorf("%s: destination quantity = %s, want %v", step, got, wantTo)
+		}
+		if got := store.ledger.balance(models.LedgerAccountExpense+":"+string(models.ExpenseCategoryOther), 0); !got.Equal(decimal.NewFromFloat(wantFee)) {
+			t.Errorf("%s: fee expense account = %s, want %v", step, got, wantFee)
+		}
+		if got := store.ledger.balance(models.LedgerAccountTransfer, 0); !got.IsZero() {
+			t.Errorf("%s: clearing account = %s, want 0", step, got)
+		}
+	}
+
+	fee := 2.0
+	transfer, err := svc.CreateTransfer(ctx, 1, port.CreateTransferRequest{FromAssetUUID: from.UUID, ToAssetUUID: to.UUID, Amount: 30, Fee: &fee})
+	if err != nil {
+		t.Fatalf("CreateTransfer: %v", err)
+	}
+	checkBalances("create", 68, 30, 2)
+	if transfer.FeeExpenseID == nil || store.expenses.rows[*transfer.FeeExpenseID] == nil {
+		t.Errorf("create: fee expense not recorded")
+	}
+
+	_, err = svc.CreateTransfer(ctx, 1, port.CreateTransferRequest{FromAssetUUID: from.UUID, ToAssetUUID: to.UUID, Amount: 69})
+	if err == nil || !strings.Contains(err.Error(), "cannot exceed") {
+		t.Errorf("overdrawing create: err = %v, want balance error", err)
+	}
+	checkBalances("overdrawing create", 68, 30, 2)
+
+	// With the transferred funds spent, shrinking, retargeting or deleting the transfer cannot take them back
+	other := store.cash(1, "IDR", 0)
+	store.adjust(to.ID, -25)
+	checkBalances("spent", 68, 5, 2)
+	amount := 10.0
+	if _, err := svc.UpdateTransfer(ctx, 1, transfer.UUID, port.UpdateTransferRequest{Amount: &amount}); err == nil || !strings.Contains(err.Error(), "cannot exceed") {
+		t.Errorf("smaller transfer: err = %v, want balance error", err)
+	}
+	checkBalances("smaller transfer refused", 68, 5, 2)
+	if _, err := svc.UpdateTransfer(ctx, 1, transfer.UUID, port.UpdateTransferRequest{ToAssetUUID: &other.UUID}); err == nil || !strings.Contains(err.Error(), "cannot exceed") {
+		t.Errorf("retarget: err = %v, want balance error", err)
+	}
+	checkBalances("retarget refused", 68, 5, 2)
+	if got := store.quantity(other.ID); !got.IsZero() {
+		t.Errorf("retarget refused: other destination quantity = %s, want 0", got)
+	}
+	if err := svc.DeleteTransfer(ctx, 1, transfer.UUID); err == nil || !strings.Contains(err.Error(), "cannot exceed") {
+		t.Errorf("spent delete: err = %v, want balance error", err)
+	}
+	checkBalances("delete refused", 68, 5, 2)
+	store.adjust(to.ID, 25)
+
+	if err := svc.DeleteTransfer(ctx, 1, transfer.UUID); err != nil {
+		t.Fatalf("DeleteTransfer: %v", err)
+	}
+	checkBalances("delete", 100, 0, 0)
+	if len(store.expenses.rows) != 0 {
+		t.Errorf("delete: %d fee expenses left, want 0", len(store.expenses.rows))
+	}
+	if _, err := svc.GetTransfer(ctx, 1, transfer.UUID); err == nil {
+		t.Errorf("delete: transfer still found")
+	}
+}
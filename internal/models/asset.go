@@ -14,12 +14,18 @@ type Asset struct {
 	Type     AssetType       `gorm:"type:asset_type" json:"type"`
 	Quantity decimal.Decimal `gorm:"type:decimal(20,8)" json:"quantity"`
 	Symbol   *string         `json:"symbol,omitempty"`
+	// PriceProviderID pins the provider's ID for the symbol (CoinGecko coin ID for CRYPTO, e.g. "uniswap")
+	// when the ticker alone is ambiguous.
+	PriceProviderID *string `gorm:"type:varchar(100)" json:"priceProviderId,omitempty"`
 
 	// Purchase Information
 	PurchasePrice    decimal.Decimal `gorm:"type:decimal(20,8);default:0" json:"purchasePrice"`
 	PurchaseDate     time.Time       `json:"purchaseDate"`
 	PurchaseCurrency string          `gorm:"type:varchar(10);default:'USD'" json:"purchaseCurrency"`
 	TotalCost        decimal.Decimal `gorm:"type:decimal(20,8);default:0" json:"totalCost"`
+	// CostMethod matches sales against purchase lots; for non-cash assets Quantity, PurchasePrice (average
+	// unit cost), PurchaseDate (oldest open lot) and TotalCost are derived from the asset's transactions.
+	CostMethod CostMethod `gorm:"type:varchar(10);default:'FIFO'" json:"costMethod"`
 
 	// Additional Costs (optional)
 	TransactionFee  *decimal.Decimal `gorm:"type:decimal(20,8)" json:"transactionFee,omitempty"`
@@ -41,6 +47,9 @@ type Asset struct {
 	Status    AssetStatus      `gorm:"type:varchar(20);default:'ACTIVE'" json:"status"`
 	SoldAt    *time.Time       `json:"soldAt,omitempty"`
 	SoldPrice *decimal.Decimal `gorm:"type:decimal(20,8)" json:"soldPrice,omitempty"`
+	// RealizedProfitLoss is what the asset's sales made over the cost of the units sold, less fees, in the
+	// purchase currency. Status, SoldAt and SoldPrice follow the sales: a position sold down to zero is SOLD.
+	RealizedProfitLoss decimal.Decimal `gorm:"type:decimal(20,8);default:0" json:"realizedProfitLoss"`
 
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
@@ -53,4 +62,8 @@ type AssetPriceHistory struct {
 	Price      decimal.Decimal `gorm:"type:decimal(20,8)" json:"price"`
 	Source     string          `json:"source"`
 	RecordedAt time.Time       `json:"recordedAt"`
+	// Currency of Price; empty on rows recorded before snapshots existed.
+	Currency string `gorm:"type:varchar(10)" json:"currency,omitempty"`
+	// SnapshotDate is set on rows written by the scheduled snapshot job (one per asset per day).
+	SnapshotDate *time.Time `gorm:"type:date" json:"snapshotDate,omitempty"`
 }
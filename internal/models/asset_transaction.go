@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type AssetTransactionType string
+
+const (
+	AssetTransactionBuy         AssetTransactionType = "BUY"
+	AssetTransactionSell        AssetTransactionType = "SELL"
+	AssetTransactionTransferIn  AssetTransactionType = "TRANSFER_IN"
+	AssetTransactionTransferOut AssetTransactionType = "TRANSFER_OUT"
+	AssetTransactionFee         AssetTransactionType = "FEE"
+)
+
+// CostMethod is how disposals are matched against an asset's purchase lots.
+type CostMethod string
+
+const (
+	CostMethodFIFO    CostMethod = "FIFO"    // oldest lots are sold first
+	CostMethodAverage CostMethod = "AVERAGE" // every lot carries the weighted-average unit cost
+)
+
+// AssetTransaction is one movement of a non-cash asset. The asset's Quantity, TotalCost, PurchasePrice and
+// PurchaseDate are derived from its transactions.
+//
+// TotalAmount is what changed hands in the asset's currency (quantity × price unless given); Fee is paid on
+// top of a BUY/TRANSFER_IN and out of a SELL's proceeds. A TRANSFER_IN carries the cost basis brought along
+// in TotalAmount. A FEE removes Quantity units paid in kind and adds TotalAmount + Fee to the cost basis.
+type AssetTransaction struct {
+	ID              int64                `gorm:"primaryKey" json:"-"`
+	UUID            string               `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	AssetID         int64                `gorm:"index" json:"-"`
+	UserID          int64                `gorm:"index" json:"-"`
+	Type            AssetTransactionType `gorm:"type:varchar(20)" json:"type"`
+	Quantity        decimal.Decimal      `gorm:"type:decimal(20,8)" json:"quantity"`
+	PricePerUnit    decimal.Decimal      `gorm:"type:decimal(20,8)" json:"pricePerUnit"`
+	TotalAmount     decimal.Decimal      `gorm:"type:decimal(20,8)" json:"totalAmount"`
+	Fee             decimal.Decimal      `gorm:"type:decimal(20,8);default:0" json:"fee"`
+	Currency        string               `gorm:"type:varchar(10)" json:"currency"`
+	TransactionDate time.Time            `json:"transactionDate"`
+	Notes           *string              `gorm:"type:text" json:"notes,omitempty"`
+	// CashAssetID is the CASH asset a SELL's proceeds (TotalAmount - Fee) were credited to, converted at
+	// ExchangeRate from the asset's currency.
+	CashAssetID  *int64           `gorm:"index" json:"-"`
+	ExchangeRate *decimal.Decimal `gorm:"type:decimal(30,12)" json:"exchangeRate,omitempty"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
+}
+
+func (AssetTransaction) TableName() string { return "asset_transactions" }
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type BudgetPeriod string
+
+const (
+	// BudgetPeriodMonthly covers one calendar month.
+	BudgetPeriodMonthly BudgetPeriod = "MONTHLY"
+	// BudgetPeriodRolling repeats every month from its first month on.
+	BudgetPeriodRolling BudgetPeriod = "ROLLING"
+)
+
+// Budget limits the spending on an expense category and its subcategories. Month is the first day of the
+// month a MONTHLY budget covers, or of the first month of a ROLLING one. A MONTHLY budget takes precedence
+// over a ROLLING one for the same category and month. With Rollover, what a ROLLING budget leaves unspent
+// carries into the next month.
+type Budget struct {
+	ID         int64           `gorm:"primaryKey" json:"-"`
+	UUID       string          `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID     int64           `gorm:"index" json:"-"`
+	CategoryID int64           `gorm:"index" json:"-"`
+	Period     BudgetPeriod    `gorm:"type:varchar(10)" json:"period"`
+	Month      time.Time       `gorm:"type:date" json:"month"`
+	Amount     decimal.Decimal `gorm:"type:decimal(20,2)" json:"amount"`
+	Rollover   bool            `json:"rollover"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	UpdatedAt  time.Time       `json:"updatedAt"`
+
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Category groups a user's expenses. Every user has the former fixed categories as defaults, with Code set to
+// their ExpenseCategory value, and can add more. A subcategory's parent is always a top-level category, so
+// insights can roll subcategories up one level. An archived category stays on the records that use it but
+// takes no new ones.
+type Category struct {
+	ID         int64      `gorm:"primaryKey" json:"-"`
+	UUID       string     `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID     int64      `gorm:"index" json:"-"`
+	ParentID   *int64     `gorm:"index" json:"-"`
+	Name       string     `json:"name"`
+	Code       *string    `gorm:"type:varchar(20)" json:"code,omitempty"`
+	Icon       *string    `json:"icon,omitempty"`
+	Color      *string    `gorm:"type:varchar(7)" json:"color,omitempty"`
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+
+	Parent *Category `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// CryptoCoin is one entry of the CoinGecko coin list. ID is the CoinGecko coin ID (e.g. "bitcoin").
+type CryptoCoin struct {
+	ID            string    `gorm:"primaryKey" json:"id"`
+	Symbol        string    `json:"symbol"`
+	Name          string    `json:"name"`
+	MarketCapRank *int      `json:"marketCapRank,omitempty"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func (CryptoCoin) TableName() string { return "crypto_coins" }
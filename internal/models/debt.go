@@ -7,20 +7,23 @@ import (
 )
 
 type Debt struct {
-	ID         int64             `gorm:"primaryKey" json:"-"`
-	UUID       string            `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
-	UserID     int64             `gorm:"index" json:"-"`
-	PartyName  string            `gorm:"column:party_name" json:"partyName"`
-	Amount     decimal.Decimal   `gorm:"type:decimal(20,2)" json:"amount"`
-	PaidAmount decimal.Decimal   `gorm:"type:decimal(20,2);default:0" json:"paidAmount"`
-	DueDate    *time.Time        `json:"dueDate,omitempty"`
-	Status     ObligationStatus  `gorm:"type:obligation_status" json:"status"`
-	Note       *string           `json:"note,omitempty"`
-	AssetID    *int64            `gorm:"index" json:"-"`
-	CreatedAt  time.Time         `json:"createdAt"`
-	UpdatedAt  time.Time         `json:"updatedAt"`
+	ID              int64            `gorm:"primaryKey" json:"-"`
+	UUID            string           `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID          int64            `gorm:"index" json:"-"`
+	PartyName       string           `gorm:"column:party_name" json:"partyName"`
+	Amount          decimal.Decimal  `gorm:"type:decimal(20,2)" json:"amount"`
+	PaidAmount      decimal.Decimal  `gorm:"type:decimal(20,2);default:0" json:"paidAmount"`
+	DueDate         *time.Time       `json:"dueDate,omitempty"`
+	Status          ObligationStatus `gorm:"type:obligation_status" json:"status"`
+	StatusChangedAt *time.Time       `json:"statusChangedAt,omitempty"`
+	Note            *string          `json:"note,omitempty"`
+	AssetID         *int64           `gorm:"index" json:"-"`
+	Disbursed       bool             `gorm:"default:false" json:"disbursed"`
+	CreatedAt       time.Time        `json:"createdAt"`
+	UpdatedAt       time.Time        `json:"updatedAt"`
 
 	Asset *Asset `gorm:"foreignKey:AssetID" json:"asset,omitempty"`
+	Tags  []Tag  `gorm:"many2many:debt_tags" json:"tags,omitempty"`
 }
 
 func (Debt) TableName() string { return "debts" }
@@ -15,6 +15,7 @@ type DebtPayment struct {
 	Note      *string         `json:"note,omitempty"`
 	AssetID   *int64          `gorm:"index" json:"-"`
 	CreatedAt time.Time       `json:"createdAt"`
+	VoidedAt  *time.Time      `json:"voidedAt,omitempty"`
 
 	Debt *Debt `gorm:"foreignKey:DebtID" json:"debt,omitempty"`
 }
@@ -26,6 +26,7 @@ const (
 	AssetStatusPlanned AssetStatus = "PLANNED"
 )
 
+// ExpenseCategory is the code of a default category; see Category.
 type ExpenseCategory string
 
 const (
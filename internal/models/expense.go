@@ -7,16 +7,20 @@ import (
 )
 
 type Expense struct {
-	ID        int64           `gorm:"primaryKey" json:"-"`
-	UUID      string          `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
-	UserID    int64           `gorm:"index" json:"-"`
-	AssetID   int64           `gorm:"index" json:"-"`
-	Amount    decimal.Decimal `gorm:"type:decimal(20,2)" json:"amount"`
-	Category  ExpenseCategory `gorm:"type:expense_category" json:"category"`
-	Note      *string         `json:"note,omitempty"`
-	Date      time.Time       `json:"date"`
-	CreatedAt time.Time       `json:"createdAt"`
+	ID         int64           `gorm:"primaryKey" json:"-"`
+	UUID       string          `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID     int64           `gorm:"index" json:"-"`
+	AssetID    int64           `gorm:"index" json:"-"`
+	Amount     decimal.Decimal `gorm:"type:decimal(20,2)" json:"amount"`
+	CategoryID int64           `gorm:"index" json:"-"`
+	Note       *string         `json:"note,omitempty"`
+	Date       time.Time       `json:"date"`
+	CreatedAt  time.Time       `json:"createdAt"`
 
 	// Belongs-to: the CASH asset this expense draws from
-	Asset *Asset `gorm:"foreignKey:AssetID" json:"asset,omitempty"`
+	Asset    *Asset    `gorm:"foreignKey:AssetID" json:"asset,omitempty"`
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Tags     []Tag     `gorm:"many2many:expense_tags" json:"tags,omitempty"`
+	// Splits divide the expense across categories; Category is then the first line's.
+	Splits []ExpenseSplit `gorm:"foreignKey:ExpenseID" json:"splits,omitempty"`
 }
@@ -0,0 +1,14 @@
+package models
+
+import "github.com/shopspring/decimal"
+
+// ExpenseSplit is one category's share of a split expense. The lines of an expense add up to its Amount.
+type ExpenseSplit struct {
+	ID         int64           `gorm:"primaryKey" json:"-"`
+	ExpenseID  int64           `gorm:"index" json:"-"`
+	CategoryID int64           `gorm:"index" json:"-"`
+	Amount     decimal.Decimal `gorm:"type:decimal(20,2)" json:"amount"`
+	Note       *string         `json:"note,omitempty"`
+
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// FXRate is the daily close of a currency pair: one unit of Base buys Rate units of Quote on RateDate.
+// Rows are stored against USD; other pairs are triangulated.
+type FXRate struct {
+	ID        int64           `gorm:"primaryKey" json:"-"`
+	Base      string          `json:"base"`
+	Quote     string          `json:"quote"`
+	RateDate  time.Time       `gorm:"type:date" json:"rateDate"`
+	Rate      decimal.Decimal `gorm:"type:decimal(30,12)" json:"rate"`
+	Source    string          `json:"source"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+func (FXRate) TableName() string { return "fx_rates" }
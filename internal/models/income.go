@@ -6,6 +6,17 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// IncomeType is the kind of distribution an income from a source asset is.
+type IncomeType string
+
+const (
+	IncomeTypeDividend IncomeType = "DIVIDEND"
+	IncomeTypeCoupon   IncomeType = "COUPON"
+	IncomeTypeInterest IncomeType = "INTEREST"
+	IncomeTypeRent     IncomeType = "RENT"
+	IncomeTypeOther    IncomeType = "OTHER"
+)
+
 type Income struct {
 	ID        int64           `gorm:"primaryKey" json:"-"`
 	UUID      string          `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
@@ -16,7 +27,12 @@ type Income struct {
 	Note      *string         `json:"note,omitempty"`
 	Date      time.Time       `json:"date"`
 	CreatedAt time.Time       `json:"createdAt"`
+	// SourceAssetID is the asset that earned the income, if any; Type is then the kind of distribution.
+	SourceAssetID *int64      `gorm:"index" json:"-"`
+	Type          *IncomeType `gorm:"column:income_type;type:varchar(20)" json:"type,omitempty"`
 
 	// Belongs-to: the CASH asset this income goes into
-	Asset *Asset `gorm:"foreignKey:AssetID" json:"asset,omitempty"`
+	Asset       *Asset `gorm:"foreignKey:AssetID" json:"asset,omitempty"`
+	SourceAsset *Asset `gorm:"foreignKey:SourceAssetID" json:"sourceAsset,omitempty"`
+	Tags        []Tag  `gorm:"many2many:income_tags" json:"tags,omitempty"`
 }
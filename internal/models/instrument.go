@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// Instrument is a listed security in the instrument registry. The same ticker may be listed on several
+// exchanges; (Symbol, Exchange) is unique.
+type Instrument struct {
+	ID             int64     `gorm:"primaryKey" json:"-"`
+	Symbol         string    `json:"symbol"`
+	Exchange       string    `json:"exchange"`
+	ProviderSymbol string    `json:"providerSymbol"` // symbol on the price provider (Yahoo), e.g. BBRI.JK
+	LotSize        int64     `json:"lotSize"`        // shares per lot; asset quantities of this instrument are in lots
+	Currency       string    `json:"currency"`
+	Timezone       string    `json:"timezone"`
+	OpenTime       string    `json:"openTime"`  // local trading start, HH:MM
+	CloseTime      string    `json:"closeTime"` // local trading end, HH:MM
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobStatusPending   JobStatus = "PENDING"
+	JobStatusRunning   JobStatus = "RUNNING"
+	JobStatusSucceeded JobStatus = "SUCCEEDED"
+	// JobStatusDead marks a job that failed MaxAttempts times; it stays in the table for inspection.
+	JobStatusDead JobStatus = "DEAD"
+)
+
+// Job is one run of a scheduled job. ScheduledFor is the schedule slot that produced it and is unique
+// per Name, so two replicas enqueueing the same slot end up with a single row.
+type Job struct {
+	ID           int64      `gorm:"primaryKey" json:"-"`
+	UUID         string     `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	Name         string     `json:"name"`
+	ScheduledFor time.Time  `json:"scheduledFor"`
+	RunAt        time.Time  `json:"runAt"`
+	Status       JobStatus  `gorm:"type:job_status;default:PENDING" json:"status"`
+	Attempts     int        `json:"attempts"`
+	MaxAttempts  int        `json:"maxAttempts"`
+	LastError    *string    `json:"lastError,omitempty"`
+	LockedBy     *string    `json:"lockedBy,omitempty"`
+	LockedAt     *time.Time `json:"lockedAt,omitempty"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+}
+
+func (Job) TableName() string { return "scheduler_jobs" }
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type LedgerReferenceType string
+
+const (
+	LedgerRefIncome            LedgerReferenceType = "INCOME"
+	LedgerRefDebt              LedgerReferenceType = "DEBT"       // borrowed cash received
+	LedgerRefReceivable        LedgerReferenceType = "RECEIVABLE" // lent cash handed out
+	LedgerRefExpense           LedgerReferenceType = "EXPENSE"
+	LedgerRefDebtPayment       LedgerReferenceType = "DEBT_PAYMENT"
+	LedgerRefReceivablePayment LedgerReferenceType = "RECEIVABLE_PAYMENT"
+	LedgerRefTransfer          LedgerReferenceType = "TRANSFER"
+	LedgerRefAdjustment        LedgerReferenceType = "ADJUSTMENT"
+	LedgerRefOpeningBalance    LedgerReferenceType = "OPENING_BALANCE"
+	LedgerRefAssetSale         LedgerReferenceType = "ASSET_SALE" // proceeds of a SELL asset transaction
+)
+
+// Ledger accounts. Entries on LedgerAccountAsset also carry the CASH asset they belong to;
+// expense entries are suffixed with the category (e.g. "EXPENSE:FOOD").
+const (
+	LedgerAccountAsset          = "ASSET"
+	LedgerAccountIncome         = "INCOME"
+	LedgerAccountExpense        = "EXPENSE"
+	LedgerAccountDebt           = "LIABILITY:DEBT"
+	LedgerAccountReceivable     = "RECEIVABLE"
+	LedgerAccountOpeningBalance = "EQUITY:OPENING"
+	LedgerAccountAdjustment     = "EQUITY:ADJUSTMENT"
+	// LedgerAccountTransfer balances transfers between CASH assets held in different currencies.
+	LedgerAccountTransfer = "CLEARING:TRANSFER"
+	// LedgerAccountAssetSale balances sale proceeds: the non-cash position they came from is not in the ledger.
+	LedgerAccountAssetSale = "CLEARING:ASSET_SALE"
+)
+
+// LedgerTransaction is one balanced posting: the amounts of its entries always sum to zero.
+// Positive amounts are debits, negative amounts are credits.
+type LedgerTransaction struct {
+	ID            int64               `gorm:"primaryKey" json:"-"`
+	UUID          string              `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID        int64               `gorm:"index" json:"-"`
+	ReferenceType LedgerReferenceType `gorm:"type:varchar(30)" json:"referenceType"`
+	ReferenceUUID string              `gorm:"type:uuid" json:"referenceUuid"`
+	Description   string              `json:"description"`
+	OccurredAt    time.Time           `json:"occurredAt"`
+	CreatedAt     time.Time           `json:"createdAt"`
+
+	Entries []LedgerEntry `gorm:"foreignKey:TransactionID" json:"entries,omitempty"`
+}
+
+type LedgerEntry struct {
+	ID            int64           `gorm:"primaryKey" json:"-"`
+	UUID          string          `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	TransactionID int64           `gorm:"index" json:"-"`
+	AssetID       *int64          `gorm:"index" json:"-"`
+	Account       string          `json:"account"`
+	Amount        decimal.Decimal `gorm:"type:decimal(20,8)" json:"amount"`
+}
@@ -15,6 +15,7 @@ type ReceivablePayment struct {
 	Note          *string         `json:"note,omitempty"`
 	AssetID       *int64          `gorm:"index" json:"-"`
 	CreatedAt     time.Time       `json:"createdAt"`
+	VoidedAt      *time.Time      `json:"voidedAt,omitempty"`
 
 	Receivable *Receivable `gorm:"foreignKey:ReceivableID" json:"receivable,omitempty"`
 }
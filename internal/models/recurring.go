@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RecurringKind string
+
+const (
+	RecurringKindExpense RecurringKind = "EXPENSE"
+	RecurringKindIncome  RecurringKind = "INCOME"
+)
+
+type RecurringFrequency string
+
+const (
+	RecurringDaily   RecurringFrequency = "DAILY"
+	RecurringWeekly  RecurringFrequency = "WEEKLY"
+	RecurringMonthly RecurringFrequency = "MONTHLY"
+	RecurringYearly  RecurringFrequency = "YEARLY"
+)
+
+// RecurringRule repeats an expense or income every Interval days, weeks, months or years from StartDate,
+// until EndDate if set. Monthly and yearly occurrences keep StartDate's day, moved back to the last day of
+// shorter months. Occurrences are counted from zero; NextIndex and NextDue are the first one not yet
+// generated, and NextDue is nil once the rule has ended.
+type RecurringRule struct {
+	ID         int64              `gorm:"primaryKey" json:"-"`
+	UUID       string             `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID     int64              `gorm:"index" json:"-"`
+	AssetID    int64              `gorm:"index" json:"-"`
+	Kind       RecurringKind      `gorm:"type:varchar(10)" json:"kind"`
+	Frequency  RecurringFrequency `gorm:"type:varchar(10)" json:"frequency"`
+	Interval   int                `gorm:"column:interval_count;default:1" json:"interval"`
+	Amount     decimal.Decimal    `gorm:"type:decimal(20,2)" json:"amount"`
+	CategoryID *int64             `json:"-"`                // EXPENSE
+	Source     *string            `json:"source,omitempty"` // INCOME
+	Note       *string            `json:"note,omitempty"`
+	StartDate  time.Time          `json:"startDate"`
+	EndDate    *time.Time         `json:"endDate,omitempty"`
+	// AutoPost posts occurrences when they fall due; otherwise they wait as PENDING for confirmation.
+	AutoPost  bool       `gorm:"default:true" json:"autoPost"`
+	NextIndex int        `json:"-"`
+	NextDue   *time.Time `json:"nextDue,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+
+	// Belongs-to: the CASH asset occurrences draw from or go into
+	Asset    *Asset    `gorm:"foreignKey:AssetID" json:"asset,omitempty"`
+	Category *Category `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+type OccurrenceStatus string
+
+const (
+	// OccurrenceScheduled is a future occurrence edited ahead of time.
+	OccurrenceScheduled OccurrenceStatus = "SCHEDULED"
+	OccurrenceSkipped   OccurrenceStatus = "SKIPPED"
+	// OccurrencePending is due and waits for confirmation, or failed to post (LastError says why).
+	OccurrencePending OccurrenceStatus = "PENDING"
+	OccurrencePosted  OccurrenceStatus = "POSTED"
+)
+
+// RecurringOccurrence records an occurrence of a rule that is not simply the rule posted as is. Amount and
+// Note, when set, replace the rule's for this occurrence only.
+type RecurringOccurrence struct {
+	ID        int64            `gorm:"primaryKey" json:"-"`
+	UUID      string           `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	RuleID    int64            `gorm:"index" json:"-"`
+	UserID    int64            `gorm:"index" json:"-"`
+	Index     int              `gorm:"column:occurrence_index" json:"index"`
+	DueDate   time.Time        `json:"dueDate"`
+	Status    OccurrenceStatus `gorm:"type:varchar(10)" json:"status"`
+	Amount    *decimal.Decimal `gorm:"type:decimal(20,2)" json:"amount,omitempty"`
+	Note      *string          `json:"note,omitempty"`
+	ExpenseID *int64           `json:"-"`
+	IncomeID  *int64           `json:"-"`
+	LastError *string          `json:"lastError,omitempty"`
+	PostedAt  *time.Time       `json:"postedAt,omitempty"`
+	CreatedAt time.Time        `json:"createdAt"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+
+	Expense *Expense `gorm:"foreignKey:ExpenseID" json:"expense,omitempty"`
+	Income  *Income  `gorm:"foreignKey:IncomeID" json:"income,omitempty"`
+}
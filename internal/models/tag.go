@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Tag is a free-form label on expenses, incomes, debts and receivables. Name is trimmed and lower-cased and
+// unique per user.
+type Tag struct {
+	ID        int64     `gorm:"primaryKey" json:"-"`
+	UUID      string    `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID    int64     `gorm:"index" json:"-"`
+	Name      string    `gorm:"type:varchar(50)" json:"name"`
+	CreatedAt time.Time `json:"createdAt"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// Transfer moves money between two CASH assets of the same user. Amount leaves FromAsset in its currency;
+// ToAmount (Amount * ExchangeRate) arrives in ToAsset. An optional fee is charged to FromAsset as a separate expense.
+type Transfer struct {
+	ID           int64            `gorm:"primaryKey" json:"-"`
+	UUID         string           `gorm:"type:uuid;default:gen_random_uuid()" json:"uuid"`
+	UserID       int64            `gorm:"index" json:"-"`
+	FromAssetID  int64            `gorm:"index" json:"-"`
+	ToAssetID    int64            `gorm:"index" json:"-"`
+	Amount       decimal.Decimal  `gorm:"type:decimal(20,2)" json:"amount"`
+	FromCurrency string           `gorm:"type:varchar(10)" json:"fromCurrency"`
+	ToAmount     decimal.Decimal  `gorm:"type:decimal(20,2)" json:"toAmount"`
+	ToCurrency   string           `gorm:"type:varchar(10)" json:"toCurrency"`
+	ExchangeRate decimal.Decimal  `gorm:"type:decimal(20,8)" json:"exchangeRate"`
+	Fee          *decimal.Decimal `gorm:"type:decimal(20,2)" json:"fee,omitempty"`
+	FeeExpenseID *int64           `json:"-"`
+	Note         *string          `json:"note,omitempty"`
+	Date         time.Time        `json:"date"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	UpdatedAt    time.Time        `json:"updatedAt"`
+
+	FromAsset  *Asset   `gorm:"foreignKey:FromAssetID" json:"fromAsset,omitempty"`
+	ToAsset    *Asset   `gorm:"foreignKey:ToAssetID" json:"toAsset,omitempty"`
+	FeeExpense *Expense `gorm:"foreignKey:FeeExpenseID" json:"feeExpense,omitempty"`
+}
@@ -22,7 +22,12 @@ const (
 	MaxAssetNameLen    = 200
 	MaxDescriptionLen  = 2000
 	MaxSymbolLen       = 20
+	MaxProviderIDLen   = 100
 	MaxYieldPeriodLen  = 20
+	MaxCategoryNameLen = 100
+	MaxIconLen         = 50
+	MaxTagLen          = 50
+	MaxTags            = 20 // per expense, income, debt or receivable
 )
 
 var emailRegex = regexp.MustCompile(`^[^@]+@[^@]+\.[^@]+$`)
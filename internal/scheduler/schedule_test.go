@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse_Next(t *testing.T) {
+	from := time.Date(2025, 3, 14, 10, 7, 30, 0, time.UTC) // a Friday
+	tests := []struct {
+		name string
+		spec string
+		want time.Time
+	}{
+		{"every 15m aligned", "@every 15m", time.Date(2025, 3, 14, 10, 15, 0, 0, time.UTC)},
+		{"hourly", "@hourly", time.Date(2025, 3, 14, 11, 0, 0, 0, time.UTC)},
+		{"daily", "@daily", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"weekly on sunday", "@weekly", time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"monthly", "@monthly", time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"every 5 minutes", "*/5 * * * *", time.Date(2025, 3, 14, 10, 10, 0, 0, time.UTC)},
+		{"list of hours", "30 6,18 * * *", time.Date(2025, 3, 14, 18, 30, 0, 0, time.UTC)},
+		{"weekday range", "0 9 * * 1-5", time.Date(2025, 3, 17, 9, 0, 0, 0, time.UTC)},
+		{"sunday as 7", "0 0 * * 7", time.Date(2025, 3, 16, 0, 0, 0, 0, time.UTC)},
+		{"day of month or weekday", "0 0 20 * 6", time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"leap day", "0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"never matches", "0 0 30 2 *", time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.spec, err)
+			}
+			if got := s.Next(from); !got.Equal(tt.want) {
+				t.Errorf("Parse(%q).Next(%s) = %s, want %s", tt.spec, from, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "0 0 0 * *", "*/0 * * * *", "5-1 * * * *", "@every 10ms", "@every soon"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) expected error", spec)
+		}
+	}
+}
+
+func Test_latestSlot(t *testing.T) {
+	s, _ := Parse("@every 1h")
+	now := time.Date(2025, 3, 14, 10, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		from   time.Time
+		want   time.Time
+		wantOK bool
+	}{
+		{"not due yet", time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC), time.Time{}, false},
+		{"one slot due", time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC), time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC), true},
+		{"missed slots collapse to latest", time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 14, 10, 0, 0, 0, time.UTC), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := latestSlot(s, tt.from, now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("latestSlot(%s) = %s, %v; want %s, %v", tt.from, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func Test_retryDelay(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{3, 2 * time.Minute},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(30*time.Second, tt.attempt); got != tt.want {
+			t.Errorf("retryDelay(30s, %d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
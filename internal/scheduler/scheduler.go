@@ -0,0 +1,412 @@
+// Package scheduler runs periodic background jobs (price refreshes, overdue sweeps, recurring transactions).
+//
+// Every registered job has a Schedule. On each tick the leader replica, chosen through a
+// port.LeaderElector, enqueues the latest due slot of every job into the job table; a slot is unique
+// per job, so a leadership hand-over cannot enqueue it twice. Every replica then claims due rows with
+// SKIP LOCKED and runs them, so a row is only ever run by one process. Failed runs are retried with
+// exponential backoff and jitter and move to DEAD after MaxAttempts.
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+)
+
+// JobFunc does the work of one job run. ctx is cancelled when the run times out
+// or when Shutdown gives up waiting for it.
+type JobFunc func(ctx context.Context) error
+
+// JobOptions tune how a job is retried. Zero values use the defaults below.
+type JobOptions struct {
+	MaxAttempts int           // runs before the job is dead-lettered (default 5)
+	Timeout     time.Duration // per run (default 5m)
+	Backoff     time.Duration // delay before the first retry, doubled on each later one (default 30s)
+	// RunOnStart also runs the job once when the scheduler starts, if this process leads on its first tick;
+	// the run replaces any slots missed while no replica was running.
+	RunOnStart bool
+}
+
+const (
+	defaultMaxAttempts = 5
+	defaultTimeout     = 5 * time.Minute
+	defaultBackoff     = 30 * time.Second
+	maxBackoff         = time.Hour
+	// maxCatchUpSlots bounds how far enqueueDue walks forward after downtime.
+	maxCatchUpSlots = 100000
+)
+
+type Config struct {
+	WorkerID     string        // identifies this process in locked_by (default hostname:pid)
+	PollInterval time.Duration // how often to schedule and claim jobs (default 15s)
+	Concurrency  int           // jobs run at once by this process (default 4)
+	Retention    time.Duration // how long SUCCEEDED rows are kept (default 7 days)
+}
+
+type entry struct {
+	name     string
+	schedule Schedule
+	fn       JobFunc
+	opts     JobOptions
+}
+
+type Scheduler struct {
+	repo   port.JobRepository
+	leader port.LeaderElector
+	cfg    Config
+
+	mu      sync.Mutex
+	entries map[string]*entry
+	names   []string
+	started time.Time
+
+	sem       chan struct{}
+	running   sync.WaitGroup
+	runCtx    context.Context
+	cancelRun context.CancelFunc
+	stopLoop  context.CancelFunc
+	loopDone  chan struct{}
+	lastPurge time.Time
+	isLeader  bool
+	firstTick bool // set once the first tick after Start has run
+}
+
+func New(repo port.JobRepository, leader port.LeaderElector, cfg Config) *Scheduler {
+	if cfg.WorkerID == "" {
+		host, _ := os.Hostname()
+		cfg.WorkerID = fmt.Sprintf("%s:%d", host, os.Getpid())
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 15 * time.Second
+	}
+	if cfg.Concurrency < 1 {
+		cfg.Concurrency = 4
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	return &Scheduler{
+		repo:    repo,
+		leader:  leader,
+		cfg:     cfg,
+		entries: make(map[string]*entry),
+		sem:     make(chan struct{}, cfg.Concurrency),
+	}
+}
+
+// Register adds a job. It must be called before Start.
+func (s *Scheduler) Register(name, spec string, fn JobFunc, opts JobOptions) error {
+	sched, err := Parse(spec)
+	if err != nil {
+		return fmt.Errorf("job %s: %w", name, err)
+	}
+	if opts.MaxAttempts < 1 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = defaultBackoff
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started.IsZero() {
+		return errors.New("scheduler already started")
+	}
+	if _, ok := s.entries[name]; ok {
+		return fmt.Errorf("job %s already registered", name)
+	}
+	s.entries[name] = &entry{name: name, schedule: sched, fn: fn, opts: opts}
+	s.names = append(s.names, name)
+	return nil
+}
+
+// Start begins polling in the background until Shutdown.
+func (s *Scheduler) Start() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.started.IsZero() {
+		return
+	}
+	s.started = time.Now().UTC()
+	s.runCtx, s.cancelRun = context.WithCancel(context.Background())
+	loopCtx, stop := context.WithCancel(context.Background())
+	s.stopLoop = stop
+	s.loopDone = make(chan struct{})
+	go s.loop(loopCtx)
+	slog.Info("scheduler started", "worker", s.cfg.WorkerID, "jobs", s.names)
+}
+
+// Shutdown stops claiming new jobs and waits for running ones to finish. If ctx expires first the
+// running jobs are cancelled; their rows are picked up again as stale by the next leader.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	if s.started.IsZero() || s.stopLoop == nil {
+		s.mu.Unlock()
+		return nil
+	}
+	stop := s.stopLoop
+	s.stopLoop = nil
+	s.mu.Unlock()
+
+	stop()
+	<-s.loopDone
+
+	drained := make(chan struct{})
+	go func() {
+		s.running.Wait()
+		close(drained)
+	}()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		s.cancelRun()
+		err = fmt.Errorf("scheduler: jobs still running at shutdown: %w", ctx.Err())
+	}
+	s.cancelRun()
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if relErr := s.leader.Release(releaseCtx); relErr != nil {
+		slog.Warn("scheduler: release leadership", "error", relErr)
+	}
+	return err
+}
+
+func (s *Scheduler) loop(ctx context.Context) {
+	defer close(s.loopDone)
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	now := time.Now().UTC()
+	leader, err := s.leader.TryAcquire(ctx)
+	if err != nil && ctx.Err() == nil {
+		slog.Warn("scheduler: leader election", "error", err)
+	}
+	if leader != s.isLeader {
+		s.isLeader = leader
+		slog.Info("scheduler leadership changed", "worker", s.cfg.WorkerID, "leader", leader)
+	}
+	if leader {
+		if !s.firstTick {
+			s.enqueueStartRuns(ctx)
+		}
+		s.enqueueDue(ctx, now)
+		s.maintain(ctx, now)
+	}
+	s.firstTick = true
+	s.runDue(ctx, now)
+}
+
+// enqueueStartRuns enqueues a run of every RunOnStart job in the slot of the scheduler's start time. Being
+// newer than any slot enqueued before, it also keeps enqueueDue from adding a catch-up run next to it.
+func (s *Scheduler) enqueueStartRuns(ctx context.Context) {
+	for _, name := range s.names {
+		e := s.entries[name]
+		if !e.opts.RunOnStart {
+			continue
+		}
+		job := &models.Job{
+			Name:         name,
+			ScheduledFor: s.started,
+			RunAt:        s.started,
+			Status:       models.JobStatusPending,
+			MaxAttempts:  e.opts.MaxAttempts,
+			CreatedAt:    s.started,
+			UpdatedAt:    s.started,
+		}
+		if _, err := s.repo.Enqueue(ctx, job); err != nil {
+			slog.Error("scheduler: enqueue start run", "job", name, "error", err)
+		}
+	}
+}
+
+// enqueueDue enqueues the latest slot at or before now for every job. Slots missed while no replica
+// was running are collapsed into that one run instead of being replayed.
+func (s *Scheduler) enqueueDue(ctx context.Context, now time.Time) {
+	for _, name := range s.names {
+		e := s.entries[name]
+		last, err := s.repo.LastScheduledFor(ctx, name)
+		if err != nil {
+			slog.Error("scheduler: last scheduled slot", "job", name, "error", err)
+			continue
+		}
+		from := s.started
+		if last != nil {
+			from = *last
+		}
+		slot, ok := latestSlot(e.schedule, from, now)
+		if !ok {
+			continue
+		}
+		job := &models.Job{
+			Name:         name,
+			ScheduledFor: slot,
+			RunAt:        now,
+			Status:       models.JobStatusPending,
+			MaxAttempts:  e.opts.MaxAttempts,
+			CreatedAt:    now,
+			UpdatedAt:    now,
+		}
+		if _, err := s.repo.Enqueue(ctx, job); err != nil {
+			slog.Error("scheduler: enqueue", "job", name, "error", err)
+		}
+	}
+}
+
+// maintain requeues runs abandoned by a dead worker and purges old successful runs.
+func (s *Scheduler) maintain(ctx context.Context, now time.Time) {
+	var longest time.Duration
+	for _, e := range s.entries {
+		longest = max(longest, e.opts.Timeout)
+	}
+	cutoff := now.Add(-(longest + 2*s.cfg.PollInterval))
+	if n, err := s.repo.RequeueStale(ctx, cutoff, now); err != nil {
+		slog.Error("scheduler: requeue stale jobs", "error", err)
+	} else if n > 0 {
+		slog.Warn("scheduler: requeued stale jobs", "count", n)
+	}
+
+	if now.Sub(s.lastPurge) < time.Hour {
+		return
+	}
+	s.lastPurge = now
+	if _, err := s.repo.PurgeSucceeded(ctx, now.Add(-s.cfg.Retention)); err != nil {
+		slog.Error("scheduler: purge succeeded jobs", "error", err)
+	}
+}
+
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	free := cap(s.sem) - len(s.sem)
+	if free == 0 {
+		return
+	}
+	jobs, err := s.repo.ClaimDue(ctx, s.names, s.cfg.WorkerID, now, free)
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.Error("scheduler: claim jobs", "error", err)
+		}
+		return
+	}
+	for i := range jobs {
+		job := jobs[i]
+		e, ok := s.entries[job.Name]
+		if !ok {
+			continue
+		}
+		s.sem <- struct{}{}
+		s.running.Add(1)
+		go func() {
+			defer func() {
+				<-s.sem
+				s.running.Done()
+			}()
+			s.execute(e, &job)
+		}()
+	}
+}
+
+func (s *Scheduler) execute(e *entry, job *models.Job) {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(s.runCtx, e.opts.Timeout)
+	err := runJob(ctx, e.fn)
+	cancel()
+
+	now := time.Now().UTC()
+	job.LockedBy = nil
+	job.LockedAt = nil
+	job.UpdatedAt = now
+	switch {
+	case err == nil:
+		job.Status = models.JobStatusSucceeded
+		job.FinishedAt = &now
+		slog.Debug("job_succeeded", "job", job.Name, "attempt", job.Attempts, "duration", time.Since(started))
+	case job.Attempts >= job.MaxAttempts:
+		msg := err.Error()
+		job.Status = models.JobStatusDead
+		job.LastError = &msg
+		job.FinishedAt = &now
+		slog.Error("job_dead_lettered", "job", job.Name, "uuid", job.UUID, "attempts", job.Attempts, "error", err)
+	default:
+		msg := err.Error()
+		delay := jitter(retryDelay(e.opts.Backoff, job.Attempts))
+		job.Status = models.JobStatusPending
+		job.LastError = &msg
+		job.RunAt = now.Add(delay)
+		slog.Warn("job_failed", "job", job.Name, "attempt", job.Attempts, "retry_in", delay, "error", err)
+	}
+
+	// The run context may already be cancelled by Shutdown; the result must still be saved.
+	saveCtx, cancelSave := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelSave()
+	if err := s.repo.Update(saveCtx, job); err != nil {
+		slog.Error("scheduler: save job result", "job", job.Name, "error", err)
+	}
+}
+
+// runJob calls fn, turning a panic into an error so one bad job cannot take the server down.
+func runJob(ctx context.Context, fn JobFunc) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+// latestSlot returns the newest slot after from that is not after now.
+func latestSlot(sched Schedule, from, now time.Time) (time.Time, bool) {
+	slot := sched.Next(from)
+	if slot.IsZero() || slot.After(now) {
+		return time.Time{}, false
+	}
+	for i := 0; i < maxCatchUpSlots; i++ {
+		next := sched.Next(slot)
+		if next.IsZero() || next.After(now) {
+			break
+		}
+		slot = next
+	}
+	return slot, true
+}
+
+// retryDelay is base doubled for every attempt after the first, capped at maxBackoff.
+func retryDelay(base time.Duration, attempt int) time.Duration {
+	d := base
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return min(d, maxBackoff)
+}
+
+// jitter adds up to 20% so jobs failing together do not retry in lockstep.
+func jitter(d time.Duration) time.Duration {
+	if d <= 0 {
+		return d
+	}
+	return d + rand.N(d/5+1)
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"monity/internal/core/port"
+	"monity/internal/models"
+)
+
+// memJobRepo is an in-memory port.JobRepository that only records enqueued jobs.
+type memJobRepo struct {
+	port.JobRepository
+	jobs []models.Job
+}
+
+func (r *memJobRepo) Enqueue(ctx context.Context, job *models.Job) (bool, error) {
+	for _, j := range r.jobs {
+		if j.Name == job.Name && j.ScheduledFor.Equal(job.ScheduledFor) {
+			return false, nil
+		}
+	}
+	r.jobs = append(r.jobs, *job)
+	return true, nil
+}
+
+func (r *memJobRepo) LastScheduledFor(ctx context.Context, name string) (*time.Time, error) {
+	var last *time.Time
+	for i, j := range r.jobs {
+		if j.Name == name && (last == nil || j.ScheduledFor.After(*last)) {
+			last = &r.jobs[i].ScheduledFor
+		}
+	}
+	return last, nil
+}
+
+func (r *memJobRepo) ClaimDue(ctx context.Context, names []string, workerID string, now time.Time, limit int) ([]models.Job, error) {
+	return nil, nil
+}
+
+func (r *memJobRepo) RequeueStale(ctx context.Context, cutoff, now time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (r *memJobRepo) PurgeSucceeded(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fixedLeader bool
+
+func (l fixedLeader) TryAcquire(ctx context.Context) (bool, error) { return bool(l), nil }
+func (l fixedLeader) Release(ctx context.Context) error            { return nil }
+
+func TestScheduler_runOnStart(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+	tests := []struct {
+		name   string
+		leader bool
+		want   []string
+	}{
+		{"leader runs start jobs once", true, []string{"sweep"}},
+		{"follower leaves them to the leader", false, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memJobRepo{}
+			s := New(repo, fixedLeader(tt.leader), Config{})
+			if err := s.Register("sweep", "@daily", noop, JobOptions{RunOnStart: true}); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Register("snapshot", "@daily", noop, JobOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			s.started = time.Now().UTC()
+
+			s.tick(context.Background())
+			s.tick(context.Background())
+			var got []string
+			for _, j := range repo.jobs {
+				got = append(got, j.Name)
+				if !j.ScheduledFor.Equal(s.started) {
+					t.Errorf("%s scheduled for %s, want the start time %s", j.Name, j.ScheduledFor, s.started)
+				}
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("enqueued %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
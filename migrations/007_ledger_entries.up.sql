@@ -0,0 +1,75 @@
+-- Double-entry ledger behind every CASH balance change.
+-- Each ledger transaction groups entries whose amounts sum to zero (positive = debit, negative = credit).
+-- CASH asset quantity equals the sum of its ASSET entries.
+CREATE TABLE ledger_transactions (
+  id             BIGSERIAL PRIMARY KEY,
+  uuid           UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  user_id        BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  reference_type VARCHAR(30) NOT NULL,
+  reference_uuid UUID NOT NULL,
+  description    TEXT NOT NULL DEFAULT '',
+  occurred_at    TIMESTAMPTZ NOT NULL,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_ledger_transactions_user_id ON ledger_transactions (user_id);
+CREATE INDEX idx_ledger_transactions_reference ON ledger_transactions (reference_type, reference_uuid);
+
+CREATE TABLE ledger_entries (
+  id             BIGSERIAL PRIMARY KEY,
+  uuid           UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  transaction_id BIGINT NOT NULL REFERENCES ledger_transactions (id) ON DELETE CASCADE,
+  asset_id       BIGINT REFERENCES assets (id) ON DELETE RESTRICT,
+  account        TEXT NOT NULL,
+  amount         DECIMAL(20, 8) NOT NULL
+);
+CREATE INDEX idx_ledger_entries_transaction_id ON ledger_entries (transaction_id);
+CREATE INDEX idx_ledger_entries_asset_id ON ledger_entries (asset_id);
+
+-- Backfill: post every existing expense and income on a CASH asset as a transaction of its own, so editing or
+-- deleting it later reverses what it actually took out of (or put into) the asset.
+WITH txns AS (
+  INSERT INTO ledger_transactions (user_id, reference_type, reference_uuid, description, occurred_at)
+  SELECT x.user_id, 'EXPENSE', x.uuid, 'expense ' || x.category::text, x.date
+  FROM expenses x JOIN assets a ON a.id = x.asset_id
+  WHERE a.type = 'CASH' AND x.amount <> 0
+  RETURNING id, reference_uuid
+)
+INSERT INTO ledger_entries (transaction_id, asset_id, account, amount)
+SELECT t.id, x.asset_id, 'ASSET', -x.amount FROM txns t JOIN expenses x ON x.uuid = t.reference_uuid
+UNION ALL
+SELECT t.id, NULL, 'EXPENSE:' || x.category::text, x.amount FROM txns t JOIN expenses x ON x.uuid = t.reference_uuid;
+
+WITH txns AS (
+  INSERT INTO ledger_transactions (user_id, reference_type, reference_uuid, description, occurred_at)
+  SELECT i.user_id, 'INCOME', i.uuid, 'income ' || i.source, i.date
+  FROM incomes i JOIN assets a ON a.id = i.asset_id
+  WHERE a.type = 'CASH' AND i.amount <> 0
+  RETURNING id, reference_uuid
+)
+INSERT INTO ledger_entries (transaction_id, asset_id, account, amount)
+SELECT t.id, i.asset_id, 'ASSET', i.amount FROM txns t JOIN incomes i ON i.uuid = t.reference_uuid
+UNION ALL
+SELECT t.id, NULL, 'INCOME', -i.amount FROM txns t JOIN incomes i ON i.uuid = t.reference_uuid;
+
+-- Then open the ledger of every CASH asset with whatever the postings above leave unexplained, dated no later
+-- than its first posting, so each ledger balance equals the asset's current quantity.
+WITH opening AS (
+  SELECT a.id AS asset_id, a.user_id, a.uuid,
+         a.quantity - COALESCE(SUM(e.amount), 0) AS amount,
+         LEAST(a.created_at, MIN(t.occurred_at)) AS occurred_at
+  FROM assets a
+  LEFT JOIN ledger_entries e ON e.asset_id = a.id
+  LEFT JOIN ledger_transactions t ON t.id = e.transaction_id
+  WHERE a.type = 'CASH'
+  GROUP BY a.id
+), txns AS (
+  INSERT INTO ledger_transactions (user_id, reference_type, reference_uuid, description, occurred_at)
+  SELECT user_id, 'OPENING_BALANCE', uuid, 'opening balance', occurred_at
+  FROM opening
+  WHERE amount <> 0
+  RETURNING id, reference_uuid
+)
+INSERT INTO ledger_entries (transaction_id, asset_id, account, amount)
+SELECT t.id, o.asset_id, 'ASSET', o.amount FROM txns t JOIN opening o ON o.uuid = t.reference_uuid
+UNION ALL
+SELECT t.id, NULL, 'EQUITY:OPENING', -o.amount FROM txns t JOIN opening o ON o.uuid = t.reference_uuid;
@@ -0,0 +1,25 @@
+-- Transfers between two CASH assets (not counted as income or expense).
+-- The optional fee is stored as a regular expense linked through fee_expense_id.
+CREATE TABLE transfers (
+  id             BIGSERIAL PRIMARY KEY,
+  uuid           UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  user_id        BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  from_asset_id  BIGINT NOT NULL REFERENCES assets (id),
+  to_asset_id    BIGINT NOT NULL REFERENCES assets (id),
+  amount         DECIMAL(20, 2) NOT NULL,
+  from_currency  VARCHAR(10) NOT NULL,
+  to_amount      DECIMAL(20, 2) NOT NULL,
+  to_currency    VARCHAR(10) NOT NULL,
+  exchange_rate  DECIMAL(20, 8) NOT NULL DEFAULT 1,
+  fee            DECIMAL(20, 2),
+  fee_expense_id BIGINT REFERENCES expenses (id) ON DELETE SET NULL,
+  note           TEXT,
+  date           TIMESTAMPTZ NOT NULL,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (from_asset_id <> to_asset_id)
+);
+CREATE INDEX idx_transfers_user_id ON transfers (user_id);
+CREATE INDEX idx_transfers_from_asset_id ON transfers (from_asset_id);
+CREATE INDEX idx_transfers_to_asset_id ON transfers (to_asset_id);
+CREATE INDEX idx_transfers_date ON transfers (date);
@@ -0,0 +1,3 @@
+-- Debts and receivables can record the initial cash movement (borrowed cash in, lent cash out) on their CASH asset.
+ALTER TABLE debts ADD COLUMN disbursed BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE receivables ADD COLUMN disbursed BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,3 @@
+-- Voided debt/receivable payments stay for audit but no longer count toward paid_amount.
+ALTER TABLE debt_payments ADD COLUMN voided_at TIMESTAMPTZ;
+ALTER TABLE receivable_payments ADD COLUMN voided_at TIMESTAMPTZ;
@@ -0,0 +1,11 @@
+-- Debts and receivables past their due date are stored as OVERDUE; status_changed_at records the last status transition.
+ALTER TABLE debts ADD COLUMN status_changed_at TIMESTAMPTZ;
+ALTER TABLE receivables ADD COLUMN status_changed_at TIMESTAMPTZ;
+
+UPDATE debts SET status = 'OVERDUE', status_changed_at = now()
+WHERE due_date < now() AND status IN ('PENDING', 'PARTIAL');
+UPDATE receivables SET status = 'OVERDUE', status_changed_at = now()
+WHERE due_date < now() AND status IN ('PENDING', 'PARTIAL');
+
+CREATE INDEX idx_debts_overdue_due_date ON debts (due_date) WHERE status IN ('PENDING', 'PARTIAL');
+CREATE INDEX idx_receivables_overdue_due_date ON receivables (due_date) WHERE status IN ('PENDING', 'PARTIAL');
@@ -0,0 +1,23 @@
+-- Runs of scheduled background jobs (internal/scheduler). One row per job name and schedule slot.
+CREATE TYPE job_status AS ENUM ('PENDING', 'RUNNING', 'SUCCEEDED', 'DEAD');
+
+CREATE TABLE scheduler_jobs (
+  id            BIGSERIAL PRIMARY KEY,
+  uuid          UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  name          VARCHAR(100) NOT NULL,
+  scheduled_for TIMESTAMPTZ NOT NULL,
+  run_at        TIMESTAMPTZ NOT NULL,
+  status        job_status NOT NULL DEFAULT 'PENDING',
+  attempts      INT NOT NULL DEFAULT 0,
+  max_attempts  INT NOT NULL DEFAULT 5,
+  last_error    TEXT,
+  locked_by     VARCHAR(255),
+  locked_at     TIMESTAMPTZ,
+  finished_at   TIMESTAMPTZ,
+  created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (name, scheduled_for)
+);
+
+CREATE INDEX idx_scheduler_jobs_due ON scheduler_jobs (run_at) WHERE status = 'PENDING';
+CREATE INDEX idx_scheduler_jobs_running ON scheduler_jobs (locked_at) WHERE status = 'RUNNING';
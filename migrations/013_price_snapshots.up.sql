@@ -0,0 +1,6 @@
+-- Scheduled price snapshots: at most one row per asset and day (snapshot_date), priced in the asset's currency.
+-- Manual and on-demand rows keep snapshot_date NULL, so they never conflict with the daily row.
+ALTER TABLE asset_price_histories ADD COLUMN currency VARCHAR(10);
+ALTER TABLE asset_price_histories ADD COLUMN snapshot_date DATE;
+
+CREATE UNIQUE INDEX idx_asset_price_histories_snapshot ON asset_price_histories (asset_id, snapshot_date);
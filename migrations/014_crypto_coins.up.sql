@@ -0,0 +1,13 @@
+-- CoinGecko coin list used to resolve crypto tickers to provider IDs (refreshed by the coin-list-refresh job).
+-- Several coins can share a ticker; the one with the best (lowest) market_cap_rank wins.
+CREATE TABLE crypto_coins (
+  id              VARCHAR(100) PRIMARY KEY,
+  symbol          VARCHAR(50) NOT NULL,
+  name            TEXT NOT NULL,
+  market_cap_rank INT,
+  updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX idx_crypto_coins_symbol ON crypto_coins (upper(symbol));
+
+-- Optional provider ID pinned on an asset (CoinGecko coin ID for CRYPTO), used instead of resolving the symbol.
+ALTER TABLE assets ADD COLUMN price_provider_id VARCHAR(100);
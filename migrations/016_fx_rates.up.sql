@@ -0,0 +1,13 @@
+-- Daily FX rates against USD: one unit of base (always USD) buys rate units of quote on rate_date.
+-- Other pairs are triangulated through USD; "as of" lookups take the latest row on or before the date.
+CREATE TABLE fx_rates (
+  id         BIGSERIAL PRIMARY KEY,
+  base       VARCHAR(10) NOT NULL,
+  quote      VARCHAR(10) NOT NULL,
+  rate_date  DATE NOT NULL,
+  rate       DECIMAL(30,12) NOT NULL CHECK (rate > 0),
+  source     VARCHAR(50) NOT NULL,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (base, quote, rate_date)
+);
@@ -0,0 +1,25 @@
+-- Asset positions are derived from asset_transactions: quantity and cost basis are the open lots left after
+-- replaying BUY/TRANSFER_IN against SELL/TRANSFER_OUT/FEE in date order, matched FIFO or at weighted-average cost.
+ALTER TABLE assets ADD COLUMN cost_method VARCHAR(10) NOT NULL DEFAULT 'FIFO' CHECK (cost_method IN ('FIFO', 'AVERAGE'));
+
+ALTER TABLE asset_transactions ADD CONSTRAINT asset_transactions_type_check
+  CHECK (type IN ('BUY', 'SELL', 'TRANSFER_IN', 'TRANSFER_OUT', 'FEE'));
+CREATE INDEX idx_asset_transactions_asset_date ON asset_transactions (asset_id, transaction_date, id);
+
+-- Every existing non-cash holding becomes a single opening BUY lot with the cost basis it already had, in
+-- the asset's purchase currency (IDR when blank, like port.DefaultCurrency).
+-- purchase_price is per share while stocks listed in lots hold their quantity in lots, so a missing cost
+-- basis is quantity × lot size × price, with the lot size of the listing picked like the instrument registry does.
+INSERT INTO asset_transactions (asset_id, user_id, type, quantity, price_per_unit, total_amount, fee, currency, transaction_date, notes)
+SELECT a.id, a.user_id, 'BUY', a.quantity, a.purchase_price,
+       CASE WHEN a.total_cost > 0 THEN a.total_cost ELSE a.quantity * COALESCE(l.lot_size, 1) * a.purchase_price END,
+       0, COALESCE(NULLIF(UPPER(TRIM(a.purchase_currency)), ''), 'IDR'), COALESCE(a.purchase_date, a.created_at, NOW()), 'opening position'
+FROM assets a
+LEFT JOIN LATERAL (
+  SELECT i.lot_size FROM instruments i
+  WHERE a.type = 'STOCK' AND i.symbol = UPPER(TRIM(a.symbol))
+  ORDER BY UPPER(i.currency) = COALESCE(NULLIF(UPPER(TRIM(a.purchase_currency)), ''), 'IDR') DESC, i.exchange
+  LIMIT 1
+) l ON true
+WHERE a.type <> 'CASH' AND a.status <> 'PLANNED' AND a.quantity > 0
+  AND NOT EXISTS (SELECT 1 FROM asset_transactions t WHERE t.asset_id = a.id);
@@ -0,0 +1,37 @@
+-- A SELL can credit its proceeds to a CASH asset: cash_amount = (total_amount - fee) * exchange_rate lands in
+-- cash_asset_id through the ledger. realized_profit_loss on the asset is derived from its transactions
+-- (in the purchase currency).
+ALTER TABLE asset_transactions ADD COLUMN cash_asset_id BIGINT REFERENCES assets(id) ON DELETE SET NULL;
+ALTER TABLE asset_transactions ADD COLUMN exchange_rate DECIMAL(30,12);
+ALTER TABLE assets ADD COLUMN realized_profit_loss DECIMAL(20,8) NOT NULL DEFAULT 0;
+
+-- Assets marked SOLD with a sold price before sales existed get that sale recorded, closing their lot, in
+-- the asset's purchase currency (IDR when blank, like port.DefaultCurrency). sold_price is per share, so
+-- the proceeds of stocks listed in lots are quantity × lot size × price, as in 017.
+CREATE TEMP TABLE sold_assets AS
+SELECT a.id, COALESCE(NULLIF(UPPER(TRIM(a.purchase_currency)), ''), 'IDR') AS currency,
+       a.quantity * COALESCE(l.lot_size, 1) * a.sold_price AS proceeds,
+       a.quantity * COALESCE(l.lot_size, 1) * a.purchase_price AS cost
+FROM assets a
+LEFT JOIN LATERAL (
+  SELECT i.lot_size FROM instruments i
+  WHERE a.type = 'STOCK' AND i.symbol = UPPER(TRIM(a.symbol))
+  ORDER BY UPPER(i.currency) = COALESCE(NULLIF(UPPER(TRIM(a.purchase_currency)), ''), 'IDR') DESC, i.exchange
+  LIMIT 1
+) l ON true
+WHERE a.type <> 'CASH' AND a.status = 'SOLD' AND a.sold_price IS NOT NULL AND a.quantity > 0;
+
+INSERT INTO asset_transactions (asset_id, user_id, type, quantity, price_per_unit, total_amount, fee, currency, transaction_date, notes)
+SELECT a.id, a.user_id, 'SELL', a.quantity, a.sold_price, s.proceeds, 0,
+       s.currency, GREATEST(COALESCE(a.sold_at, a.updated_at, NOW()), a.purchase_date), 'recorded sale'
+FROM assets a
+JOIN sold_assets s ON s.id = a.id;
+
+UPDATE assets a
+SET realized_profit_loss = s.proceeds - CASE WHEN a.total_cost > 0 THEN a.total_cost ELSE s.cost END,
+    quantity = 0,
+    total_cost = 0
+FROM sold_assets s
+WHERE s.id = a.id;
+
+DROP TABLE sold_assets;
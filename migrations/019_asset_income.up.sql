@@ -0,0 +1,9 @@
+-- An income can name the asset that earned it (a stock's dividend, a bond's coupon, a property's rent) on top of
+-- the CASH asset it lands in. income_type says what kind of distribution it was.
+ALTER TABLE incomes ADD COLUMN source_asset_id BIGINT REFERENCES assets(id) ON DELETE SET NULL;
+ALTER TABLE incomes ADD COLUMN income_type VARCHAR(20)
+  CHECK (income_type IN ('DIVIDEND', 'COUPON', 'INTEREST', 'RENT', 'OTHER'));
+CREATE INDEX idx_incomes_source_asset_date ON incomes (source_asset_id, date) WHERE source_asset_id IS NOT NULL;
+
+-- asset_income (002) was never written by the app; income from an asset is now an incomes row pointing at it.
+COMMENT ON TABLE asset_income IS 'Unused; see incomes.source_asset_id';
@@ -0,0 +1,47 @@
+-- Recurring expenses and incomes. A rule repeats every interval_count days, weeks, months or years from
+-- start_date; next_index and next_due are the generator's cursor (next_due is NULL once the rule has ended).
+CREATE TABLE recurring_rules (
+  id             BIGSERIAL PRIMARY KEY,
+  uuid           UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  user_id        BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  asset_id       BIGINT NOT NULL REFERENCES assets (id) ON DELETE CASCADE,
+  kind           VARCHAR(10) NOT NULL CHECK (kind IN ('EXPENSE', 'INCOME')),
+  frequency      VARCHAR(10) NOT NULL CHECK (frequency IN ('DAILY', 'WEEKLY', 'MONTHLY', 'YEARLY')),
+  interval_count INT NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+  amount         DECIMAL(20, 2) NOT NULL,
+  category       expense_category,
+  source         TEXT,
+  note           TEXT,
+  start_date     TIMESTAMPTZ NOT NULL,
+  end_date       TIMESTAMPTZ,
+  auto_post      BOOLEAN NOT NULL DEFAULT TRUE,
+  next_index     INT NOT NULL DEFAULT 0,
+  next_due       TIMESTAMPTZ,
+  created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK ((kind = 'EXPENSE' AND category IS NOT NULL) OR (kind = 'INCOME' AND source IS NOT NULL))
+);
+CREATE INDEX idx_recurring_rules_user_id ON recurring_rules (user_id);
+CREATE INDEX idx_recurring_rules_next_due ON recurring_rules (next_due) WHERE next_due IS NOT NULL;
+
+-- One row per occurrence that is not simply "post the rule as is": skipped or edited ahead of time, waiting
+-- for confirmation, or posted (with the expense or income it became).
+CREATE TABLE recurring_occurrences (
+  id               BIGSERIAL PRIMARY KEY,
+  uuid             UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  rule_id          BIGINT NOT NULL REFERENCES recurring_rules (id) ON DELETE CASCADE,
+  user_id          BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  occurrence_index INT NOT NULL,
+  due_date         TIMESTAMPTZ NOT NULL,
+  status           VARCHAR(10) NOT NULL CHECK (status IN ('SCHEDULED', 'SKIPPED', 'PENDING', 'POSTED')),
+  amount           DECIMAL(20, 2),
+  note             TEXT,
+  expense_id       BIGINT REFERENCES expenses (id) ON DELETE SET NULL,
+  income_id        BIGINT REFERENCES incomes (id) ON DELETE SET NULL,
+  last_error       TEXT,
+  posted_at        TIMESTAMPTZ,
+  created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (rule_id, occurrence_index)
+);
+CREATE INDEX idx_recurring_occurrences_user_status ON recurring_occurrences (user_id, status);
@@ -0,0 +1,17 @@
+-- Spending limits per expense category. A MONTHLY budget covers the calendar month starting on month; a
+-- ROLLING one applies to every month from month on and can carry unspent amounts into the next month.
+CREATE TABLE budgets (
+  id         BIGSERIAL PRIMARY KEY,
+  uuid       UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  user_id    BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  category   expense_category NOT NULL,
+  period     VARCHAR(10) NOT NULL CHECK (period IN ('MONTHLY', 'ROLLING')),
+  month      DATE NOT NULL CHECK (EXTRACT(DAY FROM month) = 1),
+  amount     DECIMAL(20, 2) NOT NULL CHECK (amount > 0),
+  rollover   BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (period = 'ROLLING' OR NOT rollover)
+);
+CREATE UNIQUE INDEX idx_budgets_monthly ON budgets (user_id, category, month) WHERE period = 'MONTHLY';
+CREATE UNIQUE INDEX idx_budgets_rolling ON budgets (user_id, category) WHERE period = 'ROLLING';
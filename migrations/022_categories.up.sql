@@ -0,0 +1,61 @@
+-- Per-user expense categories replace the fixed expense_category enum. Every user gets the former enum values
+-- as default categories (code keeps the old value, so ledger accounts such as EXPENSE:FOOD stay the same) and
+-- can add their own, one level deep under a top-level category.
+CREATE TABLE categories (
+  id          BIGSERIAL PRIMARY KEY,
+  uuid        UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  user_id     BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  parent_id   BIGINT REFERENCES categories (id) ON DELETE RESTRICT,
+  name        VARCHAR(100) NOT NULL,
+  code        VARCHAR(20),
+  icon        VARCHAR(50),
+  color       VARCHAR(7) CHECK (color ~ '^#[0-9A-Fa-f]{6}$'),
+  archived_at TIMESTAMPTZ,
+  created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+  CHECK (parent_id IS NULL OR parent_id <> id)
+);
+CREATE INDEX idx_categories_parent_id ON categories (parent_id) WHERE parent_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_categories_user_code ON categories (user_id, code) WHERE code IS NOT NULL;
+CREATE UNIQUE INDEX idx_categories_user_name ON categories (user_id, COALESCE(parent_id, 0), lower(name));
+
+INSERT INTO categories (user_id, code, name, icon, color)
+SELECT u.id, d.code, d.name, d.icon, d.color
+FROM users u
+CROSS JOIN (VALUES
+  ('FOOD', 'Food', 'utensils', '#F97316'),
+  ('TRANSPORT', 'Transport', 'car', '#3B82F6'),
+  ('HOUSING', 'Housing', 'home', '#8B5CF6'),
+  ('UTILITIES', 'Utilities', 'bolt', '#EAB308'),
+  ('HEALTH', 'Health', 'heart-pulse', '#EF4444'),
+  ('ENTERTAINMENT', 'Entertainment', 'film', '#EC4899'),
+  ('SHOPPING', 'Shopping', 'shopping-bag', '#14B8A6'),
+  ('OTHER', 'Other', 'ellipsis', '#6B7280')
+) AS d (code, name, icon, color);
+
+-- Expenses
+ALTER TABLE expenses ADD COLUMN category_id BIGINT REFERENCES categories (id) ON DELETE RESTRICT;
+UPDATE expenses e SET category_id = c.id FROM categories c WHERE c.user_id = e.user_id AND c.code = e.category::text;
+ALTER TABLE expenses ALTER COLUMN category_id SET NOT NULL;
+ALTER TABLE expenses DROP COLUMN category;
+CREATE INDEX idx_expenses_category_id ON expenses (category_id);
+
+-- Recurring rules
+ALTER TABLE recurring_rules ADD COLUMN category_id BIGINT REFERENCES categories (id) ON DELETE RESTRICT;
+UPDATE recurring_rules r SET category_id = c.id FROM categories c WHERE c.user_id = r.user_id AND c.code = r.category::text;
+ALTER TABLE recurring_rules DROP CONSTRAINT IF EXISTS recurring_rules_check;
+ALTER TABLE recurring_rules DROP COLUMN category;
+ALTER TABLE recurring_rules ADD CONSTRAINT recurring_rules_check
+  CHECK ((kind = 'EXPENSE' AND category_id IS NOT NULL) OR (kind = 'INCOME' AND source IS NOT NULL));
+
+-- Budgets
+ALTER TABLE budgets ADD COLUMN category_id BIGINT REFERENCES categories (id) ON DELETE RESTRICT;
+UPDATE budgets b SET category_id = c.id FROM categories c WHERE c.user_id = b.user_id AND c.code = b.category::text;
+ALTER TABLE budgets ALTER COLUMN category_id SET NOT NULL;
+DROP INDEX idx_budgets_monthly;
+DROP INDEX idx_budgets_rolling;
+ALTER TABLE budgets DROP COLUMN category;
+CREATE UNIQUE INDEX idx_budgets_monthly ON budgets (user_id, category_id, month) WHERE period = 'MONTHLY';
+CREATE UNIQUE INDEX idx_budgets_rolling ON budgets (user_id, category_id) WHERE period = 'ROLLING';
+
+DROP TYPE expense_category;
@@ -0,0 +1,38 @@
+-- Free-form labels ("trip-bali-2026", "reimbursable") a user can put on expenses, incomes, debts and
+-- receivables, across categories. Names are stored trimmed and lower-cased, one row per user and name.
+CREATE TABLE tags (
+  id         BIGSERIAL PRIMARY KEY,
+  uuid       UUID NOT NULL UNIQUE DEFAULT gen_random_uuid(),
+  user_id    BIGINT NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  name       VARCHAR(50) NOT NULL CHECK (name <> '' AND name = lower(btrim(name))),
+  created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+  UNIQUE (user_id, name)
+);
+
+CREATE TABLE expense_tags (
+  expense_id BIGINT NOT NULL REFERENCES expenses (id) ON DELETE CASCADE,
+  tag_id     BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+  PRIMARY KEY (expense_id, tag_id)
+);
+CREATE INDEX idx_expense_tags_tag_id ON expense_tags (tag_id);
+
+CREATE TABLE income_tags (
+  income_id BIGINT NOT NULL REFERENCES incomes (id) ON DELETE CASCADE,
+  tag_id    BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+  PRIMARY KEY (income_id, tag_id)
+);
+CREATE INDEX idx_income_tags_tag_id ON income_tags (tag_id);
+
+CREATE TABLE debt_tags (
+  debt_id BIGINT NOT NULL REFERENCES debts (id) ON DELETE CASCADE,
+  tag_id  BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+  PRIMARY KEY (debt_id, tag_id)
+);
+CREATE INDEX idx_debt_tags_tag_id ON debt_tags (tag_id);
+
+CREATE TABLE receivable_tags (
+  receivable_id BIGINT NOT NULL REFERENCES receivables (id) ON DELETE CASCADE,
+  tag_id        BIGINT NOT NULL REFERENCES tags (id) ON DELETE CASCADE,
+  PRIMARY KEY (receivable_id, tag_id)
+);
+CREATE INDEX idx_receivable_tags_tag_id ON receivable_tags (tag_id);
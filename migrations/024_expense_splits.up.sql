@@ -0,0 +1,13 @@
+-- An expense can be split across categories, e.g. one supermarket receipt with food, household and shopping
+-- lines. The lines add up to the expense amount, which still comes off the CASH asset once; category totals
+-- and budgets count the lines instead of the expense. A split expense keeps its first line's category in
+-- expenses.category_id.
+CREATE TABLE expense_splits (
+  id          BIGSERIAL PRIMARY KEY,
+  expense_id  BIGINT NOT NULL REFERENCES expenses (id) ON DELETE CASCADE,
+  category_id BIGINT NOT NULL REFERENCES categories (id) ON DELETE RESTRICT,
+  amount      DECIMAL(20,2) NOT NULL CHECK (amount > 0),
+  note        VARCHAR(500)
+);
+CREATE INDEX idx_expense_splits_expense_id ON expense_splits (expense_id);
+CREATE INDEX idx_expense_splits_category_id ON expense_splits (category_id);